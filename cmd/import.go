@@ -0,0 +1,70 @@
+package main
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"WebTasks/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// runImport выполняет подкоманду import - перенос файла выгрузки другого трекера:
+//
+//	WebTasks import -source trello -user 1 [-project "Имя"] [-dry-run] board.json
+//
+// Итог переноса печатается в out в формате JSON.
+func runImport(database *sqlx.DB, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	source := flags.String("source", "", "источник выгрузки: "+strings.Join(models.MigrationSources, ", "))
+	userID := flags.Int("user", 0, "ID пользователя, которому принадлежат проекты и задачи")
+	project := flags.String("project", "", "имя проекта вместо взятого из файла")
+	dryRun := flags.Bool("dry-run", false, "только проверить файл, ничего не создавая")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *source == "" || *userID <= 0 {
+		return errors.New("usage: import -source trello|todoist|jira -user ID [-project NAME] [-dry-run] FILE")
+	}
+
+	ctx := context.Background()
+
+	if _, err := repositories.NewUserRepo(database).GetByID(ctx, *userID); err != nil {
+		return fmt.Errorf("user %d: %w", *userID, err)
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	service := services.NewMigrationService(repositories.NewMigrationRepo(database), nil)
+
+	report, err := service.Import(ctx, models.MigrationOptions{
+		Source:  *source,
+		Project: *project,
+		DryRun:  *dryRun,
+		UserID:  *userID,
+	}, file)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
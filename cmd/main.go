@@ -4,67 +4,382 @@ import (
 	"WebTasks/config"
 	"WebTasks/internal/db"
 	"WebTasks/internal/handlers"
+	"WebTasks/internal/metrics"
 	"WebTasks/internal/repositories"
 	"WebTasks/internal/services"
-	"log"
-	"net/http"
-	"strconv"
+	"WebTasks/internal/storage"
+	"WebTasks/internal/tracing"
+	"WebTasks/internal/utils"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	// Чтение конфигурации
-	cfg, err := config.ViperConfig()
+	// Код завершения подкоманды; os.Exit вызывается последним, после закрытия базы
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// Чтение конфигурации: флаги идут до подкоманды, например main -profile production import ...
+	cfg, args, err := config.Load(os.Args[1:])
 	if err != nil {
-		log.Printf("Ошибка чтения конфигурации базы данных: %v", err)
+		if !errors.Is(err, flag.ErrHelp) {
+			slog.Error("loading config failed", "error", err)
+			exitCode = 2
+		}
+
 		return
 	}
 
+	if err := utils.SetupLogger(os.Stderr, cfg.Log.Format, cfg.Log.Level); err != nil {
+		slog.Error("configuring logger failed", "error", err)
+		exitCode = 2
+		return
+	}
+
+	if cfg.Profile != "" {
+		slog.Info("config profile selected", "profile", cfg.Profile)
+	}
+
+	// Трассировка включается до подключения к базе, чтобы в спаны попали и миграции
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOptions(cfg), os.Stdout)
+	if err != nil {
+		slog.Error("configuring tracing failed", "error", err)
+		exitCode = 2
+		return
+	}
+
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("flushing traces failed", "error", err)
+		}
+	}()
+
 	// Подключение к базе данных
 	database, err := db.DB(cfg)
 	if err != nil {
-		log.Printf("Ошибка подключения к базе данных: %v", err)
+		slog.Error("connecting to database failed", "error", err)
+		exitCode = 1
 		return
 	}
 
 	defer func() {
 		if err := database.Close(); err != nil {
-			log.Printf("Ошибка при закрытии подключения к базе данных: %v", err)
+			slog.Error("closing database failed", "error", err)
 		}
 	}()
 
+	// Применение миграций
+	if err := db.ApplyMigrations(database); err != nil {
+		slog.Error("applying migrations failed", "error", err)
+		exitCode = 1
+		return
+	}
+
+	// Подкоманда import переносит файл выгрузки другого трекера и завершает работу
+	if len(args) > 0 && args[0] == "import" {
+		if err := runImport(database, args[1:], os.Stdout); err != nil {
+			slog.Error("import failed", "error", err)
+			exitCode = 1
+		}
+
+		return
+	}
+
+	// Хранилище вложений
+	blobStore, err := storage.NewBlobStore(cfg.Storage)
+	if err != nil {
+		slog.Error("initializing attachment storage failed", "error", err)
+		exitCode = 1
+		return
+	}
+
 	// Создание репозиториев
 	userRepo := repositories.NewUserRepo(database)
 	taskRepo := repositories.RepositoryForTasks(database)
+	attachmentRepo := repositories.NewAttachmentRepo(database)
+	tagRepo := repositories.NewTagRepo(database)
+	timeEntryRepo := repositories.NewTimeEntryRepo(database)
+	planningRepo := repositories.NewPlanningRepo(database)
+	projectRepo := repositories.NewProjectRepo(database)
+	templateRepo := repositories.NewTemplateRepo(database)
+	checklistRepo := repositories.NewChecklistRepo(database)
+	viewRepo := repositories.NewViewRepo(database)
+	idempotencyRepo := repositories.NewIdempotencyRepo(database)
+	calendarRepo := repositories.NewCalendarRepo(database)
+	calDAVRepo := repositories.NewCalDAVRepo(database)
+	commentRepo := repositories.NewCommentRepo(database)
+	migrationRepo := repositories.NewMigrationRepo(database)
+	workCalendarRepo := repositories.NewWorkCalendarRepo(database)
+	memberRepo := repositories.NewMemberRepo(database)
+	healthRepo := repositories.NewHealthRepo(database)
 
 	// Создание сервисов
-	userService := services.NewUserService(userRepo)
-	taskService := services.NewTaskService(taskRepo)
+	userService := services.TraceUserService(services.NewUserService(userRepo))
+	workCalendarService := services.NewWorkCalendarService(workCalendarRepo)
+	projectService := services.NewProjectService(projectRepo, userRepo)
+	taskService := services.TraceTaskService(services.NewTaskService(taskRepo, projectService, workCalendarService))
+	taskService.SetMaxTasks(cfg.Quota.MaxTasks)
+	attachmentService := services.NewAttachmentService(attachmentRepo, taskRepo, blobStore, attachmentLimits(cfg))
+	tagService := services.NewTagService(tagRepo, taskRepo)
+	timeTrackingService := services.NewTimeTrackingService(timeEntryRepo, taskRepo)
+	planningService := services.NewPlanningService(planningRepo, taskRepo, scoringWeights(cfg), workCalendarService)
+
+	templateService := services.NewTemplateService(templateRepo, taskService)
+	checklistService := services.NewChecklistService(checklistRepo, taskRepo)
+	viewService := services.NewViewService(viewRepo, taskService, projectRepo)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL)
+	calendarService := services.NewCalendarService(calendarRepo, taskRepo)
+	calDAVService := services.NewCalDAVService(calDAVRepo, taskService)
+	commentService := services.NewCommentService(commentRepo, taskRepo)
+	migrationService := services.NewMigrationService(migrationRepo, taskService)
+	memberService := services.NewMemberService(memberRepo, taskRepo)
+	healthService := services.NewHealthService(healthRepo, db.SchemaVersion(), cfg.Health.Timeout)
+
+	// Корзины в базе общие для всех экземпляров сервиса, в памяти - только для одного
+	rateLimitStore := services.NewMemoryRateLimitStore()
+	if cfg.RateLimit.Store == "postgres" {
+		rateLimitStore = repositories.NewRateLimitRepo(database)
+	}
+
+	rateLimitService := services.NewRateLimitService(rateLimitStore, rateLimitRules(cfg))
+
+	// Число просроченных задач считается при каждом сборе /metrics
+	overdue := func(ctx context.Context) (int, error) {
+		return taskRepo.CountOverdue(ctx, time.Now())
+	}
+
+	if err := metrics.RegisterOverdueTasks(overdue, cfg.Health.Timeout); err != nil {
+		slog.Error("registering metrics failed", "error", err)
+		exitCode = 1
+		return
+	}
+
+	// SIGINT и SIGTERM останавливают сервер, затем фоновые задачи; база закрывается последней
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+
+	var workers sync.WaitGroup
+
+	defer func() {
+		stopWorkers()
+		workers.Wait()
+	}()
+
+	// Состояние фоновых задач учитывается в /readyz
+	runWorker := func(name string, run func(ctx context.Context)) {
+		workers.Add(1)
+		healthService.WorkerStarted(name)
+
+		go func() {
+			defer workers.Done()
+			defer healthService.WorkerStopped(name)
+
+			run(workersCtx)
+		}()
+	}
+
+	runWorker("idempotency_purger", func(ctx context.Context) {
+		idempotencyService.RunPurger(ctx, cfg.Idempotency.PurgeInterval)
+	})
+	runWorker("rate_limit_purger", func(ctx context.Context) {
+		rateLimitService.RunPurger(ctx, cfg.RateLimit.PurgeInterval)
+	})
 
 	// Создание обработчиков
 	taskHandler := handlers.NewHandler(taskService)
 	userHandler := handlers.NewUserHandler(userService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, cfg.Storage.MaxSize)
+	tagHandler := handlers.NewTagHandler(tagService)
+	timeTrackingHandler := handlers.NewTimeTrackingHandler(timeTrackingService)
+	planningHandler := handlers.NewPlanningHandler(planningService)
+	projectHandler := handlers.NewProjectHandler(projectService)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	checklistHandler := handlers.NewChecklistHandler(checklistService)
+	viewHandler := handlers.NewViewHandler(viewService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	calDAVHandler := handlers.NewCalDAVHandler(calDAVService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	migrationHandler := handlers.NewMigrationHandler(migrationService)
+	workCalendarHandler := handlers.NewWorkCalendarHandler(workCalendarService)
+	memberHandler := handlers.NewMemberHandler(memberService)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	cors := handlers.NewCORS(corsOptions(cfg))
+	bodyLimiter := handlers.NewBodyLimiter(bodyLimits(cfg))
+
+	// Параметры с тегом reload применяются при изменении файла конфигурации без перезапуска
+	reloader := config.NewReloader(cfg)
+	reloader.OnReload(func(cfg *config.Config) {
+		attachmentService.SetLimits(attachmentLimits(cfg))
+		attachmentHandler.SetMaxSize(cfg.Storage.MaxSize)
+		planningService.SetWeights(scoringWeights(cfg))
+		idempotencyService.SetTTL(cfg.Idempotency.TTL)
+		rateLimitService.SetRules(rateLimitRules(cfg))
+		taskService.SetMaxTasks(cfg.Quota.MaxTasks)
+		cors.SetOptions(corsOptions(cfg))
+		bodyLimiter.SetLimits(bodyLimits(cfg))
+
+		if err := utils.SetLogLevel(cfg.Log.Level); err != nil {
+			slog.Error("changing log level failed", "error", err)
+		}
+	})
+	reloader.Watch()
 
 	// Создание маршрутов
 	router := mux.NewRouter()
 
 	// Применение глобальных middleware
-	router.Use(handlers.LoggerMiddleware) // Логирование запросов
+	router.Use(handlers.TracingMiddleware)   // Спан запроса, продолжает трассу из traceparent
+	router.Use(handlers.RequestIDMiddleware) // X-Request-ID в контексте, журнале и ответе
+	router.Use(handlers.MetricsMiddleware)   // Метрики учитывают и отклонённые дальше запросы
+	router.Use(handlers.LoggerMiddleware)    // Логирование запросов
 	router.Use(handlers.AuthMiddleware)
+	router.Use(handlers.IdentityMiddleware(userService)) // Определение пользователя по API-ключу
+	router.Use(handlers.RateLimitMiddleware(rateLimitService, cfg.RateLimit.TrustProxy))
+	router.Use(bodyLimiter.Middleware) // До IdempotencyMiddleware, которая читает тело
+	router.Use(handlers.IdempotencyMiddleware(idempotencyService, "POST /tasks", "POST /users"))
 
 	// Регистрация маршрутов
 	handlers.RegisterUserRoutes(router, userHandler)
 	handlers.RegisterTaskRoutes(router, taskHandler)
+	handlers.RegisterAttachmentRoutes(router, attachmentHandler)
+	handlers.RegisterTagRoutes(router, tagHandler)
+	handlers.RegisterTimeTrackingRoutes(router, timeTrackingHandler)
+	handlers.RegisterPlanningRoutes(router, planningHandler)
+	handlers.RegisterProjectRoutes(router, projectHandler)
+	handlers.RegisterTemplateRoutes(router, templateHandler)
+	handlers.RegisterChecklistRoutes(router, checklistHandler)
+	handlers.RegisterViewRoutes(router, viewHandler)
+	handlers.RegisterCalendarRoutes(router, calendarHandler)
+	handlers.RegisterCalDAVRoutes(router, calDAVHandler)
+	handlers.RegisterCommentRoutes(router, commentHandler)
+	handlers.RegisterMigrationRoutes(router, migrationHandler)
+	handlers.RegisterWorkCalendarRoutes(router, workCalendarHandler)
+	handlers.RegisterMemberRoutes(router, memberHandler)
+	handlers.RegisterHealthRoutes(router, healthHandler)
+	handlers.RegisterMetricsRoutes(router)
 
 	// Запуск сервера
-	serverAddress := cfg.Server.IP + ":" + strconv.Itoa(cfg.Server.Port)
-	log.Printf("Сервер запущен на %s", serverAddress)
+	// CORS и заголовки безопасности оборачивают весь маршрутизатор: им нужны и запросы,
+	// для которых mux не нашёл маршрут (предварительные OPTIONS, 404, 405)
+	security := handlers.SecurityHeadersMiddleware(handlers.SecurityOptions{
+		HSTSMaxAge:            cfg.Security.HSTSMaxAge,
+		ContentSecurityPolicy: cfg.Security.ContentSecurityPolicy,
+		FrameOptions:          cfg.Security.FrameOptions,
+	})
 
-	err = http.ListenAndServe(serverAddress, router)
-	if err != nil {
-		log.Printf("Ошибка запуска сервера: %v", err)
-		return
+	server := newHTTPServer(cfg, security(cors.Middleware(router)))
+	server.RegisterOnShutdown(healthService.Drain) // /readyz отвечает 503, пока дорабатывают запросы
+
+	if err := serve(ctx, server, cfg); err != nil {
+		slog.Error("server failed", "error", err)
+		exitCode = 1
+	}
+}
+
+func attachmentLimits(cfg *config.Config) services.AttachmentLimits {
+	return services.AttachmentLimits{
+		MaxSize:      cfg.Storage.MaxSize,
+		AllowedTypes: cfg.Storage.AllowedTypes,
 	}
 }
+
+func scoringWeights(cfg *config.Config) services.ScoringWeights {
+	return services.ScoringWeights{
+		Priority: cfg.Scoring.Priority,
+		DueDate:  cfg.Scoring.DueDate,
+		Age:      cfg.Scoring.Age,
+		Blocking: cfg.Scoring.Blocking,
+		Blocked:  cfg.Scoring.Blocked,
+	}
+}
+
+// rateLimitRules переводит ограничения из конфигурации; выключенное ограничение - пустые правила.
+func rateLimitRules(cfg *config.Config) services.RateLimitRules {
+	if !cfg.RateLimit.Enabled {
+		return services.RateLimitRules{}
+	}
+
+	rule := func(rule config.RateLimitRuleConfig) services.RateLimitRule {
+		return services.RateLimitRule{Requests: rule.Requests, Period: rule.Period, Burst: rule.Burst}
+	}
+
+	// Группа без period в особых правилах пользователя берёт общее правило
+	override := func(rule config.RateLimitOverrideConfig) *services.RateLimitRule {
+		if rule.Period == 0 {
+			return nil
+		}
+
+		return &services.RateLimitRule{Requests: rule.Requests, Period: rule.Period, Burst: rule.Burst}
+	}
+
+	users := make(map[string]services.RateLimitOverride, len(cfg.RateLimit.Users))
+	for _, user := range cfg.RateLimit.Users {
+		users[services.RateLimitUserSubject(user.UserID)] = services.RateLimitOverride{
+			Read:  override(user.Read),
+			Write: override(user.Write),
+			Heavy: override(user.Heavy),
+		}
+	}
+
+	return services.RateLimitRules{
+		Read:      rule(cfg.RateLimit.Read),
+		Write:     rule(cfg.RateLimit.Write),
+		Heavy:     rule(cfg.RateLimit.Heavy),
+		Anonymous: rule(cfg.RateLimit.Anonymous),
+		Users:     users,
+	}
+}
+
+func corsOptions(cfg *config.Config) handlers.CORSOptions {
+	return handlers.CORSOptions{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           cfg.CORS.MaxAge,
+	}
+}
+
+func bodyLimits(cfg *config.Config) handlers.BodyLimits {
+	return handlers.BodyLimits{
+		Default: cfg.Limits.MaxBody,
+		Routes:  map[string]int64{"POST /tasks/bulk": cfg.Limits.MaxBulkBody},
+	}
+}
+
+func tracingOptions(cfg *config.Config) tracing.Options {
+	options := tracing.Options{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		ServiceName: cfg.Tracing.ServiceName,
+		Version:     "unknown",
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		options.Version = info.Main.Version
+	}
+
+	return options
+}
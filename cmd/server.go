@@ -0,0 +1,62 @@
+package main
+
+import (
+	"WebTasks/config"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// newHTTPServer создаёт сервер с таймаутами из конфигурации: без них медленный клиент
+// может бесконечно держать соединение и горутину.
+func newHTTPServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Server.IP + ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+}
+
+// serve обслуживает запросы до отмены ctx, затем перестаёт принимать соединения и ждёт
+// завершения начатых запросов не дольше cfg.Server.ShutdownTimeout. Оставшиеся
+// соединения закрываются принудительно.
+func serve(ctx context.Context, server *http.Server, cfg *config.Config) error {
+	serverErr := make(chan error, 1)
+
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	slog.Info("server started", "addr", server.Addr)
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down server", "timeout", cfg.Server.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("requests did not finish in time", "error", err)
+		server.Close()
+	}
+
+	if err := <-serverErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	slog.Info("server stopped")
+
+	return nil
+}
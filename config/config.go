@@ -1,44 +1,449 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
+const (
+	// DefaultPath - файл конфигурации, если путь не задан флагом -config или WEBTASKS_CONFIG.
+	DefaultPath = "./config/db.yaml"
+
+	// envPrefix - префикс переменных окружения: db.search_path задаётся WEBTASKS_DB_SEARCH_PATH.
+	envPrefix = "WEBTASKS"
+)
+
 type Config struct {
 	DB struct {
-		Host       string `yaml:"host" validate:"required"`        // Хост базы данных
-		Port       int    `yaml:"port" validate:"required"`        // Порт базы данных
-		User       string `yaml:"user" validate:"required"`        // Имя пользователя
-		Password   string `yaml:"password" validate:"required"`    // Пароль
-		DBName     string `yaml:"dbname" validate:"required"`      // Имя базы данных
-		SSLMode    string `yaml:"sslmode" validate:"required"`     // Режим SSL
-		SearchPath string `yaml:"search_path" validate:"required"` // Схема поиска
-	} `yaml:"db"`
+		Host       string `yaml:"host" mapstructure:"host" validate:"required"`                 // Хост базы данных
+		Port       int    `yaml:"port" mapstructure:"port" validate:"required,min=1,max=65535"` // Порт базы данных
+		User       string `yaml:"user" mapstructure:"user" validate:"required"`                 // Имя пользователя
+		Password   string `yaml:"password" mapstructure:"password" validate:"required"`         // Пароль
+		DBName     string `yaml:"dbname" mapstructure:"dbname" validate:"required"`             // Имя базы данных
+		SSLMode    string `yaml:"sslmode" mapstructure:"sslmode" validate:"required"`           // Режим SSL
+		SearchPath string `yaml:"search_path" mapstructure:"search_path" validate:"required"`   // Схема поиска
+	} `yaml:"db" mapstructure:"db"`
 
 	Server struct {
-		IP   string `yaml:"ip" validate:"required"`   // IP-адрес сервера
-		Port int    `yaml:"port" validate:"required"` // Порт сервера
-	} `yaml:"server"`
+		IP   string `yaml:"ip" mapstructure:"ip" validate:"required"`                     // IP-адрес сервера
+		Port int    `yaml:"port" mapstructure:"port" validate:"required,min=1,max=65535"` // Порт сервера
+
+		ReadTimeout       time.Duration `yaml:"read_timeout" mapstructure:"read_timeout" validate:"min=0s"`               // Чтение всего запроса, 0 - без ограничения
+		ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" mapstructure:"read_header_timeout" validate:"min=1s"` // Чтение заголовков
+		WriteTimeout      time.Duration `yaml:"write_timeout" mapstructure:"write_timeout" validate:"min=0s"`             // Запись ответа, 0 - без ограничения
+		IdleTimeout       time.Duration `yaml:"idle_timeout" mapstructure:"idle_timeout" validate:"min=0s"`               // Простой keep-alive соединения
+		MaxHeaderBytes    int           `yaml:"max_header_bytes" mapstructure:"max_header_bytes" validate:"min=1024"`     // Предел размера заголовков
+		ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" mapstructure:"shutdown_timeout" validate:"min=1s"`       // Ожидание начатых запросов при остановке
+	} `yaml:"server" mapstructure:"server"`
+
+	Storage StorageConfig `yaml:"storage" mapstructure:"storage"`
+	Scoring ScoringConfig `yaml:"scoring" mapstructure:"scoring" reload:"true"`
+
+	Idempotency IdempotencyConfig `yaml:"idempotency" mapstructure:"idempotency"`
+	Health      HealthConfig      `yaml:"health" mapstructure:"health"`
+	Tracing     TracingConfig     `yaml:"tracing" mapstructure:"tracing"`
+	Log         LogConfig         `yaml:"log" mapstructure:"log"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" mapstructure:"rate_limit"`
+	Quota       QuotaConfig       `yaml:"quota" mapstructure:"quota" reload:"true"`
+	CORS        CORSConfig        `yaml:"cors" mapstructure:"cors" reload:"true"`
+	Security    SecurityConfig    `yaml:"security" mapstructure:"security"`
+	Limits      LimitsConfig      `yaml:"limits" mapstructure:"limits" reload:"true"`
+
+	// Profile - окружение (development, production, ...), по которому выбран файл профиля.
+	Profile string `yaml:"-" mapstructure:"-"`
+
+	args  []string // Аргументы Load для повторной сборки при перезагрузке
+	files []string // Прочитанные файлы конфигурации
 }
 
-func ViperConfig() (*Config, error) {
-	viper.SetConfigName("db")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./config")
+// HealthConfig задаёт параметры проб /readyz и /status.
+type HealthConfig struct {
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout" validate:"min=100ms"` // Предел проверок базы данных
+}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		log.Fatalf("Error reading config file, %s", err)
+// LogConfig задаёт журнал сервиса.
+type LogConfig struct {
+	Level  string `yaml:"level" mapstructure:"level" validate:"oneof=debug info warn error" reload:"true"` // Минимальный уровень записей
+	Format string `yaml:"format" mapstructure:"format" validate:"oneof=json text"`                         // json или text
+}
+
+// RateLimitConfig задаёт ограничение частоты запросов корзинами токенов.
+type RateLimitConfig struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled" reload:"true"`                   // Включить ограничение
+	Store         string        `yaml:"store" mapstructure:"store" validate:"oneof=memory postgres"`    // memory - один экземпляр, postgres - общие корзины
+	TrustProxy    bool          `yaml:"trust_proxy" mapstructure:"trust_proxy"`                         // Брать адрес клиента из X-Forwarded-For
+	PurgeInterval time.Duration `yaml:"purge_interval" mapstructure:"purge_interval" validate:"min=1s"` // Период удаления наполнившихся корзин
+
+	Read      RateLimitRuleConfig `yaml:"read" mapstructure:"read" reload:"true"`           // Чтение
+	Write     RateLimitRuleConfig `yaml:"write" mapstructure:"write" reload:"true"`         // Изменения
+	Heavy     RateLimitRuleConfig `yaml:"heavy" mapstructure:"heavy" reload:"true"`         // Пакетные операции, импорт и выгрузка
+	Anonymous RateLimitRuleConfig `yaml:"anonymous" mapstructure:"anonymous" reload:"true"` // Запросы без API-ключа, по IP-адресу
+
+	Users []RateLimitUserConfig `yaml:"users" mapstructure:"users" reload:"true"` // Особые правила отдельных пользователей
+}
+
+// RateLimitUserConfig заменяет правила групп для пользователя user_id. Группа без period
+// берёт общее правило; requests: 0 с period снимает ограничение группы для пользователя.
+type RateLimitUserConfig struct {
+	UserID int                     `yaml:"user_id" mapstructure:"user_id" validate:"min=1"`
+	Read   RateLimitOverrideConfig `yaml:"read" mapstructure:"read"`
+	Write  RateLimitOverrideConfig `yaml:"write" mapstructure:"write"`
+	Heavy  RateLimitOverrideConfig `yaml:"heavy" mapstructure:"heavy"`
+}
+
+// RateLimitOverrideConfig - как RateLimitRuleConfig, но period: 0 означает "не задано".
+type RateLimitOverrideConfig struct {
+	Requests int           `yaml:"requests" mapstructure:"requests" validate:"min=0"`
+	Period   time.Duration `yaml:"period" mapstructure:"period" validate:"min=0s"`
+	Burst    int           `yaml:"burst" mapstructure:"burst" validate:"min=0"`
+}
+
+// RateLimitRuleConfig - requests запросов за period, подряд не больше burst (0 - как requests).
+// requests: 0 снимает ограничение.
+type RateLimitRuleConfig struct {
+	Requests int           `yaml:"requests" mapstructure:"requests" validate:"min=0"` // Запросов за период
+	Period   time.Duration `yaml:"period" mapstructure:"period" validate:"min=1s"`    // Период
+	Burst    int           `yaml:"burst" mapstructure:"burst" validate:"min=0"`       // Запросов подряд
+}
+
+// QuotaConfig задаёт квоты пользователей.
+type QuotaConfig struct {
+	MaxTasks int `yaml:"max_tasks" mapstructure:"max_tasks" validate:"min=0"` // Незакрытых задач, созданных одним пользователем; 0 - без ограничения
+}
+
+// CORSConfig задаёт, каким сайтам браузер разрешит вызывать API.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" mapstructure:"allowed_origins"`     // Origin целиком или "*"; пусто - CORS выключен
+	AllowedMethods   []string      `yaml:"allowed_methods" mapstructure:"allowed_methods"`     // Методы для предварительных запросов
+	AllowedHeaders   []string      `yaml:"allowed_headers" mapstructure:"allowed_headers"`     // Заголовки запроса для предварительных запросов
+	AllowCredentials bool          `yaml:"allow_credentials" mapstructure:"allow_credentials"` // Разрешить cookies и Authorization браузера
+	MaxAge           time.Duration `yaml:"max_age" mapstructure:"max_age" validate:"min=0s"`   // Кэширование ответа на предварительный запрос
+}
+
+// SecurityConfig задаёт заголовки безопасности ответов.
+type SecurityConfig struct {
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" mapstructure:"hsts_max_age" validate:"min=0s"`                  // Strict-Transport-Security, 0 - не отправлять
+	ContentSecurityPolicy string        `yaml:"content_security_policy" mapstructure:"content_security_policy"`              // Для HTML-ответов
+	FrameOptions          string        `yaml:"frame_options" mapstructure:"frame_options" validate:"oneof=DENY SAMEORIGIN"` // X-Frame-Options
+}
+
+// LimitsConfig задаёт пределы размера тела запроса. Вложения, импорт задач и миграция
+// ограничиваются своими пределами.
+type LimitsConfig struct {
+	MaxBody     int64 `yaml:"max_body" mapstructure:"max_body" validate:"min=1024"`           // Все маршруты, байт
+	MaxBulkBody int64 `yaml:"max_bulk_body" mapstructure:"max_bulk_body" validate:"min=1024"` // POST /tasks/bulk, байт
+}
+
+// TracingConfig задаёт экспорт трасс OpenTelemetry.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" mapstructure:"exporter" validate:"oneof=none stdout otlp"` // none, stdout или otlp
+	Endpoint    string  `yaml:"endpoint" mapstructure:"endpoint"`                                   // Коллектор OTLP/HTTP: host:port или URL
+	Insecure    bool    `yaml:"insecure" mapstructure:"insecure"`                                   // Отправка без TLS
+	SampleRatio float64 `yaml:"sample_ratio" mapstructure:"sample_ratio" validate:"min=0,max=1"`    // Доля записываемых трасс
+	ServiceName string  `yaml:"service_name" mapstructure:"service_name" validate:"required"`       // service.name в трассах
+}
+
+// IdempotencyConfig задаёт срок хранения ключей Idempotency-Key.
+type IdempotencyConfig struct {
+	TTL           time.Duration `yaml:"ttl" mapstructure:"ttl" validate:"min=1s" reload:"true"`         // Сколько хранится ответ по ключу
+	PurgeInterval time.Duration `yaml:"purge_interval" mapstructure:"purge_interval" validate:"min=1s"` // Период удаления просроченных ключей
+}
+
+// ScoringConfig задаёт веса оценки задач для списка "что делать дальше".
+type ScoringConfig struct {
+	Priority float64 `yaml:"priority" mapstructure:"priority" validate:"min=0"` // Вес приоритета
+	DueDate  float64 `yaml:"due_date" mapstructure:"due_date" validate:"min=0"` // Вес близости срока
+	Age      float64 `yaml:"age" mapstructure:"age" validate:"min=0"`           // Вес возраста задачи
+	Blocking float64 `yaml:"blocking" mapstructure:"blocking" validate:"min=0"` // Вес числа ожидающих задач
+	Blocked  float64 `yaml:"blocked" mapstructure:"blocked" validate:"min=0"`   // Штраф за незакрытые зависимости
+}
+
+// StorageConfig описывает хранилище вложений задач.
+type StorageConfig struct {
+	Driver       string   `yaml:"driver" mapstructure:"driver" validate:"oneof=local s3"`          // local или s3
+	Dir          string   `yaml:"dir" mapstructure:"dir"`                                          // Каталог для драйвера local
+	MaxSize      int64    `yaml:"max_size" mapstructure:"max_size" validate:"min=1" reload:"true"` // Максимальный размер файла в байтах
+	AllowedTypes []string `yaml:"allowed_types" mapstructure:"allowed_types" reload:"true"`        // Разрешённые MIME-типы
+
+	S3 struct {
+		Endpoint  string `yaml:"endpoint" mapstructure:"endpoint"`     // Адрес S3-совместимого сервиса
+		Region    string `yaml:"region" mapstructure:"region"`         // Регион для подписи запросов
+		Bucket    string `yaml:"bucket" mapstructure:"bucket"`         // Имя бакета
+		AccessKey string `yaml:"access_key" mapstructure:"access_key"` // Ключ доступа
+		SecretKey string `yaml:"secret_key" mapstructure:"secret_key"` // Секретный ключ
+	} `yaml:"s3" mapstructure:"s3"`
+}
+
+// defaults - нижний слой конфигурации: значения, с которыми сервис запускается без файла.
+var defaults = map[string]interface{}{
+	"db.port":                          5432,
+	"db.sslmode":                       "disable",
+	"db.search_path":                   "public",
+	"server.ip":                        "0.0.0.0",
+	"server.port":                      8080,
+	"server.read_timeout":              "5m",
+	"server.read_header_timeout":       "10s",
+	"server.write_timeout":             "5m",
+	"server.idle_timeout":              "2m",
+	"server.max_header_bytes":          1 << 20,
+	"server.shutdown_timeout":          "30s",
+	"storage.driver":                   "local",
+	"storage.dir":                      "./data/attachments",
+	"storage.max_size":                 10 << 20,
+	"storage.allowed_types":            []string{"image/png", "image/jpeg", "image/gif", "application/pdf", "text/plain"},
+	"scoring.priority":                 4,
+	"scoring.due_date":                 3,
+	"scoring.age":                      1,
+	"scoring.blocking":                 2,
+	"scoring.blocked":                  10,
+	"idempotency.ttl":                  "24h",
+	"idempotency.purge_interval":       "1h",
+	"health.timeout":                   "2s",
+	"tracing.exporter":                 "none",
+	"tracing.endpoint":                 "localhost:4318",
+	"tracing.insecure":                 true,
+	"tracing.sample_ratio":             1,
+	"tracing.service_name":             "webtasks",
+	"log.level":                        "info",
+	"log.format":                       "text",
+	"rate_limit.enabled":               true,
+	"rate_limit.store":                 "memory",
+	"rate_limit.trust_proxy":           false,
+	"rate_limit.purge_interval":        "10m",
+	"rate_limit.read.requests":         600,
+	"rate_limit.read.period":           "1m",
+	"rate_limit.read.burst":            100,
+	"rate_limit.write.requests":        120,
+	"rate_limit.write.period":          "1m",
+	"rate_limit.write.burst":           30,
+	"rate_limit.heavy.requests":        10,
+	"rate_limit.heavy.period":          "1m",
+	"rate_limit.heavy.burst":           3,
+	"rate_limit.anonymous.requests":    60,
+	"rate_limit.anonymous.period":      "1m",
+	"rate_limit.anonymous.burst":       20,
+	"quota.max_tasks":                  0,
+	"cors.allowed_origins":             []string{},
+	"cors.allowed_methods":             []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+	"cors.allowed_headers":             []string{"Authorization", "Content-Type", "Idempotency-Key", "X-Request-ID", "If-Match", "If-None-Match"},
+	"cors.allow_credentials":           false,
+	"cors.max_age":                     "10m",
+	"security.hsts_max_age":            "4320h",
+	"security.content_security_policy": "default-src 'none'; frame-ancestors 'none'",
+	"security.frame_options":           "DENY",
+	"limits.max_body":                  1 << 20,
+	"limits.max_bulk_body":             8 << 20,
+}
+
+// envAliases - переменные, которые задаёт docker-compose и образ postgres, и стандартные
+// переменные OpenTelemetry. Переменные с префиксом WEBTASKS имеют приоритет над ними.
+var envAliases = map[string][]string{
+	"db.host":     {"POSTGRES_HOST"},
+	"db.port":     {"POSTGRES_PORT"},
+	"db.user":     {"POSTGRES_USER"},
+	"db.password": {"POSTGRES_PASSWORD"},
+	"db.dbname":   {"POSTGRES_DB"},
+
+	"tracing.endpoint":     {"OTEL_EXPORTER_OTLP_ENDPOINT"},
+	"tracing.service_name": {"OTEL_SERVICE_NAME"},
+}
+
+// Load собирает конфигурацию из слоёв по возрастанию приоритета: значения по умолчанию,
+// файл (-config, WEBTASKS_CONFIG или DefaultPath), файл профиля рядом с ним
+// (db.<профиль>.yaml, профиль из -profile, WEBTASKS_ENV или ENV), переменные окружения,
+// флаги вида -db.host=localhost. Любой параметр можно прочитать из файла секрета:
+// WEBTASKS_DB_PASSWORD_FILE=/run/secrets/db_password. Возвращает аргументы после флагов
+// (подкоманду) и ошибку со всеми нарушениями тегов validate.
+func Load(args []string) (*Config, []string, error) {
+	keys := configKeys(reflect.TypeOf(Config{}), "")
+
+	flags := flag.NewFlagSet("webtasks", flag.ContinueOnError)
+	path := flags.String("config", "", "путь к файлу конфигурации (по умолчанию "+DefaultPath+")")
+	profile := flags.String("profile", "", "профиль окружения: development, production, ...")
+
+	for _, key := range keys {
+		flags.String(key, "", "переопределяет параметр "+key)
 	}
 
-	var dbConfig Config
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
 
-	err = viper.Unmarshal(&dbConfig)
+	v := viper.New()
+
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	if *profile == "" {
+		*profile = firstEnv(envPrefix+"_ENV", "ENV")
+	}
+
+	files, err := readFiles(v, *path, *profile)
 	if err != nil {
-		log.Fatalf("unable to decode config, %v", err)
+		return nil, nil, err
+	}
+
+	for _, key := range keys {
+		names := append([]string{envName(key)}, envAliases[key]...)
+
+		if err := v.BindEnv(append([]string{key}, names...)...); err != nil {
+			return nil, nil, err
+		}
+
+		if err := readSecretFile(v, key, names); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Флаги - верхний слой: учитываются только явно заданные
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name != "config" && f.Name != "profile" {
+			v.Set(f.Name, f.Value.String())
+		}
+	})
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	cfg.Profile = *profile
+	cfg.args = append([]string(nil), args...)
+	cfg.files = files
+
+	if err := Validate(&cfg); err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, flags.Args(), nil
+}
+
+// readFiles читает основной файл и, если он есть, файл профиля, и возвращает прочитанные пути.
+// Отсутствие файла по умолчанию не ошибка: сервис можно настроить только окружением.
+func readFiles(v *viper.Viper, path, profile string) ([]string, error) {
+	var files []string
+
+	explicit := path != ""
+
+	if !explicit {
+		path = firstEnv(envPrefix + "_CONFIG")
+		explicit = path != ""
+	}
+
+	if path == "" {
+		path = DefaultPath
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if explicit || !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+	} else {
+		v.SetConfigFile(path)
+
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read config file %s: %w", path, err)
+		}
+
+		files = append(files, path)
+	}
+
+	if profile == "" {
+		return files, nil
+	}
+
+	ext := filepath.Ext(path)
+	profilePath := strings.TrimSuffix(path, ext) + "." + profile + ext
+
+	if _, err := os.Stat(profilePath); errors.Is(err, os.ErrNotExist) {
+		return files, nil
+	}
+
+	v.SetConfigFile(profilePath)
+
+	if err := v.MergeInConfig(); err != nil {
+		return nil, fmt.Errorf("read profile %s: %w", profilePath, err)
+	}
+
+	return append(files, profilePath), nil
+}
+
+// readSecretFile подставляет значение из файла, на который указывает <ПЕРЕМЕННАЯ>_FILE.
+// Имена перебираются по убыванию приоритета до первого заданного: переменная WEBTASKS_*
+// важнее POSTGRES_*_FILE, как и без файлов. Задать одновременно переменную и её _FILE нельзя.
+func readSecretFile(v *viper.Viper, key string, names []string) error {
+	for _, name := range names {
+		_, set := os.LookupEnv(name)
+
+		file, ok := os.LookupEnv(name + "_FILE")
+		if !ok {
+			if set {
+				// Значение подставит viper через BindEnv
+				return nil
+			}
+
+			continue
+		}
+
+		if set {
+			return fmt.Errorf("both %s and %s_FILE are set", name, name)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read %s_FILE: %w", name, err)
+		}
+
+		v.Set(key, strings.TrimRight(string(data), "\r\n"))
+
+		return nil
+	}
+
+	return nil
+}
+
+// configKeys перечисляет ключи всех параметров ("db.host", "storage.s3.bucket", ...).
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, prefix+name+".")...)
+			continue
+		}
+
+		keys = append(keys, prefix+name)
+	}
+
+	return keys
+}
+
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
 	}
 
-	return &dbConfig, nil
+	return ""
 }
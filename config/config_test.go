@@ -0,0 +1,246 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestLoad_Layers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.yaml")
+
+	writeFile(t, path, `
+db:
+  host: "file-host"
+  user: "file-user"
+  password: "file-password"
+  dbname: "tasks"
+  search_path: "app"
+server:
+  port: 9000
+`)
+	writeFile(t, filepath.Join(dir, "db.staging.yaml"), `
+db:
+  host: "staging-host"
+`)
+	writeFile(t, filepath.Join(dir, "password"), "secret\n")
+
+	t.Setenv("ENV", "staging")
+	t.Setenv("POSTGRES_USER", "env-user")
+	t.Setenv("WEBTASKS_DB_PASSWORD_FILE", filepath.Join(dir, "password"))
+	t.Setenv("WEBTASKS_STORAGE_ALLOWED_TYPES", "image/png,text/plain")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+
+	cfg, args, err := Load([]string{"-config", path, "-server.port=9100", "import", "-source", "jira"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"import", "-source", "jira"}, args)
+	assert.Equal(t, "staging", cfg.Profile)
+	assert.Equal(t, "staging-host", cfg.DB.Host) // файл профиля
+	assert.Equal(t, "env-user", cfg.DB.User)     // окружение
+	assert.Equal(t, "secret", cfg.DB.Password)   // файл секрета
+	assert.Equal(t, "app", cfg.DB.SearchPath)    // файл
+	assert.Equal(t, 5432, cfg.DB.Port)           // значение по умолчанию
+	assert.Equal(t, 9100, cfg.Server.Port)       // флаг
+	assert.Equal(t, 24*time.Hour, cfg.Idempotency.TTL)
+	assert.Equal(t, []string{"image/png", "text/plain"}, cfg.Storage.AllowedTypes)
+	assert.Equal(t, "http://collector:4318", cfg.Tracing.Endpoint)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	// Явно указанный файл обязателен
+	_, _, err := Load([]string{"-config", filepath.Join(dir, "missing.yaml")})
+	require.Error(t, err)
+
+	// Недостающие и неверные параметры перечисляются все сразу
+	_, _, err = Load([]string{"-config", writeEmpty(t, dir), "-storage.driver=ftp"})
+
+	var invalid *ValidationError
+	require.True(t, errors.As(err, &invalid), "%v", err)
+	assert.Contains(t, invalid.Problems, "db.host: required")
+	assert.Contains(t, invalid.Problems, "db.password: required")
+	assert.Contains(t, invalid.Problems, `storage.driver: must be one of local, s3, got "ftp"`)
+
+	// Переменная и её _FILE одновременно
+	t.Setenv("POSTGRES_PASSWORD", "root")
+	t.Setenv("POSTGRES_PASSWORD_FILE", filepath.Join(dir, "password"))
+
+	_, _, err = Load([]string{"-config", writeEmpty(t, dir)})
+	require.ErrorContains(t, err, "both POSTGRES_PASSWORD and POSTGRES_PASSWORD_FILE are set")
+}
+
+func TestLoad_SecretFilePriority(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.yaml")
+
+	writeFile(t, path, `db: {host: "db", user: "postgres", dbname: "postgres"}`)
+	writeFile(t, filepath.Join(dir, "postgres"), "postgres-secret\n")
+	writeFile(t, filepath.Join(dir, "webtasks"), "webtasks-secret\n")
+
+	// Переменная WEBTASKS_* важнее файла секрета POSTGRES_*
+	t.Setenv("WEBTASKS_DB_PASSWORD", "webtasks-env")
+	t.Setenv("POSTGRES_PASSWORD_FILE", filepath.Join(dir, "postgres"))
+
+	cfg, _, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "webtasks-env", cfg.DB.Password)
+
+	// Файл секрета WEBTASKS_* важнее переменной POSTGRES_*
+	os.Unsetenv("WEBTASKS_DB_PASSWORD")
+	os.Unsetenv("POSTGRES_PASSWORD_FILE")
+	t.Setenv("WEBTASKS_DB_PASSWORD_FILE", filepath.Join(dir, "webtasks"))
+	t.Setenv("POSTGRES_PASSWORD", "postgres-env")
+
+	cfg, _, err = Load([]string{"-config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "webtasks-secret", cfg.DB.Password)
+
+	// Без переменных WEBTASKS_* берётся файл POSTGRES_*
+	os.Unsetenv("WEBTASKS_DB_PASSWORD_FILE")
+	os.Unsetenv("POSTGRES_PASSWORD")
+	t.Setenv("POSTGRES_PASSWORD_FILE", filepath.Join(dir, "postgres"))
+
+	cfg, _, err = Load([]string{"-config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres-secret", cfg.DB.Password)
+}
+
+func writeEmpty(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "empty.yaml")
+	writeFile(t, path, "{}\n")
+
+	return path
+}
+
+func TestValidate_Ranges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.yaml")
+	writeFile(t, path, `db: {host: "db", user: "postgres", password: "root", dbname: "postgres"}`)
+
+	// Конфигурация по умолчанию проходит проверку; портим отдельные параметры
+	cfg, _, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+
+	cfg.DB.Port = 70000
+	cfg.Storage.Driver = "s3"
+	cfg.Scoring.Age = -1
+	cfg.Idempotency.PurgeInterval = time.Millisecond
+	cfg.Tracing.SampleRatio = 1.5
+	cfg.RateLimit.Store = "redis"
+	cfg.RateLimit.Write.Period = 0
+	cfg.Quota.MaxTasks = -1
+	cfg.Security.FrameOptions = "ALLOW"
+	cfg.Limits.MaxBody = 0
+	cfg.CORS.AllowedOrigins = []string{"*"}
+	cfg.CORS.AllowCredentials = true
+
+	err = Validate(cfg)
+
+	var invalid *ValidationError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, []string{
+		"db.port: must be at most 65535, got 70000",
+		"scoring.age: must be at least 0, got -1",
+		"idempotency.purge_interval: must be at least 1s, got 1ms",
+		"tracing.sample_ratio: must be at most 1, got 1.5",
+		"rate_limit.store: must be one of memory, postgres, got \"redis\"",
+		"rate_limit.write.period: must be at least 1s, got 0s",
+		"quota.max_tasks: must be at least 0, got -1",
+		"security.frame_options: must be one of DENY, SAMEORIGIN, got \"ALLOW\"",
+		"limits.max_body: must be at least 1024, got 0",
+		"storage.s3.bucket: required when storage.driver is s3",
+		`cors.allowed_origins: "*" cannot be combined with allow_credentials; list the sites explicitly`,
+	}, invalid.Problems)
+}
+
+func TestLoad_RateLimitUsers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.yaml")
+	writeFile(t, path, `
+db: {host: "db", user: "postgres", password: "root", dbname: "postgres"}
+rate_limit:
+  users:
+    - user_id: 42
+      heavy: {requests: 60, period: "1m", burst: 10}
+`)
+
+	cfg, _, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+	require.Len(t, cfg.RateLimit.Users, 1)
+	assert.Equal(t, RateLimitUserConfig{
+		UserID: 42,
+		Heavy:  RateLimitOverrideConfig{Requests: 60, Period: time.Minute, Burst: 10},
+	}, cfg.RateLimit.Users[0])
+
+	cfg.RateLimit.Users = append(cfg.RateLimit.Users,
+		RateLimitUserConfig{UserID: 42},
+		RateLimitUserConfig{Write: RateLimitOverrideConfig{Requests: -1}},
+	)
+
+	var invalid *ValidationError
+	require.True(t, errors.As(Validate(cfg), &invalid))
+	assert.Equal(t, []string{
+		"rate_limit.users[2].user_id: must be at least 1, got 0",
+		"rate_limit.users[2].write.requests: must be at least 0, got -1",
+		"rate_limit.users[1].user_id: duplicate user 42",
+	}, invalid.Problems)
+}
+
+func TestReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.yaml")
+
+	base := `
+db: {host: "db", user: "postgres", password: "root", dbname: "postgres"}
+storage: {max_size: 1024}
+`
+	writeFile(t, path, base)
+
+	cfg, _, err := Load([]string{"-config", path})
+	require.NoError(t, err)
+
+	reloader := NewReloader(cfg)
+
+	var applied *Config
+	reloader.OnReload(func(next *Config) { applied = next })
+
+	// Файл не менялся - обработчики не вызываются
+	require.NoError(t, reloader.Reload())
+	assert.Nil(t, applied)
+
+	// Перезагружаемые параметры применяются
+	writeFile(t, path, base+"scoring: {priority: 7}\nidempotency: {ttl: 1h}\n")
+	require.NoError(t, reloader.Reload())
+	require.NotNil(t, applied)
+	assert.Equal(t, 7.0, applied.Scoring.Priority)
+	assert.Equal(t, time.Hour, reloader.Current().Idempotency.TTL)
+
+	// Смена хоста базы требует перезапуска и отклоняется целиком
+	applied = nil
+	writeFile(t, path, `
+db: {host: "other", user: "postgres", password: "root", dbname: "postgres"}
+storage: {max_size: 2048}
+`)
+	require.ErrorContains(t, reloader.Reload(), "restart required to change db.host")
+	assert.Nil(t, applied)
+	assert.Equal(t, int64(1024), reloader.Current().Storage.MaxSize)
+
+	// Некорректная конфигурация не применяется
+	writeFile(t, path, `
+db: {host: "db", user: "postgres", password: "root", dbname: "postgres"}
+storage: {max_size: 0}
+`)
+	require.Error(t, reloader.Reload())
+	assert.Nil(t, applied)
+}
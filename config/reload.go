@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Reloader перечитывает конфигурацию при изменении файлов и применяет её на лету.
+// Менять без перезапуска можно только параметры с тегом reload:"true" (сами или через
+// родительскую секцию); изменение остальных, например db.host, отклоняется целиком.
+type Reloader struct {
+	mu       sync.Mutex
+	current  *Config
+	handlers []func(*Config)
+}
+
+func NewReloader(cfg *Config) *Reloader {
+	return &Reloader{current: cfg}
+}
+
+// OnReload регистрирует функцию, которая получает новую конфигурацию после успешной проверки.
+func (r *Reloader) OnReload(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers = append(r.handlers, fn)
+}
+
+// Current возвращает последнюю применённую конфигурацию.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.current
+}
+
+// Watch следит за прочитанными при запуске файлами конфигурации и вызывает Reload
+// при каждом их изменении. Ошибки перезагрузки пишутся в журнал, действующая конфигурация
+// при этом сохраняется.
+func (r *Reloader) Watch() {
+	for _, file := range r.Current().files {
+		v := viper.New()
+		v.SetConfigFile(file)
+		v.OnConfigChange(func(fsnotify.Event) {
+			if err := r.Reload(); err != nil {
+				slog.Error("config reload rejected", "error", err)
+			}
+		})
+		v.WatchConfig()
+	}
+}
+
+// Reload собирает конфигурацию заново с теми же аргументами, проверяет её и вызывает
+// обработчики OnReload. Возвращает ошибку, если изменились параметры без reload:"true".
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, _, err := Load(r.current.args)
+	if err != nil {
+		return err
+	}
+
+	changes, static := diff(reflect.ValueOf(*r.current), reflect.ValueOf(*next), "", false)
+	if len(static) > 0 {
+		return fmt.Errorf("restart required to change %s", strings.Join(static, ", "))
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	for _, fn := range r.handlers {
+		fn(next)
+	}
+
+	r.current = next
+
+	slog.Info("config reloaded", "changes", strings.Join(changes, "; "))
+
+	return nil
+}
+
+// diff сравнивает параметры двух конфигураций. changes - изменения перезагружаемых
+// параметров в виде "ключ: старое -> новое", static - ключи остальных изменённых параметров
+// (без значений: среди них есть секреты).
+func diff(old, next reflect.Value, prefix string, reloadable bool) (changes, static []string) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := prefix + name
+		fieldReloadable := reloadable || field.Tag.Get("reload") == "true"
+
+		if field.Type.Kind() == reflect.Struct {
+			c, s := diff(old.Field(i), next.Field(i), key+".", fieldReloadable)
+			changes, static = append(changes, c...), append(static, s...)
+
+			continue
+		}
+
+		a, b := old.Field(i).Interface(), next.Field(i).Interface()
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+
+		if fieldReloadable {
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", key, a, b))
+		} else {
+			static = append(static, key)
+		}
+	}
+
+	return changes, static
+}
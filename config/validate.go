@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError перечисляет все нарушения тегов validate, а не только первое.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate проверяет теги validate: required, min=N, max=N (для длительностей - min=1s),
+// oneof=a b. Ключи в сообщениях совпадают с ключами файла конфигурации.
+func Validate(cfg *Config) error {
+	var problems []string
+
+	validateStruct(reflect.ValueOf(cfg).Elem(), "", &problems)
+
+	if cfg.Storage.Driver == "s3" && cfg.Storage.S3.Bucket == "" {
+		problems = append(problems, "storage.s3.bucket: required when storage.driver is s3")
+	}
+
+	if cfg.CORS.AllowCredentials && slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+		problems = append(problems, `cors.allowed_origins: "*" cannot be combined with allow_credentials; list the sites explicitly`)
+	}
+
+	seen := make(map[int]bool, len(cfg.RateLimit.Users))
+	for i, user := range cfg.RateLimit.Users {
+		if seen[user.UserID] {
+			problems = append(problems, fmt.Sprintf("rate_limit.users[%d].user_id: duplicate user %d", i, user.UserID))
+		}
+
+		seen[user.UserID] = true
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func validateStruct(value reflect.Value, prefix string, problems *[]string) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := prefix + name
+
+		if field.Type.Kind() == reflect.Struct {
+			validateStruct(value.Field(i), key+".", problems)
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			for j := 0; j < value.Field(i).Len(); j++ {
+				validateStruct(value.Field(i).Index(j), fmt.Sprintf("%s[%d].", key, j), problems)
+			}
+
+			continue
+		}
+
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+
+		for _, rule := range strings.Split(rules, ",") {
+			if problem := checkRule(value.Field(i), rule); problem != "" {
+				*problems = append(*problems, key+": "+problem)
+				break
+			}
+		}
+	}
+}
+
+// checkRule возвращает описание нарушения или пустую строку.
+func checkRule(field reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "required":
+		if field.IsZero() {
+			return "required"
+		}
+	case "oneof":
+		options := strings.Fields(arg)
+		for _, option := range options {
+			if fmt.Sprint(field.Interface()) == option {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(options, ", "), fmt.Sprint(field.Interface()))
+	case "min", "max":
+		limit, actual, err := ruleNumbers(field, arg)
+		if err != nil {
+			return fmt.Sprintf("bad rule %q: %v", rule, err)
+		}
+
+		if name == "min" && actual < limit {
+			return fmt.Sprintf("must be at least %s, got %v", arg, field.Interface())
+		}
+
+		if name == "max" && actual > limit {
+			return fmt.Sprintf("must be at most %s, got %v", arg, field.Interface())
+		}
+	default:
+		return fmt.Sprintf("unknown rule %q", rule)
+	}
+
+	return ""
+}
+
+// ruleNumbers приводит границу правила и значение поля к float64.
+func ruleNumbers(field reflect.Value, arg string) (float64, float64, error) {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		limit, err := time.ParseDuration(arg)
+		return float64(limit), float64(field.Int()), err
+	}
+
+	limit, err := strconv.ParseFloat(arg, 64)
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return limit, float64(field.Int()), err
+	case reflect.Float32, reflect.Float64:
+		return limit, field.Float(), err
+	default:
+		return 0, 0, fmt.Errorf("not a number")
+	}
+}
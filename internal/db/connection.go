@@ -2,20 +2,28 @@ package db
 
 import (
 	"WebTasks/config"
+	"WebTasks/internal/metrics"
+	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 func DB(config *config.Config) (*sqlx.DB, error) {
-	log.Printf(
-		"Подключение к базе данных с параметрами: host=%s port=%d user=%s dbname=%s sslmode=%s",
-		config.DB.Host, config.DB.Port, config.DB.User, config.DB.DBName, config.DB.SSLMode,
+	logger := slog.With(
+		"host", config.DB.Host,
+		"port", config.DB.Port,
+		"user", config.DB.User,
+		"dbname", config.DB.DBName,
+		"sslmode", config.DB.SSLMode,
 	)
+	logger.Info("connecting to database")
 
+	// Сессия работает в UTC: метки времени читаются в UTC независимо от настроек сервера БД
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s search_path=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s search_path=%s timezone=UTC",
 		config.DB.Host,
 		config.DB.Port,
 		config.DB.User,
@@ -25,16 +33,24 @@ func DB(config *config.Config) (*sqlx.DB, error) {
 		config.DB.SearchPath,
 	)
 
-	db, err := sqlx.Open("postgres", dsn)
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
-		log.Fatalf("Ошибка подключения к базе данных: %v", err)
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	// Запросы замеряются и попадают в трассы, состояние пула публикуется в /metrics
+	db := sqlx.NewDb(sql.OpenDB(instrumentedConnector{connector}), "postgres")
+
+	if err := metrics.RegisterDBStats(db.DB); err != nil {
+		logger.Warn("connection pool metrics unavailable", "error", err)
 	}
 
 	if err := db.Ping(); err != nil {
-		log.Fatalf("База данных недоступна: %v", err)
+		db.Close()
+		return nil, fmt.Errorf("database unavailable: %w", err)
 	}
 
-	log.Println("Подключение к базе данных успешно установлено!")
+	logger.Info("connected to database")
 
 	return db, nil
 }
@@ -12,8 +12,8 @@ CREATE TABLE tasks (
                        id SERIAL PRIMARY KEY,
                        name VARCHAR(100) NOT NULL,
                        status VARCHAR(50) NOT NULL,
-                       time TIMESTAMP NOT NULL,
-                       due TIMESTAMP NOT NULL,
+                       time TIMESTAMPTZ NOT NULL,
+                       due TIMESTAMPTZ NOT NULL,
                        user_id INT NOT NULL,
                        CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
 );
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"WebTasks/internal/metrics"
+	"WebTasks/internal/tracing"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentedConnector оборачивает соединения драйвера, чтобы замерять и трассировать
+// каждый запрос всех репозиториев без изменения их кода.
+type instrumentedConnector struct {
+	driver.Connector
+}
+
+// pqConn - методы соединения lib/pq, которые использует database/sql.
+type pqConn interface {
+	driver.Conn
+	driver.QueryerContext
+	driver.ExecerContext
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+func (c instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if pc, ok := conn.(pqConn); ok {
+		return instrumentedConn{pc}, nil
+	}
+
+	return conn, nil
+}
+
+type instrumentedConn struct {
+	pqConn
+}
+
+func (c instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, finish := startQuery(ctx, query)
+	rows, err := c.pqConn.QueryContext(ctx, query, args)
+	finish(err)
+
+	return rows, err
+}
+
+func (c instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx, finish := startQuery(ctx, query)
+	result, err := c.pqConn.ExecContext(ctx, query, args)
+	finish(err)
+
+	return result, err
+}
+
+// startQuery открывает спан запроса с очищенным текстом SQL; finish завершает спан
+// и учитывает запрос в метриках.
+func startQuery(ctx context.Context, query string) (context.Context, func(err error)) {
+	start := time.Now()
+
+	ctx, span := tracing.Tracer().Start(ctx, metrics.QueryName(query),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBQueryText(tracing.SanitizeSQL(query))),
+	)
+
+	return ctx, func(err error) {
+		// ErrSkip - не ошибка запроса: database/sql повторит его через подготовленный оператор
+		if errors.Is(err, driver.ErrSkip) {
+			span.End()
+			return
+		}
+
+		tracing.End(span, err)
+		metrics.ObserveQuery(query, time.Since(start), err)
+	}
+}
@@ -1,20 +1,83 @@
 package db
 
 import (
-	"log"
+	"WebTasks/internal/tracing"
+	"log/slog"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// SchemaVersion - версия схемы, которую создаёт эта сборка: число шагов миграции.
+// Её же ApplyMigrations записывает в schema_version, по ней проверяется готовность.
+func SchemaVersion() int {
+	return len(migrationQueries())
+}
+
 func ApplyMigrations(db *sqlx.DB) error {
-	queries := []string{
+	queries := migrationQueries()
+
+	// Выполнение миграций
+	for i, query := range queries {
+		slog.Debug("applying migration", "step", i+1, "query", tracing.SanitizeSQL(query))
+
+		_, err := db.Exec(query)
+		if err != nil {
+			slog.Error("migration failed", "step", i+1, "error", err)
+
+			return err
+		}
+	}
+
+	if err := recordSchemaVersion(db, len(queries)); err != nil {
+		slog.Error("recording schema version failed", "error", err)
+
+		return err
+	}
+
+	slog.Info("migrations applied", "schema_version", len(queries))
+
+	return nil
+}
+
+// recordSchemaVersion хранит в schema_version единственную строку с версией схемы.
+func recordSchemaVersion(db *sqlx.DB, version int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // после Commit откат ничего не делает
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS schema_version (
+			version INT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`DELETE FROM schema_version;`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO schema_version (version) VALUES ($1);`, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func migrationQueries() []string {
+	return []string{
 		// Создание таблицы tasks
 		`CREATE TABLE IF NOT EXISTS tasks (
 			id SERIAL PRIMARY KEY,
 			name VARCHAR(255) NOT NULL,
 			status VARCHAR(50) NOT NULL,
-			time TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			due TIMESTAMP DEFAULT NULL
+			time TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			due TIMESTAMPTZ DEFAULT NULL
 		);`,
 
 		`CREATE TABLE IF NOT EXISTS users (
@@ -24,43 +87,260 @@ func ApplyMigrations(db *sqlx.DB) error {
 		);`,
 
 		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS user_id INT REFERENCES users(id) ON DELETE CASCADE;`,
-	}
 
-	// Выполнение миграций
-	for _, query := range queries {
-		log.Printf("Выполнение миграции: %s", query)
+		// Вложения задач, содержимое хранится в BlobStore по хэшу
+		`CREATE TABLE IF NOT EXISTS attachments (
+			id SERIAL PRIMARY KEY,
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			file_name VARCHAR(255) NOT NULL,
+			content_type VARCHAR(255) NOT NULL,
+			size BIGINT NOT NULL,
+			hash CHAR(64) NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);`,
 
-		_, err := db.Exec(query)
-		if err != nil {
-			log.Printf("Ошибка выполнения миграции: %v", err)
+		`CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments (task_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_attachments_hash ON attachments (hash);`,
 
-			return err
-		}
-	}
+		`CREATE TABLE IF NOT EXISTS task_tags (
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			tag VARCHAR(50) NOT NULL,
+			PRIMARY KEY (task_id, tag)
+		);`,
 
-	log.Println("Миграции успешно применены!")
+		`CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags (tag);`,
 
-	return nil
+		// Учёт времени: запись без ended_at - запущенный таймер
+		`CREATE TABLE IF NOT EXISTS time_entries (
+			id SERIAL PRIMARY KEY,
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			started_at TIMESTAMPTZ NOT NULL,
+			ended_at TIMESTAMPTZ,
+			note TEXT NOT NULL DEFAULT '',
+			CHECK (ended_at IS NULL OR ended_at >= started_at)
+		);`,
+
+		// Не больше одного запущенного таймера на пользователя
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries (task_id);`,
+
+		// Приоритет и оценка трудоёмкости задачи
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'medium'
+			CHECK (priority IN ('low', 'medium', 'high', 'urgent'));`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_minutes INT NOT NULL DEFAULT 0
+			CHECK (estimate_minutes >= 0);`,
+
+		// Зависимости: task_id нельзя закончить раньше depends_on_id
+		`CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			depends_on_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			PRIMARY KEY (task_id, depends_on_id),
+			CHECK (task_id <> depends_on_id)
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies (depends_on_id);`,
+
+		// Проекты и их пользовательские поля
+		`CREATE TABLE IF NOT EXISTS projects (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			owner_id INT REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS custom_fields (
+			id SERIAL PRIMARY KEY,
+			project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+			name VARCHAR(50) NOT NULL,
+			type VARCHAR(10) NOT NULL CHECK (type IN ('text', 'number', 'date', 'enum', 'user')),
+			options TEXT[] NOT NULL DEFAULT '{}',
+			required BOOLEAN NOT NULL DEFAULT FALSE,
+			UNIQUE (project_id, name)
+		);`,
+
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id INT REFERENCES projects(id) ON DELETE SET NULL;`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks (project_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_custom_fields ON tasks USING GIN (custom_fields);`,
+
+		// Подзадачи, чек-листы и шаблоны задач
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES tasks(id) ON DELETE CASCADE;`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks (parent_id);`,
+
+		`CREATE TABLE IF NOT EXISTS task_checklist_items (
+			id SERIAL PRIMARY KEY,
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			position INT NOT NULL,
+			text VARCHAR(500) NOT NULL,
+			done BOOLEAN NOT NULL DEFAULT FALSE
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items (task_id);`,
+
+		`CREATE TABLE IF NOT EXISTS task_templates (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL UNIQUE,
+			definition JSONB NOT NULL,
+			created_by INT REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+
+		// Сохранённые виды списка задач
+		`CREATE TABLE IF NOT EXISTS saved_views (
+			id SERIAL PRIMARY KEY,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			project_id INT REFERENCES projects(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			query TEXT NOT NULL DEFAULT '',
+			columns TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, name)
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_saved_views_project_id ON saved_views (project_id);`,
+
+		// Ключи идемпотентности; status = 0 - запрос ещё обрабатывается
+		`CREATE TABLE IF NOT EXISTS idempotency_keys (
+			key VARCHAR(255) NOT NULL,
+			user_id INT NOT NULL DEFAULT 0,
+			endpoint VARCHAR(255) NOT NULL,
+			fingerprint CHAR(64) NOT NULL,
+			status INT NOT NULL DEFAULT 0,
+			content_type VARCHAR(255) NOT NULL DEFAULT '',
+			body BYTEA,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (key, user_id, endpoint)
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);`,
+
+		// Повторение задачи в формате RRULE и секретные ссылки на календарь пользователей
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence VARCHAR(255) NOT NULL DEFAULT '';`,
+
+		`CREATE TABLE IF NOT EXISTS calendar_feeds (
+			user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			token CHAR(64) NOT NULL UNIQUE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`,
+
+		// Имена и UID ресурсов CalDAV, созданных клиентами
+		`CREATE TABLE IF NOT EXISTS caldav_objects (
+			task_id INT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			uid VARCHAR(255) NOT NULL,
+			UNIQUE (user_id, name)
+		);`,
+
+		// Комментарии задач, в том числе перенесённые из других систем
+		`CREATE TABLE IF NOT EXISTS task_comments (
+			id SERIAL PRIMARY KEY,
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			author VARCHAR(255) NOT NULL DEFAULT '',
+			body TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments (task_id);`,
+
+		// Часовой пояс и рабочий календарь пользователя
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS workdays TEXT[] NOT NULL DEFAULT '{mon,tue,wed,thu,fri}';`,
+
+		`CREATE TABLE IF NOT EXISTS user_holidays (
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			day DATE NOT NULL,
+			name VARCHAR(100) NOT NULL DEFAULT '',
+			PRIMARY KEY (user_id, day)
+		);`,
+
+		// Исполнители и наблюдатели задачи помимо автора
+		`CREATE TABLE IF NOT EXISTS task_members (
+			task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			role VARCHAR(10) NOT NULL CHECK (role IN ('assignee', 'watcher')),
+			PRIMARY KEY (task_id, user_id, role)
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_task_members_user_id ON task_members (user_id, role);`,
+
+		// Общие для всех экземпляров сервиса корзины токенов ограничения частоты запросов
+		`CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+			key VARCHAR(200) PRIMARY KEY,
+			tokens DOUBLE PRECISION NOT NULL,
+			allowed BOOLEAN NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);`,
+
+		// Метки времени хранятся с часовым поясом. Старые значения без пояса записаны
+		// в UTC; уже переведённые колонки не трогаются, поэтому миграция повторяема.
+		`DO $$
+		DECLARE col RECORD;
+		BEGIN
+			FOR col IN
+				SELECT table_name, column_name FROM information_schema.columns
+				WHERE table_schema = current_schema() AND data_type = 'timestamp without time zone'
+					AND table_name IN ('tasks', 'attachments', 'time_entries', 'projects', 'task_templates',
+						'saved_views', 'idempotency_keys', 'calendar_feeds', 'task_comments')
+			LOOP
+				EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE TIMESTAMPTZ USING %I AT TIME ZONE ''UTC''',
+					col.table_name, col.column_name, col.column_name);
+			END LOOP;
+		END $$;`,
+
+		// Автор задачи - пользователь, чей запрос её создал; по нему считается квота задач
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS created_by INT REFERENCES users(id) ON DELETE SET NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_created_by ON tasks (created_by);`,
+
+		// Участники проекта помимо владельца; добавляет их владелец
+		`CREATE TABLE IF NOT EXISTS project_members (
+			project_id INT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+			user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			PRIMARY KEY (project_id, user_id)
+		);`,
+
+		`CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members (user_id);`,
+	}
 }
 
 func RollbackMigrations(db *sqlx.DB) error {
 	queries := []string{
+		`DROP TABLE IF EXISTS schema_version;`,
+		`DROP TABLE IF EXISTS project_members;`,
+		`DROP TABLE IF EXISTS rate_limit_buckets;`,
+		`DROP TABLE IF EXISTS task_members;`,
+		`DROP TABLE IF EXISTS user_holidays;`,
+		`DROP TABLE IF EXISTS task_comments;`,
+		`DROP TABLE IF EXISTS caldav_objects;`,
+		`DROP TABLE IF EXISTS calendar_feeds;`,
+		`DROP TABLE IF EXISTS idempotency_keys;`,
+		`DROP TABLE IF EXISTS saved_views;`,
+		`DROP TABLE IF EXISTS task_templates;`,
+		`DROP TABLE IF EXISTS task_checklist_items;`,
+		`DROP TABLE IF EXISTS task_dependencies;`,
+		`DROP TABLE IF EXISTS time_entries;`,
+		`DROP TABLE IF EXISTS task_tags;`,
+		`DROP TABLE IF EXISTS attachments;`,
 		`DROP TABLE IF EXISTS tasks;`,
+		`DROP TABLE IF EXISTS custom_fields;`,
+		`DROP TABLE IF EXISTS projects;`,
 		`DROP TABLE IF EXISTS users;`,
 	}
 
 	for _, query := range queries {
-		log.Printf("Откат миграции: %s", query)
+		slog.Debug("rolling back migration", "query", query)
 
 		_, err := db.Exec(query)
 		if err != nil {
-			log.Printf("Ошибка отката миграции: %v", err)
+			slog.Error("migration rollback failed", "query", query, "error", err)
 
 			return err
 		}
 	}
 
-	log.Println("Откат миграций успешно выполнен!")
+	slog.Info("migrations rolled back")
 
 	return nil
 }
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gorilla/mux"
+)
+
+// multipartOverhead - запас на заголовки частей и прочие поля формы.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	service services.AttachmentService
+	maxSize atomic.Int64
+}
+
+func NewAttachmentHandler(service services.AttachmentService, maxSize int64) *AttachmentHandler {
+	h := &AttachmentHandler{service: service}
+	h.SetMaxSize(maxSize)
+
+	return h
+}
+
+// SetMaxSize меняет предел тела запроса при загрузке вместе с services.AttachmentLimits.
+func (h *AttachmentHandler) SetMaxSize(maxSize int64) {
+	h.maxSize.Store(maxSize)
+}
+
+func RegisterAttachmentRoutes(router *mux.Router, handler *AttachmentHandler) {
+	router.HandleFunc("/tasks/{id}/attachments", handler.GetAttachments).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id}/attachments", handler.UploadAttachment).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/{id}/attachments/{attachmentID}", handler.DownloadAttachment).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id}/attachments/{attachmentID}", handler.DeleteAttachment).Methods(http.MethodDelete)
+}
+
+func (h *AttachmentHandler) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	attachments, err := h.service.GetAll(r.Context(), taskID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, attachments)
+}
+
+// UploadAttachment принимает multipart/form-data с файлом в поле "file".
+// Части читаются потоком, без буферизации всей формы в памяти.
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize.Load()+multipartOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Invalid multipart request", http.StatusBadRequest)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			http.Error(w, "Missing file part", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		if part.FormName() != "file" {
+			continue
+		}
+
+		attachment, err := h.service.Upload(r.Context(), taskID, part.FileName(), part)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+
+		h.writeJSON(w, http.StatusCreated, attachment)
+
+		return
+	}
+}
+
+// DownloadAttachment отдаёт файл потоком; http.ServeContent обрабатывает
+// заголовки Range, If-Range и If-None-Match.
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, attachmentID, err := h.parseIDs(r)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, blob, err := h.service.Open(r.Context(), taskID, attachmentID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.FileName,
+	}))
+	w.Header().Set("ETag", `"`+attachment.Hash+`"`)
+
+	http.ServeContent(w, r, attachment.FileName, attachment.CreatedAt, blob)
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	taskID, attachmentID, err := h.parseIDs(r)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), taskID, attachmentID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AttachmentHandler) parseIDs(r *http.Request) (int, int, error) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	attachmentID, err := strconv.Atoi(vars["attachmentID"])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return taskID, attachmentID, nil
+}
+
+func (h *AttachmentHandler) writeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrAttachmentNotFound):
+		http.Error(w, "Attachment not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrAttachmentTooLarge), errors.As(err, &maxBytesErr):
+		http.Error(w, "Attachment is too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrUnsupportedMediaType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, services.ErrAttachmentEmpty):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to process attachment", http.StatusInternalServerError)
+	}
+}
+
+func (h *AttachmentHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
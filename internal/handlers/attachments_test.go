@@ -0,0 +1,215 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttachmentService - мок для интерфейса AttachmentService
+type MockAttachmentService struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentService) Upload(ctx context.Context, taskID int, fileName string, content io.Reader) (models.Attachment, error) {
+	data, _ := io.ReadAll(content)
+	args := m.Called(ctx, taskID, fileName, string(data))
+	return args.Get(0).(models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) GetAll(ctx context.Context, taskID int) ([]models.Attachment, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentService) Open(ctx context.Context, taskID, id int) (models.Attachment, io.ReadSeekCloser, error) {
+	args := m.Called(ctx, taskID, id)
+	blob, _ := args.Get(1).(io.ReadSeekCloser)
+	return args.Get(0).(models.Attachment), blob, args.Error(2)
+}
+
+func (m *MockAttachmentService) Delete(ctx context.Context, taskID, id int) error {
+	return m.Called(ctx, taskID, id).Error(0)
+}
+
+func (m *MockAttachmentService) SetLimits(limits services.AttachmentLimits) {
+	m.Called(limits)
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func newMultipartBody(t *testing.T, field, fileName, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile(field, fileName)
+	assert.NoError(t, err)
+
+	_, err = part.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_UploadAttachment(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	handler := handlers.NewAttachmentHandler(mockService, 1024)
+
+	expected := models.Attachment{ID: 1, TaskID: 1, FileName: "notes.txt", Size: 5}
+	mockService.On("Upload", mock.Anything, 1, "notes.txt", "hello").Return(expected, nil)
+
+	body, contentType := newMultipartBody(t, "file", "notes.txt", "hello")
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/attachments", body)
+	req.Header.Set("Content-Type", contentType)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.UploadAttachment(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var actual models.Attachment
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&actual))
+	assert.Equal(t, expected, actual)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAttachmentHandler_UploadAttachment_Errors(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	handler := handlers.NewAttachmentHandler(mockService, 1024)
+
+	mockService.On("Upload", mock.Anything, 1, "image.exe", "MZ").
+		Return(models.Attachment{}, fmt.Errorf("%w: application/octet-stream", services.ErrUnsupportedMediaType))
+	mockService.On("Upload", mock.Anything, 2, "notes.txt", "hello").
+		Return(models.Attachment{}, services.ErrTaskNotFound)
+
+	tests := []struct {
+		name   string
+		taskID string
+		field  string
+		file   string
+		data   string
+		status int
+	}{
+		{"unsupported type", "1", "file", "image.exe", "MZ", http.StatusUnsupportedMediaType},
+		{"task not found", "2", "file", "notes.txt", "hello", http.StatusNotFound},
+		{"missing file part", "1", "other", "notes.txt", "hello", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := newMultipartBody(t, tt.field, tt.file, tt.data)
+			req := httptest.NewRequest(http.MethodPost, "/tasks/"+tt.taskID+"/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.taskID})
+			rr := httptest.NewRecorder()
+
+			handler.UploadAttachment(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
+
+func TestAttachmentHandler_UploadAttachment_NotMultipart(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	handler := handlers.NewAttachmentHandler(mockService, 1024)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/attachments", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.UploadAttachment(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Invalid multipart request")
+
+	mockService.AssertNotCalled(t, "Upload")
+}
+
+func TestAttachmentHandler_DownloadAttachment_Range(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	handler := handlers.NewAttachmentHandler(mockService, 1024)
+
+	attachment := models.Attachment{
+		ID:          3,
+		TaskID:      1,
+		FileName:    "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+		Size:        11,
+		Hash:        "abc",
+		CreatedAt:   time.Now(),
+	}
+	blob := nopSeekCloser{strings.NewReader("hello world")}
+
+	mockService.On("Open", mock.Anything, 1, 3).Return(attachment, blob, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1/attachments/3", nil)
+	req.Header.Set("Range", "bytes=6-")
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "attachmentID": "3"})
+	rr := httptest.NewRecorder()
+
+	handler.DownloadAttachment(rr, req)
+
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "world", rr.Body.String())
+	assert.Equal(t, "bytes 6-10/11", rr.Header().Get("Content-Range"))
+	assert.Equal(t, `"abc"`, rr.Header().Get("ETag"))
+	assert.Equal(t, "attachment; filename=notes.txt", rr.Header().Get("Content-Disposition"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAttachmentHandler_DownloadAttachment_NotFound(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	handler := handlers.NewAttachmentHandler(mockService, 1024)
+
+	mockService.On("Open", mock.Anything, 1, 9).Return(models.Attachment{}, nil, services.ErrAttachmentNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1/attachments/9", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "attachmentID": "9"})
+	rr := httptest.NewRecorder()
+
+	handler.DownloadAttachment(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Attachment not found")
+}
+
+func TestAttachmentHandler_DeleteAttachment(t *testing.T) {
+	mockService := new(MockAttachmentService)
+	handler := handlers.NewAttachmentHandler(mockService, 1024)
+
+	mockService.On("Delete", mock.Anything, 1, 3).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/1/attachments/3", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "attachmentID": "3"})
+	rr := httptest.NewRecorder()
+
+	handler.DeleteAttachment(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"WebTasks/internal/ical"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Маршруты CalDAV. Все они открыты для AuthMiddleware: клиенты присылают учётные данные
+// только после ответа 401 с WWW-Authenticate, который выдаёт requireDAVUser.
+const (
+	calDAVWellKnown   = "/.well-known/caldav"
+	calDAVRoot        = "/caldav/"
+	calDAVCollection  = "/caldav/tasks/"
+	calDAVObjectRoute = calDAVCollection + "{name:[^/]+\\.ics}"
+)
+
+const (
+	methodPropfind = "PROPFIND"
+	methodReport   = "REPORT"
+
+	maxCalDAVBody = 1 << 20
+)
+
+// CalDAVHandler отдаёт задачи пользователя как календарь VTODO (RFC 4791), чтобы
+// календарные приложения могли синхронизировать их в обе стороны.
+type CalDAVHandler struct {
+	service services.CalDAVService
+}
+
+func NewCalDAVHandler(service services.CalDAVService) *CalDAVHandler {
+	return &CalDAVHandler{service: service}
+}
+
+func RegisterCalDAVRoutes(router *mux.Router, handler *CalDAVHandler) {
+	router.HandleFunc(calDAVWellKnown, handler.WellKnown)
+	router.HandleFunc(calDAVRoot, handler.Options).Methods(http.MethodOptions)
+	router.HandleFunc(calDAVRoot, handler.PropfindRoot).Methods(methodPropfind)
+	router.HandleFunc(calDAVCollection, handler.Options).Methods(http.MethodOptions)
+	router.HandleFunc(calDAVCollection, handler.PropfindCollection).Methods(methodPropfind)
+	router.HandleFunc(calDAVCollection, handler.Report).Methods(methodReport)
+	router.HandleFunc(calDAVObjectRoute, handler.Options).Methods(http.MethodOptions)
+	router.HandleFunc(calDAVObjectRoute, handler.GetObject).Methods(http.MethodGet, http.MethodHead)
+	router.HandleFunc(calDAVObjectRoute, handler.PutObject).Methods(http.MethodPut)
+	router.HandleFunc(calDAVObjectRoute, handler.DeleteObject).Methods(http.MethodDelete)
+	router.HandleFunc(calDAVObjectRoute, handler.PropfindObject).Methods(methodPropfind)
+}
+
+// WellKnown направляет автонастройку клиента (RFC 6764) в корень CalDAV.
+func (h *CalDAVHandler) WellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, calDAVRoot, http.StatusMovedPermanently)
+}
+
+func (h *CalDAVHandler) Options(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK)
+}
+
+// PropfindRoot описывает принципала: корень служит и его адресом, и домашним каталогом календарей.
+func (h *CalDAVHandler) PropfindRoot(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := readDAVRequest(w, r)
+	if err != nil {
+		http.Error(w, "Invalid PROPFIND body", http.StatusBadRequest)
+		return
+	}
+
+	responses := []davResponse{rootProperties().response(calDAVRoot, request.requested())}
+
+	if r.Header.Get("Depth") != "0" {
+		objects, err := h.service.Objects(r.Context(), userID)
+		if err != nil {
+			h.writeServiceError(w, r, err)
+			return
+		}
+
+		responses = append(responses, collectionProperties(objects).response(calDAVCollection, request.requested()))
+	}
+
+	writeMultistatus(w, responses)
+}
+
+// PropfindCollection описывает календарь задач; с Depth: 1 - вместе с ресурсами задач.
+func (h *CalDAVHandler) PropfindCollection(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := readDAVRequest(w, r)
+	if err != nil {
+		http.Error(w, "Invalid PROPFIND body", http.StatusBadRequest)
+		return
+	}
+
+	objects, err := h.service.Objects(r.Context(), userID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	responses := []davResponse{collectionProperties(objects).response(calDAVCollection, request.requested())}
+
+	if r.Header.Get("Depth") != "0" {
+		for _, object := range objects {
+			responses = append(responses, objectProperties(object).response(objectHref(object.Name), request.requested()))
+		}
+	}
+
+	writeMultistatus(w, responses)
+}
+
+// Report выполняет calendar-query (фильтр по компонентам, сроку и свойствам VTODO)
+// и calendar-multiget (выборка ресурсов по ссылкам).
+func (h *CalDAVHandler) Report(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := readDAVRequest(w, r)
+	if err != nil {
+		http.Error(w, "Invalid REPORT body", http.StatusBadRequest)
+		return
+	}
+
+	var responses []davResponse
+
+	switch request.XMLName {
+	case xml.Name{Space: calDAVNS, Local: "calendar-query"}:
+		responses, err = h.calendarQuery(r, userID, request)
+	case xml.Name{Space: calDAVNS, Local: "calendar-multiget"}:
+		responses, err = h.calendarMultiget(r, userID, request)
+	default:
+		http.Error(w, "Unsupported report", http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeMultistatus(w, responses)
+}
+
+func (h *CalDAVHandler) calendarQuery(r *http.Request, userID int, request davRequest) ([]davResponse, error) {
+	objects, err := h.service.Objects(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []davResponse{}
+
+	for _, object := range objects {
+		if request.Filter != nil {
+			calendar, err := ical.Parse(strings.NewReader(object.Data))
+			if err != nil || !matchComponent(calendar, request.Filter.CompFilter) {
+				continue
+			}
+		}
+
+		responses = append(responses, objectProperties(object).response(objectHref(object.Name), request.requested()))
+	}
+
+	return responses, nil
+}
+
+func (h *CalDAVHandler) calendarMultiget(r *http.Request, userID int, request davRequest) ([]davResponse, error) {
+	responses := make([]davResponse, 0, len(request.Hrefs))
+
+	for _, href := range request.Hrefs {
+		href = strings.TrimSpace(href)
+
+		name, err := url.PathUnescape(strings.TrimPrefix(href, calDAVCollection))
+		if err != nil || !strings.HasPrefix(href, calDAVCollection) {
+			responses = append(responses, davResponse{Href: href, Status: "HTTP/1.1 404 Not Found"})
+			continue
+		}
+
+		object, err := h.service.Object(r.Context(), userID, name)
+		if errors.Is(err, services.ErrCalDAVObjectNotFound) {
+			responses = append(responses, davResponse{Href: href, Status: "HTTP/1.1 404 Not Found"})
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		responses = append(responses, objectProperties(object).response(href, request.requested()))
+	}
+
+	return responses, nil
+}
+
+func (h *CalDAVHandler) GetObject(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	object, err := h.service.Object(r.Context(), userID, mux.Vars(r)["name"])
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", object.ETag)
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method != http.MethodHead {
+		_, _ = io.WriteString(w, object.Data)
+	}
+}
+
+// PutObject создаёт или изменяет задачу. If-Match и If-None-Match: * защищают от
+// перезаписи чужих изменений; при их нарушении ответ 412.
+func (h *CalDAVHandler) PutObject(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	object, created, err := h.service.Put(
+		r.Context(),
+		userID,
+		mux.Vars(r)["name"],
+		http.MaxBytesReader(w, r.Body, maxCalDAVBody),
+		r.Header.Get("If-Match"),
+		r.Header.Get("If-None-Match"),
+	)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", object.ETag)
+
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) DeleteObject(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.Delete(r.Context(), userID, mux.Vars(r)["name"], r.Header.Get("If-Match"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) PropfindObject(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireDAVUser(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := readDAVRequest(w, r)
+	if err != nil {
+		http.Error(w, "Invalid PROPFIND body", http.StatusBadRequest)
+		return
+	}
+
+	object, err := h.service.Object(r.Context(), userID, mux.Vars(r)["name"])
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeMultistatus(w, []davResponse{objectProperties(object).response(objectHref(object.Name), request.requested())})
+}
+
+func (h *CalDAVHandler) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrCalDAVObjectNotFound):
+		http.Error(w, "Calendar object not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrCalDAVPrecondition):
+		http.Error(w, "Precondition failed", http.StatusPreconditionFailed)
+	case errors.Is(err, services.ErrInvalidCalendarObject):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		slog.ErrorContext(r.Context(), "caldav request failed", "error", err)
+		http.Error(w, "Failed to process calendar request", http.StatusInternalServerError)
+	}
+}
+
+// requireDAVUser возвращает ID текущего пользователя или отвечает 401 с предложением
+// Basic-аутентификации: имя пользователя любое, паролем служит API-ключ.
+func requireDAVUser(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="WebTasks"`)
+		http.Error(w, "Unauthorized: Unknown API key", http.StatusUnauthorized)
+	}
+
+	return userID, ok
+}
+
+func objectHref(name string) string {
+	return calDAVCollection + url.PathEscape(name)
+}
+
+func rootProperties() davProperties {
+	return davProperties{
+		{Space: davNS, Local: "resourcetype"}:           "<D:collection/>",
+		{Space: davNS, Local: "displayname"}:            "WebTasks",
+		{Space: davNS, Local: "current-user-principal"}: "<D:href>" + calDAVRoot + "</D:href>",
+		{Space: davNS, Local: "principal-URL"}:          "<D:href>" + calDAVRoot + "</D:href>",
+		{Space: calDAVNS, Local: "calendar-home-set"}:   "<D:href>" + calDAVRoot + "</D:href>",
+	}
+}
+
+// collectionProperties описывает календарь. getctag меняется при любом изменении задач,
+// по нему клиенты решают, нужна ли повторная синхронизация.
+func collectionProperties(objects []models.CalDAVObject) davProperties {
+	hash := sha256.New()
+	for _, object := range objects {
+		_, _ = io.WriteString(hash, object.Name+object.ETag)
+	}
+
+	ctag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+
+	return davProperties{
+		{Space: davNS, Local: "resourcetype"}:                        "<D:collection/><C:calendar/>",
+		{Space: davNS, Local: "displayname"}:                         "Tasks",
+		{Space: davNS, Local: "current-user-principal"}:              "<D:href>" + calDAVRoot + "</D:href>",
+		{Space: davNS, Local: "getetag"}:                             davText(ctag),
+		{Space: calendarServerNS, Local: "getctag"}:                  davText(ctag),
+		{Space: calDAVNS, Local: "supported-calendar-component-set"}: `<C:comp name="VTODO"/>`,
+	}
+}
+
+func objectProperties(object models.CalDAVObject) davProperties {
+	return davProperties{
+		{Space: davNS, Local: "resourcetype"}:     "",
+		{Space: davNS, Local: "getetag"}:          davText(object.ETag),
+		{Space: davNS, Local: "getcontenttype"}:   "text/calendar; charset=utf-8; component=VTODO",
+		{Space: calDAVNS, Local: "calendar-data"}: davText(object.Data),
+	}
+}
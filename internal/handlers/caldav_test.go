@@ -0,0 +1,198 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCalDAVService реализует методы CalDAVService для тестов.
+type MockCalDAVService struct {
+	mock.Mock
+}
+
+func (m *MockCalDAVService) Objects(ctx context.Context, userID int) ([]models.CalDAVObject, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.CalDAVObject), args.Error(1)
+}
+
+func (m *MockCalDAVService) Object(ctx context.Context, userID int, name string) (models.CalDAVObject, error) {
+	args := m.Called(ctx, userID, name)
+	return args.Get(0).(models.CalDAVObject), args.Error(1)
+}
+
+func (m *MockCalDAVService) Put(
+	ctx context.Context,
+	userID int,
+	name string,
+	body io.Reader,
+	ifMatch, ifNoneMatch string,
+) (models.CalDAVObject, bool, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(ctx, userID, name, string(data), ifMatch, ifNoneMatch)
+
+	return args.Get(0).(models.CalDAVObject), args.Bool(1), args.Error(2)
+}
+
+func (m *MockCalDAVService) Delete(ctx context.Context, userID int, name, ifMatch string) error {
+	return m.Called(ctx, userID, name, ifMatch).Error(0)
+}
+
+var testCalDAVObjects = []models.CalDAVObject{
+	{
+		TaskID: 1, Name: "task-1.ics", ETag: `"e1"`,
+		Data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:task-1@webtasks\r\nSUMMARY:Write report\r\n" +
+			"DUE:20300105T100000Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+	},
+	{
+		TaskID: 2, Name: "phone.ics", ETag: `"e2"`,
+		Data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:abc@phone\r\nSUMMARY:Buy milk\r\n" +
+			"STATUS:COMPLETED\r\nCOMPLETED:20300101T000000Z\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+	},
+}
+
+func newCalDAVRouter(service services.CalDAVService) *mux.Router {
+	router := mux.NewRouter()
+	handlers.RegisterCalDAVRoutes(router, handlers.NewCalDAVHandler(service))
+
+	return router
+}
+
+func serveCalDAV(router *mux.Router, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req.WithContext(handlers.WithUserID(req.Context(), 7)))
+
+	return rr
+}
+
+func TestCalDAVHandler_Discovery(t *testing.T) {
+	mockService := new(MockCalDAVService)
+	router := newCalDAVRouter(mockService)
+
+	mockService.On("Objects", mock.Anything, 7).Return(testCalDAVObjects, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/.well-known/caldav", nil))
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/caldav/", rr.Header().Get("Location"))
+
+	// Без пользователя клиенту предлагается Basic-аутентификация
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PROPFIND", "/caldav/", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, `Basic realm="WebTasks"`, rr.Header().Get("WWW-Authenticate"))
+
+	rr = serveCalDAV(router, http.MethodOptions, "/caldav/tasks/", "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("DAV"), "calendar-access")
+
+	rr = serveCalDAV(router, "PROPFIND", "/caldav/", `<?xml version="1.0"?>
+<D:propfind xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:current-user-principal/><C:calendar-home-set/></D:prop>
+</D:propfind>`, map[string]string{"Depth": "0"})
+
+	assert.Equal(t, http.StatusMultiStatus, rr.Code)
+	assert.Contains(t, rr.Body.String(), "<C:calendar-home-set><D:href>/caldav/</D:href></C:calendar-home-set>")
+	assert.NotContains(t, rr.Body.String(), "/caldav/tasks/")
+
+	rr = serveCalDAV(router, "PROPFIND", "/caldav/tasks/", `<?xml version="1.0"?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop><D:resourcetype/><CS:getctag/><D:getetag/><D:quota-used-bytes/></D:prop>
+</D:propfind>`, map[string]string{"Depth": "1"})
+
+	body := rr.Body.String()
+	assert.Equal(t, http.StatusMultiStatus, rr.Code)
+	assert.Contains(t, body, "<D:resourcetype><D:collection/><C:calendar/></D:resourcetype>")
+	assert.Contains(t, body, "<D:href>/caldav/tasks/phone.ics</D:href>")
+	assert.Contains(t, body, "<D:getetag>&#34;e1&#34;</D:getetag>")
+	assert.Contains(t, body, "<D:quota-used-bytes></D:quota-used-bytes></D:prop><D:status>HTTP/1.1 404 Not Found</D:status>")
+}
+
+func TestCalDAVHandler_Report(t *testing.T) {
+	mockService := new(MockCalDAVService)
+	router := newCalDAVRouter(mockService)
+
+	mockService.On("Objects", mock.Anything, 7).Return(testCalDAVObjects, nil)
+	mockService.On("Object", mock.Anything, 7, "phone.ics").Return(testCalDAVObjects[1], nil)
+	mockService.On("Object", mock.Anything, 7, "gone.ics").Return(models.CalDAVObject{}, services.ErrCalDAVObjectNotFound)
+
+	// Незавершённые задачи со сроком в январе 2030
+	rr := serveCalDAV(router, "REPORT", "/caldav/tasks/", `<?xml version="1.0"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VTODO">
+        <C:time-range start="20300101T000000Z" end="20300201T000000Z"/>
+        <C:prop-filter name="COMPLETED"><C:is-not-defined/></C:prop-filter>
+        <C:prop-filter name="SUMMARY"><C:text-match>REPORT</C:text-match></C:prop-filter>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`, map[string]string{"Depth": "1"})
+
+	body := rr.Body.String()
+	assert.Equal(t, http.StatusMultiStatus, rr.Code)
+	assert.Contains(t, body, "<D:href>/caldav/tasks/task-1.ics</D:href>")
+	assert.Contains(t, body, "SUMMARY:Write report")
+	assert.NotContains(t, body, "phone.ics")
+
+	rr = serveCalDAV(router, "REPORT", "/caldav/tasks/", `<?xml version="1.0"?>
+<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/></D:prop>
+  <D:href>/caldav/tasks/phone.ics</D:href>
+  <D:href>/caldav/tasks/gone.ics</D:href>
+</C:calendar-multiget>`, nil)
+
+	body = rr.Body.String()
+	assert.Equal(t, http.StatusMultiStatus, rr.Code)
+	assert.Contains(t, body, "<D:getetag>&#34;e2&#34;</D:getetag>")
+	assert.Contains(t, body, "<D:href>/caldav/tasks/gone.ics</D:href><D:status>HTTP/1.1 404 Not Found</D:status>")
+
+	rr = serveCalDAV(router, "REPORT", "/caldav/tasks/", `<D:sync-collection xmlns:D="DAV:"/>`, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCalDAVHandler_Objects(t *testing.T) {
+	mockService := new(MockCalDAVService)
+	router := newCalDAVRouter(mockService)
+
+	mockService.On("Object", mock.Anything, 7, "task-1.ics").Return(testCalDAVObjects[0], nil)
+	mockService.On("Put", mock.Anything, 7, "new.ics", "BEGIN:VCALENDAR", "", "*").
+		Return(models.CalDAVObject{ETag: `"n1"`}, true, nil)
+	mockService.On("Put", mock.Anything, 7, "task-1.ics", "BEGIN:VCALENDAR", `"old"`, "").
+		Return(models.CalDAVObject{}, false, services.ErrCalDAVPrecondition)
+	mockService.On("Delete", mock.Anything, 7, "task-1.ics", `"e1"`).Return(nil)
+
+	rr := serveCalDAV(router, http.MethodGet, "/caldav/tasks/task-1.ics", "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"e1"`, rr.Header().Get("ETag"))
+	assert.Equal(t, testCalDAVObjects[0].Data, rr.Body.String())
+
+	rr = serveCalDAV(router, http.MethodPut, "/caldav/tasks/new.ics", "BEGIN:VCALENDAR", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, `"n1"`, rr.Header().Get("ETag"))
+
+	rr = serveCalDAV(router, http.MethodPut, "/caldav/tasks/task-1.ics", "BEGIN:VCALENDAR", map[string]string{"If-Match": `"old"`})
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	rr = serveCalDAV(router, http.MethodDelete, "/caldav/tasks/task-1.ics", "", map[string]string{"If-Match": `"e1"`})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"WebTasks/internal/ical"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Пространства имён WebDAV (RFC 4918), CalDAV (RFC 4791) и расширения calendarserver (getctag).
+const (
+	davNS            = "DAV:"
+	calDAVNS         = "urn:ietf:params:xml:ns:caldav"
+	calendarServerNS = "http://calendarserver.org/ns/"
+)
+
+// davPrefixes - префиксы, объявленные в корне multistatus.
+var davPrefixes = map[string]string{
+	davNS:            "D",
+	calDAVNS:         "C",
+	calendarServerNS: "CS",
+}
+
+// davPropNames - список запрошенных свойств элемента prop.
+type davPropNames struct {
+	Names []davName `xml:",any"`
+}
+
+type davName struct {
+	XMLName xml.Name
+}
+
+// davRequest - тело PROPFIND и REPORT (calendar-query, calendar-multiget).
+type davRequest struct {
+	XMLName xml.Name
+	AllProp *struct{}     `xml:"DAV: allprop"`
+	Prop    *davPropNames `xml:"DAV: prop"`
+	Hrefs   []string      `xml:"DAV: href"`
+	Filter  *calFilter    `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// requested возвращает запрошенные свойства; nil означает allprop.
+func (r davRequest) requested() []xml.Name {
+	if r.Prop == nil {
+		return nil
+	}
+
+	names := make([]xml.Name, len(r.Prop.Names))
+	for i, name := range r.Prop.Names {
+		names[i] = name.XMLName
+	}
+
+	return names
+}
+
+type calFilter struct {
+	CompFilter calCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type calCompFilter struct {
+	Name         string          `xml:"name,attr"`
+	IsNotDefined *struct{}       `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TimeRange    *calTimeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	PropFilters  []calPropFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter"`
+	CompFilters  []calCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type calPropFilter struct {
+	Name         string        `xml:"name,attr"`
+	IsNotDefined *struct{}     `xml:"urn:ietf:params:xml:ns:caldav is-not-defined"`
+	TimeRange    *calTimeRange `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	TextMatch    *calTextMatch `xml:"urn:ietf:params:xml:ns:caldav text-match"`
+}
+
+type calTimeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type calTextMatch struct {
+	Value  string `xml:",chardata"`
+	Negate string `xml:"negate-condition,attr"`
+}
+
+// readDAVRequest разбирает тело запроса; пустое тело PROPFIND равносильно allprop.
+func readDAVRequest(w http.ResponseWriter, r *http.Request) (davRequest, error) {
+	var request davRequest
+
+	err := xml.NewDecoder(http.MaxBytesReader(w, r.Body, maxCalDAVBody)).Decode(&request)
+	if err == io.EOF {
+		return davRequest{}, nil
+	}
+
+	return request, err
+}
+
+// matchComponent проверяет компонент по comp-filter запроса calendar-query.
+func matchComponent(component *ical.Component, filter calCompFilter) bool {
+	if !strings.EqualFold(component.Name, filter.Name) {
+		return false
+	}
+
+	if filter.TimeRange != nil && !matchTodoRange(component, *filter.TimeRange) {
+		return false
+	}
+
+	for _, propFilter := range filter.PropFilters {
+		if !matchProperty(component, propFilter) {
+			return false
+		}
+	}
+
+	for _, childFilter := range filter.CompFilters {
+		var matched bool
+
+		for _, child := range component.Components {
+			if strings.EqualFold(child.Name, childFilter.Name) &&
+				(childFilter.IsNotDefined != nil || matchComponent(child, childFilter)) {
+				matched = true
+				break
+			}
+		}
+
+		if matched == (childFilter.IsNotDefined != nil) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchTodoRange - упрощённая проверка time-range для VTODO (RFC 4791, 9.9): задача
+// без срока попадает в любой интервал, со сроком - если срок лежит внутри интервала.
+func matchTodoRange(component *ical.Component, timeRange calTimeRange) bool {
+	property, ok := component.Property("DUE")
+	if !ok {
+		return true
+	}
+
+	due, _, err := ical.ParseTime(property)
+	if err != nil {
+		return false
+	}
+
+	return inRange(due, timeRange)
+}
+
+func inRange(value time.Time, timeRange calTimeRange) bool {
+	if start, err := parseRangeTime(timeRange.Start); err == nil && !value.After(start) {
+		return false
+	}
+
+	if end, err := parseRangeTime(timeRange.End); err == nil && value.After(end) {
+		return false
+	}
+
+	return true
+}
+
+func parseRangeTime(value string) (time.Time, error) {
+	parsed, _, err := ical.ParseTime(ical.Property{Name: "RANGE", Value: value})
+	return parsed, err
+}
+
+func matchProperty(component *ical.Component, filter calPropFilter) bool {
+	property, ok := component.Property(strings.ToUpper(filter.Name))
+
+	switch {
+	case filter.IsNotDefined != nil:
+		return !ok
+	case !ok:
+		return false
+	case filter.TimeRange != nil:
+		value, _, err := ical.ParseTime(property)
+		return err == nil && inRange(value, *filter.TimeRange)
+	case filter.TextMatch != nil:
+		// Сравнение без учёта регистра (collation i;ascii-casemap по умолчанию)
+		contains := strings.Contains(
+			strings.ToLower(ical.UnescapeText(property.Value)),
+			strings.ToLower(strings.TrimSpace(filter.TextMatch.Value)),
+		)
+
+		return contains != (filter.TextMatch.Negate == "yes")
+	}
+
+	return true
+}
+
+// davMultistatus - ответ 207 Multi-Status. Имена элементов записаны с префиксами,
+// объявленными в корне, чтобы клиенты получали привычную разметку.
+type davMultistatus struct {
+	XMLName   xml.Name      `xml:"D:multistatus"`
+	DAV       string        `xml:"xmlns:D,attr"`
+	CalDAV    string        `xml:"xmlns:C,attr"`
+	CalServer string        `xml:"xmlns:CS,attr"`
+	Responses []davResponse `xml:"D:response"`
+}
+
+type davResponse struct {
+	Href      string        `xml:"D:href"`
+	Status    string        `xml:"D:status,omitempty"`
+	PropStats []davPropStat `xml:"D:propstat"`
+}
+
+type davPropStat struct {
+	Props  []davProp `xml:"D:prop>prop"`
+	Status string    `xml:"D:status"`
+}
+
+// davProp - свойство с готовым XML-содержимым.
+type davProp struct {
+	XMLName xml.Name
+	Inner   string `xml:",innerxml"`
+}
+
+// davProperties - свойства ресурса: имя -> XML-содержимое.
+type davProperties map[xml.Name]string
+
+// response собирает ответ по ресурсу: найденные свойства в propstat 200, неизвестные - в 404.
+// requested == nil отдаёт все свойства, кроме calendar-data.
+func (p davProperties) response(href string, requested []xml.Name) davResponse {
+	if requested == nil {
+		for name := range p {
+			if name != (xml.Name{Space: calDAVNS, Local: "calendar-data"}) {
+				requested = append(requested, name)
+			}
+		}
+
+		sort.Slice(requested, func(i, j int) bool {
+			return requested[i].Space+requested[i].Local < requested[j].Space+requested[j].Local
+		})
+	}
+
+	var found, missing []davProp
+
+	for _, name := range requested {
+		inner, ok := p[name]
+		if ok {
+			found = append(found, davProp{XMLName: davElementName(name), Inner: inner})
+		} else {
+			missing = append(missing, davProp{XMLName: davElementName(name)})
+		}
+	}
+
+	response := davResponse{Href: href}
+
+	if len(found) > 0 {
+		response.PropStats = append(response.PropStats, davPropStat{Props: found, Status: "HTTP/1.1 200 OK"})
+	}
+
+	if len(missing) > 0 {
+		response.PropStats = append(response.PropStats, davPropStat{Props: missing, Status: "HTTP/1.1 404 Not Found"})
+	}
+
+	return response
+}
+
+// davElementName подставляет объявленный префикс; для чужих пространств имён
+// encoding/xml сам добавит атрибут xmlns.
+func davElementName(name xml.Name) xml.Name {
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		return xml.Name{Local: prefix + ":" + name.Local}
+	}
+
+	return name
+}
+
+func davText(value string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(value))
+
+	return escaped.String()
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []davResponse) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+
+	_, _ = io.WriteString(w, xml.Header)
+
+	_ = xml.NewEncoder(w).Encode(davMultistatus{
+		DAV:       davNS,
+		CalDAV:    calDAVNS,
+		CalServer: calendarServerNS,
+		Responses: responses,
+	})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"WebTasks/internal/ical"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// calendarFeedRoute открыт без заголовка Authorization: календарные приложения не умеют
+// его передавать, доступ даёт секретный токен в ссылке.
+const calendarFeedRoute = "/calendar/{token:[0-9a-f]{64}}.ics"
+
+type CalendarHandler struct {
+	service services.CalendarService
+}
+
+func NewCalendarHandler(service services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+func RegisterCalendarRoutes(router *mux.Router, handler *CalendarHandler) {
+	router.HandleFunc("/calendar/feed", handler.GetFeedLink).Methods(http.MethodGet)
+	router.HandleFunc("/calendar/feed/rotate", handler.RotateFeedLink).Methods(http.MethodPost)
+	router.HandleFunc(calendarFeedRoute, handler.GetFeed).Methods(http.MethodGet)
+}
+
+// feedLink - ссылка на календарь для подписки в календарном приложении.
+type feedLink struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+func (h *CalendarHandler) GetFeedLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := h.service.FeedToken(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Failed to get calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, feedLink{Token: token, URL: "/calendar/" + token + ".ics"})
+}
+
+func (h *CalendarHandler) RotateFeedLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := h.service.RotateFeedToken(r.Context(), userID)
+	if err != nil {
+		http.Error(w, "Failed to rotate calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, feedLink{Token: token, URL: "/calendar/" + token + ".ics"})
+}
+
+// GetFeed отдаёт календарь задач со сроками; ?as=event выдаёт события VEVENT
+// для приложений, которые не показывают VTODO.
+func (h *CalendarHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	component := ical.VTodo
+
+	switch r.URL.Query().Get("as") {
+	case "", "todo":
+	case "event":
+		component = ical.VEvent
+	default:
+		http.Error(w, "Parameter as must be todo or event", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+
+	err := h.service.WriteFeed(r.Context(), mux.Vars(r)["token"], component, w)
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		http.Error(w, "Calendar feed not found", http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		slog.ErrorContext(r.Context(), "calendar feed failed", "error", err)
+		http.Error(w, "Failed to render calendar feed", http.StatusInternalServerError)
+	}
+}
+
+func (h *CalendarHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
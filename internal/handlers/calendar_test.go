@@ -0,0 +1,102 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/ical"
+	"WebTasks/internal/services"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCalendarService реализует методы CalendarService для тестов.
+type MockCalendarService struct {
+	mock.Mock
+}
+
+func (m *MockCalendarService) FeedToken(ctx context.Context, userID int) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarService) RotateFeedToken(ctx context.Context, userID int) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarService) WriteFeed(ctx context.Context, token, component string, w io.Writer) error {
+	args := m.Called(ctx, token, component, w)
+
+	if body := args.String(0); body != "" {
+		_, _ = io.WriteString(w, body)
+	}
+
+	return args.Error(1)
+}
+
+func TestCalendarHandler_GetFeedLink(t *testing.T) {
+	mockService := new(MockCalendarService)
+	handler := handlers.NewCalendarHandler(mockService)
+
+	mockService.On("FeedToken", mock.Anything, 7).Return("abc", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar/feed", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetFeedLink(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr = httptest.NewRecorder()
+
+	handler.GetFeedLink(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var link map[string]string
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&link))
+	assert.Equal(t, "/calendar/abc.ics", link["url"])
+}
+
+func TestCalendarHandler_GetFeed(t *testing.T) {
+	mockService := new(MockCalendarService)
+	handler := handlers.NewCalendarHandler(mockService)
+
+	token := strings.Repeat("a", 64)
+	unknown := strings.Repeat("b", 64)
+
+	mockService.On("WriteFeed", mock.Anything, token, ical.VEvent, mock.Anything).Return("BEGIN:VCALENDAR\r\n", nil)
+	mockService.On("WriteFeed", mock.Anything, unknown, ical.VTodo, mock.Anything).Return("", services.ErrCalendarFeedNotFound)
+
+	// Ссылка на календарь работает без заголовка Authorization
+	router := mux.NewRouter()
+	router.Use(handlers.AuthMiddleware)
+	handlers.RegisterCalendarRoutes(router, handler)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+
+		return rr
+	}
+
+	rr := get("/calendar/" + token + ".ics?as=event")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n", rr.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, get("/calendar/"+unknown+".ics").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/calendar/"+token+".ics?as=journal").Code)
+
+	// Остальные маршруты календаря по-прежнему требуют авторизации
+	assert.Equal(t, http.StatusUnauthorized, get("/calendar/feed").Code)
+
+	mockService.AssertExpectations(t)
+}
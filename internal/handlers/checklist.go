@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ChecklistHandler struct {
+	service services.ChecklistService
+}
+
+type checklistItemRequest struct {
+	Done *bool `json:"done"`
+}
+
+func NewChecklistHandler(service services.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{service: service}
+}
+
+func RegisterChecklistRoutes(router *mux.Router, handler *ChecklistHandler) {
+	router.HandleFunc("/tasks/{id:[0-9]+}/checklist", handler.GetChecklist).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id:[0-9]+}/checklist/{itemID:[0-9]+}", handler.UpdateItem).Methods(http.MethodPatch)
+}
+
+func (h *ChecklistHandler) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.GetByTask(r.Context(), taskID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, items)
+}
+
+// UpdateItem отмечает пункт чек-листа выполненным или снимает отметку: {"done": true}.
+func (h *ChecklistHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := strconv.Atoi(vars["itemID"])
+	if err != nil {
+		http.Error(w, "Invalid checklist item ID", http.StatusBadRequest)
+		return
+	}
+
+	var body checklistItemRequest
+	if err := decodeJSON(r.Body, &body); err != nil || body.Done == nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	item, err := h.service.SetDone(r.Context(), taskID, itemID, *body.Done)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, item)
+}
+
+func (h *ChecklistHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrChecklistItemNotFound):
+		http.Error(w, "Checklist item not found", http.StatusNotFound)
+	default:
+		http.Error(w, "Failed to process checklist request", http.StatusInternalServerError)
+	}
+}
+
+func (h *ChecklistHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockChecklistService - мок для интерфейса ChecklistService
+type MockChecklistService struct {
+	mock.Mock
+}
+
+func (m *MockChecklistService) GetByTask(ctx context.Context, taskID int) ([]models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistService) SetDone(ctx context.Context, taskID, id int, done bool) (models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, id, done)
+	return args.Get(0).(models.ChecklistItem), args.Error(1)
+}
+
+func TestChecklistHandler_UpdateItem(t *testing.T) {
+	mockService := new(MockChecklistService)
+	handler := handlers.NewChecklistHandler(mockService)
+
+	mockService.On("SetDone", mock.Anything, 1, 3, true).Return(models.ChecklistItem{ID: 3, TaskID: 1, Done: true}, nil)
+	mockService.On("SetDone", mock.Anything, 1, 4, false).Return(models.ChecklistItem{}, services.ErrChecklistItemNotFound)
+
+	cases := []struct {
+		itemID string
+		body   string
+		status int
+	}{
+		{"3", `{"done":true}`, http.StatusOK},
+		{"4", `{"done":false}`, http.StatusNotFound},
+		{"3", `{}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPatch, "/tasks/1/checklist/"+tc.itemID, bytes.NewReader([]byte(tc.body)))
+		req = mux.SetURLVars(req, map[string]string{"id": "1", "itemID": tc.itemID})
+		rr := httptest.NewRecorder()
+
+		handler.UpdateItem(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc.body)
+	}
+
+	mockService.AssertExpectations(t)
+}
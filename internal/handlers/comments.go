@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type CommentHandler struct {
+	service services.CommentService
+}
+
+func NewCommentHandler(service services.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+func RegisterCommentRoutes(router *mux.Router, handler *CommentHandler) {
+	router.HandleFunc("/tasks/{id:[0-9]+}/comments", handler.GetComments).Methods(http.MethodGet)
+}
+
+func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	comments, err := h.service.GetByTask(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, services.ErrTaskNotFound) {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+
+		http.Error(w, "Failed to fetch comments", http.StatusInternalServerError)
+
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, comments)
+}
+
+func (h *CommentHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"WebTasks/internal/utils"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// corsExposedHeaders - заголовки ответа, которые браузер отдаёт скрипту фронтенда.
+var corsExposedHeaders = strings.Join([]string{
+	utils.RequestIDHeader,
+	RateLimitLimitHeader,
+	RateLimitRemainingHeader,
+	RateLimitResetHeader,
+	RateLimitPolicyHeader,
+	"Retry-After",
+	IdempotentReplayedHeader,
+	"Content-Disposition",
+	"ETag",
+	"Location",
+}, ", ")
+
+// CORSOptions задаёт, каким сайтам браузер разрешит вызывать API.
+type CORSOptions struct {
+	AllowedOrigins   []string      // Origin целиком ("https://app.example.com") или "*"; пусто - CORS выключен
+	AllowedMethods   []string      // Методы для предварительных запросов
+	AllowedHeaders   []string      // Заголовки запроса для предварительных запросов
+	AllowCredentials bool          // Разрешить cookies и Authorization браузера; не действует для "*"
+	MaxAge           time.Duration // Сколько браузер хранит ответ на предварительный запрос
+}
+
+// CORS отвечает на предварительные запросы (OPTIONS) и добавляет заголовки
+// Access-Control-* к ответам для разрешённых Origin. Параметры меняются на лету.
+type CORS struct {
+	options atomic.Pointer[CORSOptions]
+}
+
+func NewCORS(options CORSOptions) *CORS {
+	c := &CORS{}
+	c.SetOptions(options)
+
+	return c
+}
+
+func (c *CORS) SetOptions(options CORSOptions) {
+	c.options.Store(&options)
+}
+
+// Middleware оборачивает весь маршрутизатор, а не подключается через router.Use:
+// mux не вызывает middleware для OPTIONS к маршрутам с другими методами.
+// Предварительный запрос от разрешённого Origin получает 204 без проверки API-ключа.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		options := c.options.Load()
+		origin := r.Header.Get("Origin")
+
+		// Ответ зависит от Origin, поэтому кэши не должны отдавать его другим сайтам
+		w.Header().Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		listed := options.listsOrigin(origin)
+
+		if origin == "" || !listed && !slices.Contains(options.AllowedOrigins, "*") {
+			if preflight && origin != "" {
+				http.Error(w, "CORS origin not allowed", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		header := w.Header()
+
+		// Credentials разрешаются только явно перечисленным сайтам: иначе любой сайт
+		// мог бы вызывать API от имени пользователя с его cookies
+		if listed {
+			header.Set("Access-Control-Allow-Origin", origin)
+
+			if options.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		} else {
+			header.Set("Access-Control-Allow-Origin", "*")
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+
+		method := r.Header.Get("Access-Control-Request-Method")
+		if !containsFold(options.AllowedMethods, method) {
+			http.Error(w, "CORS method not allowed", http.StatusForbidden)
+			return
+		}
+
+		for _, name := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if name = strings.TrimSpace(name); name != "" && !containsFold(options.AllowedHeaders, name) {
+				http.Error(w, "CORS header not allowed: "+name, http.StatusForbidden)
+				return
+			}
+		}
+
+		header.Set("Access-Control-Allow-Methods", strings.Join(options.AllowedMethods, ", "))
+		header.Set("Access-Control-Allow-Headers", strings.Join(options.AllowedHeaders, ", "))
+
+		if options.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(options.MaxAge.Seconds())))
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// listsOrigin сообщает, перечислен ли Origin явно, а не разрешён через "*".
+func (o *CORSOptions) listsOrigin(origin string) bool {
+	for _, allowed := range o.AllowedOrigins {
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(handlers.AuthMiddleware)
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet, http.MethodPost)
+
+	cors := handlers.NewCORS(handlers.CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         10 * time.Minute,
+	})
+	handler := cors.Middleware(router)
+
+	send := func(method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/tasks", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	// Предварительный запрос обходится без API-ключа
+	rr := send(http.MethodOptions, "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "authorization, content-type",
+	})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+
+	rr = send(http.MethodOptions, "https://app.example.com", map[string]string{"Access-Control-Request-Method": "DELETE"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = send(http.MethodOptions, "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method":  "GET",
+		"Access-Control-Request-Headers": "X-Debug",
+	})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = send(http.MethodOptions, "https://evil.example.com", map[string]string{"Access-Control-Request-Method": "GET"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+
+	// Обычный запрос: заголовки CORS добавляются, проверка ключа остаётся
+	rr = send(http.MethodGet, "https://app.example.com", map[string]string{"Authorization": "Bearer key"})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rr.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+	assert.Contains(t, rr.Header().Get("Access-Control-Expose-Headers"), "RateLimit-Remaining")
+	assert.Equal(t, []string{"Origin"}, rr.Header().Values("Vary"))
+
+	rr = send(http.MethodGet, "https://app.example.com", nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = send(http.MethodGet, "https://evil.example.com", map[string]string{"Authorization": "Bearer key"})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+
+	// Список сайтов меняется на лету; через "*" credentials не разрешаются никому,
+	// явно перечисленный сайт их получает
+	cors.SetOptions(handlers.CORSOptions{AllowedOrigins: []string{"*", "https://app.example.com"}, AllowCredentials: true})
+
+	rr = send(http.MethodGet, "https://evil.example.com", map[string]string{"Authorization": "Bearer key"})
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+
+	rr = send(http.MethodGet, "https://app.example.com", map[string]string{"Authorization": "Bearer key"})
+	assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+
+	cors.SetOptions(handlers.CORSOptions{AllowedOrigins: []string{"*"}})
+
+	rr = send(http.MethodGet, "https://evil.example.com", map[string]string{"Authorization": "Bearer key"})
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	healthzRoute = "/healthz"
+	readyzRoute  = "/readyz"
+	statusRoute  = "/status"
+)
+
+type HealthHandler struct {
+	service services.HealthService
+}
+
+func NewHealthHandler(service services.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// RegisterHealthRoutes регистрирует пробы для оркестратора; они доступны без ключа.
+// /status раскрывает ошибки базы, пул соединений и сборку, поэтому требует API-ключ.
+func RegisterHealthRoutes(router *mux.Router, handler *HealthHandler) {
+	router.HandleFunc(healthzRoute, handler.Live).Methods(http.MethodGet, http.MethodHead)
+	router.HandleFunc(readyzRoute, handler.Ready).Methods(http.MethodGet, http.MethodHead)
+	router.HandleFunc(statusRoute, handler.Status).Methods(http.MethodGet)
+}
+
+// Live отвечает 200, пока процесс обрабатывает запросы.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.service.Live())
+}
+
+// Ready отвечает 200, если экземпляр готов принимать трафик, иначе 503 с причинами.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.service.Ready(r.Context())
+	h.writeJSON(w, healthStatusCode(report.Status), report)
+}
+
+// Status отдаёт подробное состояние: проверки готовности, сборку, пул соединений и фоновые задачи.
+func (h *HealthHandler) Status(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireUserID(w, r); !ok {
+		return
+	}
+
+	report := h.service.Status(r.Context())
+	h.writeJSON(w, healthStatusCode(report.Status), report)
+}
+
+func healthStatusCode(status string) int {
+	if status != models.HealthOK {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
+
+func (h *HealthHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
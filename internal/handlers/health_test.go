@@ -0,0 +1,118 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHealthService - мок для интерфейса HealthService
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) Live() models.HealthReport {
+	return m.Called().Get(0).(models.HealthReport)
+}
+
+func (m *MockHealthService) Ready(ctx context.Context) models.HealthReport {
+	return m.Called(ctx).Get(0).(models.HealthReport)
+}
+
+func (m *MockHealthService) Status(ctx context.Context) models.StatusReport {
+	return m.Called(ctx).Get(0).(models.StatusReport)
+}
+
+func (m *MockHealthService) WorkerStarted(name string) {
+	m.Called(name)
+}
+
+func (m *MockHealthService) WorkerStopped(name string) {
+	m.Called(name)
+}
+
+func (m *MockHealthService) Drain() {
+	m.Called()
+}
+
+func TestHealthHandler_Probes(t *testing.T) {
+	mockService := new(MockHealthService)
+
+	router := mux.NewRouter()
+	router.Use(handlers.AuthMiddleware)
+	handlers.RegisterHealthRoutes(router, handlers.NewHealthHandler(mockService))
+
+	mockService.On("Live").Return(models.HealthReport{Status: models.HealthOK})
+	mockService.On("Ready", mock.Anything).Return(models.HealthReport{
+		Status: models.HealthFail,
+		Checks: map[string]models.HealthCheck{
+			"database": {Status: models.HealthFail, Error: "connection refused"},
+		},
+	})
+
+	// Пробы доступны без заголовка Authorization
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rr = httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(t, `{"status":"fail","checks":{"database":{"status":"fail","error":"connection refused"}}}`, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHealthHandler_StatusRequiresKey(t *testing.T) {
+	mockService := new(MockHealthService)
+
+	router := mux.NewRouter()
+	router.Use(handlers.AuthMiddleware)
+	handlers.RegisterHealthRoutes(router, handlers.NewHealthHandler(mockService))
+
+	mockService.On("Status", mock.Anything).Return(models.StatusReport{
+		HealthReport: models.HealthReport{Status: models.HealthOK},
+		Uptime:       "1m0s",
+	})
+
+	// Подробности состояния не отдаются без ключа
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Authorization", "Bearer unknown")
+	rr = httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "Status", mock.Anything)
+
+	req = httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("Authorization", "Bearer key123")
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr = httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"uptime":"1m0s"`)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyMiddleware учитывает заголовок Idempotency-Key на перечисленных маршрутах
+// ("POST /tasks"). Повтор с тем же ключом и телом получает сохранённый ответ, с другим
+// телом - 422, пока исходный запрос выполняется - 409. Ответы 5xx и паника обработчика
+// освобождают ключ, чтобы такой запрос можно было повторить. Подключается после
+// IdentityMiddleware и BodyLimiter: ключи действуют в пределах пользователя, а размер
+// тела ограничивает BodyLimiter.
+func IdempotencyMiddleware(service services.IdempotencyService, endpoints ...string) mux.MiddlewareFunc {
+	enabled := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		enabled[endpoint] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			endpoint := routeEndpoint(r)
+
+			// Без известного пользователя ответ не сохраняется: иначе все такие клиенты
+			// делили бы одно пространство ключей и получали бы чужие ответы
+			userID, known := UserIDFromContext(r.Context())
+
+			if key == "" || !enabled[endpoint] || !known {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeBodyError(w, err)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			stored, err := service.Begin(r.Context(), key, userID, endpoint, requestFingerprint(r, body))
+			if err != nil {
+				writeIdempotencyError(w, err)
+				return
+			}
+
+			if stored != nil {
+				replayResponse(w, r, stored)
+				return
+			}
+
+			// Запрос уже обработан, поэтому отмена клиентом не должна помешать сохранению
+			ctx := context.WithoutCancel(r.Context())
+
+			release := func() {
+				if err := service.Release(ctx, key, userID, endpoint); err != nil {
+					slog.ErrorContext(ctx, "releasing idempotency key failed", "error", err)
+				}
+			}
+
+			// Без этого ключ после паники оставался бы занятым до истечения TTL;
+			// саму панику дальше обрабатывает net/http
+			defer func() {
+				if p := recover(); p != nil {
+					release()
+					panic(p)
+				}
+			}()
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status >= http.StatusInternalServerError {
+				release()
+				return
+			}
+
+			err = service.Complete(ctx, models.IdempotencyRecord{
+				Key:         key,
+				UserID:      userID,
+				Endpoint:    endpoint,
+				Status:      recorder.status,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "saving idempotent response failed", "error", err)
+			}
+		})
+	}
+}
+
+// routeEndpoint возвращает метод и шаблон маршрута mux, а вне роутера - путь запроса.
+func routeEndpoint(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + template
+		}
+	}
+
+	return r.Method + " " + r.URL.Path
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replayResponse(w http.ResponseWriter, r *http.Request, record *models.IdempotencyRecord) {
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+
+	if _, err := w.Write(record.Body); err != nil {
+		slog.ErrorContext(r.Context(), "replaying idempotent response failed", "error", err)
+	}
+}
+
+func writeIdempotencyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidIdempotencyKey):
+		http.Error(w, "Invalid Idempotency-Key header", http.StatusBadRequest)
+	case errors.Is(err, services.ErrIdempotencyMismatch):
+		http.Error(w, "Idempotency-Key was already used with a different request", http.StatusUnprocessableEntity)
+	case errors.Is(err, services.ErrIdempotencyInProgress):
+		http.Error(w, "Request with this Idempotency-Key is still in progress", http.StatusConflict)
+	default:
+		http.Error(w, "Failed to process Idempotency-Key", http.StatusInternalServerError)
+	}
+}
+
+// responseRecorder передаёт ответ клиенту и запоминает его статус и тело.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+
+	return r.ResponseWriter.Write(data)
+}
@@ -0,0 +1,211 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyService реализует методы IdempotencyService для тестов.
+type MockIdempotencyService struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyService) Begin(ctx context.Context, key string, userID int, endpoint, fingerprint string) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, key, userID, endpoint, fingerprint)
+	record, _ := args.Get(0).(*models.IdempotencyRecord)
+
+	return record, args.Error(1)
+}
+
+func (m *MockIdempotencyService) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *MockIdempotencyService) Release(ctx context.Context, key string, userID int, endpoint string) error {
+	return m.Called(ctx, key, userID, endpoint).Error(0)
+}
+
+func (m *MockIdempotencyService) Purge(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockIdempotencyService) RunPurger(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+func (m *MockIdempotencyService) SetTTL(ttl time.Duration) {
+	m.Called(ttl)
+}
+
+func newIdempotentRouter(service services.IdempotencyService, status int) (*mux.Router, *int) {
+	calls := 0
+
+	router := mux.NewRouter()
+	router.Use(handlers.IdempotencyMiddleware(service, "POST /tasks"))
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}).Methods(http.MethodPost)
+	router.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}).Methods(http.MethodPost)
+
+	return router, &calls
+}
+
+// idempotentUser - владелец API-ключа в запросах postWithKey.
+const idempotentUser = 5
+
+func postWithKey(router http.Handler, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(handlers.IdempotencyKeyHeader, key)
+	req = req.WithContext(handlers.WithUserID(req.Context(), idempotentUser))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestIdempotencyMiddleware_StoresAndReplays(t *testing.T) {
+	mockService := new(MockIdempotencyService)
+	router, calls := newIdempotentRouter(mockService, http.StatusCreated)
+
+	mockService.On("Begin", mock.Anything, "k1", idempotentUser, "POST /tasks", mock.Anything).Return(nil, nil).Once()
+	mockService.On("Complete", mock.Anything, models.IdempotencyRecord{
+		Key: "k1", UserID: idempotentUser, Endpoint: "POST /tasks", Status: http.StatusCreated,
+		ContentType: "application/json", Body: []byte(`{"id":1}`),
+	}).Return(nil).Once()
+
+	rr := postWithKey(router, "/tasks", "k1", `{"name":"Task"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	// Повтор получает сохранённый ответ без вызова обработчика
+	stored := &models.IdempotencyRecord{Status: http.StatusCreated, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+	mockService.On("Begin", mock.Anything, "k1", idempotentUser, "POST /tasks", mock.Anything).Return(stored, nil).Once()
+
+	rr = postWithKey(router, "/tasks", "k1", `{"name":"Task"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "true", rr.Header().Get(handlers.IdempotentReplayedHeader))
+	assert.Equal(t, `{"id":1}`, rr.Body.String())
+	assert.Equal(t, 1, *calls)
+
+	// Маршруты вне списка и запросы без ключа проходят как есть
+	rr = postWithKey(router, "/users", "k1", `{}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 2, *calls)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestIdempotencyMiddleware_Errors(t *testing.T) {
+	mockService := new(MockIdempotencyService)
+	router, calls := newIdempotentRouter(mockService, http.StatusCreated)
+
+	mockService.On("Begin", mock.Anything, "reused", idempotentUser, "POST /tasks", mock.Anything).Return(nil, services.ErrIdempotencyMismatch)
+	mockService.On("Begin", mock.Anything, "busy", idempotentUser, "POST /tasks", mock.Anything).Return(nil, services.ErrIdempotencyInProgress)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, postWithKey(router, "/tasks", "reused", `{"name":"Other"}`).Code)
+	assert.Equal(t, http.StatusConflict, postWithKey(router, "/tasks", "busy", `{}`).Code)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotencyMiddleware_ReleasesOnServerError(t *testing.T) {
+	mockService := new(MockIdempotencyService)
+	router, _ := newIdempotentRouter(mockService, http.StatusInternalServerError)
+
+	mockService.On("Begin", mock.Anything, "k1", idempotentUser, "POST /tasks", mock.Anything).Return(nil, nil)
+	mockService.On("Release", mock.Anything, "k1", idempotentUser, "POST /tasks").Return(nil).Once()
+
+	rr := postWithKey(router, "/tasks", "k1", `{}`)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_ReleasesOnPanic(t *testing.T) {
+	mockService := new(MockIdempotencyService)
+
+	router := mux.NewRouter()
+	router.Use(handlers.IdempotencyMiddleware(mockService, "POST /tasks"))
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	}).Methods(http.MethodPost)
+
+	mockService.On("Begin", mock.Anything, "k1", idempotentUser, "POST /tasks", mock.Anything).Return(nil, nil)
+	mockService.On("Release", mock.Anything, "k1", idempotentUser, "POST /tasks").Return(nil).Once()
+
+	// Паника доходит до net/http, но ключ к этому моменту уже освобождён
+	assert.PanicsWithValue(t, "handler failed", func() {
+		postWithKey(router, "/tasks", "k1", `{}`)
+	})
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_UnknownUser(t *testing.T) {
+	mockService := new(MockIdempotencyService)
+	router, calls := newIdempotentRouter(mockService, http.StatusCreated)
+
+	// Без пользователя ключ не учитывается: каждый запрос выполняется заново
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"name":"Task"}`))
+		req.Header.Set(handlers.IdempotencyKeyHeader, "k1")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Empty(t, rr.Header().Get(handlers.IdempotentReplayedHeader))
+	}
+
+	assert.Equal(t, 2, *calls)
+	mockService.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyMiddleware_BodyErrors(t *testing.T) {
+	mockService := new(MockIdempotencyService)
+
+	// Предел тела берётся из BodyLimiter, подключённого раньше
+	router := mux.NewRouter()
+	router.Use(handlers.NewBodyLimiter(handlers.BodyLimits{Default: 16}).Middleware)
+	router.Use(handlers.IdempotencyMiddleware(mockService, "POST /tasks"))
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {}).Methods(http.MethodPost)
+
+	send := func(body io.Reader) int {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", body)
+		req.Header.Set(handlers.IdempotencyKeyHeader, "k1")
+		req.ContentLength = -1 // Размер неизвестен, предел срабатывает при чтении
+		req = req.WithContext(handlers.WithUserID(req.Context(), idempotentUser))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send(strings.NewReader(`{"name":"A long task name"}`)))
+	assert.Equal(t, http.StatusBadRequest, send(iotest.ErrReader(errors.New("connection reset"))))
+
+	mockService.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"reflect"
+	"time"
+)
+
+var taskType = reflect.TypeOf(models.Task{})
+
+// localizeTasks возвращает копию ответа, в которой метки времени всех задач переведены
+// в часовой пояс текущего пользователя. Задачи ищутся на любой глубине: в срезах,
+// указателях и полях структур (в том числе встроенных, как в models.ScoredTask).
+// Срезы и указатели не копируются: задачи в них меняются на месте.
+func localizeTasks(ctx context.Context, data interface{}) interface{} {
+	location := LocationFromContext(ctx)
+	if data == nil || location == time.UTC {
+		return data
+	}
+
+	value := reflect.New(reflect.TypeOf(data)).Elem()
+	value.Set(reflect.ValueOf(data))
+
+	localizeValue(value, location)
+
+	return value.Interface()
+}
+
+func localizeValue(value reflect.Value, location *time.Location) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			localizeValue(value.Elem(), location)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			localizeValue(value.Index(i), location)
+		}
+	case reflect.Struct:
+		if value.Type() == taskType {
+			if value.CanAddr() {
+				localizeTask(value.Addr().Interface().(*models.Task), location)
+			}
+
+			return
+		}
+
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Field(i); field.CanSet() {
+				localizeValue(field, location)
+			}
+		}
+	}
+}
+
+// localizeTask переводит метки времени задачи в часовой пояс location.
+func localizeTask(task *models.Task, location *time.Location) {
+	if !task.Time.IsZero() {
+		task.Time = task.Time.In(location)
+	}
+
+	if !task.Due.IsZero() {
+		task.Due = task.Due.In(location)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// memberRoles сопоставляет сегмент пути с ролью участника.
+var memberRoles = map[string]string{
+	"assignees": models.RoleAssignee,
+	"watchers":  models.RoleWatcher,
+}
+
+type MemberHandler struct {
+	service services.MemberService
+}
+
+type membersRequest struct {
+	UserIDs []int `json:"user_ids"`
+}
+
+func NewMemberHandler(service services.MemberService) *MemberHandler {
+	return &MemberHandler{service: service}
+}
+
+func RegisterMemberRoutes(router *mux.Router, handler *MemberHandler) {
+	router.HandleFunc("/tasks/{id:[0-9]+}/members", handler.GetMembers).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id:[0-9]+}/{role:assignees|watchers}", handler.AddMembers).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/{id:[0-9]+}/{role:assignees|watchers}/{user:[0-9]+|me}", handler.RemoveMember).Methods(http.MethodDelete)
+}
+
+func (h *MemberHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.service.GetByTask(r.Context(), taskID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, members)
+}
+
+// AddMembers добавляет исполнителей или наблюдателей: {"user_ids": [2, 3]}.
+// Без тела запроса добавляется текущий пользователь ("подписаться на задачу").
+func (h *MemberHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var body membersRequest
+	if err := decodeJSON(r.Body, &body); err != nil && !errors.Is(err, io.EOF) {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(body.UserIDs) == 0 {
+		userID, ok := requireUserID(w, r)
+		if !ok {
+			return
+		}
+
+		body.UserIDs = []int{userID}
+	}
+
+	members, err := h.service.Add(r.Context(), taskID, memberRoles[vars["role"]], body.UserIDs)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, members)
+}
+
+// RemoveMember убирает пользователя из роли; "me" означает текущего пользователя.
+func (h *MemberHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var userID int
+
+	if vars["user"] == "me" {
+		var ok bool
+		if userID, ok = requireUserID(w, r); !ok {
+			return
+		}
+	} else if userID, err = strconv.Atoi(vars["user"]); err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.service.Remove(r.Context(), taskID, memberRoles[vars["role"]], userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, members)
+}
+
+func (h *MemberHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidMember):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to process task members", http.StatusInternalServerError)
+	}
+}
+
+func (h *MemberHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
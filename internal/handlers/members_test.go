@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMemberService - мок для интерфейса MemberService
+type MockMemberService struct {
+	mock.Mock
+}
+
+func (m *MockMemberService) GetByTask(ctx context.Context, taskID int) (models.TaskMembers, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).(models.TaskMembers), args.Error(1)
+}
+
+func (m *MockMemberService) Add(ctx context.Context, taskID int, role string, userIDs []int) (models.TaskMembers, error) {
+	args := m.Called(ctx, taskID, role, userIDs)
+	return args.Get(0).(models.TaskMembers), args.Error(1)
+}
+
+func (m *MockMemberService) Remove(ctx context.Context, taskID int, role string, userID int) (models.TaskMembers, error) {
+	args := m.Called(ctx, taskID, role, userID)
+	return args.Get(0).(models.TaskMembers), args.Error(1)
+}
+
+func (m *MockMemberService) Recipients(ctx context.Context, taskID int) ([]int, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func TestMemberHandler_AddMembers(t *testing.T) {
+	mockService := new(MockMemberService)
+	handler := handlers.NewMemberHandler(mockService)
+
+	router := mux.NewRouter()
+	handlers.RegisterMemberRoutes(router, handler)
+
+	members := models.TaskMembers{
+		Assignees: []models.TaskMember{{UserID: 2, Name: "Jane Smith"}},
+		Watchers:  []models.TaskMember{},
+	}
+
+	mockService.On("Add", mock.Anything, 1, models.RoleAssignee, []int{2}).Return(members, nil)
+	mockService.On("Add", mock.Anything, 1, models.RoleWatcher, []int{7}).Return(members, nil)
+	mockService.On("Add", mock.Anything, 1, models.RoleAssignee, []int{99}).Return(models.TaskMembers{}, services.ErrUserNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/assignees", bytes.NewReader([]byte(`{"user_ids":[2]}`)))
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"assignees":[{"user_id":2,"name":"Jane Smith"}],"watchers":[]}`, rr.Body.String())
+
+	// Без тела текущий пользователь подписывается на задачу
+	req = httptest.NewRequest(http.MethodPost, "/tasks/1/watchers", nil)
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr = httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Без тела и без API-ключа
+	req = httptest.NewRequest(http.MethodPost, "/tasks/1/watchers", nil)
+	rr = httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Несуществующий пользователь
+	req = httptest.NewRequest(http.MethodPost, "/tasks/1/assignees", bytes.NewReader([]byte(`{"user_ids":[99]}`)))
+	rr = httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestMemberHandler_RemoveMember(t *testing.T) {
+	mockService := new(MockMemberService)
+	handler := handlers.NewMemberHandler(mockService)
+
+	router := mux.NewRouter()
+	handlers.RegisterMemberRoutes(router, handler)
+
+	members := models.TaskMembers{Assignees: []models.TaskMember{}, Watchers: []models.TaskMember{}}
+
+	mockService.On("Remove", mock.Anything, 1, models.RoleWatcher, 7).Return(members, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/1/watchers/me", nil)
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"assignees":[],"watchers":[]}`, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"WebTasks/internal/metrics"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const metricsRoute = "/metrics"
+
+// RegisterMetricsRoutes регистрирует /metrics для сборщика Prometheus.
+func RegisterMetricsRoutes(router *mux.Router) {
+	router.Handle(metricsRoute, metrics.Handler()).Methods(http.MethodGet)
+}
+
+// MetricsMiddleware учитывает запросы в метриках по шаблону маршрута. Подключается
+// первым, чтобы в метрики попадали и ответы остальных middleware, например 401.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		metrics.ObserveRequest(r.Method, routeTemplate(r), recorder.status, time.Since(start))
+	})
+}
+
+// routeTemplate возвращает шаблон маршрута запроса или "unmatched".
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return "unmatched"
+}
+
+// statusRecorder запоминает код ответа, не буферизуя тело: выгрузки и скачивания
+// по-прежнему передаются потоком.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(data)
+}
+
+// Unwrap даёт http.ResponseController доступ к Flush и дедлайнам исходного ответа.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,42 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(handlers.MetricsMiddleware)
+	router.Use(handlers.AuthMiddleware)
+	handlers.RegisterMetricsRoutes(router)
+	router.HandleFunc("/metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}).Methods(http.MethodGet)
+
+	// Запрос, отклонённый AuthMiddleware, тоже учитывается
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics-test/3", nil))
+
+	// /metrics доступен без заголовка Authorization
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, `webtasks_http_requests_total{method="GET",route="/metrics-test/{id}",status="418"} 2`)
+	assert.Contains(t, body, `webtasks_http_requests_total{method="GET",route="/metrics-test/{id}",status="401"} 1`)
+	assert.False(t, strings.Contains(body, `route="/metrics-test/1"`))
+}
@@ -1,27 +1,135 @@
 package handlers
 
 import (
-	"log"
+	"WebTasks/internal/services"
+	"WebTasks/internal/utils"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+type contextKey string
+
+const userLocationKey contextKey = "userLocation"
+
+// WithUserID сохраняет ID текущего пользователя в контексте запроса. Ключ общий с
+// services.UserIDFromContext: по нему сервисы определяют автора задач.
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return services.WithUserID(ctx, userID)
+}
+
+// UserIDFromContext возвращает ID пользователя, определённого IdentityMiddleware.
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	return services.UserIDFromContext(ctx)
+}
+
+// WithUserLocation сохраняет часовой пояс текущего пользователя в контексте запроса.
+func WithUserLocation(ctx context.Context, location *time.Location) context.Context {
+	return context.WithValue(ctx, userLocationKey, location)
+}
+
+// LocationFromContext возвращает часовой пояс пользователя, по умолчанию UTC.
+func LocationFromContext(ctx context.Context) *time.Location {
+	if location, ok := ctx.Value(userLocationKey).(*time.Location); ok {
+		return location
+	}
+
+	return time.UTC
+}
+
+// RequestIDMiddleware принимает идентификатор запроса из заголовка X-Request-ID или создаёт
+// новый, сохраняет его в контексте и возвращает в заголовке ответа. К текстовым ответам
+// с ошибкой (http.Error) идентификатор дописывается строкой "Request ID: ...", чтобы
+// клиент мог сослаться на него без доступа к заголовкам.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(utils.RequestIDHeader)
+		if !utils.ValidRequestID(id) {
+			id = utils.NewRequestID()
+		}
+
+		w.Header().Set(utils.RequestIDHeader, id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", id))
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r.WithContext(utils.WithRequestID(r.Context(), id)))
+
+		if recorder.status >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+			// Ошибка записи значит, что тело не допускается (HEAD) или клиент отключился
+			_, _ = fmt.Fprintf(w, "Request ID: %s\n", id)
+		}
+	})
+}
+
+// LoggerMiddleware пишет в журнал итог запроса. Поля method и route, а также request_id
+// от RequestIDMiddleware и user_id от IdentityMiddleware попадают во все записи,
+// сделанные с контекстом запроса.
 func LoggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		log.Printf("Начало обработки запроса: %s %s", r.Method, r.URL.Path)
+		ctx := utils.WithLogAttrs(r.Context(),
+			slog.String("method", r.Method),
+			slog.String("route", routeTemplate(r)),
+		)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 
-		next.ServeHTTP(w, r) // Передача управления следующему обработчику
+		slog.DebugContext(ctx, "request started", "path", r.URL.Path)
+
+		next.ServeHTTP(recorder, r.WithContext(ctx)) // Передача управления следующему обработчику
 
-		duration := time.Since(start)
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
 
-		log.Printf("Запрос обработан: %s %s за %v", r.Method, r.URL.Path, duration)
+		slog.Log(ctx, level, "request completed",
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(start),
+		)
 	})
 }
 
+// publicRoutes - шаблоны маршрутов, доступных без заголовка Authorization.
+var publicRoutes = map[string]bool{
+	calendarFeedRoute: true,
+	calDAVWellKnown:   true,
+	calDAVRoot:        true,
+	calDAVCollection:  true,
+	calDAVObjectRoute: true,
+	healthzRoute:      true,
+	readyzRoute:       true,
+	metricsRoute:      true,
+}
+
+// isPublicRoute сообщает, что запрос пришёл на маршрут из publicRoutes.
+func isPublicRoute(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+
+	template, err := route.GetPathTemplate()
+
+	return err == nil && publicRoutes[template]
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isPublicRoute(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		authHeader := r.Header.Get("Authorization")
 
 		if authHeader == "" {
@@ -32,3 +140,48 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r) // Передача управления следующему обработчику
 	})
 }
+
+// IdentityMiddleware определяет пользователя по API-ключу из заголовка Authorization:
+// "Bearer <key>", просто ключ или Basic с ключом в качестве пароля (для клиентов CalDAV).
+// Неизвестный ключ не отклоняется, чтобы не ломать маршруты без привязки к пользователю;
+// обработчики, которым нужен пользователь, проверяют его наличие сами через UserIDFromContext.
+func IdentityMiddleware(service services.UserService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			if _, password, ok := r.BasicAuth(); ok {
+				key = password
+			}
+
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := service.GetByKey(r.Context(), key)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := WithUserID(r.Context(), user.ID)
+			utils.AddLogAttrs(ctx, slog.Int("user_id", user.ID))
+
+			if location, err := time.LoadLocation(user.Timezone); err == nil && user.Timezone != "" {
+				ctx = WithUserLocation(ctx, location)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requireUserID возвращает ID текущего пользователя или отвечает 401.
+func requireUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, ok := UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: Unknown API key", http.StatusUnauthorized)
+	}
+
+	return userID, ok
+}
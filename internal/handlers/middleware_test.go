@@ -2,12 +2,23 @@ package handlers_test
 
 import (
 	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoggerMiddleware(t *testing.T) {
@@ -31,6 +42,97 @@ func TestLoggerMiddleware(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestLoggerMiddleware_RequestFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, err := utils.NewLogger(&buf, utils.LogFormatJSON)
+	require.NoError(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	defer func() {
+		slog.SetDefault(previous)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	mockService := new(MockUserService)
+	mockService.On("GetByKey", mock.Anything, "key123").Return(models.User{ID: 7}, nil)
+
+	router := mux.NewRouter()
+	router.Use(handlers.RequestIDMiddleware)
+	router.Use(handlers.LoggerMiddleware)
+	router.Use(handlers.IdentityMiddleware(mockService))
+	router.HandleFunc("/log-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "handler")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/log-test/5", nil)
+	req.Header.Set("Authorization", "Bearer key123")
+	req.Header.Set("X-Request-ID", "client-42")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var records []map[string]interface{}
+	for decoder := json.NewDecoder(&buf); decoder.More(); {
+		var record map[string]interface{}
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+
+	require.Len(t, records, 2)
+
+	// Запись обработчика и итог запроса получают общие поля запроса
+	for _, record := range records {
+		assert.Equal(t, "/log-test/{id}", record["route"])
+		assert.Equal(t, "GET", record["method"])
+		assert.Equal(t, float64(7), record["user_id"])
+		assert.Equal(t, "client-42", record["request_id"])
+	}
+
+	assert.Equal(t, "request completed", records[1]["msg"])
+	assert.Equal(t, float64(http.StatusNotFound), records[1]["status"])
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+
+	router := mux.NewRouter()
+	router.Use(handlers.RequestIDMiddleware)
+	router.HandleFunc("/request-id/{id}", func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.RequestIDFromContext(r.Context())
+
+		if mux.Vars(r)["id"] == "missing" {
+			http.Error(w, "Task not found", http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Идентификатор клиента принимается и возвращается в ответе
+	req := httptest.NewRequest(http.MethodGet, "/request-id/1", nil)
+	req.Header.Set("X-Request-ID", "client-42")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, "client-42", seen)
+	assert.Equal(t, "client-42", rr.Header().Get("X-Request-ID"))
+	assert.Empty(t, rr.Body.String())
+
+	// Недопустимый идентификатор заменяется новым, который дописывается к тексту ошибки
+	req = httptest.NewRequest(http.MethodGet, "/request-id/missing", nil)
+	req.Header.Set("X-Request-ID", "bad id\r\n")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	id := rr.Header().Get("X-Request-ID")
+	assert.Len(t, id, 16)
+	assert.Equal(t, id, seen)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "Task not found\nRequest ID: "+id+"\n", rr.Body.String())
+}
+
 func TestAuthMiddleware_ValidHeader(t *testing.T) {
 	// Создаем тестовый обработчик, который возвращает HTTP 200
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -72,3 +174,60 @@ func TestAuthMiddleware_MissingHeader(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Unauthorized: Missing Authorization Header")
 }
+
+func TestIdentityMiddleware(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("GetByKey", mock.Anything, "key123").Return(models.User{ID: 7, Name: "Alice", Timezone: "Europe/Moscow"}, nil)
+	mockService.On("GetByKey", mock.Anything, "unknown").Return(models.User{}, errors.New("not found"))
+
+	var (
+		userID   int
+		found    bool
+		location *time.Location
+	)
+
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, found = handlers.UserIDFromContext(r.Context())
+		location = handlers.LocationFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	identityMiddleware := handlers.IdentityMiddleware(mockService)(nextHandler)
+
+	// Известный ключ: пользователь попадает в контекст
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer key123")
+	rr := httptest.NewRecorder()
+
+	identityMiddleware.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, found)
+	assert.Equal(t, 7, userID)
+	assert.Equal(t, "Europe/Moscow", location.String())
+
+	// Basic: ключ передаётся паролем
+	found = false
+	req = httptest.NewRequest(http.MethodGet, "/caldav/", nil)
+	req.SetBasicAuth("alice", "key123")
+	rr = httptest.NewRecorder()
+
+	identityMiddleware.ServeHTTP(rr, req)
+
+	assert.True(t, found)
+	assert.Equal(t, 7, userID)
+
+	// Неизвестный ключ: запрос проходит дальше без пользователя
+	found = false
+	req = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	req.Header.Set("Authorization", "unknown")
+	rr = httptest.NewRecorder()
+
+	identityMiddleware.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.False(t, found)
+	assert.Equal(t, time.UTC, location)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Выгрузки досок Trello с историей действий бывают заметно больше CSV задач.
+const maxMigrationBytes = 32 << 20
+
+type MigrationHandler struct {
+	service services.MigrationService
+}
+
+func NewMigrationHandler(service services.MigrationService) *MigrationHandler {
+	return &MigrationHandler{service: service}
+}
+
+func RegisterMigrationRoutes(router *mux.Router, handler *MigrationHandler) {
+	router.HandleFunc("/imports/{source:trello|todoist|jira}", handler.Import).Methods(http.MethodPost)
+}
+
+// Import переносит файл выгрузки из тела запроса. Параметры: project - имя создаваемого
+// проекта вместо взятого из файла, dry_run=true - только проверить и посчитать.
+// Ответ 201 - проекты созданы, 200 - пробный запуск.
+func (h *MigrationHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	options := models.MigrationOptions{
+		Source:  mux.Vars(r)["source"],
+		Project: query.Get("project"),
+		UserID:  userID,
+	}
+
+	if value := query.Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid dry_run", http.StatusBadRequest)
+			return
+		}
+
+		options.DryRun = dryRun
+	}
+
+	body := limitBody(w, r.Body, maxMigrationBytes)
+
+	report, err := h.service.Import(r.Context(), options, body)
+	if err != nil {
+		if body.err != nil {
+			writeBodyError(w, body.err)
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidMigration) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if errors.Is(err, services.ErrTaskQuotaExceeded) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "migration import failed", "source", options.Source, "error", err)
+		http.Error(w, "Failed to import export file", http.StatusInternalServerError)
+
+		return
+	}
+
+	status := http.StatusCreated
+	if report.DryRun {
+		status = http.StatusOK
+	}
+
+	h.writeJSON(w, status, report)
+}
+
+func (h *MigrationHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMigrationService реализует методы MigrationService для тестов.
+type MockMigrationService struct {
+	mock.Mock
+}
+
+func (m *MockMigrationService) Import(ctx context.Context, options models.MigrationOptions, body io.Reader) (models.MigrationReport, error) {
+	args := m.Called(ctx, options, body)
+	return args.Get(0).(models.MigrationReport), args.Error(1)
+}
+
+func TestMigrationHandler_Import(t *testing.T) {
+	mockService := new(MockMigrationService)
+
+	router := mux.NewRouter()
+	handlers.RegisterMigrationRoutes(router, handlers.NewMigrationHandler(mockService))
+
+	mockService.On("Import", mock.Anything, models.MigrationOptions{Source: "trello", Project: "Launch", UserID: 7}, mock.Anything).
+		Return(models.MigrationReport{Source: "trello", Tasks: 4}, nil)
+	mockService.On("Import", mock.Anything, models.MigrationOptions{Source: "jira", DryRun: true, UserID: 7}, mock.Anything).
+		Return(models.MigrationReport{Source: "jira", DryRun: true}, nil)
+	mockService.On("Import", mock.Anything, models.MigrationOptions{Source: "todoist", UserID: 7}, mock.Anything).
+		Return(models.MigrationReport{}, services.ErrInvalidMigration)
+	mockService.On("Import", mock.Anything, models.MigrationOptions{Source: "todoist", Project: "fail", UserID: 7}, mock.Anything).
+		Return(models.MigrationReport{}, errors.New("db down"))
+	mockService.On("Import", mock.Anything, models.MigrationOptions{Source: "trello", UserID: 7}, mock.Anything).
+		Return(models.MigrationReport{}, services.ErrTaskQuotaExceeded)
+
+	cases := []struct {
+		target string
+		status int
+	}{
+		{"/imports/trello?project=Launch", http.StatusCreated},
+		{"/imports/jira?dry_run=true", http.StatusOK},
+		{"/imports/jira?dry_run=maybe", http.StatusBadRequest},
+		{"/imports/todoist", http.StatusBadRequest},
+		{"/imports/todoist?project=fail", http.StatusInternalServerError},
+		{"/imports/trello", http.StatusForbidden},
+		{"/imports/asana", http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader("{}"))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req.WithContext(handlers.WithUserID(req.Context(), 7)))
+
+		assert.Equal(t, tc.status, rr.Code, tc.target)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/imports/trello", strings.NewReader("{}"))
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestMigrationHandler_Import_TooLarge(t *testing.T) {
+	mockService := new(MockMigrationService)
+
+	router := mux.NewRouter()
+	handlers.RegisterMigrationRoutes(router, handlers.NewMigrationHandler(mockService))
+
+	mockService.On("Import", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { _, _ = io.ReadAll(args.Get(2).(io.Reader)) }).
+		Return(models.MigrationReport{}, services.ErrInvalidMigration)
+
+	req := httptest.NewRequest(http.MethodPost, "/imports/trello", strings.NewReader(strings.Repeat("a", 32<<20+1)))
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req.WithContext(handlers.WithUserID(req.Context(), 7)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+// MockCommentService реализует методы CommentService для тестов.
+type MockCommentService struct {
+	mock.Mock
+}
+
+func (m *MockCommentService) GetByTask(ctx context.Context, taskID int) ([]models.Comment, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.Comment), args.Error(1)
+}
+
+func TestCommentHandler_GetComments(t *testing.T) {
+	mockService := new(MockCommentService)
+
+	router := mux.NewRouter()
+	handlers.RegisterCommentRoutes(router, handlers.NewCommentHandler(mockService))
+
+	mockService.On("GetByTask", mock.Anything, 1).Return([]models.Comment{{ID: 5, TaskID: 1, Author: "Alice", Body: "First"}}, nil)
+	mockService.On("GetByTask", mock.Anything, 2).Return([]models.Comment(nil), services.ErrTaskNotFound)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/tasks/1/comments", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var comments []models.Comment
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&comments))
+	assert.Equal(t, "Alice", comments[0].Author)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/tasks/2/comments", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type PlanningHandler struct {
+	service services.PlanningService
+}
+
+type dependencyRequest struct {
+	DependsOn int `json:"depends_on"`
+}
+
+func NewPlanningHandler(service services.PlanningService) *PlanningHandler {
+	return &PlanningHandler{service: service}
+}
+
+func RegisterPlanningRoutes(router *mux.Router, handler *PlanningHandler) {
+	router.HandleFunc("/tasks/next", handler.GetNextUp).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id}/dependencies", handler.GetDependencies).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id}/dependencies", handler.AddDependency).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/{id}/dependencies/{dependsOn}", handler.RemoveDependency).Methods(http.MethodDelete)
+}
+
+// GetNextUp возвращает открытые задачи текущего пользователя в порядке "что делать дальше".
+func (h *PlanningHandler) GetNextUp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 0
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+
+		limit = parsed
+	}
+
+	tasks, err := h.service.NextUp(r.Context(), userID, limit)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, tasks)
+}
+
+func (h *PlanningHandler) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.service.GetDependencies(r.Context(), taskID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, tasks)
+}
+
+func (h *PlanningHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var body dependencyRequest
+	if err := decodeJSON(r.Body, &body); err != nil || body.DependsOn == 0 {
+		writeBodyError(w, err)
+		return
+	}
+
+	if err := h.service.AddDependency(r.Context(), taskID, body.DependsOn); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlanningHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	taskID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	dependsOn, err := strconv.Atoi(vars["dependsOn"])
+	if err != nil {
+		http.Error(w, "Invalid dependency ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveDependency(r.Context(), taskID, dependsOn); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlanningHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidDependency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrDependencyCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Failed to process planning request", http.StatusInternalServerError)
+	}
+}
+
+func (h *PlanningHandler) writeJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	data = localizeTasks(r.Context(), data)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,155 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPlanningService - мок для интерфейса PlanningService
+type MockPlanningService struct {
+	mock.Mock
+}
+
+func (m *MockPlanningService) NextUp(ctx context.Context, userID, limit int) ([]models.ScoredTask, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]models.ScoredTask), args.Error(1)
+}
+
+func (m *MockPlanningService) GetDependencies(ctx context.Context, taskID int) ([]models.Task, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockPlanningService) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return m.Called(ctx, taskID, dependsOnID).Error(0)
+}
+
+func (m *MockPlanningService) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return m.Called(ctx, taskID, dependsOnID).Error(0)
+}
+
+func (m *MockPlanningService) SetWeights(weights services.ScoringWeights) {
+	m.Called(weights)
+}
+
+func TestPlanningHandler_GetNextUp(t *testing.T) {
+	mockService := new(MockPlanningService)
+	handler := handlers.NewPlanningHandler(mockService)
+
+	expected := []models.ScoredTask{
+		{Task: models.Task{ID: 2, Name: "Deploy", Priority: "high"}, Score: 4.5, Breakdown: map[string]float64{"priority": 3}},
+	}
+	mockService.On("NextUp", mock.Anything, 7, 5).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/next?limit=5", nil)
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.GetNextUp(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var actual []models.ScoredTask
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&actual))
+	assert.Equal(t, expected, actual)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPlanningHandler_GetNextUp_Unauthorized(t *testing.T) {
+	mockService := new(MockPlanningService)
+	handler := handlers.NewPlanningHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/next", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetNextUp(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "NextUp")
+}
+
+func TestPlanningHandler_AddDependency(t *testing.T) {
+	mockService := new(MockPlanningService)
+	handler := handlers.NewPlanningHandler(mockService)
+
+	mockService.On("AddDependency", mock.Anything, 1, 2).Return(nil)
+	mockService.On("AddDependency", mock.Anything, 2, 1).Return(services.ErrDependencyCycle)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/dependencies", bytes.NewReader([]byte(`{"depends_on":2}`)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.AddDependency(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/2/dependencies", bytes.NewReader([]byte(`{"depends_on":1}`)))
+	req = mux.SetURLVars(req, map[string]string{"id": "2"})
+	rr = httptest.NewRecorder()
+
+	handler.AddDependency(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRegisterTaskRoutes_NextDoesNotMatchID(t *testing.T) {
+	router := mux.NewRouter()
+	handlers.RegisterTaskRoutes(router, handlers.NewHandler(new(MockTaskService)))
+
+	mockService := new(MockPlanningService)
+	mockService.On("NextUp", mock.Anything, 7, 0).Return([]models.ScoredTask{}, nil)
+	handlers.RegisterPlanningRoutes(router, handlers.NewPlanningHandler(mockService))
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/next", nil)
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPlanningHandler_LocalizesTimes(t *testing.T) {
+	mockService := new(MockPlanningService)
+	handler := handlers.NewPlanningHandler(mockService)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	due := time.Date(2030, 1, 2, 21, 30, 0, 0, time.UTC)
+	mockService.On("NextUp", mock.Anything, 7, 0).
+		Return([]models.ScoredTask{{Task: models.Task{ID: 2, Due: due}, Score: 1}}, nil)
+	mockService.On("GetDependencies", mock.Anything, 2).Return([]models.Task{{ID: 3, Due: due}}, nil)
+
+	for _, target := range []string{"/tasks/next", "/tasks/2/dependencies"} {
+		router := mux.NewRouter()
+		handlers.RegisterPlanningRoutes(router, handler)
+
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		ctx := handlers.WithUserLocation(handlers.WithUserID(req.Context(), 7), moscow)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req.WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, rr.Code, target)
+		assert.Contains(t, rr.Body.String(), `"due":"2030-01-03T00:30:00+03:00"`, target)
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ProjectHandler struct {
+	service services.ProjectService
+}
+
+func NewProjectHandler(service services.ProjectService) *ProjectHandler {
+	return &ProjectHandler{service: service}
+}
+
+func RegisterProjectRoutes(router *mux.Router, handler *ProjectHandler) {
+	router.HandleFunc("/projects", handler.GetProjects).Methods(http.MethodGet)
+	router.HandleFunc("/projects", handler.CreateProject).Methods(http.MethodPost)
+	router.HandleFunc("/projects/{id:[0-9]+}", handler.GetProject).Methods(http.MethodGet)
+	router.HandleFunc("/projects/{id:[0-9]+}/fields", handler.GetFields).Methods(http.MethodGet)
+	router.HandleFunc("/projects/{id:[0-9]+}/fields", handler.CreateField).Methods(http.MethodPost)
+	router.HandleFunc("/projects/{id:[0-9]+}/fields/{fieldID:[0-9]+}", handler.DeleteField).Methods(http.MethodDelete)
+	router.HandleFunc("/projects/{id:[0-9]+}/members", handler.AddMembers).Methods(http.MethodPost)
+	router.HandleFunc("/projects/{id:[0-9]+}/members/{user:[0-9]+}", handler.RemoveMember).Methods(http.MethodDelete)
+}
+
+func (h *ProjectHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	var project models.Project
+	if err := decodeJSON(r.Body, &project); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	// Владельцем становится текущий пользователь, если он известен
+	project.OwnerID, _ = UserIDFromContext(r.Context())
+
+	created, err := h.service.Create(r.Context(), project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, project)
+}
+
+func (h *ProjectHandler) GetFields(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	fields, err := h.service.GetFields(r.Context(), projectID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, fields)
+}
+
+func (h *ProjectHandler) CreateField(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	var field models.CustomField
+	if err := decodeJSON(r.Body, &field); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	field.ProjectID = projectID
+
+	created, err := h.service.CreateField(r.Context(), field)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *ProjectHandler) DeleteField(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	projectID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	fieldID, err := strconv.Atoi(vars["fieldID"])
+	if err != nil {
+		http.Error(w, "Invalid field ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteField(r.Context(), projectID, fieldID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddMembers добавляет участников проекта: {"user_ids": [2, 3]}. Доступно только владельцу.
+func (h *ProjectHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	var body membersRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(body.UserIDs) == 0 {
+		http.Error(w, "user_ids is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.AddMembers(r.Context(), projectID, body.UserIDs); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	projectID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid project ID", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := strconv.Atoi(vars["user"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveMember(r.Context(), projectID, userID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProjectHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		http.Error(w, "Project not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrNotProjectOwner):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, services.ErrCustomFieldNotFound):
+		http.Error(w, "Custom field not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrCustomFieldExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidCustomField):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to process project request", http.StatusInternalServerError)
+	}
+}
+
+func (h *ProjectHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
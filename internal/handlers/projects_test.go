@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProjectService - мок для интерфейса ProjectService
+type MockProjectService struct {
+	mock.Mock
+}
+
+func (m *MockProjectService) Create(ctx context.Context, project models.Project) (models.Project, error) {
+	args := m.Called(ctx, project)
+	return args.Get(0).(models.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetByID(ctx context.Context, id int) (models.Project, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetAll(ctx context.Context) ([]models.Project, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+func (m *MockProjectService) GetFields(ctx context.Context, projectID int) ([]models.CustomField, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]models.CustomField), args.Error(1)
+}
+
+func (m *MockProjectService) CreateField(ctx context.Context, field models.CustomField) (models.CustomField, error) {
+	args := m.Called(ctx, field)
+	return args.Get(0).(models.CustomField), args.Error(1)
+}
+
+func (m *MockProjectService) DeleteField(ctx context.Context, projectID, id int) error {
+	return m.Called(ctx, projectID, id).Error(0)
+}
+
+func (m *MockProjectService) ValidateCustomFields(ctx context.Context, projectID int, values models.CustomValues) (models.CustomValues, error) {
+	args := m.Called(ctx, projectID, values)
+	return args.Get(0).(models.CustomValues), args.Error(1)
+}
+
+func (m *MockProjectService) CheckMember(ctx context.Context, projectID, userID int) error {
+	return m.Called(ctx, projectID, userID).Error(0)
+}
+
+func (m *MockProjectService) AddMembers(ctx context.Context, projectID int, userIDs []int) error {
+	return m.Called(ctx, projectID, userIDs).Error(0)
+}
+
+func (m *MockProjectService) RemoveMember(ctx context.Context, projectID, userID int) error {
+	return m.Called(ctx, projectID, userID).Error(0)
+}
+
+func TestProjectHandler_CreateProject(t *testing.T) {
+	mockService := new(MockProjectService)
+	handler := handlers.NewProjectHandler(mockService)
+
+	mockService.On("Create", mock.Anything, models.Project{Name: "Site", OwnerID: 7}).
+		Return(models.Project{ID: 1, Name: "Site", OwnerID: 7}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader([]byte(`{"name":"Site"}`)))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.CreateProject(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProjectHandler_CreateField(t *testing.T) {
+	mockService := new(MockProjectService)
+	handler := handlers.NewProjectHandler(mockService)
+
+	enum := models.CustomField{ProjectID: 1, Name: "stage", Type: "enum", Options: []string{"design", "build"}}
+	mockService.On("CreateField", mock.Anything, enum).Return(models.CustomField{ID: 4, ProjectID: 1, Name: "stage", Type: "enum"}, nil)
+	mockService.On("CreateField", mock.Anything, models.CustomField{ProjectID: 1, Name: "client", Type: "text"}).
+		Return(models.CustomField{}, services.ErrCustomFieldExists)
+	mockService.On("CreateField", mock.Anything, models.CustomField{ProjectID: 1, Name: "budget", Type: "money"}).
+		Return(models.CustomField{}, fmt.Errorf("%w: bad type", services.ErrInvalidCustomField))
+	mockService.On("CreateField", mock.Anything, models.CustomField{ProjectID: 2, Name: "client", Type: "text"}).
+		Return(models.CustomField{}, services.ErrProjectNotFound)
+
+	cases := []struct {
+		projectID string
+		body      string
+		status    int
+	}{
+		{"1", `{"name":"stage","type":"enum","options":["design","build"]}`, http.StatusCreated},
+		{"1", `{"name":"client","type":"text"}`, http.StatusConflict},
+		{"1", `{"name":"budget","type":"money"}`, http.StatusBadRequest},
+		{"2", `{"name":"client","type":"text"}`, http.StatusNotFound},
+		{"1", `{`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/projects/"+tc.projectID+"/fields", bytes.NewReader([]byte(tc.body)))
+		req = mux.SetURLVars(req, map[string]string{"id": tc.projectID})
+		rr := httptest.NewRecorder()
+
+		handler.CreateField(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc.body)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestProjectHandler_DeleteField_NotFound(t *testing.T) {
+	mockService := new(MockProjectService)
+	handler := handlers.NewProjectHandler(mockService)
+
+	mockService.On("DeleteField", mock.Anything, 1, 9).Return(services.ErrCustomFieldNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/projects/1/fields/9", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "fieldID": "9"})
+	rr := httptest.NewRecorder()
+
+	handler.DeleteField(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestProjectHandler_Members(t *testing.T) {
+	mockService := new(MockProjectService)
+	handler := handlers.NewProjectHandler(mockService)
+
+	mockService.On("AddMembers", mock.Anything, 1, []int{8, 9}).Return(nil)
+	mockService.On("AddMembers", mock.Anything, 2, []int{8}).Return(services.ErrNotProjectOwner)
+	mockService.On("AddMembers", mock.Anything, 1, []int{99}).Return(fmt.Errorf("%w: 99", services.ErrUserNotFound))
+	mockService.On("RemoveMember", mock.Anything, 1, 8).Return(nil)
+
+	cases := []struct {
+		projectID string
+		body      string
+		status    int
+	}{
+		{"1", `{"user_ids":[8,9]}`, http.StatusNoContent},
+		{"2", `{"user_ids":[8]}`, http.StatusForbidden},
+		{"1", `{"user_ids":[99]}`, http.StatusNotFound},
+		{"1", `{"user_ids":[]}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/projects/"+tc.projectID+"/members", bytes.NewReader([]byte(tc.body)))
+		req = mux.SetURLVars(req, map[string]string{"id": tc.projectID})
+		rr := httptest.NewRecorder()
+
+		handler.AddMembers(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc.body)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/projects/1/members/8", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1", "user": "8"})
+	rr := httptest.NewRecorder()
+
+	handler.RemoveMember(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockService.AssertExpectations(t)
+}
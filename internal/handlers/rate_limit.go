@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"WebTasks/internal/services"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Заголовки ограничения частоты запросов (draft-ietf-httpapi-ratelimit-headers).
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// heavyEndpoints - маршруты группы services.RateLimitHeavy: пакетные операции, импорт
+// и выгрузка задач.
+var heavyEndpoints = map[string]bool{
+	"POST /tasks/bulk":                           true,
+	"POST /tasks/import":                         true,
+	"GET /tasks/export":                          true,
+	"POST /imports/{source:trello|todoist|jira}": true,
+}
+
+// unlimitedRoutes - служебные маршруты, которые не ограничиваются: их опрашивают
+// балансировщики и системы мониторинга.
+var unlimitedRoutes = map[string]bool{
+	healthzRoute: true,
+	readyzRoute:  true,
+	statusRoute:  true,
+	metricsRoute: true,
+}
+
+// RateLimitMiddleware ограничивает частоту запросов корзинами токенов: пользователя
+// с API-ключом - по группам маршрутов, запросы без ключа - по IP-адресу. Должен стоять
+// после IdentityMiddleware. Ответ получает заголовки RateLimit-*, отклонённый запрос -
+// 429 и Retry-After. Если хранилище корзин недоступно, запрос пропускается.
+// trustProxy разрешает брать адрес клиента из X-Forwarded-For.
+func RateLimitMiddleware(service services.RateLimitService, trustProxy bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil && unlimitedRoutes[template] {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			subject := "ip:" + clientIP(r, trustProxy)
+
+			userID, ok := UserIDFromContext(r.Context())
+			if ok {
+				subject = services.RateLimitUserSubject(userID)
+			}
+
+			decision, err := service.Allow(r.Context(), rateLimitGroup(r), subject, !ok)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "rate limit check failed", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if decision.Limited {
+				header := w.Header()
+				header.Set(RateLimitLimitHeader, strconv.Itoa(decision.Limit))
+				header.Set(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+				header.Set(RateLimitResetHeader, strconv.Itoa(int(decision.Reset.Seconds())))
+				header.Set(RateLimitPolicyHeader, decision.Policy)
+			}
+
+			if !decision.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(decision.RetryAfter.Seconds())))
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitGroup определяет группу ограничения для запроса.
+func rateLimitGroup(r *http.Request) string {
+	if heavyEndpoints[routeEndpoint(r)] {
+		return services.RateLimitHeavy
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return services.RateLimitRead
+	default:
+		return services.RateLimitWrite
+	}
+}
+
+// clientIP возвращает адрес клиента. За прокси берётся первый адрес X-Forwarded-For:
+// без trustProxy заголовок не учитывается, потому что клиент может подставить любой.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
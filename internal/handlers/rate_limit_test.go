@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// failingRateLimitService имитирует недоступное хранилище корзин.
+type failingRateLimitService struct{}
+
+func (failingRateLimitService) Allow(ctx context.Context, group, subject string, anonymous bool) (services.RateLimitDecision, error) {
+	return services.RateLimitDecision{}, errors.New("connection refused")
+}
+
+func (failingRateLimitService) SetRules(rules services.RateLimitRules) {}
+
+func (failingRateLimitService) RunPurger(ctx context.Context, interval time.Duration) {}
+
+func newRateLimitRouter(service services.RateLimitService, trustProxy bool) *mux.Router {
+	users := new(MockUserService)
+	users.On("GetByKey", mock.Anything, "key123").Return(models.User{ID: 7}, nil).Maybe()
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	router := mux.NewRouter()
+	router.Use(handlers.IdentityMiddleware(users))
+	router.Use(handlers.RateLimitMiddleware(service, trustProxy))
+	router.HandleFunc("/tasks", ok).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc("/tasks/bulk", ok).Methods(http.MethodPost)
+	router.HandleFunc("/healthz", ok).Methods(http.MethodGet)
+
+	return router
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	service := services.NewRateLimitService(services.NewMemoryRateLimitStore(), services.RateLimitRules{
+		Read:  services.RateLimitRule{Requests: 100, Period: time.Minute},
+		Heavy: services.RateLimitRule{Requests: 1, Period: time.Minute},
+	})
+	router := newRateLimitRouter(service, false)
+
+	send := func(method, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := send(http.MethodPost, "/tasks/bulk", "key123")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get(handlers.RateLimitLimitHeader))
+	assert.Equal(t, "0", rr.Header().Get(handlers.RateLimitRemainingHeader))
+	assert.Equal(t, "60", rr.Header().Get(handlers.RateLimitResetHeader))
+	assert.Equal(t, "1;w=60", rr.Header().Get(handlers.RateLimitPolicyHeader))
+
+	// Тяжёлые маршруты исчерпаны, чтение считается отдельно
+	rr = send(http.MethodPost, "/tasks/bulk", "key123")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "Too Many Requests\n", rr.Body.String())
+
+	rr = send(http.MethodGet, "/tasks", "key123")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "99", rr.Header().Get(handlers.RateLimitRemainingHeader))
+
+	// Для изменений ограничение не задано
+	rr = send(http.MethodPost, "/tasks", "key123")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get(handlers.RateLimitLimitHeader))
+
+	// Пробы не ограничиваются
+	rr = send(http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get(handlers.RateLimitLimitHeader))
+}
+
+func TestRateLimitMiddleware_Anonymous(t *testing.T) {
+	service := services.NewRateLimitService(services.NewMemoryRateLimitStore(), services.RateLimitRules{
+		Anonymous: services.RateLimitRule{Requests: 1, Period: time.Minute},
+	})
+
+	send := func(router *mux.Router, remoteAddr, forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.RemoteAddr = remoteAddr
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	// Без доверия к прокси X-Forwarded-For не помогает обойти ограничение
+	router := newRateLimitRouter(service, false)
+	assert.Equal(t, http.StatusOK, send(router, "10.0.0.1:1234", "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, send(router, "10.0.0.1:1234", "203.0.113.2"))
+	assert.Equal(t, http.StatusOK, send(router, "10.0.0.2:1234", ""))
+
+	// За прокси клиенты различаются по первому адресу X-Forwarded-For
+	router = newRateLimitRouter(service, true)
+	assert.Equal(t, http.StatusOK, send(router, "10.0.0.9:1234", "203.0.113.7, 10.0.0.9"))
+	assert.Equal(t, http.StatusOK, send(router, "10.0.0.9:1234", "203.0.113.8"))
+	assert.Equal(t, http.StatusTooManyRequests, send(router, "10.0.0.9:1234", "203.0.113.7"))
+}
+
+func TestRateLimitMiddleware_StoreError(t *testing.T) {
+	router := newRateLimitRouter(failingRateLimitService{}, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	// Недоступное хранилище не должно останавливать сервис
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// errTrailingJSON - после JSON-значения в теле есть что-то ещё.
+var errTrailingJSON = errors.New("unexpected data after JSON value")
+
+// decodeJSON читает из body ровно одно JSON-значение в v. Неизвестные поля и данные
+// после значения - ошибка: опечатка в имени поля не должна молча теряться. Пустое
+// тело возвращает io.EOF.
+func decodeJSON(body io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		if err == nil {
+			err = errTrailingJSON
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// writeBodyError отвечает на ошибку чтения тела: 413, если тело больше предела,
+// иначе 400. Вызывается и с nil, когда тело прочитано, но не прошло проверку.
+func writeBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	http.Error(w, "Invalid request body", http.StatusBadRequest)
+}
+
+// limitedBody ограничивает тело как http.MaxBytesReader и запоминает превышение предела:
+// разборщики файлов импорта не всегда сохраняют исходную ошибку чтения в цепочке.
+type limitedBody struct {
+	io.ReadCloser
+	err error // *http.MaxBytesError, если тело больше предела
+}
+
+func limitBody(w http.ResponseWriter, body io.ReadCloser, limit int64) *limitedBody {
+	return &limitedBody{ReadCloser: http.MaxBytesReader(w, body, limit)}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.err = err
+	}
+
+	return n, err
+}
+
+// BodyLimits - пределы размера тела запроса в байтах.
+type BodyLimits struct {
+	Default int64            // Для всех маршрутов, кроме перечисленных в Routes
+	Routes  map[string]int64 // "METHOD шаблон" (как в IdempotencyMiddleware) -> предел
+}
+
+// selfLimitedEndpoints - маршруты, которые сами ограничивают чтение тела своими
+// пределами: вложения (storage.max_size), импорт задач и миграция из других сервисов.
+var selfLimitedEndpoints = map[string]bool{
+	"POST /tasks/{id}/attachments":               true,
+	"POST /tasks/import":                         true,
+	"POST /imports/{source:trello|todoist|jira}": true,
+}
+
+// BodyLimiter ограничивает размер тела запроса по маршрутам. Пределы меняются на лету.
+type BodyLimiter struct {
+	limits atomic.Pointer[BodyLimits]
+}
+
+func NewBodyLimiter(limits BodyLimits) *BodyLimiter {
+	l := &BodyLimiter{}
+	l.SetLimits(limits)
+
+	return l
+}
+
+func (l *BodyLimiter) SetLimits(limits BodyLimits) {
+	l.limits.Store(&limits)
+}
+
+// Middleware отвечает 413 на тело с заведомо большим Content-Length, остальные тела
+// оборачивает в http.MaxBytesReader: чтение сверх предела вернёт *http.MaxBytesError.
+func (l *BodyLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := routeEndpoint(r)
+		if r.Body == nil || r.Body == http.NoBody || selfLimitedEndpoints[endpoint] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limits := l.limits.Load()
+
+		limit, ok := limits.Routes[endpoint]
+		if !ok {
+			limit = limits.Default
+		}
+
+		if limit <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.ContentLength > limit {
+			http.Error(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,105 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_CreateTask_StrictJSON(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	for _, body := range []string{
+		`{"name": "Task", "stauts": "Pending"}`,
+		`{"name": "Task"} {"name": "Another"}`,
+		`{"name": "Task"} garbage`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+		req = req.WithContext(handlers.WithUserID(req.Context(), 5))
+		rr := httptest.NewRecorder()
+
+		handler.CreateTask(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+		assert.Equal(t, "Invalid request body\n", rr.Body.String())
+	}
+
+	// Пробелы и перевод строки после значения допустимы
+	mockService.On("Create", mock.Anything, models.Task{Name: "Task"}).Return(models.Task{ID: 1, Name: "Task"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader("{\"name\": \"Task\"}\n  "))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 5))
+	rr := httptest.NewRecorder()
+
+	handler.CreateTask(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBodyLimiter(t *testing.T) {
+	mockService := new(MockTaskService)
+	mockService.On("Bulk", mock.Anything, mock.Anything).Return(models.BulkResponse{}, nil)
+
+	var read int64
+
+	limiter := handlers.NewBodyLimiter(handlers.BodyLimits{Default: 64, Routes: map[string]int64{"POST /tasks/bulk": 1024}})
+
+	router := mux.NewRouter()
+	router.Use(limiter.Middleware)
+	handlers.RegisterTaskRoutes(router, handlers.NewHandler(mockService))
+	router.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		read, _ = io.Copy(io.Discard, r.Body)
+	}).Methods(http.MethodPut)
+
+	send := func(path, body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req = req.WithContext(handlers.WithUserID(req.Context(), 5))
+		if path == "/echo" {
+			req.Method = http.MethodPut
+		}
+
+		if chunked {
+			req.ContentLength = -1
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	large := `{"name": "` + strings.Repeat("x", 100) + `"}`
+
+	// Заявленный размер больше предела - отказ до чтения
+	rr := send("/tasks", large, false)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	// Без Content-Length предел срабатывает при чтении
+	rr = send("/tasks", large, true)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Equal(t, "Request body is too large\n", rr.Body.String())
+
+	send("/echo", strings.Repeat("x", 100), true)
+	assert.Equal(t, int64(64), read)
+
+	// У пакетных операций свой предел
+	rr = send("/tasks/bulk", `{"operations": [{"op": "delete", "id": 1}]}`+strings.Repeat(" ", 100), false)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Пределы меняются на лету
+	mockService.On("Create", mock.Anything, mock.Anything).Return(models.Task{ID: 1}, nil)
+	limiter.SetLimits(handlers.BodyLimits{Default: 1 << 20})
+
+	rr = send("/tasks", large, false)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityOptions задаёт заголовки безопасности ответов.
+type SecurityOptions struct {
+	HSTSMaxAge            time.Duration // Strict-Transport-Security; 0 - не отправлять
+	ContentSecurityPolicy string        // Для ответов text/html; пусто - не отправлять
+	FrameOptions          string        // X-Frame-Options: DENY или SAMEORIGIN
+}
+
+// SecurityHeadersMiddleware добавляет к каждому ответу X-Content-Type-Options: nosniff,
+// X-Frame-Options, Referrer-Policy и Strict-Transport-Security (браузеры учитывают его
+// только по HTTPS). HTML-ответы получают ещё Content-Security-Policy. Оборачивает весь
+// маршрутизатор, чтобы заголовки были и у ответов 404 и 405.
+func SecurityHeadersMiddleware(options SecurityOptions) func(http.Handler) http.Handler {
+	hsts := ""
+	if options.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(options.HSTSMaxAge.Seconds()))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("Referrer-Policy", "no-referrer")
+
+			if options.FrameOptions != "" {
+				header.Set("X-Frame-Options", options.FrameOptions)
+			}
+
+			if hsts != "" {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+
+			if options.ContentSecurityPolicy != "" {
+				w = &cspWriter{ResponseWriter: w, policy: options.ContentSecurityPolicy}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// cspWriter добавляет Content-Security-Policy, если ответ оказался HTML. Тип известен
+// только к отправке заголовков, поэтому проверка - в WriteHeader.
+type cspWriter struct {
+	http.ResponseWriter
+	policy      string
+	wroteHeader bool
+}
+
+func (w *cspWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+
+		if strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+			w.Header().Set("Content-Security-Policy", w.policy)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cspWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		// Без Content-Type net/http определит тип по содержимому - делаем это сами,
+		// чтобы не пропустить HTML
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(data))
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(data)
+}
+
+// Unwrap даёт http.ResponseController доступ к Flush и дедлайнам исходного ответа.
+func (w *cspWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *cspWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
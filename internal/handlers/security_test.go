@@ -0,0 +1,50 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	middleware := handlers.SecurityHeadersMiddleware(handlers.SecurityOptions{
+		HSTSMaxAge:            180 * 24 * time.Hour,
+		ContentSecurityPolicy: "default-src 'none'",
+		FrameOptions:          "DENY",
+	})
+
+	serve := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		middleware(handler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		return rr
+	}
+
+	rr := serve(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", rr.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", rr.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "max-age=15552000", rr.Header().Get("Strict-Transport-Security"))
+	assert.Empty(t, rr.Header().Get("Content-Security-Policy"))
+
+	// HTML получает CSP, даже если тип определяется по содержимому
+	rr = serve(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<!DOCTYPE html><html></html>"))
+	})
+	assert.Equal(t, "default-src 'none'", rr.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "<!DOCTYPE html><html></html>", rr.Body.String())
+
+	rr = serve(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, "default-src 'none'", rr.Header().Get("Content-Security-Policy"))
+}
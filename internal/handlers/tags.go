@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type TagHandler struct {
+	service services.TagService
+}
+
+type tagsPayload struct {
+	Tags []string `json:"tags"`
+}
+
+func NewTagHandler(service services.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+func RegisterTagRoutes(router *mux.Router, handler *TagHandler) {
+	router.HandleFunc("/tasks/{id}/tags", handler.GetTags).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id}/tags", handler.SetTags).Methods(http.MethodPut)
+}
+
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	tags, err := h.service.GetByTask(r.Context(), taskID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tagsPayload{Tags: tags})
+}
+
+func (h *TagHandler) SetTags(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var payload tagsPayload
+	if err := decodeJSON(r.Body, &payload); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	tags, err := h.service.Set(r.Context(), taskID, payload.Tags)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tagsPayload{Tags: tags})
+}
+
+func (h *TagHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidTag):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to process tags", http.StatusInternalServerError)
+	}
+}
+
+func (h *TagHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTagService - мок для интерфейса TagService
+type MockTagService struct {
+	mock.Mock
+}
+
+func (m *MockTagService) GetByTask(ctx context.Context, taskID int) ([]string, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTagService) Set(ctx context.Context, taskID int, tags []string) ([]string, error) {
+	args := m.Called(ctx, taskID, tags)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestTagHandler_GetTags(t *testing.T) {
+	mockService := new(MockTagService)
+	handler := handlers.NewTagHandler(mockService)
+
+	mockService.On("GetByTask", mock.Anything, 1).Return([]string{"backend"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/1/tags", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.GetTags(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"tags":["backend"]}`, rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTagHandler_SetTags(t *testing.T) {
+	mockService := new(MockTagService)
+	handler := handlers.NewTagHandler(mockService)
+
+	mockService.On("Set", mock.Anything, 1, []string{"#Backend"}).Return([]string{"backend"}, nil)
+	mockService.On("Set", mock.Anything, 2, []string{"a b"}).Return([]string(nil), services.ErrInvalidTag)
+
+	req := httptest.NewRequest(http.MethodPut, "/tasks/1/tags", bytes.NewReader([]byte(`{"tags":["#Backend"]}`)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.SetTags(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"tags":["backend"]}`, rr.Body.String())
+
+	// Некорректный тег
+	req = httptest.NewRequest(http.MethodPut, "/tasks/2/tags", bytes.NewReader([]byte(`{"tags":["a b"]}`)))
+	req = mux.SetURLVars(req, map[string]string{"id": "2"})
+	rr = httptest.NewRecorder()
+
+	handler.SetTags(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
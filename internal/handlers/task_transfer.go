@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxImportBytes = 10 << 20
+
+// ExportTasks выгружает задачи текущего пользователя в CSV, JSON или NDJSON по мере
+// чтения из базы. Параметры отбора те же, что у GET /tasks, кроме user_id. Время задач
+// выгружается в часовом поясе пользователя, как и в остальных ответах.
+func (h *Handler) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = models.TransferCSV
+	}
+
+	exporter := newTaskExporter(format, w)
+	if exporter == nil {
+		http.Error(w, "Format must be csv, json or ndjson", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := services.ParseTaskFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter = filter.ForUser(userID)
+	filter.UserID = userID
+
+	// Заголовки отправляются с первой задачей, чтобы ошибки фильтра ещё можно было вернуть статусом
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+
+		started = true
+
+		w.Header().Set("Content-Type", exporter.contentType())
+		w.Header().Set("Content-Disposition", `attachment; filename="tasks.`+format+`"`)
+		w.WriteHeader(http.StatusOK)
+
+		return exporter.begin()
+	}
+
+	location := LocationFromContext(r.Context())
+
+	err = h.service.Export(r.Context(), filter, func(task models.Task) error {
+		if err := start(); err != nil {
+			return err
+		}
+
+		localizeTask(&task, location)
+
+		return exporter.write(task)
+	})
+	if err != nil {
+		if started {
+			slog.ErrorContext(r.Context(), "task export failed", "error", err)
+			return
+		}
+
+		switch {
+		case errors.Is(err, services.ErrInvalidTaskFilter):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to export tasks", http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	if err := start(); err == nil {
+		err = exporter.end()
+	}
+
+	if err != nil {
+		slog.ErrorContext(r.Context(), "task export failed", "error", err)
+	}
+}
+
+// ImportTasks загружает задачи из CSV, JSON или .ics (VTODO) от имени текущего пользователя.
+// Параметры: format=csv|json|ics (по умолчанию по Content-Type), dry_run=true,
+// map.<поле>=<колонка CSV>. Ответ 422 содержит ошибки по строкам.
+func (h *Handler) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	options := models.ImportOptions{
+		Format:  query.Get("format"),
+		Mapping: make(map[string]string),
+		UserID:  userID,
+	}
+
+	if options.Format == "" {
+		switch contentType := r.Header.Get("Content-Type"); {
+		case strings.HasPrefix(contentType, "application/json"):
+			options.Format = models.TransferJSON
+		case strings.HasPrefix(contentType, "text/calendar"):
+			options.Format = models.TransferICS
+		default:
+			options.Format = models.TransferCSV
+		}
+	}
+
+	if value := query.Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid dry_run", http.StatusBadRequest)
+			return
+		}
+
+		options.DryRun = dryRun
+	}
+
+	for key, values := range query {
+		if field, ok := strings.CutPrefix(key, "map."); ok {
+			options.Mapping[field] = values[0]
+		}
+	}
+
+	body := limitBody(w, r.Body, maxImportBytes)
+
+	report, err := h.service.Import(r.Context(), options, body)
+	if err != nil {
+		if body.err != nil {
+			writeBodyError(w, body.err)
+			return
+		}
+
+		if errors.Is(err, services.ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, "Failed to import tasks", http.StatusInternalServerError)
+
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	} else if report.Imported > 0 {
+		status = http.StatusCreated
+	}
+
+	h.writeJSON(w, r, status, report)
+}
+
+// taskExporter записывает задачи в ответ в одном из форматов выгрузки.
+type taskExporter interface {
+	contentType() string
+	begin() error
+	write(task models.Task) error
+	end() error
+}
+
+func newTaskExporter(format string, w http.ResponseWriter) taskExporter {
+	switch format {
+	case models.TransferCSV:
+		return &csvExporter{writer: csv.NewWriter(w)}
+	case models.TransferJSON:
+		return &jsonExporter{w: w, encoder: json.NewEncoder(w)}
+	case models.TransferNDJSON:
+		return &ndjsonExporter{encoder: json.NewEncoder(w)}
+	}
+
+	return nil
+}
+
+// csvExportHeader - колонки выгрузки CSV; их же понимает POST /tasks/import.
+var csvExportHeader = []string{
+	"id", "name", "status", "time", "due", "user_id", "priority",
+	"estimate_minutes", "project_id", "parent_id", "custom_fields",
+}
+
+type csvExporter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+func (e *csvExporter) contentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvExporter) begin() error {
+	return e.writer.Write(csvExportHeader)
+}
+
+func (e *csvExporter) write(task models.Task) error {
+	customFields := ""
+
+	if len(task.CustomFields) > 0 {
+		encoded, err := json.Marshal(task.CustomFields)
+		if err != nil {
+			return err
+		}
+
+		customFields = string(encoded)
+	}
+
+	err := e.writer.Write([]string{
+		strconv.Itoa(task.ID),
+		task.Name,
+		task.Status,
+		formatExportTime(task.Time),
+		formatExportTime(task.Due),
+		strconv.Itoa(task.UserID),
+		task.Priority,
+		strconv.Itoa(task.EstimateMinutes),
+		strconv.Itoa(task.ProjectID),
+		strconv.Itoa(task.ParentID),
+		customFields,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Сбрасываем буфер порциями, чтобы клиент получал данные по ходу выгрузки
+	if e.rows++; e.rows%100 == 0 {
+		e.writer.Flush()
+		return e.writer.Error()
+	}
+
+	return nil
+}
+
+func (e *csvExporter) end() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func formatExportTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.Format(time.RFC3339)
+}
+
+type jsonExporter struct {
+	w       http.ResponseWriter
+	encoder *json.Encoder
+	rows    int
+}
+
+func (e *jsonExporter) contentType() string { return "application/json" }
+
+func (e *jsonExporter) begin() error {
+	_, err := e.w.Write([]byte("["))
+	return err
+}
+
+func (e *jsonExporter) write(task models.Task) error {
+	if e.rows > 0 {
+		if _, err := e.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+
+	e.rows++
+
+	return e.encoder.Encode(task)
+}
+
+func (e *jsonExporter) end() error {
+	_, err := e.w.Write([]byte("]\n"))
+	return err
+}
+
+type ndjsonExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExporter) contentType() string { return "application/x-ndjson" }
+
+func (e *ndjsonExporter) begin() error { return nil }
+
+func (e *ndjsonExporter) write(task models.Task) error {
+	return e.encoder.Encode(task)
+}
+
+func (e *ndjsonExporter) end() error { return nil }
@@ -0,0 +1,156 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandler_ExportTasks(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	due := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+	tasks := []models.Task{
+		{ID: 1, Name: "Deploy", Status: "Pending", Due: due, UserID: 7, CustomFields: models.CustomValues{"stage": "build"}},
+		{ID: 2, Name: "Review, then merge", Status: "Completed", UserID: 7},
+	}
+
+	mockService.On("Export", mock.Anything, models.TaskFilter{UserID: 7, Status: "Pending"}, mock.Anything).Return(tasks, nil)
+	mockService.On("Export", mock.Anything, models.TaskFilter{UserID: 7}, mock.Anything).Return(tasks, nil)
+
+	export := func(query string) *httptest.ResponseRecorder {
+		// user_id другого пользователя заменяется текущим
+		req := httptest.NewRequest(http.MethodGet, "/tasks/export?"+query, nil)
+		req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+		rr := httptest.NewRecorder()
+
+		handler.ExportTasks(rr, req)
+
+		return rr
+	}
+
+	rr := export("status=Pending&user_id=3")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "id,name,status,time,due,user_id,priority,estimate_minutes,project_id,parent_id,custom_fields\n"+
+		`1,Deploy,Pending,,2030-01-02T15:00:00Z,7,,0,0,0,"{""stage"":""build""}"`+"\n"+
+		`2,"Review, then merge",Completed,,,7,,0,0,0,`+"\n", rr.Body.String())
+
+	rr = export("format=ndjson")
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(rr.Body.String()), "\n"), 2)
+
+	rr = export("format=json")
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), `[{"id":1`))
+	assert.Contains(t, rr.Body.String(), `,{"id":2`)
+
+	assert.Equal(t, http.StatusBadRequest, export("format=xml").Code)
+
+	// Время выгружается в часовом поясе пользователя
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/export?format=ndjson", nil)
+	req = req.WithContext(handlers.WithUserLocation(handlers.WithUserID(req.Context(), 7), moscow))
+	rr = httptest.NewRecorder()
+
+	handler.ExportTasks(rr, req)
+	assert.Contains(t, rr.Body.String(), `"due":"2030-01-02T18:00:00+03:00"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_ExportTasks_Errors(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	mockService.On("Export", mock.Anything, mock.Anything, mock.Anything).Return(nil, services.ErrInvalidTaskFilter)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/export?format=json", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ExportTasks(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr = httptest.NewRecorder()
+
+	handler.ExportTasks(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.NotEqual(t, "application/json", rr.Header().Get("Content-Type"))
+}
+
+func TestHandler_ImportTasks(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	csvOptions := models.ImportOptions{
+		Format:  models.TransferCSV,
+		Mapping: map[string]string{"name": "Title"},
+		DryRun:  true,
+		UserID:  7,
+	}
+	jsonOptions := models.ImportOptions{Format: models.TransferJSON, Mapping: map[string]string{}, UserID: 7}
+
+	mockService.On("Import", mock.Anything, csvOptions, mock.Anything).
+		Return(models.ImportReport{DryRun: true, Total: 1}, nil)
+	mockService.On("Import", mock.Anything, jsonOptions, mock.Anything).
+		Return(models.ImportReport{Total: 2, Errors: []models.ImportRowError{{Row: 2, Error: "task name is required"}}}, nil)
+
+	importTasks := func(target, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+		rr := httptest.NewRecorder()
+
+		handler.ImportTasks(rr, req)
+
+		return rr
+	}
+
+	rr := importTasks("/tasks/import?dry_run=true&map.name=Title", "text/csv", "Title\nA\n")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Формат определяется по Content-Type
+	rr = importTasks("/tasks/import", "application/json", `[{"name":"A"},{"name":""}]`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"row":2`)
+
+	rr = importTasks("/tasks/import?dry_run=maybe", "text/csv", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_ImportTasks_TooLarge(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	// Разборщик читает тело целиком, а ошибку чтения заворачивает без %w
+	mockService.On("Import", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { _, _ = io.ReadAll(args.Get(2).(io.Reader)) }).
+		Return(models.ImportReport{}, fmt.Errorf("%w: unreadable file", services.ErrInvalidImport))
+
+	body := strings.Repeat("a", 10<<20+1)
+	req := httptest.NewRequest(http.MethodPost, "/tasks/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.ImportTasks(rr, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	mockService.AssertExpectations(t)
+}
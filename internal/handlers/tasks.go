@@ -4,6 +4,7 @@ import (
 	"WebTasks/internal/models"
 	"WebTasks/internal/services"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -20,22 +21,50 @@ func NewHandler(service services.TaskService) *Handler {
 
 func RegisterTaskRoutes(router *mux.Router, handler *Handler) {
 	router.HandleFunc("/tasks", handler.GetTasks).Methods(http.MethodGet)
-	router.HandleFunc("/tasks/{id}", handler.GetTaskByID).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id:[0-9]+}", handler.GetTaskByID).Methods(http.MethodGet)
 	router.HandleFunc("/tasks", handler.CreateTask).Methods(http.MethodPost)
-	router.HandleFunc("/tasks/{id}", handler.UpdateTask).Methods(http.MethodPut)
-	router.HandleFunc("/tasks/{id}", handler.DeleteTask).Methods(http.MethodDelete)
+	router.HandleFunc("/tasks/{id:[0-9]+}", handler.UpdateTask).Methods(http.MethodPut)
+	router.HandleFunc("/tasks/{id:[0-9]+}", handler.DeleteTask).Methods(http.MethodDelete)
+	router.HandleFunc("/tasks/bulk", handler.BulkTasks).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/quick", handler.QuickAddTask).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/export", handler.ExportTasks).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/import", handler.ImportTasks).Methods(http.MethodPost)
 }
 
+// GetTasks отдаёт список задач, параметры фильтра описаны в services.ParseTaskFilter.
 func (h *Handler) GetTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	tasks, err := h.service.GetAll(ctx)
+	filter, err := services.ParseTaskFilter(r.URL.Query())
 	if err != nil {
-		http.Error(w, "Failed to fetch tasks", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// "assignee=me" и "watcher=me" относятся к владельцу API-ключа
+	if userID, ok := UserIDFromContext(ctx); ok {
+		filter = filter.ForUser(userID)
+	}
+
+	tasks, err := h.service.List(ctx, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTaskFilter):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectNotFound):
+			http.Error(w, "Project not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to fetch tasks", http.StatusInternalServerError)
+		}
+
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, tasks)
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, tasks)
 }
 
 func (h *Handler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
@@ -53,25 +82,36 @@ func (h *Handler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, task)
+	h.writeJSON(w, r, http.StatusOK, task)
 }
 
+// CreateTask требует известный API-ключ: по его владельцу считается квота задач.
 func (h *Handler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if _, ok := requireUserID(w, r); !ok {
+		return
+	}
+
 	var task models.Task
-	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeJSON(r.Body, &task); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
 	createdTask, err := h.service.Create(ctx, task)
 	if err != nil {
+		if errors.Is(err, services.ErrTaskQuotaExceeded) || errors.Is(err, services.ErrNotProjectMember) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
 		http.Error(w, err.Error(), http.StatusBadRequest)
+
 		return
 	}
 
-	h.writeJSON(w, http.StatusCreated, createdTask)
+	h.writeJSON(w, r, http.StatusCreated, createdTask)
 }
 
 func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
@@ -84,8 +124,8 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var task models.Task
-	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeJSON(r.Body, &task); err != nil {
+		writeBodyError(w, err)
 		return
 	}
 
@@ -93,11 +133,16 @@ func (h *Handler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 
 	updatedTask, err := h.service.Update(ctx, task)
 	if err != nil {
+		if errors.Is(err, services.ErrTaskQuotaExceeded) || errors.Is(err, services.ErrNotProjectMember) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, updatedTask)
+	h.writeJSON(w, r, http.StatusOK, updatedTask)
 }
 
 func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
@@ -118,12 +163,105 @@ func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// BulkTasks выполняет пакет операций над задачами. Ответ 200 - всё применено,
+// 422 - атомарный пакет отклонён целиком, 207 - в режиме best_effort часть операций не прошла.
+// Как и CreateTask, требует известный API-ключ.
+func (h *Handler) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if _, ok := requireUserID(w, r); !ok {
+		return
+	}
+
+	var request models.BulkRequest
+	if err := decodeJSON(r.Body, &request); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	response, err := h.service.Bulk(ctx, request)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulk) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, "Failed to apply bulk operations", http.StatusInternalServerError)
+
+		return
+	}
+
+	status := http.StatusOK
+
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+		if response.Mode == models.BulkModeAtomic {
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	h.writeJSON(w, r, status, response)
+}
+
+// QuickAddTask создаёт задачу текущего пользователя из строки вида "deploy api next friday #ops !high".
+// В ответе кроме задачи - разбор строки; с dry_run=true задача не сохраняется (ответ 200).
+func (h *Handler) QuickAddTask(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var request models.QuickAddRequest
+	if err := decodeJSON(r.Body, &request); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	request.UserID = userID
+
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid dry_run", http.StatusBadRequest)
+			return
+		}
+
+		request.DryRun = dryRun
+	}
+
+	result, err := h.service.QuickAdd(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidQuickAdd) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if errors.Is(err, services.ErrTaskQuotaExceeded) || errors.Is(err, services.ErrNotProjectMember) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		http.Error(w, "Failed to create task", http.StatusInternalServerError)
+
+		return
+	}
+
+	status := http.StatusCreated
+	if request.DryRun {
+		status = http.StatusOK
+	}
+
+	h.writeJSON(w, r, status, result)
+}
+
 func (h *Handler) parseID(r *http.Request) (int, error) {
 	idStr := mux.Vars(r)["id"]
 	return strconv.Atoi(idStr)
 }
 
-func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	data = localizeTasks(r.Context(), data)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
@@ -3,10 +3,12 @@ package handlers_test
 import (
 	"WebTasks/internal/handlers"
 	"WebTasks/internal/models"
+	"WebTasks/internal/services"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -36,6 +38,11 @@ func (m *MockTaskService) GetAll(ctx context.Context) ([]models.Task, error) {
 	return args.Get(0).([]models.Task), args.Error(1)
 }
 
+func (m *MockTaskService) List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
 func (m *MockTaskService) Update(ctx context.Context, task models.Task) (models.Task, error) {
 	args := m.Called(ctx, task)
 	return args.Get(0).(models.Task), args.Error(1)
@@ -46,6 +53,43 @@ func (m *MockTaskService) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *MockTaskService) Export(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) error {
+	args := m.Called(ctx, filter, fn)
+
+	if tasks, ok := args.Get(0).([]models.Task); ok {
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+	}
+
+	return args.Error(1)
+}
+
+func (m *MockTaskService) Import(ctx context.Context, options models.ImportOptions, body io.Reader) (models.ImportReport, error) {
+	args := m.Called(ctx, options, body)
+	return args.Get(0).(models.ImportReport), args.Error(1)
+}
+
+func (m *MockTaskService) Bulk(ctx context.Context, request models.BulkRequest) (models.BulkResponse, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(models.BulkResponse), args.Error(1)
+}
+
+func (m *MockTaskService) QuickAdd(ctx context.Context, request models.QuickAddRequest) (models.QuickAddResult, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(models.QuickAddResult), args.Error(1)
+}
+
+func (m *MockTaskService) ReserveTasks(ctx context.Context, tasks []models.Task) error {
+	return m.Called(ctx, tasks).Error(0)
+}
+
+func (m *MockTaskService) SetMaxTasks(maxTasks int) {
+	m.Called(maxTasks)
+}
+
 // --------------------------------------------------------------------------------------
 // ТЕСТЫ НА УСПЕШНОЕ ПОВЕДЕНИЕ
 // --------------------------------------------------------------------------------------
@@ -59,7 +103,7 @@ func TestHandler_GetTasks(t *testing.T) {
 		{ID: 2, Name: "Task 2", Status: "Completed"},
 	}
 
-	mockService.On("GetAll", mock.Anything).Return(expectedTasks, nil)
+	mockService.On("List", mock.Anything, models.TaskFilter{}).Return(expectedTasks, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
 	rr := httptest.NewRecorder()
@@ -112,6 +156,7 @@ func TestHandler_CreateTask(t *testing.T) {
 
 	body, _ := json.Marshal(inputTask)
 	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 5))
 	rr := httptest.NewRecorder()
 
 	handler.CreateTask(rr, req)
@@ -178,7 +223,7 @@ func TestHandler_GetTasks_ServiceError(t *testing.T) {
 	mockService := new(MockTaskService)
 	handler := handlers.NewHandler(mockService)
 
-	mockService.On("GetAll", mock.Anything).Return([]models.Task{}, errors.New("database error"))
+	mockService.On("List", mock.Anything, models.TaskFilter{}).Return([]models.Task{}, errors.New("database error"))
 
 	req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
 	rr := httptest.NewRecorder()
@@ -233,6 +278,7 @@ func TestHandler_CreateTask_InvalidJSON(t *testing.T) {
 	// Намеренно некорректный JSON (пропущена кавычка или скобка)
 	body := []byte(`{"Name":"Bad Task", "Status":`)
 	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 5))
 	rr := httptest.NewRecorder()
 
 	handler.CreateTask(rr, req)
@@ -254,6 +300,7 @@ func TestHandler_CreateTask_ServiceError(t *testing.T) {
 
 	body, _ := json.Marshal(inputTask)
 	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 5))
 	rr := httptest.NewRecorder()
 
 	handler.CreateTask(rr, req)
@@ -264,6 +311,97 @@ func TestHandler_CreateTask_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestHandler_CreateTask_QuotaExceeded(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	inputTask := models.Task{Name: "New Task", Status: "Pending", UserID: 5}
+
+	mockService.On("Create", mock.Anything, inputTask).Return(models.Task{}, services.ErrTaskQuotaExceeded)
+
+	body, _ := json.Marshal(inputTask)
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 5))
+	rr := httptest.NewRecorder()
+
+	handler.CreateTask(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "task quota exceeded")
+
+	mockService.AssertExpectations(t)
+}
+
+// Квота считается по владельцу ключа, поэтому с неизвестным ключом задачи не создаются вовсе:
+// иначе запросы с "Authorization: garbage" обходили бы quota.max_tasks.
+func TestTaskRoutes_UnknownKeyCannotCreate(t *testing.T) {
+	mockService := new(MockTaskService)
+	users := new(MockUserService)
+	users.On("GetByKey", mock.Anything, "garbage").Return(models.User{}, errors.New("not found"))
+
+	router := mux.NewRouter()
+	router.Use(handlers.AuthMiddleware)
+	router.Use(handlers.IdentityMiddleware(users))
+	handlers.RegisterTaskRoutes(router, handlers.NewHandler(mockService))
+
+	for _, path := range []string{"/tasks", "/tasks/bulk", "/tasks/quick", "/tasks/import?format=json"} {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(`{"name": "Task"}`)))
+		req.Header.Set("Authorization", "garbage")
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, path)
+	}
+
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "Bulk", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "QuickAdd", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandler_CreateTask_NotProjectMember(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	inputTask := models.Task{Name: "New Task", UserID: 5, ProjectID: 3}
+
+	mockService.On("Create", mock.Anything, inputTask).Return(models.Task{}, services.ErrNotProjectMember)
+
+	body, _ := json.Marshal(inputTask)
+	req := httptest.NewRequest(http.MethodPost, "/tasks", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 5))
+	rr := httptest.NewRecorder()
+
+	handler.CreateTask(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "not a member of the project")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_UpdateTask_QuotaExceeded(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	inputTask := models.Task{ID: 1, Name: "Reopened", Status: "Pending"}
+
+	mockService.On("Update", mock.Anything, inputTask).Return(models.Task{}, services.ErrTaskQuotaExceeded)
+
+	body, _ := json.Marshal(inputTask)
+	req := httptest.NewRequest(http.MethodPut, "/tasks/1", bytes.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.UpdateTask(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "task quota exceeded")
+
+	mockService.AssertExpectations(t)
+}
+
 func TestHandler_UpdateTask_InvalidID(t *testing.T) {
 	mockService := new(MockTaskService)
 	handler := handlers.NewHandler(mockService)
@@ -351,3 +489,145 @@ func TestHandler_DeleteTask_NotFound(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestHandler_GetTasks_Filter(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	filter := models.TaskFilter{
+		ProjectID: 1,
+		Status:    "Pending",
+		Custom:    map[string]string{"stage": "build"},
+		SortBy:    "cf.points",
+		Desc:      true,
+		Limit:     5,
+	}
+
+	mockService.On("List", mock.Anything, filter).Return([]models.Task{{ID: 3}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?project_id=1&status=Pending&cf.stage=build&sort=cf.points&order=desc&limit=5", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetTasks(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Некорректные параметры отклоняются до обращения к сервису
+	for _, query := range []string{"project_id=abc", "order=up"} {
+		req = httptest.NewRequest(http.MethodGet, "/tasks?"+query, nil)
+		rr = httptest.NewRecorder()
+
+		handler.GetTasks(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_GetTasks_AssigneeMe(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	mockService.On("List", mock.Anything, models.TaskFilter{AssigneeID: 7, WatcherID: 2}).Return([]models.Task{{ID: 1}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks?assignee=me&watcher=2", nil)
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.GetTasks(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Отрицательный ID не превращается в "me"
+	req = httptest.NewRequest(http.MethodGet, "/tasks?assignee=-1", nil)
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr = httptest.NewRecorder()
+
+	handler.GetTasks(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_BulkTasks(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	atomic := models.BulkRequest{Operations: []models.BulkOperation{{Op: models.BulkDelete, ID: 1}}}
+	bestEffort := models.BulkRequest{Mode: models.BulkModeBestEffort, Operations: []models.BulkOperation{{Op: models.BulkDelete, ID: 2}}}
+	invalid := models.BulkRequest{Mode: "eventually"}
+
+	mockService.On("Bulk", mock.Anything, atomic).
+		Return(models.BulkResponse{Mode: models.BulkModeAtomic, Failed: 1}, nil)
+	mockService.On("Bulk", mock.Anything, bestEffort).
+		Return(models.BulkResponse{Mode: models.BulkModeBestEffort, Succeeded: 1, Failed: 1}, nil)
+	mockService.On("Bulk", mock.Anything, invalid).
+		Return(models.BulkResponse{}, services.ErrInvalidBulk)
+
+	cases := []struct {
+		request models.BulkRequest
+		status  int
+	}{
+		{atomic, http.StatusUnprocessableEntity},
+		{bestEffort, http.StatusMultiStatus},
+		{invalid, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		body, _ := json.Marshal(tc.request)
+		req := httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewReader(body))
+		req = req.WithContext(handlers.WithUserID(req.Context(), 5))
+		rr := httptest.NewRecorder()
+
+		handler.BulkTasks(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_QuickAddTask(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := handlers.NewHandler(mockService)
+
+	created := models.QuickAddRequest{Text: "deploy api next friday #ops", Timezone: "Europe/Moscow", UserID: 7}
+	preview := models.QuickAddRequest{Text: "deploy api", UserID: 7, DryRun: true}
+	invalid := models.QuickAddRequest{Text: "!high", UserID: 7}
+
+	mockService.On("QuickAdd", mock.Anything, created).
+		Return(models.QuickAddResult{Task: models.Task{ID: 1, Name: "deploy api"}, Tags: []string{"ops"}}, nil)
+	mockService.On("QuickAdd", mock.Anything, preview).
+		Return(models.QuickAddResult{Task: models.Task{Name: "deploy api"}}, nil)
+	mockService.On("QuickAdd", mock.Anything, invalid).
+		Return(models.QuickAddResult{}, services.ErrInvalidQuickAdd)
+
+	cases := []struct {
+		target string
+		body   string
+		status int
+	}{
+		{"/tasks/quick", `{"text": "deploy api next friday #ops", "timezone": "Europe/Moscow"}`, http.StatusCreated},
+		{"/tasks/quick?dry_run=true", `{"text": "deploy api"}`, http.StatusOK},
+		{"/tasks/quick", `{"text": "!high"}`, http.StatusBadRequest},
+		{"/tasks/quick?dry_run=maybe", `{"text": "deploy api"}`, http.StatusBadRequest},
+		{"/tasks/quick", `text`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewReader([]byte(tc.body)))
+		rr := httptest.NewRecorder()
+
+		handler.QuickAddTask(rr, req.WithContext(handlers.WithUserID(req.Context(), 7)))
+
+		assert.Equal(t, tc.status, rr.Code, tc.target)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.QuickAddTask(rr, httptest.NewRequest(http.MethodPost, "/tasks/quick", bytes.NewReader([]byte(`{"text": "x"}`))))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	service services.TemplateService
+}
+
+func NewTemplateHandler(service services.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+func RegisterTemplateRoutes(router *mux.Router, handler *TemplateHandler) {
+	router.HandleFunc("/templates", handler.GetTemplates).Methods(http.MethodGet)
+	router.HandleFunc("/templates", handler.CreateTemplate).Methods(http.MethodPost)
+	router.HandleFunc("/templates/{id:[0-9]+}", handler.GetTemplate).Methods(http.MethodGet)
+	router.HandleFunc("/templates/{id:[0-9]+}", handler.DeleteTemplate).Methods(http.MethodDelete)
+	router.HandleFunc("/templates/{id:[0-9]+}/instantiate", handler.Instantiate).Methods(http.MethodPost)
+}
+
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, templates)
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var template models.TaskTemplate
+	if err := decodeJSON(r.Body, &template); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	template.CreatedBy, _ = UserIDFromContext(r.Context())
+
+	created, err := h.service.Create(r.Context(), template)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, created)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, template)
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Instantiate создаёт задачи по шаблону. Тело необязательно:
+// {"variables": {"name": "..."}, "start": "2024-03-01T09:00:00Z"}. Задачи создаются от имени
+// владельца API-ключа и расходуют его квоту, поэтому неизвестный ключ получает 401.
+func (h *TemplateHandler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	var params models.TemplateInstantiation
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &params); err != nil {
+			writeBodyError(w, err)
+			return
+		}
+	}
+
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	params.UserID = userID
+
+	tasks, err := h.service.Instantiate(r.Context(), id, params)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, tasks)
+}
+
+func (h *TemplateHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrTemplateNotFound):
+		http.Error(w, "Template not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrTemplateExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidTemplate), errors.Is(err, services.ErrTemplateVariables):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrTaskQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, "Failed to process template request", http.StatusInternalServerError)
+	}
+}
+
+func (h *TemplateHandler) writeJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	data = localizeTasks(r.Context(), data)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
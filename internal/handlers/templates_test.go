@@ -0,0 +1,114 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTemplateService - мок для интерфейса TemplateService
+type MockTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockTemplateService) Create(ctx context.Context, template models.TaskTemplate) (models.TaskTemplate, error) {
+	args := m.Called(ctx, template)
+	return args.Get(0).(models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) GetByID(ctx context.Context, id int) (models.TaskTemplate, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) GetAll(ctx context.Context) ([]models.TaskTemplate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) Delete(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockTemplateService) Instantiate(ctx context.Context, id int, params models.TemplateInstantiation) ([]models.Task, error) {
+	args := m.Called(ctx, id, params)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	mockService := new(MockTemplateService)
+	handler := handlers.NewTemplateHandler(mockService)
+
+	expected := models.TaskTemplate{
+		Name:      "Deploy",
+		Task:      models.TemplateTask{Name: "Deploy {{version}}", Subtasks: []models.TemplateTask{{Name: "Migrate"}}},
+		CreatedBy: 7,
+	}
+	mockService.On("Create", mock.Anything, expected).Return(models.TaskTemplate{ID: 1, Name: "Deploy"}, nil)
+
+	body := []byte(`{"name":"Deploy","task":{"name":"Deploy {{version}}","subtasks":[{"name":"Migrate"}]}}`)
+	req := httptest.NewRequest(http.MethodPost, "/templates", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.CreateTemplate(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTemplateHandler_Instantiate(t *testing.T) {
+	mockService := new(MockTemplateService)
+	handler := handlers.NewTemplateHandler(mockService)
+
+	params := models.TemplateInstantiation{Variables: map[string]string{"version": "1.2"}, UserID: 5}
+	mockService.On("Instantiate", mock.Anything, 1, params).Return([]models.Task{{ID: 10}, {ID: 11, ParentID: 10}}, nil)
+	mockService.On("Instantiate", mock.Anything, 1, models.TemplateInstantiation{UserID: 5}).
+		Return([]models.Task{}, fmt.Errorf("%w: missing variables: version", services.ErrTemplateVariables))
+	mockService.On("Instantiate", mock.Anything, 2, models.TemplateInstantiation{UserID: 5}).
+		Return([]models.Task{}, services.ErrTemplateNotFound)
+	mockService.On("Instantiate", mock.Anything, 3, models.TemplateInstantiation{UserID: 5}).
+		Return([]models.Task{}, services.ErrTaskQuotaExceeded)
+
+	cases := []struct {
+		id     string
+		body   string
+		status int
+	}{
+		{"1", `{"variables":{"version":"1.2"}}`, http.StatusCreated},
+		{"1", ``, http.StatusBadRequest},
+		{"2", ``, http.StatusNotFound},
+		{"3", ``, http.StatusForbidden},
+		{"1", `{"variables":`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/templates/"+tc.id+"/instantiate", bytes.NewReader([]byte(tc.body)))
+		req = mux.SetURLVars(req.WithContext(handlers.WithUserID(req.Context(), 5)), map[string]string{"id": tc.id})
+		rr := httptest.NewRecorder()
+
+		handler.Instantiate(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc.body)
+	}
+
+	// Без известного ключа задачи не создаются: их автор расходует квоту
+	req := httptest.NewRequest(http.MethodPost, "/templates/1/instantiate", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.Instantiate(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertExpectations(t)
+}
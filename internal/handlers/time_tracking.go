@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type TimeTrackingHandler struct {
+	service services.TimeTrackingService
+}
+
+// worklogRequest - тело ручной записи: конец задаётся либо ended_at, либо minutes.
+type worklogRequest struct {
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note"`
+}
+
+type timerRequest struct {
+	Note string `json:"note"`
+}
+
+func NewTimeTrackingHandler(service services.TimeTrackingService) *TimeTrackingHandler {
+	return &TimeTrackingHandler{service: service}
+}
+
+func RegisterTimeTrackingRoutes(router *mux.Router, handler *TimeTrackingHandler) {
+	router.HandleFunc("/timer", handler.GetTimer).Methods(http.MethodGet)
+	router.HandleFunc("/timer/stop", handler.StopTimer).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/{id}/timer/start", handler.StartTimer).Methods(http.MethodPost)
+	router.HandleFunc("/tasks/{id}/worklogs", handler.GetWorklogs).Methods(http.MethodGet)
+	router.HandleFunc("/tasks/{id}/worklogs", handler.CreateWorklog).Methods(http.MethodPost)
+	router.HandleFunc("/worklogs/{id}", handler.DeleteWorklog).Methods(http.MethodDelete)
+	router.HandleFunc("/reports/time", handler.GetReport).Methods(http.MethodGet)
+}
+
+func (h *TimeTrackingHandler) GetTimer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.GetRunning(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeTrackingHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	// Тело необязательно: в нём можно передать только заметку
+	var body timerRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &body); err != nil {
+			writeBodyError(w, err)
+			return
+		}
+	}
+
+	entry, err := h.service.StartTimer(r.Context(), userID, taskID, body.Note)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *TimeTrackingHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.StopTimer(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeTrackingHandler) GetWorklogs(w http.ResponseWriter, r *http.Request) {
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.GetByTask(r.Context(), taskID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *TimeTrackingHandler) CreateWorklog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var body worklogRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if body.EndedAt == nil && body.Minutes > 0 {
+		ended := body.StartedAt.Add(time.Duration(body.Minutes) * time.Minute)
+		body.EndedAt = &ended
+	}
+
+	entry, err := h.service.LogWork(r.Context(), models.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: body.StartedAt,
+		EndedAt:   body.EndedAt,
+		Note:      body.Note,
+	})
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, entry)
+}
+
+func (h *TimeTrackingHandler) DeleteWorklog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid worklog ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReport отдаёт агрегированный отчёт в JSON (по умолчанию) или CSV (?format=csv).
+func (h *TimeTrackingHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseReportFilter(r.URL.Query(), LocationFromContext(r.Context()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "Unsupported format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.service.Report(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if format == "csv" {
+		h.writeCSV(w, rows)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, rows)
+}
+
+// parseReportFilter разбирает параметры отчёта. Даты в from, to и группировке date
+// относятся к поясу tz, по умолчанию - к поясу пользователя.
+func parseReportFilter(query url.Values, location *time.Location) (models.TimeReportFilter, error) {
+	filter := models.TimeReportFilter{GroupBy: query.Get("group_by")}
+
+	var err error
+
+	if value := query.Get("tz"); value != "" {
+		if location, err = time.LoadLocation(value); err != nil || value == "Local" {
+			return filter, errors.New("invalid tz")
+		}
+	}
+
+	filter.Timezone = location.String()
+
+	if filter.From, err = parseReportTime(query.Get("from"), location); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+
+	if filter.To, err = parseReportTime(query.Get("to"), location); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+
+	if value := query.Get("user_id"); value != "" {
+		if filter.UserID, err = strconv.Atoi(value); err != nil {
+			return filter, errors.New("invalid user_id")
+		}
+	}
+
+	if value := query.Get("task_id"); value != "" {
+		if filter.TaskID, err = strconv.Atoi(value); err != nil {
+			return filter, errors.New("invalid task_id")
+		}
+	}
+
+	return filter, nil
+}
+
+// parseReportTime принимает дату (2006-01-02, полночь в поясе location) или полную
+// метку времени RFC 3339.
+func parseReportTime(value string, location *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if parsed, err := time.ParseInLocation(time.DateOnly, value, location); err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func (h *TimeTrackingHandler) writeCSV(w http.ResponseWriter, rows []models.TimeReportRow) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="time-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"key", "label", "entries", "seconds", "hours"})
+
+	for _, row := range rows {
+		_ = writer.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.Itoa(row.Entries),
+			strconv.FormatInt(row.Seconds, 10),
+			strconv.FormatFloat(float64(row.Seconds)/3600, 'f', 2, 64),
+		})
+	}
+
+	writer.Flush()
+}
+
+func (h *TimeTrackingHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrTaskNotFound):
+		http.Error(w, "Task not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrTimeEntryNotFound):
+		http.Error(w, "Worklog not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrNoRunningTimer):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrTimerRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidTimeEntry), errors.Is(err, services.ErrInvalidReport):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to process time tracking request", http.StatusInternalServerError)
+	}
+}
+
+func (h *TimeTrackingHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
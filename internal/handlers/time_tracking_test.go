@@ -0,0 +1,195 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTimeTrackingService - мок для интерфейса TimeTrackingService
+type MockTimeTrackingService struct {
+	mock.Mock
+}
+
+func (m *MockTimeTrackingService) StartTimer(ctx context.Context, userID, taskID int, note string) (models.TimeEntry, error) {
+	args := m.Called(ctx, userID, taskID, note)
+	return args.Get(0).(models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeTrackingService) StopTimer(ctx context.Context, userID int) (models.TimeEntry, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeTrackingService) GetRunning(ctx context.Context, userID int) (models.TimeEntry, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeTrackingService) LogWork(ctx context.Context, entry models.TimeEntry) (models.TimeEntry, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeTrackingService) GetByTask(ctx context.Context, taskID int) ([]models.TimeEntry, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeTrackingService) Delete(ctx context.Context, userID, id int) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockTimeTrackingService) Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.TimeReportRow), args.Error(1)
+}
+
+func TestTimeTrackingHandler_StartTimer(t *testing.T) {
+	mockService := new(MockTimeTrackingService)
+	handler := handlers.NewTimeTrackingHandler(mockService)
+
+	mockService.On("StartTimer", mock.Anything, 7, 1, "review").Return(models.TimeEntry{ID: 3, TaskID: 1, UserID: 7}, nil)
+	mockService.On("StartTimer", mock.Anything, 7, 2, "").Return(models.TimeEntry{}, services.ErrTimerRunning)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/timer/start", bytes.NewReader([]byte(`{"note":"review"}`)))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.StartTimer(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	// Уже запущенный таймер
+	req = httptest.NewRequest(http.MethodPost, "/tasks/2/timer/start", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "2"})
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr = httptest.NewRecorder()
+
+	handler.StartTimer(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTimeTrackingHandler_StartTimer_Unauthorized(t *testing.T) {
+	mockService := new(MockTimeTrackingService)
+	handler := handlers.NewTimeTrackingHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/timer/start", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	rr := httptest.NewRecorder()
+
+	handler.StartTimer(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "StartTimer")
+}
+
+func TestTimeTrackingHandler_CreateWorklog_Minutes(t *testing.T) {
+	mockService := new(MockTimeTrackingService)
+	handler := handlers.NewTimeTrackingHandler(mockService)
+
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	expected := models.TimeEntry{TaskID: 1, UserID: 7, StartedAt: start, EndedAt: &end, Note: "call"}
+
+	mockService.On("LogWork", mock.Anything, expected).Return(models.TimeEntry{ID: 4}, nil)
+
+	body := []byte(`{"started_at":"2024-03-01T09:00:00Z","minutes":90,"note":"call"}`)
+	req := httptest.NewRequest(http.MethodPost, "/tasks/1/worklogs", bytes.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.CreateWorklog(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTimeTrackingHandler_GetReport_CSV(t *testing.T) {
+	mockService := new(MockTimeTrackingService)
+	handler := handlers.NewTimeTrackingHandler(mockService)
+
+	filter := models.TimeReportFilter{
+		GroupBy:  "user",
+		From:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Timezone: "UTC",
+	}
+	rows := []models.TimeReportRow{{Key: "7", Label: "Alice", Entries: 2, Seconds: 5400}}
+
+	mockService.On("Report", mock.Anything, filter).Return(rows, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/time?group_by=user&from=2024-03-01&to=2024-04-01&format=csv", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetReport(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "key,label,entries,seconds,hours\n7,Alice,2,5400,1.50\n", rr.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTimeTrackingHandler_GetReport_InvalidParams(t *testing.T) {
+	mockService := new(MockTimeTrackingService)
+	handler := handlers.NewTimeTrackingHandler(mockService)
+
+	for _, query := range []string{"from=yesterday", "format=xml", "user_id=abc", "tz=Mars/Olympus", "tz=Local"} {
+		req := httptest.NewRequest(http.MethodGet, "/reports/time?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		handler.GetReport(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+
+	mockService.AssertNotCalled(t, "Report")
+}
+
+func TestTimeTrackingHandler_GetReport_Timezone(t *testing.T) {
+	mockService := new(MockTimeTrackingService)
+	handler := handlers.NewTimeTrackingHandler(mockService)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	// Границы дат и группировка по дням - в поясе пользователя, tz его переопределяет
+	mockService.On("Report", mock.Anything, models.TimeReportFilter{
+		GroupBy:  "date",
+		From:     time.Date(2024, 3, 1, 0, 0, 0, 0, moscow),
+		Timezone: "Europe/Moscow",
+	}).Return([]models.TimeReportRow{}, nil).Once()
+	mockService.On("Report", mock.Anything, models.TimeReportFilter{
+		GroupBy:  "date",
+		From:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Timezone: "UTC",
+	}).Return([]models.TimeReportRow{}, nil).Once()
+
+	for _, query := range []string{"group_by=date&from=2024-03-01", "group_by=date&from=2024-03-01&tz=UTC"} {
+		req := httptest.NewRequest(http.MethodGet, "/reports/time?"+query, nil)
+		req = req.WithContext(handlers.WithUserLocation(req.Context(), moscow))
+		rr := httptest.NewRecorder()
+
+		handler.GetReport(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, query)
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -34,6 +34,11 @@ func (m *MockUserService) GetByID(ctx context.Context, id int) (models.User, err
 	return args.Get(0).(models.User), args.Error(1)
 }
 
+func (m *MockUserService) GetByKey(ctx context.Context, key string) (models.User, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
 func (m *MockUserService) Update(ctx context.Context, user models.User) (models.User, error) {
 	args := m.Called(ctx, user)
 	return args.Get(0).(models.User), args.Error(1)
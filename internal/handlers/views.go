@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type ViewHandler struct {
+	service services.ViewService
+}
+
+func NewViewHandler(service services.ViewService) *ViewHandler {
+	return &ViewHandler{service: service}
+}
+
+func RegisterViewRoutes(router *mux.Router, handler *ViewHandler) {
+	router.HandleFunc("/views", handler.GetViews).Methods(http.MethodGet)
+	router.HandleFunc("/views", handler.CreateView).Methods(http.MethodPost)
+	router.HandleFunc("/views/{id:[0-9]+}", handler.GetView).Methods(http.MethodGet)
+	router.HandleFunc("/views/{id:[0-9]+}", handler.UpdateView).Methods(http.MethodPut)
+	router.HandleFunc("/views/{id:[0-9]+}", handler.DeleteView).Methods(http.MethodDelete)
+	router.HandleFunc("/views/{id:[0-9]+}/tasks", handler.GetViewTasks).Methods(http.MethodGet)
+}
+
+func (h *ViewHandler) GetViews(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	views, err := h.service.GetAll(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, views)
+}
+
+func (h *ViewHandler) CreateView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var view models.SavedView
+	if err := decodeJSON(r.Body, &view); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	view.UserID = userID
+
+	created, err := h.service.Create(r.Context(), view)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, created)
+}
+
+func (h *ViewHandler) GetView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	view, err := h.service.GetByID(r.Context(), userID, id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, view)
+}
+
+func (h *ViewHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	var view models.SavedView
+	if err := decodeJSON(r.Body, &view); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	view.ID = id
+
+	updated, err := h.service.Update(r.Context(), userID, view)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, updated)
+}
+
+func (h *ViewHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), userID, id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetViewTasks выполняет сохранённый фильтр; параметры запроса переопределяют сохранённые.
+func (h *ViewHandler) GetViewTasks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid view ID", http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.service.Tasks(r.Context(), userID, id, r.URL.Query())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, tasks)
+}
+
+func (h *ViewHandler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrViewNotFound):
+		http.Error(w, "View not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrProjectNotFound):
+		http.Error(w, "Project not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrViewForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, services.ErrViewExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInvalidView), errors.Is(err, services.ErrInvalidTaskFilter):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to process view request", http.StatusInternalServerError)
+	}
+}
+
+func (h *ViewHandler) writeJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	data = localizeTasks(r.Context(), data)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockViewService - мок для интерфейса ViewService
+type MockViewService struct {
+	mock.Mock
+}
+
+func (m *MockViewService) Create(ctx context.Context, view models.SavedView) (models.SavedView, error) {
+	args := m.Called(ctx, view)
+	return args.Get(0).(models.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) GetByID(ctx context.Context, userID, id int) (models.SavedView, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(models.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) GetAll(ctx context.Context, userID int) ([]models.SavedView, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) Update(ctx context.Context, userID int, view models.SavedView) (models.SavedView, error) {
+	args := m.Called(ctx, userID, view)
+	return args.Get(0).(models.SavedView), args.Error(1)
+}
+
+func (m *MockViewService) Delete(ctx context.Context, userID, id int) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockViewService) Tasks(ctx context.Context, userID, id int, overrides url.Values) ([]models.Task, error) {
+	args := m.Called(ctx, userID, id, overrides)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func TestViewHandler_CreateView(t *testing.T) {
+	mockService := new(MockViewService)
+	handler := handlers.NewViewHandler(mockService)
+
+	view := models.SavedView{UserID: 7, Name: "Overdue", Query: "sort=due", Columns: []string{"name", "due"}}
+	mockService.On("Create", mock.Anything, view).Return(models.SavedView{ID: 1, UserID: 7, Name: "Overdue"}, nil)
+
+	body := []byte(`{"name":"Overdue","query":"sort=due","columns":["name","due"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/views", bytes.NewReader(body))
+	req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+
+	handler.CreateView(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	// Без известного пользователя вид не создаётся
+	req = httptest.NewRequest(http.MethodPost, "/views", bytes.NewReader(body))
+	rr = httptest.NewRecorder()
+
+	handler.CreateView(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestViewHandler_GetViewTasks(t *testing.T) {
+	mockService := new(MockViewService)
+	handler := handlers.NewViewHandler(mockService)
+
+	mockService.On("Tasks", mock.Anything, 7, 1, url.Values{"limit": {"5"}}).Return([]models.Task{{ID: 3}}, nil)
+	mockService.On("Tasks", mock.Anything, 7, 2, url.Values{}).Return([]models.Task(nil), services.ErrViewNotFound)
+	mockService.On("Tasks", mock.Anything, 7, 1, url.Values{"order": {"up"}}).Return([]models.Task(nil), services.ErrInvalidTaskFilter)
+
+	cases := []struct {
+		id     string
+		query  string
+		status int
+	}{
+		{"1", "?limit=5", http.StatusOK},
+		{"2", "", http.StatusNotFound},
+		{"1", "?order=up", http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/views/"+tc.id+"/tasks"+tc.query, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": tc.id})
+		req = req.WithContext(handlers.WithUserID(req.Context(), 7))
+		rr := httptest.NewRecorder()
+
+		handler.GetViewTasks(rr, req)
+
+		assert.Equal(t, tc.status, rr.Code, tc.query)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestViewHandler_DeleteView_Forbidden(t *testing.T) {
+	mockService := new(MockViewService)
+	handler := handlers.NewViewHandler(mockService)
+
+	mockService.On("Delete", mock.Anything, 8, 2).Return(services.ErrViewForbidden)
+
+	req := httptest.NewRequest(http.MethodDelete, "/views/2", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "2"})
+	req = req.WithContext(handlers.WithUserID(req.Context(), 8))
+	rr := httptest.NewRecorder()
+
+	handler.DeleteView(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestViewHandler_GetViewTasks_LocalizesTimes(t *testing.T) {
+	mockService := new(MockViewService)
+	handler := handlers.NewViewHandler(mockService)
+
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	assert.NoError(t, err)
+
+	created := time.Date(2030, 1, 2, 21, 30, 0, 0, time.UTC)
+	mockService.On("Tasks", mock.Anything, 7, 1, url.Values{}).Return([]models.Task{{ID: 3, Time: created}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/views/1/tasks", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+	req = req.WithContext(handlers.WithUserLocation(handlers.WithUserID(req.Context(), 7), moscow))
+	rr := httptest.NewRecorder()
+
+	handler.GetViewTasks(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"time":"2030-01-03T00:30:00+03:00"`)
+	assert.Contains(t, rr.Body.String(), `"due":"0001-01-01T00:00:00Z"`)
+
+	mockService.AssertExpectations(t)
+}
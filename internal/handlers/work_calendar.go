@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type WorkCalendarHandler struct {
+	service services.WorkCalendarService
+}
+
+func NewWorkCalendarHandler(service services.WorkCalendarService) *WorkCalendarHandler {
+	return &WorkCalendarHandler{service: service}
+}
+
+func RegisterWorkCalendarRoutes(router *mux.Router, handler *WorkCalendarHandler) {
+	router.HandleFunc("/users/me/calendar", handler.GetCalendar).Methods(http.MethodGet)
+	router.HandleFunc("/users/me/calendar", handler.UpdateCalendar).Methods(http.MethodPut)
+}
+
+// GetCalendar отдаёт часовой пояс, рабочие дни и праздники текущего пользователя.
+func (h *WorkCalendarHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.service.Get(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "Failed to fetch calendar")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, calendar)
+}
+
+// UpdateCalendar заменяет календарь текущего пользователя целиком.
+func (h *WorkCalendarHandler) UpdateCalendar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requireUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var calendar models.WorkCalendar
+	if err := decodeJSON(r.Body, &calendar); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	calendar, err := h.service.Update(r.Context(), userID, calendar)
+	if err != nil {
+		h.writeError(w, err, "Failed to update calendar")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, calendar)
+}
+
+func (h *WorkCalendarHandler) writeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWorkCalendar):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func (h *WorkCalendarHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"WebTasks/internal/handlers"
+	"WebTasks/internal/models"
+	"WebTasks/internal/services"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWorkCalendarService реализует методы WorkCalendarService для тестов.
+type MockWorkCalendarService struct {
+	mock.Mock
+}
+
+func (m *MockWorkCalendarService) Get(ctx context.Context, userID int) (models.WorkCalendar, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.WorkCalendar), args.Error(1)
+}
+
+func (m *MockWorkCalendarService) Update(ctx context.Context, userID int, calendar models.WorkCalendar) (models.WorkCalendar, error) {
+	args := m.Called(ctx, userID, calendar)
+	return args.Get(0).(models.WorkCalendar), args.Error(1)
+}
+
+func TestWorkCalendarHandler(t *testing.T) {
+	mockService := new(MockWorkCalendarService)
+
+	router := mux.NewRouter()
+	handlers.RegisterWorkCalendarRoutes(router, handlers.NewWorkCalendarHandler(mockService))
+
+	stored := models.WorkCalendar{Timezone: "Europe/Moscow", Workdays: []string{"mon"}, Holidays: []models.Holiday{}}
+
+	mockService.On("Get", mock.Anything, 7).Return(stored, nil)
+	mockService.On("Update", mock.Anything, 7, models.WorkCalendar{Timezone: "Europe/Moscow", Workdays: []string{"mon"}}).
+		Return(stored, nil)
+	mockService.On("Update", mock.Anything, 7, models.WorkCalendar{Timezone: "Mars/Olympus"}).
+		Return(models.WorkCalendar{}, services.ErrInvalidWorkCalendar)
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users/me/calendar", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req.WithContext(handlers.WithUserID(req.Context(), 7)))
+
+		return rr
+	}
+
+	rr := serve(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var calendar models.WorkCalendar
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&calendar))
+	assert.Equal(t, stored, calendar)
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPut, `{"timezone": "Europe/Moscow", "workdays": ["mon"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, `{"timezone": "Mars/Olympus"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, `{`).Code)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/me/calendar", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,378 @@
+// Package ical читает и пишет подмножество iCalendar (RFC 5545), нужное для календаря задач:
+// компоненты, свойства с параметрами, экранирование текста и перенос длинных строк.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	VCalendar = "VCALENDAR"
+	VTodo     = "VTODO"
+	VEvent    = "VEVENT"
+
+	dateTimeLayout = "20060102T150405"
+	dateLayout     = "20060102"
+	maxLineOctets  = 75
+)
+
+// Property - свойство компонента. Имена свойств и параметров хранятся в верхнем регистре.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component - компонент календаря с вложенными компонентами.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Property возвращает первое свойство с именем name.
+func (c *Component) Property(name string) (Property, bool) {
+	for _, property := range c.Properties {
+		if property.Name == name {
+			return property, true
+		}
+	}
+
+	return Property{}, false
+}
+
+// Text возвращает текстовое значение свойства без экранирования.
+func (c *Component) Text(name string) string {
+	property, ok := c.Property(name)
+	if !ok {
+		return ""
+	}
+
+	return UnescapeText(property.Value)
+}
+
+// List собирает значения всех свойств name, разделённые запятыми (например, CATEGORIES).
+func (c *Component) List(name string) []string {
+	var values []string
+
+	for _, property := range c.Properties {
+		if property.Name != name {
+			continue
+		}
+
+		for _, value := range splitUnescaped(property.Value, ',') {
+			if value = strings.TrimSpace(UnescapeText(value)); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+// Find возвращает вложенные компоненты с именем name на любой глубине.
+func (c *Component) Find(name string) []*Component {
+	var found []*Component
+
+	for _, child := range c.Components {
+		if child.Name == name {
+			found = append(found, child)
+		}
+
+		found = append(found, child.Find(name)...)
+	}
+
+	return found
+}
+
+// Parse читает первый VCALENDAR из r.
+func Parse(r io.Reader) (*Component, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var (
+		stack []*Component
+		root  *Component
+		line  string
+		num   int
+	)
+
+	handle := func(text string, num int) error {
+		property, err := parseLine(text)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", num, err)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			component := &Component{Name: strings.ToUpper(property.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			} else if component.Name != VCalendar {
+				return fmt.Errorf("line %d: expected BEGIN:VCALENDAR", num)
+			}
+
+			stack = append(stack, component)
+
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return fmt.Errorf("line %d: unexpected END:%s", num, property.Value)
+			}
+
+			if len(stack) == 1 {
+				root = stack[0]
+			}
+
+			stack = stack[:len(stack)-1]
+
+		default:
+			if len(stack) == 0 {
+				return fmt.Errorf("line %d: property outside of a component", num)
+			}
+
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+
+		return nil
+	}
+
+	start := 0
+
+	for scanner.Scan() {
+		num++
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		// Строка, начинающаяся с пробела или табуляции, продолжает предыдущую
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line += text[1:]
+			continue
+		}
+
+		if line != "" {
+			if err := handle(line, start); err != nil {
+				return nil, err
+			}
+
+			if root != nil {
+				return root, nil
+			}
+		}
+
+		line, start = text, num
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if line != "" && root == nil {
+		if err := handle(line, start); err != nil {
+			return nil, err
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("no complete VCALENDAR found")
+	}
+
+	return root, nil
+}
+
+// parseLine разбирает строку вида NAME;PARAM=value;PARAM="quoted":value.
+func parseLine(line string) (Property, error) {
+	var property Property
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return property, fmt.Errorf("malformed content line %q", line)
+	}
+
+	property.Name = strings.ToUpper(line[:i])
+	rest := line[i:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return property, fmt.Errorf("malformed parameter in %q", line)
+		}
+
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return property, fmt.Errorf("unterminated quoted parameter in %q", line)
+			}
+
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return property, fmt.Errorf("missing value in %q", line)
+			}
+
+			value = rest[:end]
+			rest = rest[end:]
+		}
+
+		if property.Params == nil {
+			property.Params = make(map[string]string)
+		}
+
+		property.Params[name] = value
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return property, fmt.Errorf("missing value in %q", line)
+	}
+
+	property.Value = rest[1:]
+
+	return property, nil
+}
+
+// ParseTime разбирает значение DATE или DATE-TIME. Время с суффиксом Z - UTC, с параметром
+// TZID - в указанной зоне, "плавающее" время и неизвестные зоны считаются UTC.
+// dateOnly сообщает, что значение было датой без времени.
+func ParseTime(property Property) (value time.Time, dateOnly bool, err error) {
+	text := property.Value
+
+	if property.Params["VALUE"] == "DATE" || len(text) == len(dateLayout) {
+		value, err = time.Parse(dateLayout, text)
+		return value, true, err
+	}
+
+	if strings.HasSuffix(text, "Z") {
+		value, err = time.Parse(dateTimeLayout+"Z", text)
+		return value, false, err
+	}
+
+	location := time.UTC
+
+	if tzid := property.Params["TZID"]; tzid != "" {
+		if loaded, loadErr := time.LoadLocation(tzid); loadErr == nil {
+			location = loaded
+		}
+	}
+
+	value, err = time.ParseInLocation(dateTimeLayout, text, location)
+
+	return value, false, err
+}
+
+// FormatTime возвращает DATE-TIME в UTC.
+func FormatTime(value time.Time) string {
+	return value.UTC().Format(dateTimeLayout) + "Z"
+}
+
+// EscapeText экранирует значение типа TEXT.
+func EscapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// UnescapeText восстанавливает значение типа TEXT.
+func UnescapeText(text string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(text)
+}
+
+// splitUnescaped делит значение по разделителю, не экранированному обратной косой чертой.
+func splitUnescaped(value string, separator byte) []string {
+	var (
+		parts []string
+		start int
+	)
+
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case separator:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, value[start:])
+}
+
+// Writer пишет календарь построчно, не собирая его целиком в памяти.
+// Первая ошибка записи запоминается и возвращается из Flush.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+func (w *Writer) Begin(name string) {
+	w.line("BEGIN:" + name)
+}
+
+func (w *Writer) End(name string) {
+	w.line("END:" + name)
+}
+
+// Property записывает свойство с уже подготовленным значением.
+func (w *Writer) Property(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// Text записывает свойство типа TEXT с экранированием.
+func (w *Writer) Text(name, value string) {
+	w.Property(name, EscapeText(value))
+}
+
+// Time записывает свойство DATE-TIME в UTC.
+func (w *Writer) Time(name string, value time.Time) {
+	w.Property(name, FormatTime(value))
+}
+
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.w.Flush()
+}
+
+// line пишет строку, перенося её по 75 октетов без разрыва символов UTF-8.
+func (w *Writer) line(text string) {
+	if w.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+
+	for len(text) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+
+		if _, w.err = w.w.WriteString(text[:cut] + "\r\n "); w.err != nil {
+			return
+		}
+
+		text = text[cut:]
+		// Пробел в начале строки продолжения тоже занимает октет
+		limit = maxLineOctets - 1
+	}
+
+	_, w.err = w.w.WriteString(text + "\r\n")
+}
@@ -0,0 +1,77 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	source := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"SUMMARY:Buy milk\\, bread\r\n" +
+		"  and eggs\r\n" +
+		"DUE;TZID=\"Europe/Moscow\":20300102T150000\r\n" +
+		"CATEGORIES:home,shop\r\n" +
+		"CATEGORIES:weekly\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20300105\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	calendar, err := Parse(strings.NewReader(source))
+	require.NoError(t, err)
+
+	todos := calendar.Find(VTodo)
+	require.Len(t, todos, 1)
+	assert.Equal(t, "Buy milk, bread and eggs", todos[0].Text("SUMMARY"))
+	assert.Equal(t, []string{"home", "shop", "weekly"}, todos[0].List("CATEGORIES"))
+
+	due, _ := todos[0].Property("DUE")
+	value, dateOnly, err := ParseTime(due)
+	require.NoError(t, err)
+	assert.False(t, dateOnly)
+	assert.Equal(t, time.Date(2030, 1, 2, 12, 0, 0, 0, time.UTC), value.UTC())
+
+	start, _ := calendar.Find(VEvent)[0].Property("DTSTART")
+	_, dateOnly, err = ParseTime(start)
+	require.NoError(t, err)
+	assert.True(t, dateOnly)
+
+	for _, broken := range []string{
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VTODO\r\nEND:VTODO\r\n",
+		"BEGIN:VCALENDAR\r\nSUMMARY\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\n",
+	} {
+		_, err := Parse(strings.NewReader(broken))
+		assert.Error(t, err, broken)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var out strings.Builder
+
+	writer := NewWriter(&out)
+	writer.Begin(VCalendar)
+	writer.Text("SUMMARY", strings.Repeat("задача; ", 10))
+	writer.Time("DUE", time.Date(2030, 1, 2, 15, 0, 0, 0, time.FixedZone("MSK", 3*3600)))
+	writer.End(VCalendar)
+	require.NoError(t, writer.Flush())
+
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+
+	assert.Contains(t, out.String(), "DUE:20300102T120000Z\r\n")
+
+	// Записанное читается обратно без потерь
+	calendar, err := Parse(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("задача; ", 10), calendar.Text("SUMMARY"))
+}
@@ -0,0 +1,56 @@
+// Package importers разбирает файлы выгрузки других трекеров (Trello, Todoist, Jira)
+// в проекты с деревьями задач. Проверка и запись задач остаются за сервисом.
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExport = errors.New("invalid export file")
+
+// Result - проекты из файла и предупреждения о данных, которые не удалось разобрать.
+type Result struct {
+	Projects []models.ImportedProject
+	Warnings []string
+}
+
+func (r *Result) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Parse разбирает выгрузку системы source (models.MigrationTrello и другие).
+func Parse(source string, body io.Reader) (Result, error) {
+	switch source {
+	case models.MigrationTrello:
+		return parseTrello(body)
+	case models.MigrationTodoist:
+		return parseTodoist(body)
+	case models.MigrationJira:
+		return parseJira(body)
+	}
+
+	return Result{}, fmt.Errorf("%w: source must be one of: %s", ErrInvalidExport, strings.Join(models.MigrationSources, ", "))
+}
+
+// tagName превращает метку в тег: теги не содержат пробелов.
+func tagName(label string) string {
+	return strings.Join(strings.Fields(label), "-")
+}
+
+// parseTime пробует форматы по очереди; время без зоны считается UTC.
+func parseTime(value string, layouts ...string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
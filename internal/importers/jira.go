@@ -0,0 +1,368 @@
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jiraDateLayouts - форматы дат CSV-выгрузки (зависят от настроек Jira) и JSON API.
+var jiraDateLayouts = []string{
+	"02/Jan/06 3:04 PM", "2/Jan/06 3:04 PM", "02/Jan/06", "2/Jan/06",
+	"2006-01-02T15:04:05.000-0700", "2006-01-02 15:04", "2006-01-02",
+}
+
+var jiraPriorities = map[string]string{
+	"highest": models.PriorityUrgent,
+	"blocker": models.PriorityUrgent,
+	"high":    models.PriorityHigh,
+	"major":   models.PriorityHigh,
+	"medium":  models.PriorityMedium,
+	"low":     models.PriorityLow,
+	"minor":   models.PriorityLow,
+	"lowest":  models.PriorityLow,
+	"trivial": models.PriorityLow,
+}
+
+// jiraIssue - задача Jira до сборки подзадач. parent - id или ключ родительской задачи.
+type jiraIssue struct {
+	id      string
+	key     string
+	parent  string
+	project string
+	tree    models.TaskTree
+}
+
+// parseJira переносит выгрузку задач Jira: CSV из поиска задач или JSON ответа
+// REST API /search. Статус Jira сохраняется как есть, подзадачи вкладываются в родителя,
+// задачи разных проектов Jira попадают в разные проекты.
+func parseJira(body io.Reader) (Result, error) {
+	reader := bufio.NewReader(body)
+
+	for {
+		next, err := reader.Peek(1)
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: empty Jira export", ErrInvalidExport)
+		}
+
+		switch next[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = reader.ReadByte()
+			continue
+		case '{':
+			return parseJiraJSON(reader)
+		}
+
+		return parseJiraCSV(reader)
+	}
+}
+
+func parseJiraCSV(body io.Reader) (Result, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: expected a Jira CSV export", ErrInvalidExport)
+	}
+
+	// Метки и комментарии выгружаются в несколько колонок с одинаковым заголовком
+	columns := make(map[string][]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = append(columns[name], i)
+	}
+
+	if len(columns["summary"]) == 0 {
+		return Result{}, fmt.Errorf("%w: Jira CSV must have a Summary column", ErrInvalidExport)
+	}
+
+	var (
+		result Result
+		issues []jiraIssue
+	)
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: line %d: %v", ErrInvalidExport, line, err)
+		}
+
+		all := func(names ...string) []string {
+			var values []string
+
+			for _, name := range names {
+				for _, i := range columns[name] {
+					if i < len(record) && strings.TrimSpace(record[i]) != "" {
+						values = append(values, strings.TrimSpace(record[i]))
+					}
+				}
+			}
+
+			return values
+		}
+
+		get := func(names ...string) string {
+			if values := all(names...); len(values) > 0 {
+				return values[0]
+			}
+
+			return ""
+		}
+
+		issue := jiraIssue{
+			id:      get("issue id"),
+			key:     get("issue key"),
+			parent:  get("parent id", "parent"),
+			project: get("project name", "project key"),
+			tree: models.TaskTree{
+				Task: models.Task{
+					Name:     get("summary"),
+					Status:   get("status"),
+					Priority: jiraPriorities[strings.ToLower(get("priority"))],
+				},
+			},
+		}
+
+		issue.tree.Task.Time, _ = parseTime(get("created"), jiraDateLayouts...)
+
+		if due := get("due date"); due != "" {
+			var ok bool
+			if issue.tree.Task.Due, ok = parseTime(due, jiraDateLayouts...); !ok {
+				result.warn("line %d: due date %q is not recognized and is skipped", line, due)
+			}
+		}
+
+		if estimate, err := strconv.Atoi(get("original estimate")); err == nil && estimate > 0 {
+			issue.tree.Task.EstimateMinutes = estimate / 60
+		}
+
+		for _, labels := range all("labels") {
+			issue.tree.Tags = append(issue.tree.Tags, strings.Fields(labels)...)
+		}
+
+		// Комментарий выгружается как "дата;автор;текст"
+		for _, value := range all("comment") {
+			comment := models.Comment{Body: value}
+
+			if parts := strings.SplitN(value, ";", 3); len(parts) == 3 {
+				if created, ok := parseTime(parts[0], jiraDateLayouts...); ok {
+					comment = models.Comment{Author: parts[1], Body: parts[2], CreatedAt: created}
+				}
+			}
+
+			issue.tree.Comments = append(issue.tree.Comments, comment)
+		}
+
+		issues = append(issues, issue)
+	}
+
+	result.Projects = buildJiraProjects(issues, &result)
+
+	return result, nil
+}
+
+// jiraSearch - ответ REST API /rest/api/{2,3}/search.
+type jiraSearch struct {
+	Issues []struct {
+		ID     string `json:"id"`
+		Key    string `json:"key"`
+		Fields struct {
+			Summary  string                     `json:"summary"`
+			Status   struct{ Name string }      `json:"status"`
+			Priority *struct{ Name string }     `json:"priority"`
+			DueDate  string                     `json:"duedate"`
+			Created  string                     `json:"created"`
+			Labels   []string                   `json:"labels"`
+			Project  struct{ Key, Name string } `json:"project"`
+			Parent   *struct{ ID, Key string }  `json:"parent"`
+			Estimate int                        `json:"timeoriginalestimate"`
+			Comment  struct {
+				Comments []struct {
+					Author  struct{ DisplayName string } `json:"author"`
+					Body    json.RawMessage              `json:"body"`
+					Created string                       `json:"created"`
+				} `json:"comments"`
+			} `json:"comment"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+func parseJiraJSON(body io.Reader) (Result, error) {
+	var search jiraSearch
+	if err := json.NewDecoder(body).Decode(&search); err != nil {
+		return Result{}, fmt.Errorf("%w: expected a Jira search JSON response", ErrInvalidExport)
+	}
+
+	var result Result
+
+	issues := make([]jiraIssue, 0, len(search.Issues))
+
+	for _, source := range search.Issues {
+		fields := source.Fields
+
+		issue := jiraIssue{
+			id:      source.ID,
+			key:     source.Key,
+			project: fields.Project.Name,
+			tree: models.TaskTree{
+				Task: models.Task{Name: fields.Summary, Status: fields.Status.Name, EstimateMinutes: fields.Estimate / 60},
+				Tags: fields.Labels,
+			},
+		}
+
+		if issue.project == "" {
+			issue.project = fields.Project.Key
+		}
+
+		if fields.Parent != nil {
+			issue.parent = fields.Parent.ID
+		}
+
+		if fields.Priority != nil {
+			issue.tree.Task.Priority = jiraPriorities[strings.ToLower(fields.Priority.Name)]
+		}
+
+		issue.tree.Task.Time, _ = parseTime(fields.Created, jiraDateLayouts...)
+
+		if fields.DueDate != "" {
+			var ok bool
+			if issue.tree.Task.Due, ok = parseTime(fields.DueDate, jiraDateLayouts...); !ok {
+				result.warn("issue %s: due date %q is not recognized and is skipped", source.Key, fields.DueDate)
+			}
+		}
+
+		for _, comment := range fields.Comment.Comments {
+			created, _ := parseTime(comment.Created, jiraDateLayouts...)
+
+			issue.tree.Comments = append(issue.tree.Comments, models.Comment{
+				Author:    comment.Author.DisplayName,
+				Body:      jiraCommentText(comment.Body),
+				CreatedAt: created,
+			})
+		}
+
+		issues = append(issues, issue)
+	}
+
+	result.Projects = buildJiraProjects(issues, &result)
+
+	return result, nil
+}
+
+// jiraCommentText возвращает текст комментария: строку API v2 или документ
+// Atlassian Document Format API v3, абзацы которого разделяются переводом строки.
+func jiraCommentText(body json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(body, &text); err == nil {
+		return text
+	}
+
+	var document adfNode
+	if err := json.Unmarshal(body, &document); err != nil {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	document.write(&builder)
+
+	return strings.TrimSpace(builder.String())
+}
+
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+func (n adfNode) write(builder *strings.Builder) {
+	builder.WriteString(n.Text)
+
+	for _, child := range n.Content {
+		child.write(builder)
+	}
+
+	if n.Type == "paragraph" || n.Type == "hardBreak" {
+		builder.WriteString("\n")
+	}
+}
+
+// buildJiraProjects вкладывает подзадачи в родителей и раскладывает задачи по проектам
+// в порядке их появления в выгрузке. Подзадача без родителя в файле становится задачей.
+func buildJiraProjects(issues []jiraIssue, result *Result) []models.ImportedProject {
+	byRef := make(map[string]int, len(issues)*2)
+	for i, issue := range issues {
+		for _, ref := range []string{issue.id, issue.key} {
+			if ref != "" {
+				byRef[ref] = i
+			}
+		}
+	}
+
+	children := make(map[int][]int, len(issues))
+
+	for i, issue := range issues {
+		parent := -1
+
+		if issue.parent != "" {
+			if index, ok := byRef[issue.parent]; ok && index != i {
+				parent = index
+			} else {
+				result.warn("issue %s: parent %s is not in the export, imported as a top-level task", issue.key, issue.parent)
+			}
+		}
+
+		children[parent] = append(children[parent], i)
+	}
+
+	built := 0
+
+	var build func(index int) models.TaskTree
+
+	build = func(index int) models.TaskTree {
+		built++
+
+		tree := issues[index].tree
+		for _, child := range children[index] {
+			tree.Subtasks = append(tree.Subtasks, build(child))
+		}
+
+		return tree
+	}
+
+	var projects []models.ImportedProject
+
+	positions := make(map[string]int)
+
+	for _, index := range children[-1] {
+		name := issues[index].project
+		if name == "" {
+			name = "Jira"
+		}
+
+		position, ok := positions[name]
+		if !ok {
+			position = len(projects)
+			positions[name] = position
+			projects = append(projects, models.ImportedProject{Name: name})
+		}
+
+		projects[position].Tasks = append(projects[position].Tasks, build(index))
+	}
+
+	if skipped := len(issues) - built; skipped > 0 {
+		result.warn("%d issues with cyclic parent links are skipped", skipped)
+	}
+
+	return projects
+}
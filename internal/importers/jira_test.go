@@ -0,0 +1,90 @@
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jiraCSVExport = "Summary,Issue key,Issue id,Parent id,Status,Project name,Priority,Due date,Created,Labels,Labels,Original Estimate,Comment,Comment\n" +
+	"Login page,WEB-1,10001,,In Progress,Website,Highest,15/Mar/24 12:00 AM,01/Mar/24 9:30 AM,frontend,auth,7200," +
+	"\"02/Mar/24 10:00 AM;5b10ac8d;Needs design\",\n" +
+	"Password reset,WEB-2,10002,10001,To Do,Website,Low,,01/Mar/24 9:45 AM,,,,,\n" +
+	"Crash on start,APP-7,10003,,Done,Mobile,Medium,,01/Mar/24 11:00 AM,,,,,\n" +
+	"Orphan,WEB-9,10009,99999,To Do,Website,,someday,,,,,,\n"
+
+const jiraJSONExport = `{"issues": [
+  {"id": "1", "key": "OPS-1", "fields": {
+    "summary": "Rotate keys", "status": {"name": "Backlog"}, "priority": {"name": "High"},
+    "duedate": "2024-04-01", "created": "2024-03-01T09:00:00.000+0300", "labels": ["security"],
+    "project": {"key": "OPS", "name": "Operations"}, "timeoriginalestimate": 3600,
+    "comment": {"comments": [
+      {"author": {"displayName": "Eve"}, "created": "2024-03-02T10:00:00.000+0000", "body": "Plain text"},
+      {"author": {"displayName": "Dan"}, "created": "2024-03-03T10:00:00.000+0000", "body": {"type": "doc", "content": [
+        {"type": "paragraph", "content": [{"type": "text", "text": "Rich "}, {"type": "text", "text": "text"}]}]}}
+    ]}}},
+  {"id": "2", "key": "OPS-2", "fields": {"summary": "Update docs", "status": {"name": "Backlog"},
+    "project": {"key": "OPS", "name": "Operations"}, "parent": {"id": "1", "key": "OPS-1"}}}
+]}`
+
+func TestParseJiraCSV(t *testing.T) {
+	result, err := Parse(models.MigrationJira, strings.NewReader(jiraCSVExport))
+	require.NoError(t, err)
+
+	// Задачи разных проектов Jira - в разных проектах
+	require.Len(t, result.Projects, 2)
+	assert.Equal(t, "Website", result.Projects[0].Name)
+	assert.Equal(t, "Mobile", result.Projects[1].Name)
+
+	website := result.Projects[0].Tasks
+	require.Len(t, website, 2)
+
+	login := website[0]
+	assert.Equal(t, "In Progress", login.Task.Status)
+	assert.Equal(t, models.PriorityUrgent, login.Task.Priority)
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), login.Task.Due)
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), login.Task.Time)
+	assert.Equal(t, 120, login.Task.EstimateMinutes)
+	assert.Equal(t, []string{"frontend", "auth"}, login.Tags)
+	require.Len(t, login.Comments, 1)
+	assert.Equal(t, "Needs design", login.Comments[0].Body)
+	assert.Equal(t, "5b10ac8d", login.Comments[0].Author)
+
+	require.Len(t, login.Subtasks, 1)
+	assert.Equal(t, "Password reset", login.Subtasks[0].Task.Name)
+
+	// Родителя нет в файле: задача переносится на верхний уровень с предупреждением
+	assert.Equal(t, "Orphan", website[1].Task.Name)
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], `"someday"`)
+	assert.Contains(t, result.Warnings[1], "WEB-9")
+}
+
+func TestParseJiraJSON(t *testing.T) {
+	result, err := Parse(models.MigrationJira, strings.NewReader("\n"+jiraJSONExport))
+	require.NoError(t, err)
+	require.Len(t, result.Projects, 1)
+	assert.Equal(t, "Operations", result.Projects[0].Name)
+
+	tasks := result.Projects[0].Tasks
+	require.Len(t, tasks, 1)
+
+	keys := tasks[0]
+	assert.Equal(t, "Rotate keys", keys.Task.Name)
+	assert.Equal(t, models.PriorityHigh, keys.Task.Priority)
+	assert.Equal(t, 60, keys.Task.EstimateMinutes)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), keys.Task.Due)
+	assert.Equal(t, []string{"security"}, keys.Tags)
+
+	require.Len(t, keys.Comments, 2)
+	assert.Equal(t, "Plain text", keys.Comments[0].Body)
+	assert.Equal(t, "Rich text", keys.Comments[1].Body)
+	assert.Equal(t, "Dan", keys.Comments[1].Author)
+
+	require.Len(t, keys.Subtasks, 1)
+	assert.Equal(t, "Update docs", keys.Subtasks[0].Task.Name)
+}
@@ -0,0 +1,187 @@
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// todoistDateLayouts - форматы конкретных дат в колонке DATE. Повторяющиеся сроки
+// ("every monday") и относительные ("tomorrow") не переносятся.
+var todoistDateLayouts = []string{
+	"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02T15:04:05Z07:00",
+	"Jan 2 2006", "Jan 2 2006 15:04", "2 Jan 2006", "2 Jan 2006 15:04",
+}
+
+// todoistPriorities - PRIORITY выгрузки: 1 - высший (p1), 4 - низший.
+var todoistPriorities = map[string]string{
+	"1": models.PriorityUrgent,
+	"2": models.PriorityHigh,
+	"3": models.PriorityMedium,
+	"4": models.PriorityLow,
+}
+
+// todoistAuthorID - хвост " (123456)" с идентификатором автора.
+var todoistAuthorID = regexp.MustCompile(`\s*\(\d+\)$`)
+
+// todoistNode - задача с номером родителя в порядке строк файла; -1 - задача верхнего уровня.
+type todoistNode struct {
+	tree   models.TaskTree
+	parent int
+}
+
+// parseTodoist переносит CSV-выгрузку проекта Todoist: разделы (TYPE=section) становятся
+// статусами следующих за ними задач, отступ INDENT - вложенностью подзадач, заметки
+// (TYPE=note) - комментариями предыдущей задачи, слова @метка из названия - тегами.
+func parseTodoist(body io.Reader) (Result, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: expected a Todoist CSV export", ErrInvalidExport)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	if _, ok := columns["TYPE"]; !ok {
+		return Result{}, fmt.Errorf("%w: Todoist CSV must have TYPE and CONTENT columns", ErrInvalidExport)
+	}
+
+	if _, ok := columns["CONTENT"]; !ok {
+		return Result{}, fmt.Errorf("%w: Todoist CSV must have TYPE and CONTENT columns", ErrInvalidExport)
+	}
+
+	var (
+		result  Result
+		nodes   []todoistNode
+		stack   []int // Номера последних задач на каждом уровне отступа
+		section string
+	)
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return Result{}, fmt.Errorf("%w: line %d: %v", ErrInvalidExport, line, err)
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		switch strings.ToLower(get("TYPE")) {
+		case "section":
+			section = get("CONTENT")
+			stack = stack[:0]
+		case "note":
+			if len(nodes) == 0 {
+				result.warn("line %d: note without a task is skipped", line)
+				continue
+			}
+
+			comment := models.Comment{Author: todoistAuthorID.ReplaceAllString(get("AUTHOR"), ""), Body: get("CONTENT")}
+			comment.CreatedAt, _ = parseTime(get("DATE"), todoistDateLayouts...)
+
+			last := &nodes[len(nodes)-1].tree
+			last.Comments = append(last.Comments, comment)
+		case "task":
+			tree := models.TaskTree{Task: models.Task{Status: section, Priority: todoistPriorities[get("PRIORITY")]}}
+			tree.Task.Name, tree.Tags = splitTodoistLabels(get("CONTENT"))
+
+			if date := get("DATE"); date != "" {
+				due, ok := parseTime(date, todoistDateLayouts...)
+				if !ok {
+					result.warn("line %d: due date %q is not a fixed date and is skipped", line, date)
+				}
+
+				tree.Task.Due = due
+			}
+
+			if duration, err := strconv.Atoi(get("DURATION")); err == nil && duration > 0 {
+				tree.Task.EstimateMinutes = duration
+				if strings.EqualFold(get("DURATION_UNIT"), "day") {
+					tree.Task.EstimateMinutes = duration * 24 * 60
+				}
+			}
+
+			indent, err := strconv.Atoi(get("INDENT"))
+			if err != nil || indent < 1 {
+				indent = 1
+			}
+
+			if indent-1 < len(stack) {
+				stack = stack[:indent-1]
+			}
+
+			parent := -1
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+
+			stack = append(stack, len(nodes))
+			nodes = append(nodes, todoistNode{tree: tree, parent: parent})
+		}
+	}
+
+	result.Projects = []models.ImportedProject{{Name: "Todoist", Tasks: buildTodoistTrees(nodes)}}
+
+	return result, nil
+}
+
+// splitTodoistLabels убирает из названия слова @метка и возвращает их как теги.
+func splitTodoistLabels(content string) (string, []string) {
+	var (
+		words []string
+		tags  []string
+	)
+
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && strings.HasPrefix(word, "@") {
+			tags = append(tags, word[1:])
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	return strings.Join(words, " "), tags
+}
+
+// buildTodoistTrees собирает деревья задач по номерам родителей.
+func buildTodoistTrees(nodes []todoistNode) []models.TaskTree {
+	children := make(map[int][]int, len(nodes))
+	for i, node := range nodes {
+		children[node.parent] = append(children[node.parent], i)
+	}
+
+	var build func(parent int) []models.TaskTree
+
+	build = func(parent int) []models.TaskTree {
+		var trees []models.TaskTree
+
+		for _, i := range children[parent] {
+			tree := nodes[i].tree
+			tree.Subtasks = build(i)
+			trees = append(trees, tree)
+		}
+
+		return trees
+	}
+
+	return build(-1)
+}
@@ -0,0 +1,55 @@
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const todoistExport = "TYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG,TIMEZONE,DURATION,DURATION_UNIT\n" +
+	"task,Plan trip @travel @family,,1,1,Ann (101),,2024-06-01,en,UTC,90,minute\n" +
+	"task,Book flights,,2,2,Ann (101),,every monday,en,UTC,,\n" +
+	"note,Window seats please,,,,Bob (102),,2024-05-01 10:00,en,UTC,,\n" +
+	",,,,,,,,,,,\n" +
+	"section,In progress,,,,,,,,,,\n" +
+	"task,Pack,,4,1,Ann (101),,,en,UTC,,\n"
+
+func TestParseTodoist(t *testing.T) {
+	result, err := Parse(models.MigrationTodoist, strings.NewReader(todoistExport))
+	require.NoError(t, err)
+	require.Len(t, result.Projects, 1)
+
+	tasks := result.Projects[0].Tasks
+	require.Len(t, tasks, 2)
+
+	plan := tasks[0]
+	assert.Equal(t, "Plan trip", plan.Task.Name)
+	assert.Equal(t, []string{"travel", "family"}, plan.Tags)
+	assert.Equal(t, models.PriorityUrgent, plan.Task.Priority)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), plan.Task.Due)
+	assert.Equal(t, 90, plan.Task.EstimateMinutes)
+	assert.Empty(t, plan.Task.Status)
+
+	// Отступ 2 - подзадача, заметка - комментарий к ней
+	require.Len(t, plan.Subtasks, 1)
+	flights := plan.Subtasks[0]
+	assert.Equal(t, "Book flights", flights.Task.Name)
+	assert.True(t, flights.Task.Due.IsZero())
+	require.Len(t, flights.Comments, 1)
+	assert.Equal(t, "Bob", flights.Comments[0].Author)
+	assert.Equal(t, "Window seats please", flights.Comments[0].Body)
+
+	// Раздел задаёт статус следующих задач
+	assert.Equal(t, "In progress", tasks[1].Task.Status)
+	assert.Equal(t, models.PriorityLow, tasks[1].Task.Priority)
+
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], `"every monday"`)
+
+	_, err = Parse(models.MigrationTodoist, strings.NewReader("NAME,DUE\nTask,2024-01-01\n"))
+	assert.ErrorIs(t, err, ErrInvalidExport)
+}
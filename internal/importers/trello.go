@@ -0,0 +1,176 @@
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// trelloBoard - поля JSON-выгрузки доски Trello, которые переносятся в задачи.
+type trelloBoard struct {
+	Name       string            `json:"name"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+	Actions    []trelloAction    `json:"actions"`
+}
+
+type trelloList struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type trelloCard struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	IDList      string        `json:"idList"`
+	Due         *time.Time    `json:"due"`
+	DueComplete bool          `json:"dueComplete"`
+	Closed      bool          `json:"closed"`
+	Pos         float64       `json:"pos"`
+	Labels      []trelloLabel `json:"labels"`
+}
+
+type trelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloChecklist struct {
+	IDCard     string            `json:"idCard"`
+	Pos        float64           `json:"pos"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+type trelloAction struct {
+	Type string    `json:"type"`
+	Date time.Time `json:"date"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			ID string `json:"id"`
+		} `json:"card"`
+	} `json:"data"`
+	MemberCreator struct {
+		FullName string `json:"fullName"`
+	} `json:"memberCreator"`
+}
+
+// parseTrello переносит карточки доски: список карточки становится статусом, выполненный
+// срок - статусом Completed, метки без названия - тегами по цвету. Архивные карточки
+// и карточки архивных списков пропускаются.
+func parseTrello(body io.Reader) (Result, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(body).Decode(&board); err != nil {
+		return Result{}, fmt.Errorf("%w: expected a Trello board JSON export", ErrInvalidExport)
+	}
+
+	var result Result
+
+	lists := make(map[string]trelloList, len(board.Lists))
+	for _, list := range board.Lists {
+		lists[list.ID] = list
+	}
+
+	checklists := make(map[string][]trelloChecklist)
+	for _, checklist := range board.Checklists {
+		checklists[checklist.IDCard] = append(checklists[checklist.IDCard], checklist)
+	}
+
+	// Выгрузка содержит действия от новых к старым
+	sort.SliceStable(board.Actions, func(i, j int) bool { return board.Actions[i].Date.Before(board.Actions[j].Date) })
+
+	comments := make(map[string][]models.Comment)
+	for _, action := range board.Actions {
+		if action.Type != "commentCard" {
+			continue
+		}
+
+		comments[action.Data.Card.ID] = append(comments[action.Data.Card.ID], models.Comment{
+			Author:    action.MemberCreator.FullName,
+			Body:      action.Data.Text,
+			CreatedAt: action.Date,
+		})
+	}
+
+	cards := board.Cards
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+
+	project := models.ImportedProject{Name: board.Name}
+
+	for _, card := range cards {
+		list, ok := lists[card.IDList]
+		if card.Closed || (ok && list.Closed) {
+			continue
+		}
+
+		tree := models.TaskTree{
+			Task:     models.Task{Name: card.Name, Status: list.Name, Time: trelloCreated(card.ID)},
+			Comments: comments[card.ID],
+		}
+
+		if card.Due != nil {
+			tree.Task.Due = *card.Due
+		}
+
+		if card.DueComplete {
+			tree.Task.Status = "Completed"
+		}
+
+		for _, label := range card.Labels {
+			name := label.Name
+			if name == "" {
+				name = label.Color
+			}
+
+			if name != "" {
+				tree.Tags = append(tree.Tags, tagName(name))
+			}
+		}
+
+		cardChecklists := checklists[card.ID]
+		sort.SliceStable(cardChecklists, func(i, j int) bool { return cardChecklists[i].Pos < cardChecklists[j].Pos })
+
+		for _, checklist := range cardChecklists {
+			items := checklist.CheckItems
+			sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+
+			for _, item := range items {
+				tree.Checklist = append(tree.Checklist, item.Name)
+				tree.Done = append(tree.Done, item.State == "complete")
+			}
+		}
+
+		project.Tasks = append(project.Tasks, tree)
+	}
+
+	result.Projects = []models.ImportedProject{project}
+
+	return result, nil
+}
+
+// trelloCreated извлекает время создания из идентификатора Trello: первые 8 шестнадцатеричных
+// цифр - секунды Unix.
+func trelloCreated(id string) time.Time {
+	if len(id) < 8 {
+		return time.Time{}
+	}
+
+	seconds, err := strconv.ParseInt(id[:8], 16, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Unix(seconds, 0).UTC()
+}
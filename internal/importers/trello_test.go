@@ -0,0 +1,72 @@
+package importers
+
+import (
+	"WebTasks/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trelloExport = `{
+  "name": "Launch",
+  "lists": [
+    {"id": "l1", "name": "To Do"},
+    {"id": "l2", "name": "Doing"},
+    {"id": "l3", "name": "Old", "closed": true}
+  ],
+  "cards": [
+    {"id": "65a0c3000000000000000001", "name": "Write copy", "idList": "l2", "pos": 2,
+     "due": "2024-02-01T12:00:00.000Z", "labels": [{"name": "Marketing Site", "color": "green"}, {"name": "", "color": "red"}]},
+    {"id": "65a0c3000000000000000002", "name": "Book venue", "idList": "l1", "pos": 1, "dueComplete": true,
+     "due": "2024-01-20T12:00:00.000Z"},
+    {"id": "65a0c3000000000000000003", "name": "Archived", "idList": "l1", "closed": true},
+    {"id": "65a0c3000000000000000004", "name": "In old list", "idList": "l3"}
+  ],
+  "checklists": [
+    {"idCard": "65a0c3000000000000000001", "pos": 2, "checkItems": [{"name": "Review", "state": "incomplete", "pos": 1}]},
+    {"idCard": "65a0c3000000000000000001", "pos": 1, "checkItems": [
+      {"name": "Outline", "state": "complete", "pos": 1}, {"name": "Draft", "state": "incomplete", "pos": 2}]}
+  ],
+  "actions": [
+    {"type": "commentCard", "date": "2024-01-12T10:00:00.000Z", "data": {"text": "Second", "card": {"id": "65a0c3000000000000000001"}},
+     "memberCreator": {"fullName": "Bob"}},
+    {"type": "updateCard", "date": "2024-01-11T11:00:00.000Z", "data": {"card": {"id": "65a0c3000000000000000001"}}},
+    {"type": "commentCard", "date": "2024-01-11T10:00:00.000Z", "data": {"text": "First", "card": {"id": "65a0c3000000000000000001"}},
+     "memberCreator": {"fullName": "Alice"}}
+  ]
+}`
+
+func TestParseTrello(t *testing.T) {
+	result, err := Parse(models.MigrationTrello, strings.NewReader(trelloExport))
+	require.NoError(t, err)
+	require.Len(t, result.Projects, 1)
+
+	project := result.Projects[0]
+	assert.Equal(t, "Launch", project.Name)
+	require.Len(t, project.Tasks, 2)
+
+	// Карточки идут в порядке pos, архивные пропущены
+	venue, writeCopy := project.Tasks[0], project.Tasks[1]
+	assert.Equal(t, "Book venue", venue.Task.Name)
+	assert.Equal(t, "Completed", venue.Task.Status)
+
+	assert.Equal(t, "Doing", writeCopy.Task.Status)
+	assert.Equal(t, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC), writeCopy.Task.Due)
+	assert.Equal(t, time.Unix(0x65a0c300, 0).UTC(), writeCopy.Task.Time)
+	assert.Equal(t, []string{"Marketing-Site", "red"}, writeCopy.Tags)
+	assert.Equal(t, []string{"Outline", "Draft", "Review"}, writeCopy.Checklist)
+	assert.Equal(t, []bool{true, false, false}, writeCopy.Done)
+
+	require.Len(t, writeCopy.Comments, 2)
+	assert.Equal(t, "First", writeCopy.Comments[0].Body)
+	assert.Equal(t, "Alice", writeCopy.Comments[0].Author)
+
+	_, err = Parse(models.MigrationTrello, strings.NewReader("name,lists"))
+	assert.ErrorIs(t, err, ErrInvalidExport)
+
+	_, err = Parse("asana", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, ErrInvalidExport)
+}
@@ -0,0 +1,182 @@
+// Package metrics собирает метрики сервиса в формате Prometheus: HTTP-запросы, запросы
+// к базе данных, пул соединений и бизнес-события.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "webtasks"
+
+// Источники созданных задач для метки source.
+const (
+	SourceAPI       = "api"
+	SourceBulk      = "bulk"
+	SourceQuickAdd  = "quick_add"
+	SourceImport    = "import"
+	SourceMigration = "migration"
+)
+
+// Registry - реестр, который отдаёт /metrics. Свой реестр вместо глобального, чтобы
+// в выдачу не попадали метрики сторонних библиотек.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Database query latency by statement kind and table, see QueryName.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"query", "outcome"})
+
+	tasksCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_created_total",
+		Help:      "Tasks created, by source.",
+	}, []string{"source"})
+
+	tasksCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_completed_total",
+		Help:      "Tasks moved from an open to a closed status.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		dbQueryDuration,
+		tasksCreated,
+		tasksCompleted,
+	)
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest учитывает HTTP-запрос. route - шаблон маршрута mux ("/tasks/{id}"),
+// а не путь запроса, чтобы число рядов не зависело от ID в URL.
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+
+	httpRequests.With(labels).Inc()
+	httpDuration.With(labels).Observe(duration.Seconds())
+}
+
+// ObserveQuery учитывает запрос к базе данных.
+func ObserveQuery(query string, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	dbQueryDuration.WithLabelValues(QueryName(query), outcome).Observe(duration.Seconds())
+}
+
+// TasksCreated учитывает count созданных задач из источника source.
+func TasksCreated(source string, count int) {
+	if count > 0 {
+		tasksCreated.WithLabelValues(source).Add(float64(count))
+	}
+}
+
+// TasksCompleted учитывает count закрытых задач.
+func TasksCompleted(count int) {
+	if count > 0 {
+		tasksCompleted.Add(float64(count))
+	}
+}
+
+// RegisterDBStats публикует состояние пула соединений db.
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// RegisterOverdueTasks публикует число просроченных открытых задач. count вызывается
+// при каждом сборе метрик с ограничением timeout.
+func RegisterOverdueTasks(count func(ctx context.Context) (int, error), timeout time.Duration) error {
+	return Registry.Register(&overdueCollector{count: count, timeout: timeout})
+}
+
+var overdueDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "tasks_overdue"),
+	"Open tasks whose due date has passed.",
+	nil, nil,
+)
+
+type overdueCollector struct {
+	count   func(ctx context.Context) (int, error)
+	timeout time.Duration
+}
+
+func (c *overdueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- overdueDesc
+}
+
+func (c *overdueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	count, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(overdueDesc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(overdueDesc, prometheus.GaugeValue, float64(count))
+}
+
+// QueryName сводит текст SQL к виду "<оператор> <таблица>" ("select tasks",
+// "insert task_tags"), чтобы метка query не зависела от параметров и форматирования.
+func QueryName(query string) string {
+	fields := strings.Fields(strings.ToLower(query))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	verb := fields[0]
+
+	// Таблица идёт после FROM, INTO или сразу после UPDATE
+	for i, field := range fields {
+		if i+1 < len(fields) && (field == "from" || field == "into" || (field == "update" && i == 0)) {
+			table := strings.TrimPrefix(fields[i+1], "public.")
+			table = strings.TrimRight(table, "(;,")
+
+			if table != "" && !strings.HasPrefix(table, "(") {
+				return verb + " " + table
+			}
+		}
+	}
+
+	return verb
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"SELECT id, name FROM public.tasks WHERE id = $1":                "select tasks",
+		"\n\tINSERT INTO public.task_tags (task_id, tag_id) VALUES ($1)": "insert task_tags",
+		"UPDATE public.tasks SET name = $1":                              "update tasks",
+		"DELETE FROM public.tasks WHERE id = $1;":                        "delete tasks",
+		"SELECT count(*) FROM (SELECT 1) AS t":                           "select",
+		"BEGIN":                                                          "begin",
+		"   ":                                                            "unknown",
+	}
+
+	for query, want := range tests {
+		assert.Equal(t, want, QueryName(query), query)
+	}
+}
+
+func TestObserveQuery(t *testing.T) {
+	ObserveQuery("SELECT * FROM public.projects", time.Millisecond, nil)
+	ObserveQuery("SELECT * FROM public.projects", time.Millisecond, errors.New("boom"))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(dbQueryDuration.WithLabelValues("select projects", "ok").(prometheus.Histogram)))
+	assert.Equal(t, 1, testutil.CollectAndCount(dbQueryDuration.WithLabelValues("select projects", "error").(prometheus.Histogram)))
+}
+
+func TestTaskCounters(t *testing.T) {
+	before := testutil.ToFloat64(tasksCreated.WithLabelValues(SourceBulk))
+
+	TasksCreated(SourceBulk, 3)
+	TasksCreated(SourceBulk, 0)
+
+	assert.Equal(t, before+3, testutil.ToFloat64(tasksCreated.WithLabelValues(SourceBulk)))
+}
+
+func TestOverdueCollector(t *testing.T) {
+	collector := &overdueCollector{
+		count:   func(ctx context.Context) (int, error) { return 4, nil },
+		timeout: time.Second,
+	}
+
+	expected := `
+# HELP webtasks_tasks_overdue Open tasks whose due date has passed.
+# TYPE webtasks_tasks_overdue gauge
+webtasks_tasks_overdue 4
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// Ошибка базы делает сбор неуспешным, а не публикует ноль
+	collector.count = func(ctx context.Context) (int, error) { return 0, errors.New("timeout") }
+
+	assert.Error(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	ID          int       `db:"id" json:"id"`
+	TaskID      int       `db:"task_id" json:"task_id"`
+	FileName    string    `db:"file_name" json:"file_name"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int64     `db:"size" json:"size"`
+	Hash        string    `db:"hash" json:"hash"` // SHA-256 содержимого, он же ключ в хранилище
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,63 @@
+package models
+
+// Операции пакетного изменения задач.
+const (
+	BulkCreate     = "create"
+	BulkUpdate     = "update"
+	BulkDelete     = "delete"
+	BulkStatus     = "status"
+	BulkAddTags    = "add_tags"
+	BulkRemoveTags = "remove_tags"
+	BulkSetTags    = "set_tags"
+)
+
+// Режимы пакета: atomic - всё или ничего, best_effort - каждая операция отдельно.
+const (
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+)
+
+// Итог отдельной операции пакета.
+const (
+	BulkResultOK      = "ok"
+	BulkResultFailed  = "failed"
+	BulkResultSkipped = "skipped" // Не применена из-за ошибки в другой операции атомарного пакета
+)
+
+type BulkOperation struct {
+	Op     string   `json:"op"`
+	ID     int      `json:"id,omitempty"`
+	Task   *Task    `json:"task,omitempty"`
+	Status string   `json:"status,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+type BulkRequest struct {
+	Mode       string          `json:"mode"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+type BulkResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     int    `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Task   *Task  `json:"task,omitempty"`
+}
+
+type BulkResponse struct {
+	Mode      string       `json:"mode"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// TaskChange - проверенное изменение, готовое к записи в базу.
+// Статус приводится к обновлению, теги - к одной из операций над task_tags.
+type TaskChange struct {
+	Op     string
+	TaskID int
+	Task   *Task
+	Tags   []string
+}
@@ -0,0 +1,13 @@
+package models
+
+// CalDAVObject - задача как ресурс коллекции CalDAV. Имя и UID задаёт клиент при создании
+// ресурса; задачи, созданные через API, доступны как "task-<id>.ics".
+type CalDAVObject struct {
+	TaskID int    `db:"task_id"`
+	UserID int    `db:"user_id"`
+	Name   string `db:"name"`
+	UID    string `db:"uid"`
+	ETag   string `db:"-"`
+	Data   string `db:"-"` // VCALENDAR с одним VTODO
+	Task   Task   `db:"-"`
+}
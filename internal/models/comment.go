@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Comment - комментарий к задаче. Author - имя автора в системе, откуда перенесён комментарий.
+type Comment struct {
+	ID        int       `db:"id" json:"id"`
+	TaskID    int       `db:"task_id" json:"task_id"`
+	Author    string    `db:"author" json:"author,omitempty"`
+	Body      string    `db:"body" json:"body"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Состояния проверок здоровья сервиса.
+const (
+	HealthOK   = "ok"
+	HealthFail = "fail"
+)
+
+// HealthCheck - результат одной проверки готовности.
+type HealthCheck struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// HealthReport - ответ /healthz и /readyz: общий статус fail, если не прошла хотя бы одна проверка.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// BuildInfo - сведения о сборке из runtime/debug.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// PoolStats - состояние пула соединений с базой (sql.DBStats).
+type PoolStats struct {
+	MaxOpen      int    `json:"max_open"`
+	Open         int    `json:"open"`
+	InUse        int    `json:"in_use"`
+	Idle         int    `json:"idle"`
+	WaitCount    int64  `json:"wait_count"`
+	WaitDuration string `json:"wait_duration"`
+}
+
+// StatusReport - подробный ответ /status.
+type StatusReport struct {
+	HealthReport
+	Build         BuildInfo       `json:"build"`
+	StartedAt     time.Time       `json:"started_at"`
+	Uptime        string          `json:"uptime"`
+	SchemaVersion int             `json:"schema_version"`
+	Pool          PoolStats       `json:"pool"`
+	Workers       map[string]bool `json:"workers"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyRecord хранит ответ на запрос с заголовком Idempotency-Key.
+// Ключ действует в пределах пользователя (0 - неизвестный) и маршрута;
+// Status = 0 означает, что исходный запрос ещё обрабатывается.
+type IdempotencyRecord struct {
+	Key         string    `db:"key"`
+	UserID      int       `db:"user_id"`
+	Endpoint    string    `db:"endpoint"`
+	Fingerprint string    `db:"fingerprint"`
+	Status      int       `db:"status"`
+	ContentType string    `db:"content_type"`
+	Body        []byte    `db:"body"`
+	CreatedAt   time.Time `db:"created_at"`
+}
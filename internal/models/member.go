@@ -0,0 +1,22 @@
+package models
+
+// Роли участников задачи помимо автора (Task.UserID).
+const (
+	RoleAssignee = "assignee"
+	RoleWatcher  = "watcher"
+)
+
+// CurrentUser в фильтре задач означает пользователя, выполняющего запрос ("assignee=me").
+const CurrentUser = -1
+
+type TaskMember struct {
+	UserID int    `db:"user_id" json:"user_id"`
+	Name   string `db:"name" json:"name"`
+	Role   string `db:"role" json:"-"`
+}
+
+// TaskMembers - исполнители и наблюдатели задачи.
+type TaskMembers struct {
+	Assignees []TaskMember `json:"assignees"`
+	Watchers  []TaskMember `json:"watchers"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// Системы, из выгрузок которых переносятся задачи.
+const (
+	MigrationTrello  = "trello"  // JSON доски
+	MigrationTodoist = "todoist" // CSV проекта
+	MigrationJira    = "jira"    // CSV или JSON поиска задач
+)
+
+var MigrationSources = []string{MigrationTrello, MigrationTodoist, MigrationJira}
+
+// ImportedProject - проект из выгрузки с деревьями задач. Списки и колонки исходной
+// системы становятся статусами задач, метки - тегами.
+type ImportedProject struct {
+	Name  string
+	Tasks []TaskTree
+}
+
+// MigrationOptions - параметры переноса. Project заменяет имя проекта из файла.
+type MigrationOptions struct {
+	Source  string
+	Project string
+	DryRun  bool
+	UserID  int
+}
+
+type MigratedProject struct {
+	Project Project `json:"project"`
+	Tasks   int     `json:"tasks"`
+}
+
+// MigrationReport - итог переноса. Warnings перечисляет данные, перенесённые не полностью.
+type MigrationReport struct {
+	Source    string            `json:"source"`
+	DryRun    bool              `json:"dry_run"`
+	Projects  []MigratedProject `json:"projects"`
+	Tasks     int               `json:"tasks"`
+	Checklist int               `json:"checklist_items"`
+	Comments  int               `json:"comments"`
+	Warnings  []string          `json:"warnings"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Типы пользовательских полей.
+const (
+	FieldTypeText   = "text"
+	FieldTypeNumber = "number"
+	FieldTypeDate   = "date"
+	FieldTypeEnum   = "enum"
+	FieldTypeUser   = "user"
+)
+
+var FieldTypes = []string{FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeEnum, FieldTypeUser}
+
+type Project struct {
+	ID        int       `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	OwnerID   int       `db:"owner_id" json:"owner_id,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// CustomField - определение пользовательского поля проекта.
+type CustomField struct {
+	ID        int            `db:"id" json:"id"`
+	ProjectID int            `db:"project_id" json:"project_id"`
+	Name      string         `db:"name" json:"name"`
+	Type      string         `db:"type" json:"type"`
+	Options   pq.StringArray `db:"options" json:"options,omitempty"` // Допустимые значения для enum
+	Required  bool           `db:"required" json:"required"`
+}
+
+// CustomValues - значения пользовательских полей задачи, хранятся в JSONB.
+type CustomValues map[string]interface{}
+
+func (v CustomValues) Value() (driver.Value, error) {
+	if v == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(v)
+}
+
+func (v *CustomValues) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return fmt.Errorf("cannot scan %T into CustomValues", src)
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Виды распознанных фрагментов строки быстрого добавления.
+const (
+	QuickAddDue      = "due"
+	QuickAddTag      = "tag"
+	QuickAddPriority = "priority"
+)
+
+// QuickAddRequest - строка быстрого добавления задачи. Timezone - зона IANA, в которой
+// понимаются "завтра" и "в 15:00"; по умолчанию UTC.
+type QuickAddRequest struct {
+	Text     string `json:"text"`
+	Timezone string `json:"timezone,omitempty"`
+	UserID   int    `json:"-"`
+	DryRun   bool   `json:"-"`
+}
+
+// QuickAddToken - фрагмент строки, из которого взято значение поля задачи.
+type QuickAddToken struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// QuickAddParse - разбор строки: оставшийся текст становится названием задачи.
+type QuickAddParse struct {
+	Name     string          `json:"name"`
+	Due      *time.Time      `json:"due,omitempty"` // В часовом поясе запроса
+	Tags     []string        `json:"tags,omitempty"`
+	Priority string          `json:"priority,omitempty"`
+	Timezone string          `json:"timezone"`
+	Tokens   []QuickAddToken `json:"tokens"`
+}
+
+// QuickAddResult - созданная задача (при пробном разборе - без ID) и разбор строки.
+type QuickAddResult struct {
+	Task  Task          `json:"task"`
+	Tags  []string      `json:"tags"`
+	Parse QuickAddParse `json:"parse"`
+}
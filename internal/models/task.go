@@ -1,19 +1,97 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type User struct {
-	ID    int    `db:"id"`
-	Name  string `db:"name"`
-	Key   string `db:"key"`
-	Tasks []Task // Слайс из структуры задачи. Куча задач будут в виде слайсов для одного пользователя
+	ID       int    `db:"id"`
+	Name     string `db:"name"`
+	Key      string `db:"key"`
+	Timezone string `db:"timezone"` // Зона IANA, в которой пользователю показываются даты
+	Tasks    []Task // Слайс из структуры задачи. Куча задач будут в виде слайсов для одного пользователя
+}
+
+// Уровни приоритета задачи в порядке возрастания важности.
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+var Priorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// ClosedStatuses - статусы (в нижнем регистре), при которых задача считается закрытой.
+var ClosedStatuses = []string{"completed", "done", "closed", "cancelled"}
+
+func IsClosedStatus(status string) bool {
+	for _, closed := range ClosedStatuses {
+		if strings.EqualFold(closed, status) {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Task struct {
-	ID     int       `db:"id" json:"id"`
-	Name   string    `db:"name" json:"name"`
-	Status string    `db:"status" json:"status"`
-	Time   time.Time `db:"time" json:"time"`
-	Due    time.Time `db:"due" json:"due"`
-	UserID int       `db:"user_id" json:"user_id"`
+	ID              int          `db:"id" json:"id"`
+	Name            string       `db:"name" json:"name"`
+	Status          string       `db:"status" json:"status"`
+	Time            time.Time    `db:"time" json:"time"`
+	Due             time.Time    `db:"due" json:"due"`
+	UserID          int          `db:"user_id" json:"user_id"`
+	Priority        string       `db:"priority" json:"priority,omitempty"`
+	EstimateMinutes int          `db:"estimate_minutes" json:"estimate_minutes,omitempty"` // Оценка трудоёмкости
+	ProjectID       int          `db:"project_id" json:"project_id,omitempty"`
+	ParentID        int          `db:"parent_id" json:"parent_id,omitempty"` // Родительская задача для подзадач
+	CustomFields    CustomValues `db:"custom_fields" json:"custom_fields,omitempty"`
+	Recurrence      string       `db:"recurrence" json:"recurrence,omitempty"` // Правило повторения RRULE (RFC 5545)
+	CreatedBy       int          `db:"created_by" json:"-"`                    // Кто создал задачу; по нему считается квота, из запроса не задаётся
+}
+
+// TaskFilter - условия отбора и сортировки списка задач.
+// Ключи Custom и SortBy вида "cf.<name>" относятся к пользовательским полям.
+type TaskFilter struct {
+	UserID      int
+	AssigneeID  int // Задачи, где пользователь - исполнитель
+	WatcherID   int // Задачи, где пользователь - наблюдатель
+	MemberID    int // Задачи, где пользователь - автор, исполнитель или наблюдатель
+	ProjectID   int
+	ParentID    int
+	Status      string
+	Custom      map[string]string
+	SortBy      string
+	Desc        bool
+	SortNumeric bool // Сортировать пользовательское поле как число
+	Limit       int
+	Offset      int
+}
+
+// ForUser подставляет userID вместо CurrentUser.
+func (f TaskFilter) ForUser(userID int) TaskFilter {
+	for _, id := range []*int{&f.AssigneeID, &f.WatcherID, &f.MemberID} {
+		if *id == CurrentUser {
+			*id = userID
+		}
+	}
+
+	return f
+}
+
+// TaskCandidate - открытая задача с данными о зависимостях для расчёта очерёдности.
+type TaskCandidate struct {
+	Task
+	OpenBlockers int `db:"open_blockers"` // Сколько незакрытых задач блокируют эту
+	Blocks       int `db:"blocks"`        // Сколько задач ждут эту
+}
+
+// ScoredTask - задача с итоговой оценкой и её составляющими.
+type ScoredTask struct {
+	Task
+	Score     float64            `json:"score"`
+	Overdue   bool               `json:"overdue"`
+	Breakdown map[string]float64 `json:"breakdown"`
 }
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type TaskTemplate struct {
+	ID        int          `db:"id" json:"id"`
+	Name      string       `db:"name" json:"name"`
+	Task      TemplateTask `db:"definition" json:"task"`
+	CreatedBy int          `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
+// TemplateTask - задача шаблона. В названиях и пунктах чек-листа допускаются
+// подстановки вида {{name}}; DueOffset отсчитывается от момента создания ("3d", "2d4h", "90m").
+type TemplateTask struct {
+	Name      string         `json:"name"`
+	Status    string         `json:"status,omitempty"`
+	Priority  string         `json:"priority,omitempty"`
+	DueOffset string         `json:"due_offset,omitempty"`
+	Tags      []string       `json:"tags,omitempty"`
+	Checklist []string       `json:"checklist,omitempty"`
+	Subtasks  []TemplateTask `json:"subtasks,omitempty"`
+}
+
+func (t TemplateTask) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+func (t *TemplateTask) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case []byte:
+		return json.Unmarshal(data, t)
+	case string:
+		return json.Unmarshal([]byte(data), t)
+	default:
+		return fmt.Errorf("cannot scan %T into TemplateTask", src)
+	}
+}
+
+// TemplateInstantiation - параметры создания задач по шаблону.
+type TemplateInstantiation struct {
+	Variables map[string]string `json:"variables"`
+	Start     time.Time         `json:"start"` // Отсчёт сроков, по умолчанию - текущий момент
+	UserID    int               `json:"-"`
+}
+
+// TaskTree - задача с тегами, чек-листом, комментариями и подзадачами, создаваемая целиком.
+type TaskTree struct {
+	Task      Task
+	Tags      []string
+	Checklist []string
+	Done      []bool // Отметки выполнения пунктов Checklist по их индексам
+	Comments  []Comment
+	Subtasks  []TaskTree
+}
+
+type ChecklistItem struct {
+	ID       int    `db:"id" json:"id"`
+	TaskID   int    `db:"task_id" json:"task_id"`
+	Position int    `db:"position" json:"position"`
+	Text     string `db:"text" json:"text"`
+	Done     bool   `db:"done" json:"done"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// TimeEntry - запись учёта времени. Запущенный таймер - запись без EndedAt.
+type TimeEntry struct {
+	ID        int        `db:"id" json:"id"`
+	TaskID    int        `db:"task_id" json:"task_id"`
+	UserID    int        `db:"user_id" json:"user_id"`
+	StartedAt time.Time  `db:"started_at" json:"started_at"`
+	EndedAt   *time.Time `db:"ended_at" json:"ended_at,omitempty"`
+	Note      string     `db:"note" json:"note"`
+}
+
+// TimeReportFilter задаёт группировку и отбор записей для отчёта.
+type TimeReportFilter struct {
+	GroupBy string
+	From    time.Time
+	To      time.Time
+	UserID  int
+	TaskID  int
+	// Timezone - IANA-пояс, в котором записи делятся по датам (группировка date)
+	Timezone string
+}
+
+// TimeReportRow - одна строка агрегированного отчёта.
+type TimeReportRow struct {
+	Key     string `db:"key" json:"key"`
+	Label   string `db:"label" json:"label"`
+	Entries int    `db:"entries" json:"entries"`
+	Seconds int64  `db:"seconds" json:"seconds"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// Форматы выгрузки и загрузки задач.
+const (
+	TransferCSV    = "csv"
+	TransferJSON   = "json"
+	TransferNDJSON = "ndjson"
+	TransferICS    = "ics" // Только загрузка: задачи из VTODO
+)
+
+// ImportTask - задача из загружаемого файла вместе с тегами.
+type ImportTask struct {
+	Task
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ImportOptions - параметры загрузки. Mapping сопоставляет полю задачи ("name", "due",
+// "cf.stage") заголовок колонки CSV; без сопоставления колонка ищется по имени поля.
+type ImportOptions struct {
+	Format  string
+	Mapping map[string]string
+	DryRun  bool
+	UserID  int
+}
+
+// ImportRowError - ошибка строки файла. Row - номер строки CSV с учётом заголовка
+// или номер элемента JSON, начиная с 1.
+type ImportRowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+// ImportReport - итог загрузки. При ошибках в строках ни одна задача не создаётся.
+type ImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+	Tasks    []Task           `json:"tasks,omitempty"`
+}
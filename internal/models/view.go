@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SavedView - сохранённый фильтр списка задач. Личный вид видит только владелец,
+// вид с проектом доступен всем пользователям проекта.
+type SavedView struct {
+	ID        int            `db:"id" json:"id"`
+	UserID    int            `db:"user_id" json:"user_id"`
+	ProjectID int            `db:"project_id" json:"project_id,omitempty"`
+	Name      string         `db:"name" json:"name"`
+	Query     string         `db:"query" json:"query"` // Параметры GET /tasks, например "status=Pending&sort=due"
+	Columns   pq.StringArray `db:"columns" json:"columns,omitempty"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// Weekdays - сокращённые названия дней недели; индекс совпадает с time.Weekday.
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// DefaultWorkdays - рабочая неделя нового пользователя.
+var DefaultWorkdays = []string{"mon", "tue", "wed", "thu", "fri"}
+
+// Holiday - нерабочий день календаря пользователя.
+type Holiday struct {
+	Date string `db:"day" json:"date"` // YYYY-MM-DD
+	Name string `db:"name" json:"name,omitempty"`
+}
+
+// WorkCalendar - часовой пояс и рабочий календарь пользователя, по которым считаются
+// сроки вида "через 3 рабочих дня" и просрочка.
+type WorkCalendar struct {
+	Timezone string    `json:"timezone"`
+	Workdays []string  `json:"workdays"`
+	Holidays []Holiday `json:"holidays"`
+}
@@ -0,0 +1,34 @@
+package repositories
+
+const (
+	CreateAttachmentQuery = `
+	INSERT INTO public.attachments (task_id, file_name, content_type, size, hash)
+	VALUES (:task_id, :file_name, :content_type, :size, :hash)
+	RETURNING id, task_id, file_name, content_type, size, hash, created_at;`
+
+	GetAttachmentByIDQuery = `
+	SELECT id, task_id, file_name, content_type, size, hash, created_at
+	FROM public.attachments
+	WHERE id = $1 AND task_id = $2;`
+
+	GetAttachmentsByTaskQuery = `
+	SELECT id, task_id, file_name, content_type, size, hash, created_at
+	FROM public.attachments
+	WHERE task_id = $1
+	ORDER BY id;`
+
+	DeleteAttachmentQuery = `
+	DELETE FROM public.attachments
+	WHERE id = $1 AND task_id = $2
+	RETURNING hash;`
+
+	// LockAttachmentHashQuery блокирует содержимое до конца транзакции; блокировки
+	// у разных хэшей могут совпасть по hashtext, это лишь изредка упорядочивает лишнее
+	LockAttachmentHashQuery = `
+	SELECT pg_advisory_xact_lock(hashtext('attachment:' || $1));`
+
+	CountAttachmentsByHashQuery = `
+	SELECT COUNT(*)
+	FROM public.attachments
+	WHERE hash = $1;`
+)
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BlobFunc сохраняет или удаляет содержимое вложения в хранилище. Репозиторий вызывает
+// её внутри транзакции, удерживая блокировку хэша, поэтому загрузка и удаление одного
+// и того же содержимого не пересекаются.
+type BlobFunc func(ctx context.Context, hash string) error
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment, putBlob BlobFunc) (*models.Attachment, error)
+	GetByID(ctx context.Context, taskID, id int) (*models.Attachment, error)
+	GetByTask(ctx context.Context, taskID int) ([]models.Attachment, error)
+	Delete(ctx context.Context, taskID, id int, deleteBlob BlobFunc) error
+}
+
+type AttachmentRepo struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentRepo(db *sqlx.DB) AttachmentRepository {
+	return &AttachmentRepo{db: db}
+}
+
+// Create сохраняет содержимое через putBlob и добавляет вложение одной операцией под
+// блокировкой хэша: параллельное удаление не сотрёт содержимое, пока запись не видна.
+func (r *AttachmentRepo) Create(ctx context.Context, attachment *models.Attachment, putBlob BlobFunc) (*models.Attachment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logError(ctx, "Begin transaction in Create attachment", err)
+		return nil, err
+	}
+
+	defer rollback(ctx, tx, "Create attachment")
+
+	if err := lockAttachmentHash(ctx, tx, attachment.Hash); err != nil {
+		return nil, err
+	}
+
+	if err := putBlob(ctx, attachment.Hash); err != nil {
+		return nil, err
+	}
+
+	created, err := insertAttachment(ctx, tx, attachment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logError(ctx, "Commit in Create attachment", err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func insertAttachment(ctx context.Context, tx *sqlx.Tx, attachment *models.Attachment) (*models.Attachment, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, tx, CreateAttachmentQuery, attachment)
+	if err != nil {
+		logError(ctx, "CreateAttachmentQuery", err)
+		return nil, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			logError(ctx, "Close rows in Create", closeErr)
+		}
+	}()
+
+	if rows.Next() {
+		var created models.Attachment
+		if err := rows.StructScan(&created); err != nil {
+			logError(ctx, "StructScan (Create)", err)
+			return nil, err
+		}
+
+		return &created, nil
+	}
+
+	slog.ErrorContext(ctx, "attachment creation returned no rows")
+
+	return nil, errors.New("attachment creation failed")
+}
+
+func (r *AttachmentRepo) GetByID(ctx context.Context, taskID, id int) (*models.Attachment, error) {
+	var attachment models.Attachment
+
+	err := r.db.GetContext(ctx, &attachment, GetAttachmentByIDQuery, id, taskID)
+	if err != nil {
+		logError(ctx, "GetAttachmentByIDQuery", err)
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *AttachmentRepo) GetByTask(ctx context.Context, taskID int) ([]models.Attachment, error) {
+	attachments := []models.Attachment{}
+
+	err := r.db.SelectContext(ctx, &attachments, GetAttachmentsByTaskQuery, taskID)
+	if err != nil {
+		logError(ctx, "GetAttachmentsByTaskQuery", err)
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// Delete удаляет вложение и, если на его содержимое больше не ссылается ни одно
+// вложение, вызывает deleteBlob. Подсчёт ссылок и удаление содержимого выполняются под
+// блокировкой хэша, которую берёт и Create. Ошибка deleteBlob откатывает удаление.
+func (r *AttachmentRepo) Delete(ctx context.Context, taskID, id int, deleteBlob BlobFunc) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logError(ctx, "Begin transaction in Delete attachment", err)
+		return err
+	}
+
+	defer rollback(ctx, tx, "Delete attachment")
+
+	var hash string
+	if err := tx.GetContext(ctx, &hash, DeleteAttachmentQuery, id, taskID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logError(ctx, "DeleteAttachmentQuery", err)
+		}
+
+		return err
+	}
+
+	if err := lockAttachmentHash(ctx, tx, hash); err != nil {
+		return err
+	}
+
+	// После блокировки запрос видит вложения, добавленные параллельными загрузками
+	var count int
+	if err := tx.GetContext(ctx, &count, CountAttachmentsByHashQuery, hash); err != nil {
+		logError(ctx, "CountAttachmentsByHashQuery", err)
+		return err
+	}
+
+	if count == 0 {
+		if err := deleteBlob(ctx, hash); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logError(ctx, "Commit in Delete attachment", err)
+		return err
+	}
+
+	return nil
+}
+
+func lockAttachmentHash(ctx context.Context, tx *sqlx.Tx, hash string) error {
+	if _, err := tx.ExecContext(ctx, LockAttachmentHashQuery, hash); err != nil {
+		logError(ctx, "LockAttachmentHashQuery", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentColumns = []string{"id", "task_id", "file_name", "content_type", "size", "hash", "created_at"}
+
+func TestAttachmentRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewAttachmentRepo(sqlx.NewDb(db, "sqlmock"))
+
+	attachment := &models.Attachment{
+		TaskID:      1,
+		FileName:    "screenshot.png",
+		ContentType: "image/png",
+		Size:        42,
+		Hash:        "abc",
+	}
+
+	var stored []string
+
+	// Содержимое сохраняется под блокировкой хэша до вставки записи
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\('attachment:' \|\| \$1\)\)`).
+		WithArgs("abc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO public.attachments`).
+		WithArgs(attachment.TaskID, attachment.FileName, attachment.ContentType, attachment.Size, attachment.Hash).
+		WillReturnRows(sqlmock.NewRows(attachmentColumns).
+			AddRow(1, 1, "screenshot.png", "image/png", 42, "abc", time.Now()))
+	mock.ExpectCommit()
+
+	created, err := repo.Create(context.Background(), attachment, func(ctx context.Context, hash string) error {
+		stored = append(stored, hash)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+	assert.Equal(t, "screenshot.png", created.FileName)
+	assert.Equal(t, []string{"abc"}, stored)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttachmentRepo_Create_BlobFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewAttachmentRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs("abc").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = repo.Create(context.Background(), &models.Attachment{TaskID: 1, Hash: "abc"},
+		func(ctx context.Context, hash string) error { return errors.New("disk full") })
+
+	assert.EqualError(t, err, "disk full")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttachmentRepo_GetByTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewAttachmentRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT (.+) FROM public.attachments WHERE task_id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(attachmentColumns).
+			AddRow(1, 1, "a.png", "image/png", 10, "h1", time.Now()).
+			AddRow(2, 1, "b.pdf", "application/pdf", 20, "h2", time.Now()))
+
+	attachments, err := repo.GetByTask(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+	assert.Equal(t, "b.pdf", attachments[1].FileName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttachmentRepo_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewAttachmentRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM public.attachments WHERE id = \$1 AND task_id = \$2 RETURNING hash`).
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectRollback()
+
+	err = repo.Delete(context.Background(), 1, 5, func(ctx context.Context, hash string) error {
+		t.Fatalf("blob %s must not be deleted", hash)
+		return nil
+	})
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttachmentRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewAttachmentRepo(sqlx.NewDb(db, "sqlmock"))
+
+	var deleted []string
+	deleteBlob := func(ctx context.Context, hash string) error {
+		deleted = append(deleted, hash)
+		return nil
+	}
+
+	expectDelete := func(id int, hash string, references int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`DELETE FROM public.attachments`).
+			WithArgs(id, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow(hash))
+		mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs(hash).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM public.attachments WHERE hash = \$1`).
+			WithArgs(hash).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(references))
+		mock.ExpectCommit()
+	}
+
+	// Содержимое, на которое ссылается другое вложение, остаётся в хранилище
+	expectDelete(1, "shared", 1)
+	expectDelete(2, "single", 0)
+
+	assert.NoError(t, repo.Delete(context.Background(), 1, 1, deleteBlob))
+	assert.NoError(t, repo.Delete(context.Background(), 1, 2, deleteBlob))
+	assert.Equal(t, []string{"single"}, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttachmentRepo_Delete_BlobFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewAttachmentRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Ошибка хранилища откатывает удаление записи
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM public.attachments`).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("single"))
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs("single").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COUNT`).WithArgs("single").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	err = repo.Delete(context.Background(), 1, 2, func(ctx context.Context, hash string) error {
+		return errors.New("storage unavailable")
+	})
+
+	assert.EqualError(t, err, "storage unavailable")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
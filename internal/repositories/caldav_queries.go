@@ -0,0 +1,18 @@
+package repositories
+
+const (
+	GetCalDAVObjectByNameQuery = `
+	SELECT task_id, user_id, name, uid
+	FROM public.caldav_objects
+	WHERE user_id = $1 AND name = $2;`
+
+	GetCalDAVObjectsByUserQuery = `
+	SELECT task_id, user_id, name, uid
+	FROM public.caldav_objects
+	WHERE user_id = $1;`
+
+	SaveCalDAVObjectQuery = `
+	INSERT INTO public.caldav_objects (task_id, user_id, name, uid)
+	VALUES (:task_id, :user_id, :name, :uid)
+	ON CONFLICT (task_id) DO UPDATE SET name = EXCLUDED.name, uid = EXCLUDED.uid;`
+)
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CalDAVRepository interface {
+	GetByName(ctx context.Context, userID int, name string) (*models.CalDAVObject, error)
+	GetByUser(ctx context.Context, userID int) ([]models.CalDAVObject, error)
+	Save(ctx context.Context, object models.CalDAVObject) error
+}
+
+type CalDAVRepo struct {
+	db *sqlx.DB
+}
+
+func NewCalDAVRepo(db *sqlx.DB) CalDAVRepository {
+	return &CalDAVRepo{db: db}
+}
+
+// GetByName возвращает sql.ErrNoRows, если клиент не создавал ресурс с таким именем.
+func (r *CalDAVRepo) GetByName(ctx context.Context, userID int, name string) (*models.CalDAVObject, error) {
+	var object models.CalDAVObject
+
+	if err := r.db.GetContext(ctx, &object, GetCalDAVObjectByNameQuery, userID, name); err != nil {
+		logError(ctx, "GetCalDAVObjectByNameQuery", err)
+		return nil, err
+	}
+
+	return &object, nil
+}
+
+func (r *CalDAVRepo) GetByUser(ctx context.Context, userID int) ([]models.CalDAVObject, error) {
+	objects := []models.CalDAVObject{}
+
+	if err := r.db.SelectContext(ctx, &objects, GetCalDAVObjectsByUserQuery, userID); err != nil {
+		logError(ctx, "GetCalDAVObjectsByUserQuery", err)
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// Save запоминает имя и UID ресурса задачи; повторное сохранение их заменяет.
+// Имя уже занятое другой задачей пользователя даёт ErrDuplicate.
+func (r *CalDAVRepo) Save(ctx context.Context, object models.CalDAVObject) error {
+	_, err := r.db.NamedExecContext(ctx, SaveCalDAVObjectQuery, object)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+
+	if err != nil {
+		logError(ctx, "SaveCalDAVObjectQuery", err)
+	}
+
+	return err
+}
@@ -0,0 +1,48 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalDAVRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewCalDAVRepo(sqlx.NewDb(db, "sqlmock"))
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT task_id, user_id, name, uid FROM public.caldav_objects WHERE user_id = \$1 AND name = \$2`).
+		WithArgs(7, "phone.ics").
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "user_id", "name", "uid"}).AddRow(3, 7, "phone.ics", "abc@phone"))
+	mock.ExpectQuery(`FROM public.caldav_objects WHERE user_id = \$1 AND name = \$2`).
+		WithArgs(7, "missing.ics").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(`INSERT INTO public.caldav_objects .+ ON CONFLICT \(task_id\) DO UPDATE`).
+		WithArgs(4, 7, "taken.ics", "xyz").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	object, err := repo.GetByName(ctx, 7, "phone.ics")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, object.TaskID)
+	assert.Equal(t, "abc@phone", object.UID)
+
+	_, err = repo.GetByName(ctx, 7, "missing.ics")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repo.Save(ctx, models.CalDAVObject{TaskID: 4, UserID: 7, Name: "taken.ics", UID: "xyz"})
+	assert.ErrorIs(t, err, repositories.ErrDuplicate)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,22 @@
+package repositories
+
+const (
+	// EnsureCalendarTokenQuery сохраняет токен, если у пользователя его ещё нет,
+	// и возвращает действующий токен.
+	EnsureCalendarTokenQuery = `
+	INSERT INTO public.calendar_feeds (user_id, token)
+	VALUES ($1, $2)
+	ON CONFLICT (user_id) DO UPDATE SET token = calendar_feeds.token
+	RETURNING token;`
+
+	ReplaceCalendarTokenQuery = `
+	INSERT INTO public.calendar_feeds (user_id, token)
+	VALUES ($1, $2)
+	ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+	RETURNING token;`
+
+	GetUserIDByCalendarTokenQuery = `
+	SELECT user_id
+	FROM public.calendar_feeds
+	WHERE token = $1;`
+)
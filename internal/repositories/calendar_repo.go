@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CalendarRepository interface {
+	EnsureToken(ctx context.Context, userID int, token string) (string, error)
+	ReplaceToken(ctx context.Context, userID int, token string) (string, error)
+	GetUserIDByToken(ctx context.Context, token string) (int, error)
+}
+
+type CalendarRepo struct {
+	db *sqlx.DB
+}
+
+func NewCalendarRepo(db *sqlx.DB) CalendarRepository {
+	return &CalendarRepo{db: db}
+}
+
+// EnsureToken возвращает токен пользователя, сохраняя token, если токена ещё нет.
+func (r *CalendarRepo) EnsureToken(ctx context.Context, userID int, token string) (string, error) {
+	var stored string
+
+	if err := r.db.GetContext(ctx, &stored, EnsureCalendarTokenQuery, userID, token); err != nil {
+		logError(ctx, "EnsureCalendarTokenQuery", err)
+		return "", err
+	}
+
+	return stored, nil
+}
+
+func (r *CalendarRepo) ReplaceToken(ctx context.Context, userID int, token string) (string, error) {
+	var stored string
+
+	if err := r.db.GetContext(ctx, &stored, ReplaceCalendarTokenQuery, userID, token); err != nil {
+		logError(ctx, "ReplaceCalendarTokenQuery", err)
+		return "", err
+	}
+
+	return stored, nil
+}
+
+// GetUserIDByToken возвращает sql.ErrNoRows для неизвестного токена.
+func (r *CalendarRepo) GetUserIDByToken(ctx context.Context, token string) (int, error) {
+	var userID int
+
+	if err := r.db.GetContext(ctx, &userID, GetUserIDByCalendarTokenQuery, token); err != nil {
+		logError(ctx, "GetUserIDByCalendarTokenQuery", err)
+		return 0, err
+	}
+
+	return userID, nil
+}
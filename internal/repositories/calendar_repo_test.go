@@ -0,0 +1,47 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarRepo_Tokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewCalendarRepo(sqlx.NewDb(db, "sqlmock"))
+	ctx := context.Background()
+
+	// Существующий токен не перезаписывается
+	mock.ExpectQuery(`INSERT INTO public.calendar_feeds .+ DO UPDATE SET token = calendar_feeds.token`).
+		WithArgs(7, "candidate").
+		WillReturnRows(sqlmock.NewRows([]string{"token"}).AddRow("existing"))
+	mock.ExpectQuery(`SELECT user_id FROM public.calendar_feeds WHERE token = \$1`).
+		WithArgs("existing").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+	mock.ExpectQuery(`SELECT user_id FROM public.calendar_feeds WHERE token = \$1`).
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	token, err := repo.EnsureToken(ctx, 7, "candidate")
+	assert.NoError(t, err)
+	assert.Equal(t, "existing", token)
+
+	userID, err := repo.GetUserIDByToken(ctx, "existing")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, userID)
+
+	_, err = repo.GetUserIDByToken(ctx, "unknown")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
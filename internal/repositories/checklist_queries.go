@@ -0,0 +1,23 @@
+package repositories
+
+const (
+	AddChecklistItemQuery = `
+	INSERT INTO public.task_checklist_items (task_id, position, text)
+	VALUES ($1, $2, $3);`
+
+	AddDoneChecklistItemQuery = `
+	INSERT INTO public.task_checklist_items (task_id, position, text, done)
+	VALUES ($1, $2, $3, TRUE);`
+
+	GetChecklistByTaskQuery = `
+	SELECT id, task_id, position, text, done
+	FROM public.task_checklist_items
+	WHERE task_id = $1
+	ORDER BY position, id;`
+
+	SetChecklistItemDoneQuery = `
+	UPDATE public.task_checklist_items
+	SET done = $3
+	WHERE id = $1 AND task_id = $2
+	RETURNING id, task_id, position, text, done;`
+)
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ChecklistRepository interface {
+	GetByTask(ctx context.Context, taskID int) ([]models.ChecklistItem, error)
+	SetDone(ctx context.Context, taskID, id int, done bool) (*models.ChecklistItem, error)
+}
+
+type ChecklistRepo struct {
+	db *sqlx.DB
+}
+
+func NewChecklistRepo(db *sqlx.DB) ChecklistRepository {
+	return &ChecklistRepo{db: db}
+}
+
+func (r *ChecklistRepo) GetByTask(ctx context.Context, taskID int) ([]models.ChecklistItem, error) {
+	items := []models.ChecklistItem{}
+
+	err := r.db.SelectContext(ctx, &items, GetChecklistByTaskQuery, taskID)
+	if err != nil {
+		logError(ctx, "GetChecklistByTaskQuery", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetDone возвращает sql.ErrNoRows, если у задачи нет такого пункта.
+func (r *ChecklistRepo) SetDone(ctx context.Context, taskID, id int, done bool) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+
+	err := r.db.GetContext(ctx, &item, SetChecklistItemDoneQuery, id, taskID, done)
+	if err != nil {
+		logError(ctx, "SetChecklistItemDoneQuery", err)
+		return nil, err
+	}
+
+	return &item, nil
+}
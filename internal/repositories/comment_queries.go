@@ -0,0 +1,13 @@
+package repositories
+
+const (
+	AddCommentQuery = `
+	INSERT INTO public.task_comments (task_id, author, body, created_at)
+	VALUES ($1, $2, $3, $4);`
+
+	GetCommentsByTaskQuery = `
+	SELECT id, task_id, author, body, created_at
+	FROM public.task_comments
+	WHERE task_id = $1
+	ORDER BY created_at, id;`
+)
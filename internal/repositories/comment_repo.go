@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CommentRepository interface {
+	GetByTask(ctx context.Context, taskID int) ([]models.Comment, error)
+}
+
+type CommentRepo struct {
+	db *sqlx.DB
+}
+
+func NewCommentRepo(db *sqlx.DB) CommentRepository {
+	return &CommentRepo{db: db}
+}
+
+func (r *CommentRepo) GetByTask(ctx context.Context, taskID int) ([]models.Comment, error) {
+	comments := []models.Comment{}
+
+	err := r.db.SelectContext(ctx, &comments, GetCommentsByTaskQuery, taskID)
+	if err != nil {
+		logError(ctx, "GetCommentsByTaskQuery", err)
+		return nil, err
+	}
+
+	return comments, nil
+}
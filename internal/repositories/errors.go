@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrDuplicate возвращается, когда запись нарушает уникальное ограничение.
+var ErrDuplicate = errors.New("duplicate record")
+
+// ErrMissingReference возвращается, когда запись ссылается на несуществующую строку.
+var ErrMissingReference = errors.New("referenced record does not exist")
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,3 @@
+package repositories
+
+const GetSchemaVersionQuery = `SELECT version FROM public.schema_version LIMIT 1;`
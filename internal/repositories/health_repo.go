@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// HealthRepository отвечает на вопросы проверок готовности о базе данных.
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (int, error)
+	Stats() sql.DBStats
+}
+
+type HealthRepo struct {
+	db *sqlx.DB
+}
+
+func NewHealthRepo(db *sqlx.DB) HealthRepository {
+	return &HealthRepo{db: db}
+}
+
+func (r *HealthRepo) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// SchemaVersion возвращает версию, записанную последним применением миграций.
+func (r *HealthRepo) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+
+	if err := r.db.GetContext(ctx, &version, GetSchemaVersionQuery); err != nil {
+		logError(ctx, "GetSchemaVersionQuery", err)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (r *HealthRepo) Stats() sql.DBStats {
+	return r.db.Stats()
+}
@@ -0,0 +1,30 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/repositories"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthRepo_SchemaVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewHealthRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT version FROM public.schema_version`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(42))
+
+	version, err := repo.SchemaVersion(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
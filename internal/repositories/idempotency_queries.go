@@ -0,0 +1,31 @@
+package repositories
+
+const (
+	// ReserveIdempotencyKeyQuery занимает ключ; просроченную, но ещё не удалённую запись
+	// переиспользует, действующую оставляет без изменений.
+	ReserveIdempotencyKeyQuery = `
+	INSERT INTO public.idempotency_keys (key, user_id, endpoint, fingerprint, created_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (key, user_id, endpoint) DO UPDATE
+	SET fingerprint = EXCLUDED.fingerprint, status = 0, content_type = '', body = NULL,
+		created_at = EXCLUDED.created_at
+	WHERE idempotency_keys.created_at < $6;`
+
+	GetIdempotencyKeyQuery = `
+	SELECT key, user_id, endpoint, fingerprint, status, content_type, body, created_at
+	FROM public.idempotency_keys
+	WHERE key = $1 AND user_id = $2 AND endpoint = $3;`
+
+	CompleteIdempotencyKeyQuery = `
+	UPDATE public.idempotency_keys
+	SET status = $4, content_type = $5, body = $6
+	WHERE key = $1 AND user_id = $2 AND endpoint = $3;`
+
+	DeleteIdempotencyKeyQuery = `
+	DELETE FROM public.idempotency_keys
+	WHERE key = $1 AND user_id = $2 AND endpoint = $3;`
+
+	DeleteExpiredIdempotencyKeysQuery = `
+	DELETE FROM public.idempotency_keys
+	WHERE created_at < $1;`
+)
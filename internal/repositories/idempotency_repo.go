@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record models.IdempotencyRecord, expiredBefore time.Time) (bool, error)
+	Get(ctx context.Context, key string, userID int, endpoint string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record models.IdempotencyRecord) error
+	Delete(ctx context.Context, key string, userID int, endpoint string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type IdempotencyRepo struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyRepo(db *sqlx.DB) IdempotencyRepository {
+	return &IdempotencyRepo{db: db}
+}
+
+// Reserve возвращает true, если ключ занят этим запросом, и false, если по ключу
+// уже есть действующая запись.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, record models.IdempotencyRecord, expiredBefore time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, ReserveIdempotencyKeyQuery,
+		record.Key, record.UserID, record.Endpoint, record.Fingerprint, record.CreatedAt, expiredBefore)
+	if err != nil {
+		logError(ctx, "ReserveIdempotencyKeyQuery", err)
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logError(ctx, "RowsAffected in Reserve", err)
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *IdempotencyRepo) Get(ctx context.Context, key string, userID int, endpoint string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+
+	err := r.db.GetContext(ctx, &record, GetIdempotencyKeyQuery, key, userID, endpoint)
+	if err != nil {
+		logError(ctx, "GetIdempotencyKeyQuery", err)
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, CompleteIdempotencyKeyQuery,
+		record.Key, record.UserID, record.Endpoint, record.Status, record.ContentType, record.Body)
+	if err != nil {
+		logError(ctx, "CompleteIdempotencyKeyQuery", err)
+	}
+
+	return err
+}
+
+func (r *IdempotencyRepo) Delete(ctx context.Context, key string, userID int, endpoint string) error {
+	_, err := r.db.ExecContext(ctx, DeleteIdempotencyKeyQuery, key, userID, endpoint)
+	if err != nil {
+		logError(ctx, "DeleteIdempotencyKeyQuery", err)
+	}
+
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, DeleteExpiredIdempotencyKeysQuery, before)
+	if err != nil {
+		logError(ctx, "DeleteExpiredIdempotencyKeysQuery", err)
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,66 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepo_Reserve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewIdempotencyRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	expiredBefore := now.Add(-time.Hour)
+	record := models.IdempotencyRecord{Key: "k1", UserID: 1, Endpoint: "POST /tasks", Fingerprint: "abc", CreatedAt: now}
+
+	mock.ExpectExec(`INSERT INTO public.idempotency_keys`).
+		WithArgs("k1", 1, "POST /tasks", "abc", now, expiredBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO public.idempotency_keys`).
+		WithArgs("k1", 1, "POST /tasks", "abc", now, expiredBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reserved, err := repo.Reserve(context.Background(), record, expiredBefore)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	// Действующая запись не перезаписывается
+	reserved, err = repo.Reserve(context.Background(), record, expiredBefore)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepo_DeleteExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewIdempotencyRepo(sqlx.NewDb(db, "sqlmock"))
+
+	before := time.Now()
+	mock.ExpectExec(`DELETE FROM public.idempotency_keys WHERE created_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(context.Background(), before)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,29 @@
+package repositories
+
+// taskMemberCondition - условие списка задач по участнику: ID пользователя и роль.
+const taskMemberCondition = "id IN (SELECT task_id FROM public.task_members WHERE user_id = %s AND role = %s)"
+
+const (
+	GetTaskMembersQuery = `
+	SELECT m.user_id, u.name, m.role
+	FROM public.task_members m
+	JOIN public.users u ON u.id = m.user_id
+	WHERE m.task_id = $1
+	ORDER BY u.name, m.user_id;`
+
+	AddTaskMembersQuery = `
+	INSERT INTO public.task_members (task_id, user_id, role)
+	SELECT $1, user_id, $3 FROM unnest($2::INT[]) AS user_id
+	ON CONFLICT DO NOTHING;`
+
+	RemoveTaskMemberQuery = `
+	DELETE FROM public.task_members
+	WHERE task_id = $1 AND user_id = $2 AND role = $3;`
+
+	// Автор задачи и все её участники без повторов
+	GetTaskRecipientsQuery = `
+	SELECT user_id FROM public.tasks WHERE id = $1 AND user_id IS NOT NULL
+	UNION
+	SELECT user_id FROM public.task_members WHERE task_id = $1
+	ORDER BY user_id;`
+)
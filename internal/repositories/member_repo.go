@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type MemberRepository interface {
+	GetByTask(ctx context.Context, taskID int) (models.TaskMembers, error)
+	Add(ctx context.Context, taskID int, role string, userIDs []int) error
+	Remove(ctx context.Context, taskID int, role string, userID int) error
+	Recipients(ctx context.Context, taskID int) ([]int, error)
+}
+
+type MemberRepo struct {
+	db *sqlx.DB
+}
+
+func NewMemberRepo(db *sqlx.DB) MemberRepository {
+	return &MemberRepo{db: db}
+}
+
+func (r *MemberRepo) GetByTask(ctx context.Context, taskID int) (models.TaskMembers, error) {
+	var rows []models.TaskMember
+
+	if err := r.db.SelectContext(ctx, &rows, GetTaskMembersQuery, taskID); err != nil {
+		logError(ctx, "GetTaskMembersQuery", err)
+		return models.TaskMembers{}, err
+	}
+
+	members := models.TaskMembers{Assignees: []models.TaskMember{}, Watchers: []models.TaskMember{}}
+
+	for _, member := range rows {
+		if member.Role == models.RoleAssignee {
+			members.Assignees = append(members.Assignees, member)
+		} else {
+			members.Watchers = append(members.Watchers, member)
+		}
+	}
+
+	return members, nil
+}
+
+// Add добавляет участников с ролью role; уже добавленные пропускаются. Несуществующий
+// пользователь даёт ErrMissingReference, и никто из списка не добавляется.
+func (r *MemberRepo) Add(ctx context.Context, taskID int, role string, userIDs []int) error {
+	ids := make(pq.Int64Array, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = int64(id)
+	}
+
+	if _, err := r.db.ExecContext(ctx, AddTaskMembersQuery, taskID, ids, role); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrMissingReference
+		}
+
+		logError(ctx, "AddTaskMembersQuery", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *MemberRepo) Remove(ctx context.Context, taskID int, role string, userID int) error {
+	if _, err := r.db.ExecContext(ctx, RemoveTaskMemberQuery, taskID, userID, role); err != nil {
+		logError(ctx, "RemoveTaskMemberQuery", err)
+		return err
+	}
+
+	return nil
+}
+
+// Recipients возвращает ID автора, исполнителей и наблюдателей задачи по возрастанию.
+func (r *MemberRepo) Recipients(ctx context.Context, taskID int) ([]int, error) {
+	recipients := []int{}
+
+	if err := r.db.SelectContext(ctx, &recipients, GetTaskRecipientsQuery, taskID); err != nil {
+		logError(ctx, "GetTaskRecipientsQuery", err)
+		return nil, err
+	}
+
+	return recipients, nil
+}
@@ -0,0 +1,82 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemberRepo_GetByTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMemberRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT m.user_id, u.name, m.role FROM public.task_members m`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "name", "role"}).
+			AddRow(2, "Jane Smith", models.RoleWatcher).
+			AddRow(1, "John Doe", models.RoleAssignee))
+
+	members, err := repo.GetByTask(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TaskMember{{UserID: 1, Name: "John Doe", Role: models.RoleAssignee}}, members.Assignees)
+	assert.Equal(t, []models.TaskMember{{UserID: 2, Name: "Jane Smith", Role: models.RoleWatcher}}, members.Watchers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemberRepo_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMemberRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`INSERT INTO public.task_members .* unnest\(\$2::INT\[\]\) .* ON CONFLICT DO NOTHING`).
+		WithArgs(1, "{2,3}", models.RoleAssignee).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	mock.ExpectExec(`INSERT INTO public.task_members`).
+		WithArgs(1, "{99}", models.RoleWatcher).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	err = repo.Add(context.Background(), 1, models.RoleAssignee, []int{2, 3})
+	assert.NoError(t, err)
+
+	err = repo.Add(context.Background(), 1, models.RoleWatcher, []int{99})
+	assert.ErrorIs(t, err, repositories.ErrMissingReference)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemberRepo_Recipients(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMemberRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT user_id FROM public.tasks WHERE id = \$1 .* UNION`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(2))
+
+	recipients, err := repo.Recipients(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, recipients)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
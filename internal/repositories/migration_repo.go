@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type MigrationRepository interface {
+	Import(ctx context.Context, projects []models.ImportedProject, ownerID int) ([]models.Project, error)
+}
+
+type MigrationRepo struct {
+	db *sqlx.DB
+}
+
+func NewMigrationRepo(db *sqlx.DB) MigrationRepository {
+	return &MigrationRepo{db: db}
+}
+
+// Import создаёт проекты с задачами одной транзакцией: при ошибке не остаётся ничего.
+func (r *MigrationRepo) Import(ctx context.Context, projects []models.ImportedProject, ownerID int) ([]models.Project, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logError(ctx, "Begin transaction in Import", err)
+		return nil, err
+	}
+
+	defer rollback(ctx, tx, "Import")
+
+	created := make([]models.Project, 0, len(projects))
+
+	for _, imported := range projects {
+		var project models.Project
+
+		if err := tx.GetContext(ctx, &project, CreateProjectQuery, imported.Name, ownerID); err != nil {
+			logError(ctx, "CreateProjectQuery (Import)", err)
+			return nil, err
+		}
+
+		var tasks []models.Task
+
+		for i := range imported.Tasks {
+			tree := imported.Tasks[i]
+			assignProject(&tree, project.ID)
+
+			if err := createTaskTree(ctx, tx, &tree, 0, &tasks); err != nil {
+				return nil, err
+			}
+		}
+
+		created = append(created, project)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logError(ctx, "Commit in Import", err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// assignProject переносит задачу и все её подзадачи в проект. Подзадачи копируются,
+// чтобы не менять деревья вызывающего кода.
+func assignProject(tree *models.TaskTree, projectID int) {
+	tree.Task.ProjectID = projectID
+
+	subtasks := make([]models.TaskTree, len(tree.Subtasks))
+	copy(subtasks, tree.Subtasks)
+
+	for i := range subtasks {
+		assignProject(&subtasks[i], projectID)
+	}
+
+	tree.Subtasks = subtasks
+}
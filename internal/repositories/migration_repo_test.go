@@ -0,0 +1,84 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationRepo_Import(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMigrationRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	projects := []models.ImportedProject{{
+		Name: "Launch",
+		Tasks: []models.TaskTree{{
+			Task:      models.Task{Name: "Write copy", Status: "Doing", Time: now, UserID: 7, Priority: "medium"},
+			Checklist: []string{"Outline", "Draft"},
+			Done:      []bool{true},
+			Comments:  []models.Comment{{Author: "Alice", Body: "First", CreatedAt: now}},
+			Subtasks:  []models.TaskTree{{Task: models.Task{Name: "Proofread", Status: "Doing", Time: now, UserID: 7, Priority: "medium"}}},
+		}},
+	}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO public.projects`).
+		WithArgs("Launch", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_id"}).AddRow(3, "Launch", 7))
+	mock.ExpectQuery(`INSERT INTO public.tasks`).
+		WithArgs("Write copy", "Doing", now, time.Time{}, 7, "medium", 0, 3, 0, []byte("{}"), "", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "project_id"}).AddRow(10, "Write copy", 3))
+	mock.ExpectExec(`INSERT INTO public.task_checklist_items .+ TRUE`).WithArgs(10, 1, "Outline").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO public.task_checklist_items`).WithArgs(10, 2, "Draft").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(`INSERT INTO public.task_comments`).WithArgs(10, "Alice", "First", now).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO public.tasks`).
+		WithArgs("Proofread", "Doing", now, time.Time{}, 7, "medium", 0, 3, 10, []byte("{}"), "", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "project_id", "parent_id"}).AddRow(11, "Proofread", 3, 10))
+	mock.ExpectCommit()
+
+	created, err := repo.Import(context.Background(), projects, 7)
+
+	assert.NoError(t, err)
+	assert.Len(t, created, 1)
+	assert.Equal(t, 3, created[0].ID)
+	assert.Zero(t, projects[0].Tasks[0].Subtasks[0].Task.ProjectID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrationRepo_Import_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewMigrationRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO public.projects`).
+		WithArgs("Launch", 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Launch"))
+	mock.ExpectQuery(`INSERT INTO public.tasks`).WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	_, err = repo.Import(context.Background(), []models.ImportedProject{{
+		Name:  "Launch",
+		Tasks: []models.TaskTree{{Task: models.Task{Name: "Task"}}},
+	}}, 7)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
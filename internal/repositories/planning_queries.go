@@ -0,0 +1,46 @@
+package repositories
+
+const (
+	AddDependencyQuery = `
+	INSERT INTO public.task_dependencies (task_id, depends_on_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+
+	RemoveDependencyQuery = `
+	DELETE FROM public.task_dependencies
+	WHERE task_id = $1 AND depends_on_id = $2;`
+
+	GetDependenciesQuery = `
+	SELECT t.id, t.name, t.status, t.time, t.due, COALESCE(t.user_id, 0) AS user_id, t.priority, t.estimate_minutes
+	FROM public.task_dependencies d
+	JOIN public.tasks t ON t.id = d.depends_on_id
+	WHERE d.task_id = $1
+	ORDER BY t.id;`
+
+	// HasDependencyPathQuery проверяет, зависит ли $1 (транзитивно) от $2.
+	HasDependencyPathQuery = `
+	WITH RECURSIVE chain AS (
+		SELECT depends_on_id FROM public.task_dependencies WHERE task_id = $1
+		UNION
+		SELECT d.depends_on_id
+		FROM public.task_dependencies d
+		JOIN chain c ON d.task_id = c.depends_on_id
+	)
+	SELECT EXISTS (SELECT 1 FROM chain WHERE depends_on_id = $2);`
+
+	// GetNextUpCandidatesQuery выбирает открытые задачи пользователя вместе с числом
+	// незакрытых блокирующих задач и числом задач, которые ждут текущую.
+	// $2 - список закрытых статусов в нижнем регистре.
+	GetNextUpCandidatesQuery = `
+	SELECT t.id, t.name, t.status, t.time, t.due, COALESCE(t.user_id, 0) AS user_id, t.priority, t.estimate_minutes,
+		(SELECT COUNT(*)
+			FROM public.task_dependencies d
+			JOIN public.tasks b ON b.id = d.depends_on_id
+			WHERE d.task_id = t.id AND LOWER(b.status) <> ALL($2)) AS open_blockers,
+		(SELECT COUNT(*)
+			FROM public.task_dependencies d
+			JOIN public.tasks w ON w.id = d.task_id
+			WHERE d.depends_on_id = t.id AND LOWER(w.status) <> ALL($2)) AS blocks
+	FROM public.tasks t
+	WHERE t.user_id = $1 AND LOWER(t.status) <> ALL($2);`
+)
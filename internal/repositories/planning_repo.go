@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PlanningRepository interface {
+	AddDependency(ctx context.Context, taskID, dependsOnID int) error
+	RemoveDependency(ctx context.Context, taskID, dependsOnID int) error
+	GetDependencies(ctx context.Context, taskID int) ([]models.Task, error)
+	HasDependencyPath(ctx context.Context, fromID, toID int) (bool, error)
+	GetNextUpCandidates(ctx context.Context, userID int) ([]models.TaskCandidate, error)
+}
+
+type PlanningRepo struct {
+	db *sqlx.DB
+}
+
+func NewPlanningRepo(db *sqlx.DB) PlanningRepository {
+	return &PlanningRepo{db: db}
+}
+
+func (r *PlanningRepo) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	_, err := r.db.ExecContext(ctx, AddDependencyQuery, taskID, dependsOnID)
+	if err != nil {
+		logError(ctx, "AddDependencyQuery", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PlanningRepo) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	_, err := r.db.ExecContext(ctx, RemoveDependencyQuery, taskID, dependsOnID)
+	if err != nil {
+		logError(ctx, "RemoveDependencyQuery", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PlanningRepo) GetDependencies(ctx context.Context, taskID int) ([]models.Task, error) {
+	tasks := []models.Task{}
+
+	err := r.db.SelectContext(ctx, &tasks, GetDependenciesQuery, taskID)
+	if err != nil {
+		logError(ctx, "GetDependenciesQuery", err)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *PlanningRepo) HasDependencyPath(ctx context.Context, fromID, toID int) (bool, error) {
+	var exists bool
+
+	err := r.db.GetContext(ctx, &exists, HasDependencyPathQuery, fromID, toID)
+	if err != nil {
+		logError(ctx, "HasDependencyPathQuery", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *PlanningRepo) GetNextUpCandidates(ctx context.Context, userID int) ([]models.TaskCandidate, error) {
+	candidates := []models.TaskCandidate{}
+
+	err := r.db.SelectContext(ctx, &candidates, GetNextUpCandidatesQuery, userID, pq.Array(models.ClosedStatuses))
+	if err != nil {
+		logError(ctx, "GetNextUpCandidatesQuery", err)
+		return nil, err
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,78 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanningRepo_GetNextUpCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewPlanningRepo(sqlx.NewDb(db, "sqlmock"))
+
+	columns := []string{"id", "name", "status", "time", "due", "user_id", "priority", "estimate_minutes", "open_blockers", "blocks"}
+
+	mock.ExpectQuery(`SELECT (.+) FROM public.tasks t WHERE t.user_id = \$1`).
+		WithArgs(7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Deploy", "Pending", time.Now(), time.Now(), 7, "high", 60, 1, 0).
+			AddRow(2, "Migrate DB", "In Progress", time.Now(), time.Now(), 7, "medium", 30, 0, 2))
+
+	candidates, err := repo.GetNextUpCandidates(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 2)
+	assert.Equal(t, "high", candidates[0].Priority)
+	assert.Equal(t, 1, candidates[0].OpenBlockers)
+	assert.Equal(t, 2, candidates[1].Blocks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlanningRepo_HasDependencyPath(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewPlanningRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`WITH RECURSIVE chain`).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := repo.HasDependencyPath(context.Background(), 2, 1)
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlanningRepo_AddDependency(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewPlanningRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`INSERT INTO public.task_dependencies`).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.AddDependency(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import "fmt"
+
+// projectMemberCondition - пользователь %[2]s участвует в проекте %[1]s: владеет им или
+// добавлен владельцем в project_members. Поля задач не учитываются: их задаёт клиент.
+const projectMemberCondition = `(
+	EXISTS (SELECT 1 FROM public.projects p WHERE p.id = %[1]s AND p.owner_id = %[2]s)
+	OR EXISTS (SELECT 1 FROM public.project_members pm WHERE pm.project_id = %[1]s AND pm.user_id = %[2]s))`
+
+var IsProjectMemberQuery = `SELECT ` + fmt.Sprintf(projectMemberCondition, "$1", "$2") + `;`
+
+const (
+	CreateProjectQuery = `
+	INSERT INTO public.projects (name, owner_id)
+	VALUES ($1, NULLIF($2, 0))
+	RETURNING id, name, COALESCE(owner_id, 0) AS owner_id, created_at;`
+
+	GetProjectByIDQuery = `
+	SELECT id, name, COALESCE(owner_id, 0) AS owner_id, created_at
+	FROM public.projects
+	WHERE id = $1;`
+
+	GetAllProjectsQuery = `
+	SELECT id, name, COALESCE(owner_id, 0) AS owner_id, created_at
+	FROM public.projects
+	ORDER BY id;`
+
+	CreateCustomFieldQuery = `
+	INSERT INTO public.custom_fields (project_id, name, type, options, required)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, project_id, name, type, options, required;`
+
+	GetCustomFieldsByProjectQuery = `
+	SELECT id, project_id, name, type, options, required
+	FROM public.custom_fields
+	WHERE project_id = $1
+	ORDER BY id;`
+
+	AddProjectMemberQuery = `
+	INSERT INTO public.project_members (project_id, user_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+
+	RemoveProjectMemberQuery = `
+	DELETE FROM public.project_members
+	WHERE project_id = $1 AND user_id = $2;`
+
+	DeleteCustomFieldQuery = `
+	DELETE FROM public.custom_fields
+	WHERE id = $1 AND project_id = $2;`
+)
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ProjectRepository interface {
+	Create(ctx context.Context, project *models.Project) (*models.Project, error)
+	GetByID(ctx context.Context, id int) (*models.Project, error)
+	GetAll(ctx context.Context) ([]models.Project, error)
+	IsMember(ctx context.Context, projectID, userID int) (bool, error)
+	AddMember(ctx context.Context, projectID, userID int) error
+	RemoveMember(ctx context.Context, projectID, userID int) error
+	CreateField(ctx context.Context, field *models.CustomField) (*models.CustomField, error)
+	GetFields(ctx context.Context, projectID int) ([]models.CustomField, error)
+	DeleteField(ctx context.Context, projectID, id int) error
+}
+
+type ProjectRepo struct {
+	db *sqlx.DB
+}
+
+func NewProjectRepo(db *sqlx.DB) ProjectRepository {
+	return &ProjectRepo{db: db}
+}
+
+func (r *ProjectRepo) Create(ctx context.Context, project *models.Project) (*models.Project, error) {
+	var created models.Project
+
+	err := r.db.GetContext(ctx, &created, CreateProjectQuery, project.Name, project.OwnerID)
+	if err != nil {
+		logError(ctx, "CreateProjectQuery", err)
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *ProjectRepo) GetByID(ctx context.Context, id int) (*models.Project, error) {
+	var project models.Project
+
+	err := r.db.GetContext(ctx, &project, GetProjectByIDQuery, id)
+	if err != nil {
+		logError(ctx, "GetProjectByIDQuery", err)
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *ProjectRepo) GetAll(ctx context.Context) ([]models.Project, error) {
+	projects := []models.Project{}
+
+	err := r.db.SelectContext(ctx, &projects, GetAllProjectsQuery)
+	if err != nil {
+		logError(ctx, "GetAllProjectsQuery", err)
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// IsMember сообщает, участвует ли пользователь в проекте: владеет им или добавлен
+// в участники владельцем.
+func (r *ProjectRepo) IsMember(ctx context.Context, projectID, userID int) (bool, error) {
+	var member bool
+
+	err := r.db.GetContext(ctx, &member, IsProjectMemberQuery, projectID, userID)
+	if err != nil {
+		logError(ctx, "IsProjectMemberQuery", err)
+		return false, err
+	}
+
+	return member, nil
+}
+
+// AddMember добавляет участника проекта; повторное добавление ничего не меняет.
+func (r *ProjectRepo) AddMember(ctx context.Context, projectID, userID int) error {
+	if _, err := r.db.ExecContext(ctx, AddProjectMemberQuery, projectID, userID); err != nil {
+		logError(ctx, "AddProjectMemberQuery", err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveMember возвращает sql.ErrNoRows, если пользователь не был участником проекта.
+func (r *ProjectRepo) RemoveMember(ctx context.Context, projectID, userID int) error {
+	result, err := r.db.ExecContext(ctx, RemoveProjectMemberQuery, projectID, userID)
+	if err != nil {
+		logError(ctx, "RemoveProjectMemberQuery", err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateField возвращает ErrDuplicate, если в проекте уже есть поле с таким именем.
+func (r *ProjectRepo) CreateField(ctx context.Context, field *models.CustomField) (*models.CustomField, error) {
+	var created models.CustomField
+
+	// nil-массив ушёл бы в базу как NULL, а колонка options NOT NULL
+	options := field.Options
+	if options == nil {
+		options = pq.StringArray{}
+	}
+
+	err := r.db.GetContext(ctx, &created, CreateCustomFieldQuery,
+		field.ProjectID, field.Name, field.Type, options, field.Required)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		logError(ctx, "CreateCustomFieldQuery", err)
+
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *ProjectRepo) GetFields(ctx context.Context, projectID int) ([]models.CustomField, error) {
+	fields := []models.CustomField{}
+
+	err := r.db.SelectContext(ctx, &fields, GetCustomFieldsByProjectQuery, projectID)
+	if err != nil {
+		logError(ctx, "GetCustomFieldsByProjectQuery", err)
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func (r *ProjectRepo) DeleteField(ctx context.Context, projectID, id int) error {
+	result, err := r.db.ExecContext(ctx, DeleteCustomFieldQuery, id, projectID)
+	if err != nil {
+		logError(ctx, "DeleteCustomFieldQuery", err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
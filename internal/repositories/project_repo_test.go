@@ -0,0 +1,132 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var customFieldColumns = []string{"id", "project_id", "name", "type", "options", "required"}
+
+func TestProjectRepo_CreateField(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewProjectRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`INSERT INTO public.custom_fields`).
+		WithArgs(1, "stage", "enum", "{\"design\",\"build\"}", false).
+		WillReturnRows(sqlmock.NewRows(customFieldColumns).
+			AddRow(4, 1, "stage", "enum", []byte(`{design,build}`), false))
+
+	created, err := repo.CreateField(context.Background(), &models.CustomField{
+		ProjectID: 1, Name: "stage", Type: "enum", Options: pq.StringArray{"design", "build"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, created.ID)
+	assert.Equal(t, pq.StringArray{"design", "build"}, created.Options)
+
+	// Поле без вариантов пишется пустым массивом, а не NULL
+	mock.ExpectQuery(`INSERT INTO public.custom_fields`).
+		WithArgs(1, "client", "text", "{}", true).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.CreateField(context.Background(), &models.CustomField{ProjectID: 1, Name: "client", Type: "text", Required: true})
+
+	assert.ErrorIs(t, err, repositories.ErrDuplicate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProjectRepo_GetFields(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewProjectRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT (.+) FROM public.custom_fields WHERE project_id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(customFieldColumns).
+			AddRow(1, 1, "client", "text", []byte(`{}`), true).
+			AddRow(2, 1, "points", "number", []byte(`{}`), false))
+
+	fields, err := repo.GetFields(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, fields, 2)
+	assert.True(t, fields[0].Required)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProjectRepo_IsMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewProjectRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`p.owner_id = \$2\) OR EXISTS \(SELECT 1 FROM public.project_members pm WHERE pm.project_id = \$1 AND pm.user_id = \$2\)`).
+		WithArgs(1, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	member, err := repo.IsMember(context.Background(), 1, 7)
+
+	assert.NoError(t, err)
+	assert.False(t, member)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProjectRepo_Members(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewProjectRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`INSERT INTO public.project_members \(project_id, user_id\) VALUES \(\$1, \$2\) ON CONFLICT DO NOTHING`).
+		WithArgs(1, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM public.project_members WHERE project_id = \$1 AND user_id = \$2`).
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.AddMember(context.Background(), 1, 8))
+	assert.ErrorIs(t, repo.RemoveMember(context.Background(), 1, 9), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProjectRepo_DeleteField_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewProjectRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`DELETE FROM public.custom_fields WHERE id = \$1 AND project_id = \$2`).
+		WithArgs(9, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.DeleteField(context.Background(), 1, 9)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,26 +2,43 @@ package repositories
 
 const (
 	CreateTaskQuery = `
-	INSERT INTO public.tasks (name, status, time, due, user_id) 
-VALUES (:name, :status, :time, :due, :user_id) 
-RETURNING id, name, status, time, due, user_id;`
+	INSERT INTO public.tasks (name, status, time, due, user_id, priority, estimate_minutes, project_id, parent_id, custom_fields, recurrence, created_by) 
+VALUES (:name, :status, :time, :due, :user_id, :priority, :estimate_minutes, NULLIF(:project_id, 0), NULLIF(:parent_id, 0), :custom_fields, :recurrence, NULLIF(:created_by, 0)) 
+RETURNING id, name, status, time, due, COALESCE(user_id, 0) AS user_id, COALESCE(project_id, 0) AS project_id, COALESCE(parent_id, 0) AS parent_id, custom_fields, recurrence, priority, estimate_minutes;`
 
 	GetTaskByIDQuery = `
-	SELECT id, name, status, time, due 
+	SELECT id, name, status, time, due, COALESCE(user_id, 0) AS user_id, COALESCE(project_id, 0) AS project_id, COALESCE(parent_id, 0) AS parent_id, custom_fields, recurrence, priority, estimate_minutes, COALESCE(created_by, user_id, 0) AS created_by 
 	FROM public.tasks 
 	WHERE id = $1;`
 
 	GetAllTasksQuery = `
-	SELECT id, name, status, time, due 
+	SELECT id, name, status, time, due, COALESCE(user_id, 0) AS user_id, COALESCE(project_id, 0) AS project_id, COALESCE(parent_id, 0) AS parent_id, custom_fields, recurrence, priority, estimate_minutes 
 	FROM public.tasks;`
 
 	UpdateTaskQuery = `
 	UPDATE public.tasks 
-	SET name = :name, status = :status, time = :time, due = :due 
+	SET name = :name, status = :status, time = :time, due = :due, 
+		priority = :priority, estimate_minutes = :estimate_minutes, 
+		project_id = NULLIF(:project_id, 0), custom_fields = :custom_fields, recurrence = :recurrence 
 	WHERE id = :id 
-	RETURNING id, name, status, time, due;`
+	RETURNING id, name, status, time, due, COALESCE(user_id, 0) AS user_id, COALESCE(project_id, 0) AS project_id, COALESCE(parent_id, 0) AS parent_id, custom_fields, recurrence, priority, estimate_minutes;`
+
+	// ListTasksQuery - основа для отбора задач; условия и сортировка добавляются в TaskRepo.List
+	ListTasksQuery = `
+	SELECT id, name, status, time, due, COALESCE(user_id, 0) AS user_id, COALESCE(project_id, 0) AS project_id, COALESCE(parent_id, 0) AS parent_id, custom_fields, recurrence, priority, estimate_minutes 
+	FROM public.tasks`
 
 	DeleteTaskQuery = `
 	DELETE FROM public.tasks 
 	WHERE id = $1;`
+
+	// Нулевой срок Go (0001-01-01) означает, что срока нет
+	CountOverdueTasksQuery = `
+	SELECT count(*) FROM public.tasks
+	WHERE due > '0001-01-01 00:00:00+00' AND due < $1 AND NOT (lower(status) = ANY($2));`
+
+	// У задач, созданных до появления created_by, автором считается владелец
+	CountOpenTasksByUserQuery = `
+	SELECT count(*) FROM public.tasks
+	WHERE (created_by = $1 OR (created_by IS NULL AND user_id = $1)) AND NOT (lower(status) = ANY($2));`
 )
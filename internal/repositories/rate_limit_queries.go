@@ -0,0 +1,24 @@
+package repositories
+
+// rateLimitRefill - число токенов корзины с учётом пополнения со времени прошлого запроса,
+// не больше ёмкости $2; $3 - скорость пополнения в токенах в секунду. В SET выражение
+// видит значения строки до обновления.
+const rateLimitRefill = `LEAST($2::float8, b.tokens + GREATEST(EXTRACT(EPOCH FROM now() - b.updated_at), 0) * $3::float8)`
+
+// Время берётся из базы, чтобы экземпляры с разными часами считали одинаково.
+// Строка блокируется на время обновления, поэтому параллельные запросы не тратят
+// один и тот же токен.
+const (
+	TakeRateLimitTokenQuery = `
+	INSERT INTO public.rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+	VALUES ($1, $2::float8 - 1, true, now())
+	ON CONFLICT (key) DO UPDATE SET
+		allowed = ` + rateLimitRefill + ` >= 1,
+		tokens = ` + rateLimitRefill + ` - CASE WHEN ` + rateLimitRefill + ` >= 1 THEN 1 ELSE 0 END,
+		updated_at = now()
+	RETURNING tokens, allowed;`
+
+	PurgeRateLimitBucketsQuery = `
+	DELETE FROM public.rate_limit_buckets
+	WHERE updated_at < now() - $1 * interval '1 second';`
+)
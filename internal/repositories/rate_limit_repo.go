@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RateLimitRepository хранит корзины токенов в базе данных, общей для всех экземпляров сервиса.
+type RateLimitRepository interface {
+	Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error)
+	Purge(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+type RateLimitRepo struct {
+	db *sqlx.DB
+}
+
+func NewRateLimitRepo(db *sqlx.DB) RateLimitRepository {
+	return &RateLimitRepo{db: db}
+}
+
+// Take пополняет корзину key со скоростью rate токенов в секунду до burst и забирает
+// из неё токен, если он есть. Возвращает оставшиеся токены и признак, что токен взят.
+func (r *RateLimitRepo) Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	var result struct {
+		Tokens  float64 `db:"tokens"`
+		Allowed bool    `db:"allowed"`
+	}
+
+	if err := r.db.GetContext(ctx, &result, TakeRateLimitTokenQuery, key, burst, rate); err != nil {
+		logError(ctx, "TakeRateLimitTokenQuery", err)
+		return 0, false, err
+	}
+
+	return result.Tokens, result.Allowed, nil
+}
+
+// Purge удаляет корзины, к которым не обращались дольше idle: они уже полны
+// и ничем не отличаются от новых.
+func (r *RateLimitRepo) Purge(ctx context.Context, idle time.Duration) (int64, error) {
+	result, err := r.db.ExecContext(ctx, PurgeRateLimitBucketsQuery, idle.Seconds())
+	if err != nil {
+		logError(ctx, "PurgeRateLimitBucketsQuery", err)
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
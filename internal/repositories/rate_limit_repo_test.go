@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitRepo_Take(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewRateLimitRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`INSERT INTO public.rate_limit_buckets AS b .+ ON CONFLICT \(key\) DO UPDATE SET .+ RETURNING tokens, allowed`).
+		WithArgs("write:user:1", 10, 0.5).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "allowed"}).AddRow(4.5, true))
+
+	tokens, allowed, err := repo.Take(context.Background(), "write:user:1", 0.5, 10)
+
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 4.5, tokens)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimitRepo_TakeError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewRateLimitRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`INSERT INTO public.rate_limit_buckets`).WillReturnError(errors.New("connection refused"))
+
+	_, allowed, err := repo.Take(context.Background(), "read:ip:10.0.0.1", 1, 5)
+
+	assert.Error(t, err)
+	assert.False(t, allowed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimitRepo_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewRateLimitRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`DELETE FROM public.rate_limit_buckets WHERE updated_at < now\(\) - \$1 \* interval '1 second'`).
+		WithArgs(float64(120)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	deleted, err := repo.Purge(context.Background(), 2*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,22 @@
+package repositories
+
+const (
+	GetTagsByTaskQuery = `
+	SELECT tag
+	FROM public.task_tags
+	WHERE task_id = $1
+	ORDER BY tag;`
+
+	AddTaskTagQuery = `
+	INSERT INTO public.task_tags (task_id, tag)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+
+	RemoveTaskTagQuery = `
+	DELETE FROM public.task_tags
+	WHERE task_id = $1 AND tag = $2;`
+
+	DeleteTagsByTaskQuery = `
+	DELETE FROM public.task_tags
+	WHERE task_id = $1;`
+)
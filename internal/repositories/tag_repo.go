@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TagRepository interface {
+	GetByTask(ctx context.Context, taskID int) ([]string, error)
+	Set(ctx context.Context, taskID int, tags []string) error
+	Add(ctx context.Context, taskID int, tag string) error
+	Remove(ctx context.Context, taskID int, tag string) error
+}
+
+type TagRepo struct {
+	db *sqlx.DB
+}
+
+func NewTagRepo(db *sqlx.DB) TagRepository {
+	return &TagRepo{db: db}
+}
+
+func (r *TagRepo) GetByTask(ctx context.Context, taskID int) ([]string, error) {
+	tags := []string{}
+
+	err := r.db.SelectContext(ctx, &tags, GetTagsByTaskQuery, taskID)
+	if err != nil {
+		logError(ctx, "GetTagsByTaskQuery", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// Set заменяет все теги задачи одним набором в рамках транзакции.
+func (r *TagRepo) Set(ctx context.Context, taskID int, tags []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logError(ctx, "Begin transaction in Set", err)
+		return err
+	}
+
+	defer rollback(ctx, tx, "Set")
+
+	if _, err := tx.ExecContext(ctx, DeleteTagsByTaskQuery, taskID); err != nil {
+		logError(ctx, "DeleteTagsByTaskQuery", err)
+		return err
+	}
+
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, AddTaskTagQuery, taskID, tag); err != nil {
+			logError(ctx, "AddTaskTagQuery", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *TagRepo) Add(ctx context.Context, taskID int, tag string) error {
+	_, err := r.db.ExecContext(ctx, AddTaskTagQuery, taskID, tag)
+	if err != nil {
+		logError(ctx, "AddTaskTagQuery", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *TagRepo) Remove(ctx context.Context, taskID int, tag string) error {
+	_, err := r.db.ExecContext(ctx, RemoveTaskTagQuery, taskID, tag)
+	if err != nil {
+		logError(ctx, "RemoveTaskTagQuery", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagRepo_GetByTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTagRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT tag FROM public.task_tags WHERE task_id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}).AddRow("backend").AddRow("urgent"))
+
+	tags, err := repo.GetByTask(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backend", "urgent"}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepo_Set(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTagRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM public.task_tags WHERE task_id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).
+		WithArgs(1, "backend").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).
+		WithArgs(1, "urgent").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.Set(context.Background(), 1, []string{"backend", "urgent"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagRepo_Set_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTagRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM public.task_tags WHERE task_id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).
+		WithArgs(1, "backend").
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	err = repo.Set(context.Background(), 1, []string{"backend"})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BulkError указывает изменение пакета, на котором остановилась транзакция.
+type BulkError struct {
+	Index int
+	Err   error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("change %d: %v", e.Index, e.Err)
+}
+
+func (e *BulkError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyBulk применяет изменения в одной транзакции. Для create и update возвращается
+// записанная задача, для остальных операций - nil. При ошибке транзакция откатывается,
+// а ошибка оборачивается в *BulkError с номером изменения.
+func (r *TaskRepo) ApplyBulk(ctx context.Context, changes []models.TaskChange) ([]*models.Task, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logError(ctx, "Begin transaction in ApplyBulk", err)
+		return nil, err
+	}
+
+	defer rollback(ctx, tx, "ApplyBulk")
+
+	results := make([]*models.Task, len(changes))
+
+	for i, change := range changes {
+		task, err := applyTaskChange(ctx, tx, change)
+		if err != nil {
+			return nil, &BulkError{Index: i, Err: err}
+		}
+
+		results[i] = task
+	}
+
+	if err := tx.Commit(); err != nil {
+		logError(ctx, "Commit in ApplyBulk", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func applyTaskChange(ctx context.Context, tx *sqlx.Tx, change models.TaskChange) (*models.Task, error) {
+	switch change.Op {
+	case models.BulkCreate:
+		created, err := execTaskQuery(ctx, tx, CreateTaskQuery, change.Task)
+		if err != nil {
+			return nil, err
+		}
+
+		return created, addTags(ctx, tx, created.ID, change.Tags)
+
+	case models.BulkUpdate:
+		return execTaskQuery(ctx, tx, UpdateTaskQuery, change.Task)
+
+	case models.BulkDelete:
+		result, err := tx.ExecContext(ctx, DeleteTaskQuery, change.TaskID)
+		if err != nil {
+			logError(ctx, "DeleteTaskQuery", err)
+			return nil, err
+		}
+
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return nil, sql.ErrNoRows
+		}
+
+		return nil, nil
+
+	case models.BulkAddTags:
+		return nil, addTags(ctx, tx, change.TaskID, change.Tags)
+
+	case models.BulkRemoveTags:
+		for _, tag := range change.Tags {
+			if _, err := tx.ExecContext(ctx, RemoveTaskTagQuery, change.TaskID, tag); err != nil {
+				logError(ctx, "RemoveTaskTagQuery", err)
+				return nil, err
+			}
+		}
+
+		return nil, nil
+
+	case models.BulkSetTags:
+		if _, err := tx.ExecContext(ctx, DeleteTagsByTaskQuery, change.TaskID); err != nil {
+			logError(ctx, "DeleteTagsByTaskQuery", err)
+			return nil, err
+		}
+
+		return nil, addTags(ctx, tx, change.TaskID, change.Tags)
+	}
+
+	return nil, fmt.Errorf("unsupported change %q", change.Op)
+}
+
+func addTags(ctx context.Context, tx *sqlx.Tx, taskID int, tags []string) error {
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, AddTaskTagQuery, taskID, tag); err != nil {
+			logError(ctx, "AddTaskTagQuery", err)
+			return err
+		}
+	}
+
+	return nil
+}
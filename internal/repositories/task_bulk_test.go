@@ -0,0 +1,83 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRepo_ApplyBulk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	created := &models.Task{Name: "Deploy", Status: "Pending", Time: now, Priority: "medium"}
+	updated := &models.Task{ID: 2, Name: "Review", Status: "Completed", Time: now, Priority: "high"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO public.tasks`).
+		WithArgs("Deploy", "Pending", now, time.Time{}, 0, "medium", 0, 0, 0, []byte("{}"), "", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(10, "Deploy"))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).WithArgs(10, "release").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`UPDATE public.tasks`).
+		WithArgs("Review", "Completed", now, time.Time{}, "high", 0, 0, []byte("{}"), "", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(2, "Review", "Completed"))
+	mock.ExpectExec(`DELETE FROM public.task_tags WHERE task_id = \$1;`).WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).WithArgs(3, "ops").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM public.tasks`).WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tasks, err := repo.ApplyBulk(context.Background(), []models.TaskChange{
+		{Op: models.BulkCreate, Task: created, Tags: []string{"release"}},
+		{Op: models.BulkUpdate, Task: updated},
+		{Op: models.BulkSetTags, TaskID: 3, Tags: []string{"ops"}},
+		{Op: models.BulkDelete, TaskID: 4},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 4)
+	assert.Equal(t, 10, tasks[0].ID)
+	assert.Equal(t, "Completed", tasks[1].Status)
+	assert.Nil(t, tasks[2])
+	assert.Nil(t, tasks[3])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTaskRepo_ApplyBulk_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO public.task_tags`).WithArgs(1, "ops").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM public.tasks`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = repo.ApplyBulk(context.Background(), []models.TaskChange{
+		{Op: models.BulkAddTags, TaskID: 1, Tags: []string{"ops"}},
+		{Op: models.BulkDelete, TaskID: 2},
+	})
+
+	var bulkErr *repositories.BulkError
+	assert.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, 1, bulkErr.Index)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
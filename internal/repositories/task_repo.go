@@ -4,17 +4,27 @@ import (
 	"WebTasks/internal/models"
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *models.Task) (*models.Task, error)
 	GetByID(ctx context.Context, id int) (*models.Task, error)
 	GetAll(ctx context.Context) ([]models.Task, error)
+	List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error)
+	Stream(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) error
+	ApplyBulk(ctx context.Context, changes []models.TaskChange) ([]*models.Task, error)
 	Update(ctx context.Context, task *models.Task) (*models.Task, error)
 	Delete(ctx context.Context, id int) error
+	CountOverdue(ctx context.Context, now time.Time) (int, error)
+	CountOpenByUser(ctx context.Context, userID int) (int, error)
 }
 
 type TaskRepo struct {
@@ -28,28 +38,28 @@ func RepositoryForTasks(db *sqlx.DB) TaskRepository {
 func (r *TaskRepo) Create(ctx context.Context, task *models.Task) (*models.Task, error) {
 	rows, err := r.db.NamedQueryContext(ctx, CreateTaskQuery, task)
 	if err != nil {
-		log.Printf("Error executing CreateTaskQuery: %v", err)
+		logError(ctx, "CreateTaskQuery", err)
 		return nil, err
 	}
 
 	defer func() {
 		closeErr := rows.Close()
 		if closeErr != nil {
-			log.Printf("Error closing rows in Create: %v", closeErr)
+			logError(ctx, "Close rows in Create", closeErr)
 		}
 	}()
 
 	var createdTask models.Task
 	if rows.Next() {
 		if err := rows.StructScan(&createdTask); err != nil {
-			log.Printf("Error scanning created task: %v", err)
+			logError(ctx, "StructScan (Create)", err)
 			return nil, err
 		}
 
 		return &createdTask, nil
 	}
 
-	log.Printf("Task creation failed, no rows returned")
+	slog.ErrorContext(ctx, "task creation returned no rows")
 
 	// Пустая строка перед return
 	return nil, errors.New("task creation failed: no rows returned")
@@ -60,7 +70,7 @@ func (r *TaskRepo) GetByID(ctx context.Context, id int) (*models.Task, error) {
 
 	err := r.db.GetContext(ctx, &task, GetTaskByIDQuery, id)
 	if err != nil {
-		log.Printf("Error executing GetTaskByIDQuery for id %d: %v", id, err)
+		logError(ctx, "GetTaskByIDQuery", err)
 		return nil, err
 	}
 
@@ -72,38 +82,198 @@ func (r *TaskRepo) GetAll(ctx context.Context) ([]models.Task, error) {
 
 	err := r.db.SelectContext(ctx, &tasks, GetAllTasksQuery)
 	if err != nil {
-		log.Printf("Error executing GetAllTasksQuery: %v", err)
+		logError(ctx, "GetAllTasksQuery", err)
 		return nil, err
 	}
 
 	return tasks, nil
 }
 
+// taskSortColumns - разрешённые поля сортировки списка задач.
+var taskSortColumns = map[string]string{
+	"id":       "id",
+	"name":     "name",
+	"status":   "status",
+	"time":     "time",
+	"due":      "due",
+	"priority": "array_position(ARRAY['low', 'medium', 'high', 'urgent']::VARCHAR[], priority)",
+	"estimate": "estimate_minutes",
+}
+
+// CustomFieldPrefix отмечает пользовательское поле в фильтрах и сортировке.
+const CustomFieldPrefix = "cf."
+
+func (r *TaskRepo) List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	query, args, err := buildListTasksQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+
+	if err := r.db.SelectContext(ctx, &tasks, query, args...); err != nil {
+		logError(ctx, "ListTasksQuery", err)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Stream передаёт задачи по фильтру в fn по одной, не загружая весь список в память.
+// Ошибка fn прерывает чтение и возвращается как есть.
+func (r *TaskRepo) Stream(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) error {
+	query, args, err := buildListTasksQuery(filter)
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		logError(ctx, "ListTasksQuery", err)
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var task models.Task
+		if err := rows.StructScan(&task); err != nil {
+			logError(ctx, "StructScan (Stream)", err)
+			return err
+		}
+
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// buildListTasksQuery дописывает к ListTasksQuery условия и сортировку.
+// Имена пользовательских полей передаются параметрами, в текст запроса попадают только
+// колонки из taskSortColumns.
+func buildListTasksQuery(filter models.TaskFilter) (string, []interface{}, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.UserID != 0 {
+		conditions = append(conditions, "user_id = "+arg(filter.UserID))
+	}
+
+	if filter.AssigneeID != 0 {
+		conditions = append(conditions, fmt.Sprintf(taskMemberCondition, arg(filter.AssigneeID), arg(models.RoleAssignee)))
+	}
+
+	if filter.WatcherID != 0 {
+		conditions = append(conditions, fmt.Sprintf(taskMemberCondition, arg(filter.WatcherID), arg(models.RoleWatcher)))
+	}
+
+	if filter.MemberID != 0 {
+		member := arg(filter.MemberID)
+		conditions = append(conditions, fmt.Sprintf("(user_id = %s OR id IN (SELECT task_id FROM public.task_members WHERE user_id = %s))", member, member))
+	}
+
+	if filter.ProjectID != 0 {
+		conditions = append(conditions, "project_id = "+arg(filter.ProjectID))
+	}
+
+	if filter.ParentID != 0 {
+		conditions = append(conditions, "parent_id = "+arg(filter.ParentID))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+
+	// Сортируем имена, чтобы текст запроса не зависел от порядка обхода map
+	names := make([]string, 0, len(filter.Custom))
+	for name := range filter.Custom {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		conditions = append(conditions, fmt.Sprintf("custom_fields ->> %s = %s", arg(name), arg(filter.Custom[name])))
+	}
+
+	query := ListTasksQuery
+	if len(conditions) > 0 {
+		query += "\n\tWHERE " + strings.Join(conditions, " AND ")
+	}
+
+	order := "id"
+
+	switch {
+	case filter.SortBy == "":
+	case strings.HasPrefix(filter.SortBy, CustomFieldPrefix):
+		order = "custom_fields ->> " + arg(strings.TrimPrefix(filter.SortBy, CustomFieldPrefix))
+		if filter.SortNumeric {
+			order = fmt.Sprintf("(%s)::NUMERIC", order)
+		}
+	default:
+		column, ok := taskSortColumns[filter.SortBy]
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported sort field %q", filter.SortBy)
+		}
+
+		order = column
+	}
+
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+
+	query += fmt.Sprintf("\n\tORDER BY %s %s", order, direction)
+	if order != "id" {
+		query += fmt.Sprintf(" NULLS LAST, id %s", direction)
+	}
+
+	if filter.Limit > 0 {
+		query += "\n\tLIMIT " + arg(filter.Limit)
+	}
+
+	if filter.Offset > 0 {
+		query += " OFFSET " + arg(filter.Offset)
+	}
+
+	return query, args, nil
+}
+
 func (r *TaskRepo) Update(ctx context.Context, task *models.Task) (*models.Task, error) {
 	rows, err := r.db.NamedQueryContext(ctx, UpdateTaskQuery, task)
 	if err != nil {
-		log.Printf("Error executing UpdateTaskQuery: %v", err)
+		logError(ctx, "UpdateTaskQuery", err)
 		return nil, err
 	}
 
 	defer func() {
 		closeErr := rows.Close()
 		if closeErr != nil {
-			log.Printf("Error closing rows in Update: %v", closeErr)
+			logError(ctx, "Close rows in Update", closeErr)
 		}
 	}()
 
 	var updatedTask models.Task
 	if rows.Next() {
 		if err := rows.StructScan(&updatedTask); err != nil {
-			log.Printf("Error scanning updated task: %v", err)
+			logError(ctx, "StructScan (Update)", err)
 			return nil, err
 		}
 
 		return &updatedTask, nil
 	}
 
-	log.Printf("Task update failed, no rows returned")
+	slog.ErrorContext(ctx, "task update returned no rows")
 
 	// Пустая строка перед return
 	return nil, errors.New("task update failed: no rows returned")
@@ -112,9 +282,33 @@ func (r *TaskRepo) Update(ctx context.Context, task *models.Task) (*models.Task,
 func (r *TaskRepo) Delete(ctx context.Context, id int) error {
 	_, err := r.db.ExecContext(ctx, DeleteTaskQuery, id)
 	if err != nil {
-		log.Printf("Error executing DeleteTaskQuery for id %d: %v", id, err)
+		logError(ctx, "DeleteTaskQuery", err)
 		return err
 	}
 
 	return nil
 }
+
+// CountOverdue считает открытые задачи со сроком раньше now.
+func (r *TaskRepo) CountOverdue(ctx context.Context, now time.Time) (int, error) {
+	var count int
+
+	if err := r.db.GetContext(ctx, &count, CountOverdueTasksQuery, now, pq.Array(models.ClosedStatuses)); err != nil {
+		logError(ctx, "CountOverdueTasksQuery", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountOpenByUser считает незакрытые задачи, созданные пользователем, - для квоты quota.max_tasks.
+func (r *TaskRepo) CountOpenByUser(ctx context.Context, userID int) (int, error) {
+	var count int
+
+	if err := r.db.GetContext(ctx, &count, CountOpenTasksByUserQuery, userID, pq.Array(models.ClosedStatuses)); err != nil {
+		logError(ctx, "CountOpenTasksByUserQuery", err)
+		return 0, err
+	}
+
+	return count, nil
+}
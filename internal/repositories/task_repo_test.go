@@ -4,6 +4,7 @@ import (
 	"WebTasks/internal/models"
 	"WebTasks/internal/repositories"
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -31,7 +32,7 @@ func TestTaskRepo_Create(t *testing.T) {
 	}
 
 	mock.ExpectQuery(`INSERT INTO public.tasks`).
-		WithArgs(task.Name, task.Status, task.Time, task.Due, task.UserID).
+		WithArgs(task.Name, task.Status, task.Time, task.Due, task.UserID, task.Priority, task.EstimateMinutes, task.ProjectID, task.ParentID, []byte("{}"), task.Recurrence, task.CreatedBy).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "time", "due", "user_id"}).
 			AddRow(1, "Test Task", "Pending", task.Time, task.Due, 1))
 
@@ -56,7 +57,7 @@ func TestTaskRepo_GetAll(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := repositories.RepositoryForTasks(sqlxDB)
 
-	mock.ExpectQuery(`SELECT id, name, status, time, due FROM public.tasks`).
+	mock.ExpectQuery(`SELECT id, name, status, time, due, (.+), priority, estimate_minutes FROM public.tasks`).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "time", "due"}).
 			AddRow(1, "Task 1", "Pending", time.Now(), time.Now().Add(24*time.Hour)).
 			AddRow(2, "Task 2", "Completed", time.Now(), time.Now().Add(48*time.Hour)))
@@ -81,7 +82,7 @@ func TestTaskRepo_GetById(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := repositories.RepositoryForTasks(sqlxDB)
 
-	mock.ExpectQuery(`SELECT id, name, status, time, due FROM public.tasks WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, status, time, due, (.+), priority, estimate_minutes, COALESCE\(created_by, user_id, 0\) AS created_by FROM public.tasks WHERE id = \$1`).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "time", "due"}).
 			AddRow(1, "Task 1", "Pending", time.Now(), time.Now().Add(24*time.Hour)))
@@ -115,7 +116,7 @@ func TestTaskRepo_Update(t *testing.T) {
 	}
 
 	mock.ExpectQuery(`UPDATE public.tasks SET`).
-		WithArgs(task.Name, task.Status, task.Time, task.Due, task.ID).
+		WithArgs(task.Name, task.Status, task.Time, task.Due, task.Priority, task.EstimateMinutes, task.ProjectID, []byte("{}"), task.Recurrence, task.ID).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "time", "due"}).
 			AddRow(1, "Updated Task", "Completed", task.Time, task.Due))
 
@@ -149,3 +150,136 @@ func TestTaskRepo_Delete(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestTaskRepo_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+
+	filter := models.TaskFilter{
+		ProjectID:   1,
+		Status:      "Pending",
+		Custom:      map[string]string{"stage": "build", "client": "Acme"},
+		SortBy:      "cf.points",
+		Desc:        true,
+		SortNumeric: true,
+		Limit:       20,
+	}
+
+	mock.ExpectQuery(`FROM public.tasks WHERE project_id = \$1 AND status = \$2 `+
+		`AND custom_fields ->> \$3 = \$4 AND custom_fields ->> \$5 = \$6 `+
+		`ORDER BY \(custom_fields ->> \$7\)::NUMERIC DESC NULLS LAST, id DESC LIMIT \$8`).
+		WithArgs(1, "Pending", "client", "Acme", "stage", "build", "points", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "project_id", "custom_fields"}).
+			AddRow(3, "Task 3", 1, []byte(`{"client":"Acme","stage":"build","points":8}`)))
+
+	tasks, err := repo.List(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, float64(8), tasks[0].CustomFields["points"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = repo.List(context.Background(), models.TaskFilter{SortBy: "password"})
+	assert.Error(t, err)
+}
+
+func TestTaskRepo_ListByMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM public.tasks WHERE id IN \(SELECT task_id FROM public.task_members WHERE user_id = \$1 AND role = \$2\) `+
+		`AND \(user_id = \$3 OR id IN \(SELECT task_id FROM public.task_members WHERE user_id = \$3\)\)`).
+		WithArgs(2, models.RoleAssignee, 7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Task 1"))
+
+	tasks, err := repo.List(context.Background(), models.TaskFilter{AssigneeID: 2, MemberID: 7})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTaskRepo_Stream(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`FROM public.tasks WHERE user_id = \$1 ORDER BY id ASC`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id"}).
+			AddRow(1, "Task 1", 7).
+			AddRow(2, "Task 2", 7).
+			AddRow(3, "Task 3", 7))
+
+	// Ошибка обработчика прерывает чтение
+	stop := errors.New("stop")
+
+	var names []string
+	err = repo.Stream(context.Background(), models.TaskFilter{UserID: 7}, func(task models.Task) error {
+		names = append(names, task.Name)
+		if len(names) == 2 {
+			return stop
+		}
+
+		return nil
+	})
+
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"Task 1", "Task 2"}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTaskRepo_CountOverdue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM public.tasks WHERE due > .+ AND due < \$1 AND NOT \(lower\(status\) = ANY\(\$2\)\)`).
+		WithArgs(now, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := repo.CountOverdue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTaskRepo_CountOpenByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.RepositoryForTasks(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM public.tasks WHERE \(created_by = \$1 OR \(created_by IS NULL AND user_id = \$1\)\) AND NOT \(lower\(status\) = ANY\(\$2\)\)`).
+		WithArgs(7, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	count, err := repo.CountOpenByUser(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,22 @@
+package repositories
+
+const (
+	CreateTemplateQuery = `
+	INSERT INTO public.task_templates (name, definition, created_by)
+	VALUES ($1, $2, NULLIF($3, 0))
+	RETURNING id, name, definition, COALESCE(created_by, 0) AS created_by, created_at;`
+
+	GetTemplateByIDQuery = `
+	SELECT id, name, definition, COALESCE(created_by, 0) AS created_by, created_at
+	FROM public.task_templates
+	WHERE id = $1;`
+
+	GetAllTemplatesQuery = `
+	SELECT id, name, definition, COALESCE(created_by, 0) AS created_by, created_at
+	FROM public.task_templates
+	ORDER BY name;`
+
+	DeleteTemplateQuery = `
+	DELETE FROM public.task_templates
+	WHERE id = $1;`
+)
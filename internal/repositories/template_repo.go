@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TemplateRepository interface {
+	Create(ctx context.Context, template *models.TaskTemplate) (*models.TaskTemplate, error)
+	GetByID(ctx context.Context, id int) (*models.TaskTemplate, error)
+	GetAll(ctx context.Context) ([]models.TaskTemplate, error)
+	Delete(ctx context.Context, id int) error
+	Instantiate(ctx context.Context, tree *models.TaskTree) ([]models.Task, error)
+}
+
+type TemplateRepo struct {
+	db *sqlx.DB
+}
+
+func NewTemplateRepo(db *sqlx.DB) TemplateRepository {
+	return &TemplateRepo{db: db}
+}
+
+// Create возвращает ErrDuplicate, если шаблон с таким именем уже есть.
+func (r *TemplateRepo) Create(ctx context.Context, template *models.TaskTemplate) (*models.TaskTemplate, error) {
+	var created models.TaskTemplate
+
+	err := r.db.GetContext(ctx, &created, CreateTemplateQuery, template.Name, template.Task, template.CreatedBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		logError(ctx, "CreateTemplateQuery", err)
+
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *TemplateRepo) GetByID(ctx context.Context, id int) (*models.TaskTemplate, error) {
+	var template models.TaskTemplate
+
+	err := r.db.GetContext(ctx, &template, GetTemplateByIDQuery, id)
+	if err != nil {
+		logError(ctx, "GetTemplateByIDQuery", err)
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *TemplateRepo) GetAll(ctx context.Context) ([]models.TaskTemplate, error) {
+	templates := []models.TaskTemplate{}
+
+	err := r.db.SelectContext(ctx, &templates, GetAllTemplatesQuery)
+	if err != nil {
+		logError(ctx, "GetAllTemplatesQuery", err)
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *TemplateRepo) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, DeleteTemplateQuery, id)
+	if err != nil {
+		logError(ctx, "DeleteTemplateQuery", err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Instantiate создаёт дерево задач с тегами, чек-листами и комментариями в одной транзакции.
+// Задачи возвращаются в порядке обхода: родитель раньше своих подзадач.
+func (r *TemplateRepo) Instantiate(ctx context.Context, tree *models.TaskTree) ([]models.Task, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logError(ctx, "Begin transaction in Instantiate", err)
+		return nil, err
+	}
+
+	defer rollback(ctx, tx, "Instantiate")
+
+	var created []models.Task
+
+	if err := createTaskTree(ctx, tx, tree, 0, &created); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logError(ctx, "Commit in Instantiate", err)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func createTaskTree(ctx context.Context, tx *sqlx.Tx, tree *models.TaskTree, parentID int, created *[]models.Task) error {
+	task := tree.Task
+	task.ParentID = parentID
+
+	inserted, err := execTaskQuery(ctx, tx, CreateTaskQuery, &task)
+	if err != nil {
+		return err
+	}
+
+	if err := addTags(ctx, tx, inserted.ID, tree.Tags); err != nil {
+		return err
+	}
+
+	for i, text := range tree.Checklist {
+		query := AddChecklistItemQuery
+		if i < len(tree.Done) && tree.Done[i] {
+			query = AddDoneChecklistItemQuery
+		}
+
+		if _, err := tx.ExecContext(ctx, query, inserted.ID, i+1, text); err != nil {
+			logError(ctx, "AddChecklistItemQuery", err)
+			return err
+		}
+	}
+
+	for _, comment := range tree.Comments {
+		if _, err := tx.ExecContext(ctx, AddCommentQuery, inserted.ID, comment.Author, comment.Body, comment.CreatedAt); err != nil {
+			logError(ctx, "AddCommentQuery", err)
+			return err
+		}
+	}
+
+	*created = append(*created, *inserted)
+
+	for i := range tree.Subtasks {
+		if err := createTaskTree(ctx, tx, &tree.Subtasks[i], inserted.ID, created); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// execTaskQuery выполняет CreateTaskQuery или UpdateTaskQuery в транзакции и закрывает
+// курсор до следующих запросов. Если строка не вернулась, возвращается sql.ErrNoRows.
+func execTaskQuery(ctx context.Context, tx *sqlx.Tx, query string, task *models.Task) (*models.Task, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, tx, query, task)
+	if err != nil {
+		logError(ctx, "Task query in transaction", err)
+		return nil, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			logError(ctx, "Close rows in execTaskQuery", closeErr)
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, sql.ErrNoRows
+	}
+
+	var result models.Task
+	if err := rows.StructScan(&result); err != nil {
+		logError(ctx, "StructScan (execTaskQuery)", err)
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,76 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRepo_Instantiate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTemplateRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	tree := &models.TaskTree{
+		Task:      models.Task{Name: "Release", Status: "Pending", Time: now, Priority: "medium"},
+		Tags:      []string{"deploy"},
+		Checklist: []string{"Tag build"},
+		Subtasks:  []models.TaskTree{{Task: models.Task{Name: "Migrate", Status: "Pending", Time: now, Priority: "medium"}}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO public.tasks`).
+		WithArgs("Release", "Pending", now, time.Time{}, 0, "medium", 0, 0, 0, []byte("{}"), "", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Release"))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).WithArgs(1, "deploy").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO public.task_checklist_items`).WithArgs(1, 1, "Tag build").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO public.tasks`).
+		WithArgs("Migrate", "Pending", now, time.Time{}, 0, "medium", 0, 0, 1, []byte("{}"), "", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id"}).AddRow(2, "Migrate", 1))
+	mock.ExpectCommit()
+
+	tasks, err := repo.Instantiate(context.Background(), tree)
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, 1, tasks[1].ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTemplateRepo_Instantiate_Rollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTemplateRepo(sqlx.NewDb(db, "sqlmock"))
+
+	tree := &models.TaskTree{
+		Task: models.Task{Name: "Release"},
+		Tags: []string{"deploy"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO public.tasks`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Release"))
+	mock.ExpectExec(`INSERT INTO public.task_tags`).WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	_, err = repo.Instantiate(context.Background(), tree)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
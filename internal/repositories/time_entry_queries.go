@@ -0,0 +1,71 @@
+package repositories
+
+const (
+	CreateTimeEntryQuery = `
+	INSERT INTO public.time_entries (task_id, user_id, started_at, ended_at, note)
+	VALUES (:task_id, :user_id, :started_at, :ended_at, :note)
+	RETURNING id, task_id, user_id, started_at, ended_at, note;`
+
+	GetRunningTimeEntryQuery = `
+	SELECT id, task_id, user_id, started_at, ended_at, note
+	FROM public.time_entries
+	WHERE user_id = $1 AND ended_at IS NULL;`
+
+	StopTimeEntryQuery = `
+	UPDATE public.time_entries
+	SET ended_at = $2
+	WHERE user_id = $1 AND ended_at IS NULL
+	RETURNING id, task_id, user_id, started_at, ended_at, note;`
+
+	GetTimeEntriesByTaskQuery = `
+	SELECT id, task_id, user_id, started_at, ended_at, note
+	FROM public.time_entries
+	WHERE task_id = $1
+	ORDER BY started_at;`
+
+	DeleteTimeEntryQuery = `
+	DELETE FROM public.time_entries
+	WHERE id = $1 AND user_id = $2;`
+
+	// TimeReportQueryTemplate дополняется выражениями группировки из timeReportGroups.
+	// Учитываются только завершённые записи.
+	TimeReportQueryTemplate = `
+	SELECT %s AS key, %s AS label, COUNT(e.id) AS entries,
+		COALESCE(SUM(EXTRACT(EPOCH FROM (e.ended_at - e.started_at))), 0)::BIGINT AS seconds
+	FROM public.time_entries e
+	JOIN public.users u ON u.id = e.user_id
+	JOIN public.tasks t ON t.id = e.task_id
+	%s
+	WHERE e.ended_at IS NOT NULL
+		AND e.started_at >= $1 AND e.started_at < $2
+		AND ($3 = 0 OR e.user_id = $3)
+		AND ($4 = 0 OR e.task_id = $4)
+	GROUP BY 1, 2
+	ORDER BY %s;`
+)
+
+type timeReportGroup struct {
+	key   string
+	label string
+	join  string
+	order string
+	zoned bool // Выражения используют часовой пояс отчёта ($5)
+}
+
+var timeReportGroups = map[string]timeReportGroup{
+	"user": {key: "u.id::TEXT", label: "u.name", order: "seconds DESC, key"},
+	"task": {key: "t.id::TEXT", label: "t.name", order: "seconds DESC, key"},
+	"tag": {
+		key:   "COALESCE(tg.tag, '')",
+		label: "COALESCE(tg.tag, '')",
+		join:  "LEFT JOIN public.task_tags tg ON tg.task_id = e.task_id",
+		order: "seconds DESC, key",
+	},
+	// Дата начала берётся в поясе отчёта, а не в поясе сессии базы
+	"date": {
+		key:   "((e.started_at AT TIME ZONE $5)::date)::TEXT",
+		label: "((e.started_at AT TIME ZONE $5)::date)::TEXT",
+		order: "key",
+		zoned: true,
+	},
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TimeEntryRepository interface {
+	Create(ctx context.Context, entry *models.TimeEntry) (*models.TimeEntry, error)
+	GetRunning(ctx context.Context, userID int) (*models.TimeEntry, error)
+	Stop(ctx context.Context, userID int, endedAt time.Time) (*models.TimeEntry, error)
+	GetByTask(ctx context.Context, taskID int) ([]models.TimeEntry, error)
+	Delete(ctx context.Context, userID, id int) error
+	Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error)
+}
+
+type TimeEntryRepo struct {
+	db *sqlx.DB
+}
+
+func NewTimeEntryRepo(db *sqlx.DB) TimeEntryRepository {
+	return &TimeEntryRepo{db: db}
+}
+
+// Create добавляет запись; для записи без EndedAt уникальный индекс гарантирует,
+// что у пользователя не больше одного запущенного таймера (иначе ErrDuplicate).
+func (r *TimeEntryRepo) Create(ctx context.Context, entry *models.TimeEntry) (*models.TimeEntry, error) {
+	rows, err := r.db.NamedQueryContext(ctx, CreateTimeEntryQuery, entry)
+	if err != nil {
+		logError(ctx, "CreateTimeEntryQuery", err)
+
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		return nil, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			logError(ctx, "Close rows in Create", closeErr)
+		}
+	}()
+
+	if rows.Next() {
+		var created models.TimeEntry
+		if err := rows.StructScan(&created); err != nil {
+			logError(ctx, "StructScan (Create)", err)
+			return nil, err
+		}
+
+		return &created, nil
+	}
+
+	slog.ErrorContext(ctx, "time entry creation returned no rows")
+
+	return nil, errors.New("time entry creation failed")
+}
+
+func (r *TimeEntryRepo) GetRunning(ctx context.Context, userID int) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+
+	err := r.db.GetContext(ctx, &entry, GetRunningTimeEntryQuery, userID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logError(ctx, "GetRunningTimeEntryQuery", err)
+		}
+
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *TimeEntryRepo) Stop(ctx context.Context, userID int, endedAt time.Time) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+
+	err := r.db.GetContext(ctx, &entry, StopTimeEntryQuery, userID, endedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logError(ctx, "StopTimeEntryQuery", err)
+		}
+
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *TimeEntryRepo) GetByTask(ctx context.Context, taskID int) ([]models.TimeEntry, error) {
+	entries := []models.TimeEntry{}
+
+	err := r.db.SelectContext(ctx, &entries, GetTimeEntriesByTaskQuery, taskID)
+	if err != nil {
+		logError(ctx, "GetTimeEntriesByTaskQuery", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *TimeEntryRepo) Delete(ctx context.Context, userID, id int) error {
+	result, err := r.db.ExecContext(ctx, DeleteTimeEntryQuery, id, userID)
+	if err != nil {
+		logError(ctx, "DeleteTimeEntryQuery", err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *TimeEntryRepo) Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	group, ok := timeReportGroups[filter.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown report grouping %q", filter.GroupBy)
+	}
+
+	query := fmt.Sprintf(TimeReportQueryTemplate, group.key, group.label, group.join, group.order)
+	rows := []models.TimeReportRow{}
+
+	args := []interface{}{filter.From, filter.To, filter.UserID, filter.TaskID}
+	if group.zoned {
+		args = append(args, filter.Timezone)
+	}
+
+	err := r.db.SelectContext(ctx, &rows, query, args...)
+	if err != nil {
+		logError(ctx, "TimeReportQuery", err)
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,134 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var timeEntryColumns = []string{"id", "task_id", "user_id", "started_at", "ended_at", "note"}
+
+func TestTimeEntryRepo_Create_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTimeEntryRepo(sqlx.NewDb(db, "sqlmock"))
+
+	entry := &models.TimeEntry{TaskID: 1, UserID: 2, StartedAt: time.Now()}
+
+	mock.ExpectQuery(`INSERT INTO public.time_entries`).
+		WithArgs(entry.TaskID, entry.UserID, entry.StartedAt, entry.EndedAt, entry.Note).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.Create(context.Background(), entry)
+
+	assert.ErrorIs(t, err, repositories.ErrDuplicate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTimeEntryRepo_Stop(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTimeEntryRepo(sqlx.NewDb(db, "sqlmock"))
+
+	started := time.Now().Add(-time.Hour)
+	ended := time.Now()
+
+	mock.ExpectQuery(`UPDATE public.time_entries SET ended_at = \$2 WHERE user_id = \$1 AND ended_at IS NULL`).
+		WithArgs(2, ended).
+		WillReturnRows(sqlmock.NewRows(timeEntryColumns).AddRow(1, 1, 2, started, ended, ""))
+
+	entry, err := repo.Stop(context.Background(), 2, ended)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, entry.ID)
+	assert.Equal(t, ended, *entry.EndedAt)
+
+	mock.ExpectQuery(`UPDATE public.time_entries`).
+		WithArgs(3, ended).
+		WillReturnRows(sqlmock.NewRows(timeEntryColumns))
+
+	_, err = repo.Stop(context.Background(), 3, ended)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTimeEntryRepo_Report(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTimeEntryRepo(sqlx.NewDb(db, "sqlmock"))
+
+	filter := models.TimeReportFilter{
+		GroupBy: "tag",
+		From:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		UserID:  2,
+	}
+
+	mock.ExpectQuery(`SELECT COALESCE\(tg.tag, ''\) AS key, (.+) LEFT JOIN public.task_tags tg`).
+		WithArgs(filter.From, filter.To, 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "label", "entries", "seconds"}).
+			AddRow("backend", "backend", 3, 5400).
+			AddRow("", "", 1, 600))
+
+	rows, err := repo.Report(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, int64(5400), rows[0].Seconds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Неизвестная группировка не доходит до базы
+	_, err = repo.Report(context.Background(), models.TimeReportFilter{GroupBy: "project"})
+	assert.Error(t, err)
+}
+
+func TestTimeEntryRepo_Report_ByDate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewTimeEntryRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Запись, начатая 1 марта в 22:30 UTC, в Москве относится уже ко 2 марта:
+	// дата берётся в поясе отчёта, переданном параметром, а не в поясе сессии
+	filter := models.TimeReportFilter{
+		GroupBy:  "date",
+		From:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		Timezone: "Europe/Moscow",
+	}
+
+	mock.ExpectQuery(`SELECT \(\(e.started_at AT TIME ZONE \$5\)::date\)::TEXT AS key, (.+) ORDER BY key`).
+		WithArgs(filter.From, filter.To, 0, 0, "Europe/Moscow").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "label", "entries", "seconds"}).
+			AddRow("2024-03-02", "2024-03-02", 1, 3600))
+
+	rows, err := repo.Report(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TimeReportRow{{Key: "2024-03-02", Label: "2024-03-02", Entries: 1, Seconds: 3600}}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
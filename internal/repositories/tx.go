@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// rollback откатывает транзакцию в defer; после Commit откат ничего не делает.
+func rollback(ctx context.Context, tx *sqlx.Tx, operation string) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		slog.ErrorContext(ctx, "transaction rollback failed", "operation", operation, "error", err)
+	}
+}
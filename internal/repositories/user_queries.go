@@ -2,24 +2,29 @@ package repositories
 
 const (
 	CreateUserQuery = `
-	INSERT INTO public.users (name, key) 
-	VALUES (:name, :key) 
-	RETURNING id, name, key;`
+	INSERT INTO public.users (name, key, timezone) 
+	VALUES (:name, :key, COALESCE(NULLIF(:timezone, ''), 'UTC')) 
+	RETURNING id, name, key, timezone;`
 
 	GetUserByIDQuery = `
-	SELECT id, name, key
+	SELECT id, name, key, timezone
 	FROM public.users
 	WHERE id = $1;`
 
+	GetUserByKeyQuery = `
+	SELECT id, name, key, timezone
+	FROM public.users
+	WHERE key = $1;`
+
 	GetAllUsersQuery = `
-	SELECT id, name, key
+	SELECT id, name, key, timezone
 	FROM public.users;`
 
 	UpdateUserQuery = `
 	UPDATE public.users
-	SET name = :name, key = :key
+	SET name = :name, key = :key, timezone = COALESCE(NULLIF(:timezone, ''), timezone)
 	WHERE id = :id
-	RETURNING id, name, key;`
+	RETURNING id, name, key, timezone;`
 
 	DeleteUserQuery = `
 	DELETE FROM public.users
@@ -4,7 +4,7 @@ import (
 	"WebTasks/internal/models"
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -13,6 +13,7 @@ type UserRepository interface {
 	Create(ctx context.Context, user *models.User) (*models.User, error)
 	GetAll(ctx context.Context) ([]models.User, error)
 	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByKey(ctx context.Context, key string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) (*models.User, error)
 	Delete(ctx context.Context, id int) error
 }
@@ -28,28 +29,28 @@ func NewUserRepo(db *sqlx.DB) UserRepository {
 func (r *UserRepo) Create(ctx context.Context, user *models.User) (*models.User, error) {
 	rows, err := r.db.NamedQueryContext(ctx, CreateUserQuery, user)
 	if err != nil {
-		logError("CreateUserQuery", err)
+		logError(ctx, "CreateUserQuery", err)
 		return nil, err
 	}
 
 	// Отложенный вызов с проверкой возможной ошибки закрытия
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			logError("Close rows in Create", closeErr)
+			logError(ctx, "Close rows in Create", closeErr)
 		}
 	}()
 
 	if rows.Next() {
 		var createdUser models.User
 		if err := rows.StructScan(&createdUser); err != nil {
-			logError("StructScan (Create)", err)
+			logError(ctx, "StructScan (Create)", err)
 			return nil, err
 		}
 
 		return &createdUser, nil
 	}
 
-	log.Printf("User creation failed: no rows returned")
+	slog.ErrorContext(ctx, "user creation returned no rows")
 
 	return nil, errors.New("user creation failed")
 }
@@ -59,7 +60,7 @@ func (r *UserRepo) GetAll(ctx context.Context) ([]models.User, error) {
 
 	err := r.db.SelectContext(ctx, &users, GetAllUsersQuery)
 	if err != nil {
-		logError("GetAllUsersQuery", err)
+		logError(ctx, "GetAllUsersQuery", err)
 		return nil, err
 	}
 
@@ -71,7 +72,19 @@ func (r *UserRepo) GetByID(ctx context.Context, id int) (*models.User, error) {
 
 	err := r.db.GetContext(ctx, &user, GetUserByIDQuery, id)
 	if err != nil {
-		logError("GetUserByIDQuery", err)
+		logError(ctx, "GetUserByIDQuery", err)
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepo) GetByKey(ctx context.Context, key string) (*models.User, error) {
+	var user models.User
+
+	err := r.db.GetContext(ctx, &user, GetUserByKeyQuery, key)
+	if err != nil {
+		logError(ctx, "GetUserByKeyQuery", err)
 		return nil, err
 	}
 
@@ -81,27 +94,27 @@ func (r *UserRepo) GetByID(ctx context.Context, id int) (*models.User, error) {
 func (r *UserRepo) Update(ctx context.Context, user *models.User) (*models.User, error) {
 	rows, err := r.db.NamedQueryContext(ctx, UpdateUserQuery, user)
 	if err != nil {
-		logError("UpdateUserQuery", err)
+		logError(ctx, "UpdateUserQuery", err)
 		return nil, err
 	}
 
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
-			logError("Close rows in Update", closeErr)
+			logError(ctx, "Close rows in Update", closeErr)
 		}
 	}()
 
 	if rows.Next() {
 		var updatedUser models.User
 		if err := rows.StructScan(&updatedUser); err != nil {
-			logError("StructScan (Update)", err)
+			logError(ctx, "StructScan (Update)", err)
 			return nil, err
 		}
 
 		return &updatedUser, nil
 	}
 
-	log.Printf("User update failed: no rows returned")
+	slog.ErrorContext(ctx, "user update returned no rows")
 
 	return nil, errors.New("user update failed")
 }
@@ -109,13 +122,15 @@ func (r *UserRepo) Update(ctx context.Context, user *models.User) (*models.User,
 func (r *UserRepo) Delete(ctx context.Context, id int) error {
 	_, err := r.db.ExecContext(ctx, DeleteUserQuery, id)
 	if err != nil {
-		logError("DeleteUserQuery", err)
+		logError(ctx, "DeleteUserQuery", err)
 		return err
 	}
 
 	return nil
 }
 
-func logError(query string, err error) {
-	log.Printf("Error executing query '%s': %v", query, err)
+// logError пишет в журнал ошибку запроса query; поля запроса (request_id, user_id)
+// берутся из ctx.
+func logError(ctx context.Context, query string, err error) {
+	slog.ErrorContext(ctx, "query failed", "query", query, "error", err)
 }
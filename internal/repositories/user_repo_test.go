@@ -22,13 +22,14 @@ func TestUserRepo_Create(t *testing.T) {
 	repo := repositories.NewUserRepo(sqlxDB)
 
 	user := &models.User{
-		Name: "Test User",
-		Key:  "test-key",
+		Name:     "Test User",
+		Key:      "test-key",
+		Timezone: "Europe/Moscow",
 	}
 
 	mock.ExpectQuery(`INSERT INTO public.users`).
-		WithArgs(user.Name, user.Key).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key"}).AddRow(1, "Test User", "test-key"))
+		WithArgs(user.Name, user.Key, user.Timezone).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key", "timezone"}).AddRow(1, "Test User", "test-key", "Europe/Moscow"))
 
 	ctx := context.Background()
 	createdUser, err := repo.Create(ctx, user)
@@ -38,6 +39,7 @@ func TestUserRepo_Create(t *testing.T) {
 	assert.Equal(t, 1, createdUser.ID)
 	assert.Equal(t, "Test User", createdUser.Name)
 	assert.Equal(t, "test-key", createdUser.Key)
+	assert.Equal(t, "Europe/Moscow", createdUser.Timezone)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -51,10 +53,10 @@ func TestUserRepo_GetAll(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := repositories.NewUserRepo(sqlxDB)
 
-	mock.ExpectQuery(`SELECT id, name, key FROM public.users`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key"}).
-			AddRow(1, "User1", "key1").
-			AddRow(2, "User2", "key2"))
+	mock.ExpectQuery(`SELECT id, name, key, timezone FROM public.users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key", "timezone"}).
+			AddRow(1, "User1", "key1", "UTC").
+			AddRow(2, "User2", "key2", "UTC"))
 
 	ctx := context.Background()
 	users, err := repo.GetAll(ctx)
@@ -77,9 +79,9 @@ func TestUserRepo_GetById(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := repositories.NewUserRepo(sqlxDB)
 
-	mock.ExpectQuery(`SELECT id, name, key FROM public.users WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, key, timezone FROM public.users WHERE id = \$1`).
 		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key"}).AddRow(1, "User1", "key1"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key", "timezone"}).AddRow(1, "User1", "key1", "UTC"))
 
 	ctx := context.Background()
 	user, err := repo.GetByID(ctx, 1)
@@ -92,6 +94,29 @@ func TestUserRepo_GetById(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUserRepo_GetByKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repositories.NewUserRepo(sqlxDB)
+
+	mock.ExpectQuery(`SELECT id, name, key, timezone FROM public.users WHERE key = \$1`).
+		WithArgs("key1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key", "timezone"}).AddRow(1, "User1", "key1", "UTC"))
+
+	ctx := context.Background()
+	user, err := repo.GetByKey(ctx, "key1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "User1", user.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUserRepo_Update(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -103,14 +128,15 @@ func TestUserRepo_Update(t *testing.T) {
 	repo := repositories.NewUserRepo(sqlxDB)
 
 	user := &models.User{
-		ID:   1,
-		Name: "Updated User",
-		Key:  "updated-key",
+		ID:       1,
+		Name:     "Updated User",
+		Key:      "updated-key",
+		Timezone: "UTC",
 	}
 
 	mock.ExpectQuery(`UPDATE public.users SET`).
-		WithArgs(user.Name, user.Key, user.ID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key"}).AddRow(1, "Updated User", "updated-key"))
+		WithArgs(user.Name, user.Key, user.Timezone, user.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "key", "timezone"}).AddRow(1, "Updated User", "updated-key", "UTC"))
 
 	ctx := context.Background()
 	updatedUser, err := repo.Update(ctx, user)
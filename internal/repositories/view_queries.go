@@ -0,0 +1,32 @@
+package repositories
+
+import "fmt"
+
+// GetVisibleViewsQuery - личные виды пользователя и виды проектов, в которых он участвует
+var GetVisibleViewsQuery = `
+	SELECT id, user_id, COALESCE(project_id, 0) AS project_id, name, query, columns, created_at, updated_at
+	FROM public.saved_views
+	WHERE user_id = $1 OR (project_id IS NOT NULL AND ` + fmt.Sprintf(projectMemberCondition, "saved_views.project_id", "$1") + `)
+	ORDER BY name, id;`
+
+const (
+	CreateViewQuery = `
+	INSERT INTO public.saved_views (user_id, project_id, name, query, columns)
+	VALUES ($1, NULLIF($2, 0), $3, $4, $5)
+	RETURNING id, user_id, COALESCE(project_id, 0) AS project_id, name, query, columns, created_at, updated_at;`
+
+	GetViewByIDQuery = `
+	SELECT id, user_id, COALESCE(project_id, 0) AS project_id, name, query, columns, created_at, updated_at
+	FROM public.saved_views
+	WHERE id = $1;`
+
+	UpdateViewQuery = `
+	UPDATE public.saved_views
+	SET project_id = NULLIF($2, 0), name = $3, query = $4, columns = $5, updated_at = NOW()
+	WHERE id = $1
+	RETURNING id, user_id, COALESCE(project_id, 0) AS project_id, name, query, columns, created_at, updated_at;`
+
+	DeleteViewQuery = `
+	DELETE FROM public.saved_views
+	WHERE id = $1;`
+)
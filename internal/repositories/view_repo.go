@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ViewRepository interface {
+	Create(ctx context.Context, view *models.SavedView) (*models.SavedView, error)
+	GetByID(ctx context.Context, id int) (*models.SavedView, error)
+	GetVisible(ctx context.Context, userID int) ([]models.SavedView, error)
+	Update(ctx context.Context, view *models.SavedView) (*models.SavedView, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ViewRepo struct {
+	db *sqlx.DB
+}
+
+func NewViewRepo(db *sqlx.DB) ViewRepository {
+	return &ViewRepo{db: db}
+}
+
+// Create возвращает ErrDuplicate, если у пользователя уже есть вид с таким именем.
+func (r *ViewRepo) Create(ctx context.Context, view *models.SavedView) (*models.SavedView, error) {
+	var created models.SavedView
+
+	err := r.db.GetContext(ctx, &created, CreateViewQuery,
+		view.UserID, view.ProjectID, view.Name, view.Query, viewColumns(view))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		logError(ctx, "CreateViewQuery", err)
+
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *ViewRepo) GetByID(ctx context.Context, id int) (*models.SavedView, error) {
+	var view models.SavedView
+
+	err := r.db.GetContext(ctx, &view, GetViewByIDQuery, id)
+	if err != nil {
+		logError(ctx, "GetViewByIDQuery", err)
+		return nil, err
+	}
+
+	return &view, nil
+}
+
+func (r *ViewRepo) GetVisible(ctx context.Context, userID int) ([]models.SavedView, error) {
+	views := []models.SavedView{}
+
+	err := r.db.SelectContext(ctx, &views, GetVisibleViewsQuery, userID)
+	if err != nil {
+		logError(ctx, "GetVisibleViewsQuery", err)
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (r *ViewRepo) Update(ctx context.Context, view *models.SavedView) (*models.SavedView, error) {
+	var updated models.SavedView
+
+	err := r.db.GetContext(ctx, &updated, UpdateViewQuery,
+		view.ID, view.ProjectID, view.Name, view.Query, viewColumns(view))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+
+		logError(ctx, "UpdateViewQuery", err)
+
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (r *ViewRepo) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, DeleteViewQuery, id)
+	if err != nil {
+		logError(ctx, "DeleteViewQuery", err)
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// viewColumns подменяет nil пустым массивом: колонка columns NOT NULL.
+func viewColumns(view *models.SavedView) pq.StringArray {
+	if view.Columns == nil {
+		return pq.StringArray{}
+	}
+
+	return view.Columns
+}
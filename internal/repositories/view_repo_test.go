@@ -0,0 +1,70 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var viewColumns = []string{"id", "user_id", "project_id", "name", "query", "columns", "created_at", "updated_at"}
+
+func TestViewRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewViewRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO public.saved_views`).
+		WithArgs(7, 0, "Overdue", "sort=due", "{}").
+		WillReturnRows(sqlmock.NewRows(viewColumns).AddRow(1, 7, 0, "Overdue", "sort=due", []byte(`{}`), now, now))
+
+	created, err := repo.Create(context.Background(), &models.SavedView{UserID: 7, Name: "Overdue", Query: "sort=due"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+
+	mock.ExpectQuery(`INSERT INTO public.saved_views`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.Create(context.Background(), &models.SavedView{UserID: 7, Name: "Overdue"})
+
+	assert.ErrorIs(t, err, repositories.ErrDuplicate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewRepo_GetVisible(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewViewRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM public.saved_views WHERE user_id = \$1 OR \(project_id IS NOT NULL AND \(\s*EXISTS \(SELECT 1 FROM public.projects p WHERE p.id = saved_views.project_id AND p.owner_id = \$1\)`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(viewColumns).
+			AddRow(1, 7, 0, "Mine", "", []byte(`{name,due}`), now, now).
+			AddRow(2, 8, 1, "Team", "status=Pending", []byte(`{}`), now, now))
+
+	views, err := repo.GetVisible(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Len(t, views, 2)
+	assert.Equal(t, pq.StringArray{"name", "due"}, views[0].Columns)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
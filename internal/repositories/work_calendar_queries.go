@@ -0,0 +1,27 @@
+package repositories
+
+const (
+	GetWorkCalendarQuery = `
+	SELECT timezone, workdays
+	FROM public.users
+	WHERE id = $1;`
+
+	GetHolidaysQuery = `
+	SELECT to_char(day, 'YYYY-MM-DD') AS day, name
+	FROM public.user_holidays
+	WHERE user_id = $1
+	ORDER BY day;`
+
+	UpdateWorkCalendarQuery = `
+	UPDATE public.users
+	SET timezone = $2, workdays = $3
+	WHERE id = $1;`
+
+	DeleteHolidaysQuery = `
+	DELETE FROM public.user_holidays
+	WHERE user_id = $1;`
+
+	AddHolidayQuery = `
+	INSERT INTO public.user_holidays (user_id, day, name)
+	VALUES ($1, $2, $3);`
+)
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WorkCalendarRepository interface {
+	Get(ctx context.Context, userID int) (models.WorkCalendar, error)
+	Replace(ctx context.Context, userID int, calendar models.WorkCalendar) error
+}
+
+type WorkCalendarRepo struct {
+	db *sqlx.DB
+}
+
+func NewWorkCalendarRepo(db *sqlx.DB) WorkCalendarRepository {
+	return &WorkCalendarRepo{db: db}
+}
+
+// Get возвращает календарь пользователя или sql.ErrNoRows, если пользователя нет.
+func (r *WorkCalendarRepo) Get(ctx context.Context, userID int) (models.WorkCalendar, error) {
+	var settings struct {
+		Timezone string         `db:"timezone"`
+		Workdays pq.StringArray `db:"workdays"`
+	}
+
+	if err := r.db.GetContext(ctx, &settings, GetWorkCalendarQuery, userID); err != nil {
+		logError(ctx, "GetWorkCalendarQuery", err)
+		return models.WorkCalendar{}, err
+	}
+
+	calendar := models.WorkCalendar{
+		Timezone: settings.Timezone,
+		Workdays: settings.Workdays,
+		Holidays: []models.Holiday{},
+	}
+
+	if err := r.db.SelectContext(ctx, &calendar.Holidays, GetHolidaysQuery, userID); err != nil {
+		logError(ctx, "GetHolidaysQuery", err)
+		return models.WorkCalendar{}, err
+	}
+
+	return calendar, nil
+}
+
+// Replace сохраняет пояс, рабочие дни и заменяет список праздников одной транзакцией.
+func (r *WorkCalendarRepo) Replace(ctx context.Context, userID int, calendar models.WorkCalendar) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer rollback(ctx, tx, "Replace work calendar")
+
+	result, err := tx.ExecContext(ctx, UpdateWorkCalendarQuery, userID, calendar.Timezone, pq.StringArray(calendar.Workdays))
+	if err != nil {
+		logError(ctx, "UpdateWorkCalendarQuery", err)
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, DeleteHolidaysQuery, userID); err != nil {
+		logError(ctx, "DeleteHolidaysQuery", err)
+		return err
+	}
+
+	for _, holiday := range calendar.Holidays {
+		if _, err := tx.ExecContext(ctx, AddHolidayQuery, userID, holiday.Date, holiday.Name); err != nil {
+			logError(ctx, "AddHolidayQuery", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,87 @@
+package repositories_test
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkCalendarRepo_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewWorkCalendarRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`SELECT timezone, workdays FROM public.users WHERE id = \$1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone", "workdays"}).AddRow("Europe/Moscow", []byte("{mon,tue}")))
+	mock.ExpectQuery(`FROM public.user_holidays WHERE user_id = \$1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"day", "name"}).AddRow("2030-01-07", "Рождество"))
+
+	calendar, err := repo.Get(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.WorkCalendar{
+		Timezone: "Europe/Moscow",
+		Workdays: []string{"mon", "tue"},
+		Holidays: []models.Holiday{{Date: "2030-01-07", Name: "Рождество"}},
+	}, calendar)
+
+	mock.ExpectQuery(`SELECT timezone, workdays FROM public.users`).
+		WithArgs(8).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Get(context.Background(), 8)
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkCalendarRepo_Replace(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	repo := repositories.NewWorkCalendarRepo(sqlx.NewDb(db, "sqlmock"))
+
+	calendar := models.WorkCalendar{
+		Timezone: "Europe/Moscow",
+		Workdays: []string{"mon", "fri"},
+		Holidays: []models.Holiday{{Date: "2030-01-07"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE public.users SET timezone = \$2, workdays = \$3`).
+		WithArgs(7, "Europe/Moscow", "{\"mon\",\"fri\"}").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM public.user_holidays`).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO public.user_holidays`).
+		WithArgs(7, "2030-01-07", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.Replace(context.Background(), 7, calendar))
+
+	// Неизвестный пользователь: транзакция откатывается
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE public.users`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.ErrorIs(t, repo.Replace(context.Background(), 8, calendar), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"WebTasks/internal/storage"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	ErrAttachmentNotFound   = errors.New("attachment not found")
+	ErrAttachmentTooLarge   = errors.New("attachment is too large")
+	ErrAttachmentEmpty      = errors.New("attachment is empty")
+	ErrUnsupportedMediaType = errors.New("unsupported attachment type")
+)
+
+// AttachmentLimits ограничивает загружаемые файлы.
+type AttachmentLimits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, taskID int, fileName string, content io.Reader) (models.Attachment, error)
+	GetAll(ctx context.Context, taskID int) ([]models.Attachment, error)
+	Open(ctx context.Context, taskID, id int) (models.Attachment, io.ReadSeekCloser, error)
+	Delete(ctx context.Context, taskID, id int) error
+	SetLimits(limits AttachmentLimits)
+}
+
+type attachmentServiceImpl struct {
+	repo   repositories.AttachmentRepository
+	tasks  repositories.TaskRepository
+	store  storage.BlobStore
+	limits atomic.Pointer[AttachmentLimits]
+}
+
+func NewAttachmentService(
+	repo repositories.AttachmentRepository,
+	tasks repositories.TaskRepository,
+	store storage.BlobStore,
+	limits AttachmentLimits,
+) AttachmentService {
+	s := &attachmentServiceImpl{repo: repo, tasks: tasks, store: store}
+	s.SetLimits(limits)
+
+	return s
+}
+
+// SetLimits заменяет ограничения на лету; загрузки, начатые раньше, проверяются по старым.
+func (s *attachmentServiceImpl) SetLimits(limits AttachmentLimits) {
+	s.limits.Store(&limits)
+}
+
+func (s *attachmentServiceImpl) Upload(
+	ctx context.Context, taskID int, fileName string, content io.Reader,
+) (models.Attachment, error) {
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return models.Attachment{}, err
+	}
+
+	// Сохраняем файл во временный файл, попутно считая хэш и размер:
+	// ключ в хранилище известен только после чтения всего содержимого
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+
+	limits := s.limits.Load()
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(content, limits.MaxSize+1))
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("read attachment: %w", err)
+	}
+
+	if size > limits.MaxSize {
+		return models.Attachment{}, ErrAttachmentTooLarge
+	}
+
+	if size == 0 {
+		return models.Attachment{}, ErrAttachmentEmpty
+	}
+
+	contentType, err := s.detectType(tmp)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	// Одинаковое содержимое хранится один раз
+	putBlob := func(ctx context.Context, key string) error {
+		exists, err := s.store.Exists(ctx, key)
+		if err != nil || exists {
+			return err
+		}
+
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		return s.store.Put(ctx, key, tmp, size)
+	}
+
+	created, err := s.repo.Create(ctx, &models.Attachment{
+		TaskID:      taskID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		Hash:        hex.EncodeToString(hash.Sum(nil)),
+	}, putBlob)
+	if err != nil {
+		return models.Attachment{}, err
+	}
+
+	return *created, nil
+}
+
+func (s *attachmentServiceImpl) GetAll(ctx context.Context, taskID int) ([]models.Attachment, error) {
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+func (s *attachmentServiceImpl) Open(ctx context.Context, taskID, id int) (models.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := s.get(ctx, taskID, id)
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	blob, err := s.store.Open(ctx, attachment.Hash)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return models.Attachment{}, nil, ErrAttachmentNotFound
+	}
+
+	if err != nil {
+		return models.Attachment{}, nil, err
+	}
+
+	return *attachment, blob, nil
+}
+
+// Delete удаляет вложение; содержимое удаляется, только если на него больше не
+// ссылается ни одно вложение (проверку выполняет репозиторий под блокировкой хэша).
+func (s *attachmentServiceImpl) Delete(ctx context.Context, taskID, id int) error {
+	err := s.repo.Delete(ctx, taskID, id, s.store.Delete)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAttachmentNotFound
+	}
+
+	return err
+}
+
+func (s *attachmentServiceImpl) get(ctx context.Context, taskID, id int) (*models.Attachment, error) {
+	attachment, err := s.repo.GetByID(ctx, taskID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+
+	return attachment, err
+}
+
+func (s *attachmentServiceImpl) checkTask(ctx context.Context, taskID int) error {
+	return checkTaskExists(ctx, s.tasks, taskID)
+}
+
+// detectType определяет MIME-тип по содержимому, а не по имени файла,
+// и проверяет его по списку разрешённых.
+func (s *attachmentServiceImpl) detectType(file io.ReadSeeker) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	head := make([]byte, 512)
+
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+
+	for _, allowed := range s.limits.Load().AllowedTypes {
+		if strings.EqualFold(allowed, mediaType) {
+			return contentType, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+}
+
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+
+	if len(name) > 255 {
+		name = name[:255]
+	}
+
+	return name
+}
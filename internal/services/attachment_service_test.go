@@ -0,0 +1,232 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"WebTasks/internal/storage"
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTaskRepository реализует методы TaskRepository для тестов.
+type MockTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaskRepository) Create(ctx context.Context, task *models.Task) (*models.Task, error) {
+	args := m.Called(ctx, task)
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByID(ctx context.Context, id int) (*models.Task, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetAll(ctx context.Context) ([]models.Task, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) Update(ctx context.Context, task *models.Task) (*models.Task, error) {
+	args := m.Called(ctx, task)
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) Delete(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockTaskRepository) CountOverdue(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountOpenByUser(ctx context.Context, userID int) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) Stream(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) error {
+	args := m.Called(ctx, filter, fn)
+
+	if tasks, ok := args.Get(0).([]models.Task); ok {
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+	}
+
+	return args.Error(1)
+}
+
+func (m *MockTaskRepository) ApplyBulk(ctx context.Context, changes []models.TaskChange) ([]*models.Task, error) {
+	args := m.Called(ctx, changes)
+	tasks, _ := args.Get(0).([]*models.Task)
+
+	return tasks, args.Error(1)
+}
+
+// MockAttachmentRepository реализует методы AttachmentRepository для тестов.
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(
+	ctx context.Context, attachment *models.Attachment, putBlob repositories.BlobFunc,
+) (*models.Attachment, error) {
+	args := m.Called(ctx, attachment)
+	if err := putBlob(ctx, attachment.Hash); err != nil {
+		return nil, err
+	}
+	return args.Get(0).(*models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, taskID, id int) (*models.Attachment, error) {
+	args := m.Called(ctx, taskID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByTask(ctx context.Context, taskID int) ([]models.Attachment, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.Attachment), args.Error(1)
+}
+
+// Delete вызывает deleteBlob для хэша, который тест передал вторым значением Return:
+// так задаётся содержимое, на которое после удаления не осталось ссылок.
+func (m *MockAttachmentRepository) Delete(ctx context.Context, taskID, id int, deleteBlob repositories.BlobFunc) error {
+	args := m.Called(ctx, taskID, id)
+	if hash := args.String(1); hash != "" && args.Error(0) == nil {
+		return deleteBlob(ctx, hash)
+	}
+	return args.Error(0)
+}
+
+func newTestAttachmentService(t *testing.T) (AttachmentService, *MockAttachmentRepository, *MockTaskRepository, storage.BlobStore) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	repo := new(MockAttachmentRepository)
+	tasks := new(MockTaskRepository)
+	limits := AttachmentLimits{MaxSize: 64, AllowedTypes: []string{"text/plain"}}
+
+	return NewAttachmentService(repo, tasks, store, limits), repo, tasks, store
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	service, repo, tasks, store := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	// sha256("hello")
+	const hash = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(a *models.Attachment) bool {
+		return a.TaskID == 1 && a.FileName == "notes.txt" && a.Size == 5 && a.Hash == hash &&
+			a.ContentType == "text/plain; charset=utf-8"
+	})).Return(&models.Attachment{ID: 7, TaskID: 1, FileName: "notes.txt", Hash: hash}, nil)
+
+	result, err := service.Upload(ctx, 1, "../../notes.txt", strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.Equal(t, 7, result.ID)
+
+	exists, err := store.Exists(ctx, hash)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	repo.AssertExpectations(t)
+}
+
+func TestAttachmentService_Upload_Limits(t *testing.T) {
+	service, repo, tasks, _ := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	tasks.On("GetByID", ctx, 2).Return(nil, sql.ErrNoRows)
+
+	_, err := service.Upload(ctx, 1, "big.txt", strings.NewReader(strings.Repeat("a", 65)))
+	require.ErrorIs(t, err, ErrAttachmentTooLarge)
+
+	_, err = service.Upload(ctx, 1, "empty.txt", strings.NewReader(""))
+	require.ErrorIs(t, err, ErrAttachmentEmpty)
+
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	_, err = service.Upload(ctx, 1, "image.txt", bytes.NewReader(png))
+	require.ErrorIs(t, err, ErrUnsupportedMediaType)
+
+	_, err = service.Upload(ctx, 2, "notes.txt", strings.NewReader("hello"))
+	require.ErrorIs(t, err, ErrTaskNotFound)
+
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAttachmentService_Open(t *testing.T) {
+	service, repo, _, store := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "h1", strings.NewReader("content"), 7))
+
+	repo.On("GetByID", ctx, 1, 3).Return(&models.Attachment{ID: 3, TaskID: 1, Hash: "h1"}, nil)
+	repo.On("GetByID", ctx, 1, 4).Return(nil, sql.ErrNoRows)
+
+	attachment, blob, err := service.Open(ctx, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, attachment.ID)
+
+	data, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+	require.NoError(t, blob.Close())
+
+	_, _, err = service.Open(ctx, 1, 4)
+	require.ErrorIs(t, err, ErrAttachmentNotFound)
+}
+
+func TestAttachmentService_Delete(t *testing.T) {
+	service, repo, _, store := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "shared", strings.NewReader("x"), 1))
+	require.NoError(t, store.Put(ctx, "single", strings.NewReader("y"), 1))
+
+	repo.On("Delete", ctx, 1, 1).Return(nil, "")
+	repo.On("Delete", ctx, 1, 2).Return(nil, "single")
+	repo.On("Delete", ctx, 1, 3).Return(sql.ErrNoRows, "")
+
+	// Содержимое, на которое ссылается другое вложение, остаётся в хранилище
+	require.NoError(t, service.Delete(ctx, 1, 1))
+
+	exists, err := store.Exists(ctx, "shared")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	require.NoError(t, service.Delete(ctx, 1, 2))
+
+	exists, err = store.Exists(ctx, "single")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.ErrorIs(t, service.Delete(ctx, 1, 3), ErrAttachmentNotFound)
+
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"WebTasks/internal/ical"
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCalDAVObjectNotFound  = errors.New("calendar object not found")
+	ErrCalDAVPrecondition    = errors.New("precondition failed")
+	ErrInvalidCalendarObject = errors.New("invalid calendar object")
+)
+
+// defaultObjectNameRe - имя ресурса задачи, созданной не через CalDAV.
+var defaultObjectNameRe = regexp.MustCompile(`^task-([1-9][0-9]*)\.ics$`)
+
+// CalDAVService отображает коллекцию VTODO пользователя на его задачи. Изменения
+// проходят через TaskService и его проверки.
+type CalDAVService interface {
+	Objects(ctx context.Context, userID int) ([]models.CalDAVObject, error)
+	Object(ctx context.Context, userID int, name string) (models.CalDAVObject, error)
+	Put(ctx context.Context, userID int, name string, body io.Reader, ifMatch, ifNoneMatch string) (models.CalDAVObject, bool, error)
+	Delete(ctx context.Context, userID int, name, ifMatch string) error
+}
+
+type calDAVServiceImpl struct {
+	repo  repositories.CalDAVRepository
+	tasks TaskService
+	now   func() time.Time
+}
+
+func NewCalDAVService(repo repositories.CalDAVRepository, tasks TaskService) CalDAVService {
+	return &calDAVServiceImpl{repo: repo, tasks: tasks, now: time.Now}
+}
+
+// Objects возвращает все задачи пользователя как ресурсы коллекции.
+func (s *calDAVServiceImpl) Objects(ctx context.Context, userID int) ([]models.CalDAVObject, error) {
+	stored, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTask := make(map[int]models.CalDAVObject, len(stored))
+	for _, object := range stored {
+		byTask[object.TaskID] = object
+	}
+
+	var objects []models.CalDAVObject
+
+	err = s.tasks.Export(ctx, models.TaskFilter{UserID: userID}, func(task models.Task) error {
+		object, ok := byTask[task.ID]
+		if !ok {
+			object = defaultObject(userID, task.ID)
+		}
+
+		objects = append(objects, renderObject(object, task))
+
+		return nil
+	})
+
+	return objects, err
+}
+
+func (s *calDAVServiceImpl) Object(ctx context.Context, userID int, name string) (models.CalDAVObject, error) {
+	object, task, err := s.resolve(ctx, userID, name)
+	if err != nil {
+		return models.CalDAVObject{}, err
+	}
+
+	return renderObject(object, *task), nil
+}
+
+// Put создаёт или обновляет задачу по ресурсу с одним VTODO. ifMatch и ifNoneMatch - значения
+// заголовков If-Match и If-None-Match; при их нарушении возвращается ErrCalDAVPrecondition.
+// created сообщает, что ресурс создан.
+func (s *calDAVServiceImpl) Put(
+	ctx context.Context,
+	userID int,
+	name string,
+	body io.Reader,
+	ifMatch, ifNoneMatch string,
+) (models.CalDAVObject, bool, error) {
+	object, existing, err := s.resolve(ctx, userID, name)
+	exists := err == nil
+
+	if err != nil && !errors.Is(err, ErrCalDAVObjectNotFound) {
+		return models.CalDAVObject{}, false, err
+	}
+
+	if exists {
+		object = renderObject(object, *existing)
+	}
+
+	if err := checkPreconditions(exists, object.ETag, ifMatch, ifNoneMatch); err != nil {
+		return models.CalDAVObject{}, false, err
+	}
+
+	todo, err := parseSingleTodo(body)
+	if err != nil {
+		return models.CalDAVObject{}, false, err
+	}
+
+	incoming, rowErr := todoToImportTask(todo)
+	if rowErr != nil {
+		return models.CalDAVObject{}, false, fmt.Errorf("%w: %s %s", ErrInvalidCalendarObject, rowErr.Column, rowErr.Error)
+	}
+
+	if exists {
+		task, err := s.tasks.Update(ctx, mergeTodo(incoming.Task, *existing))
+		if err != nil {
+			return models.CalDAVObject{}, false, fmt.Errorf("%w: %v", ErrInvalidCalendarObject, err)
+		}
+
+		return renderObject(object, task), false, nil
+	}
+
+	task := incoming.Task
+	task.UserID = userID
+
+	if task.Time.IsZero() {
+		task.Time = s.now()
+	}
+
+	created, err := s.tasks.Create(ctx, task)
+	if err != nil {
+		return models.CalDAVObject{}, false, fmt.Errorf("%w: %v", ErrInvalidCalendarObject, err)
+	}
+
+	object = models.CalDAVObject{TaskID: created.ID, UserID: userID, Name: name, UID: todo.Text("UID")}
+	if object.UID == "" {
+		object.UID = defaultTaskUID(created.ID)
+	}
+
+	if err := s.repo.Save(ctx, object); err != nil {
+		return models.CalDAVObject{}, false, err
+	}
+
+	return renderObject(object, created), true, nil
+}
+
+func (s *calDAVServiceImpl) Delete(ctx context.Context, userID int, name, ifMatch string) error {
+	object, task, err := s.resolve(ctx, userID, name)
+	if err != nil {
+		return err
+	}
+
+	object = renderObject(object, *task)
+
+	if err := checkPreconditions(true, object.ETag, ifMatch, ""); err != nil {
+		return err
+	}
+
+	return s.tasks.Delete(ctx, task.ID)
+}
+
+// resolve находит задачу ресурса: по сохранённому имени или по имени "task-<id>.ics".
+// Чужие задачи не видны.
+func (s *calDAVServiceImpl) resolve(ctx context.Context, userID int, name string) (models.CalDAVObject, *models.Task, error) {
+	stored, err := s.repo.GetByName(ctx, userID, name)
+
+	var object models.CalDAVObject
+
+	switch {
+	case err == nil:
+		object = *stored
+	case errors.Is(err, sql.ErrNoRows):
+		match := defaultObjectNameRe.FindStringSubmatch(name)
+		if match == nil {
+			return models.CalDAVObject{}, nil, ErrCalDAVObjectNotFound
+		}
+
+		taskID, _ := strconv.Atoi(match[1])
+		object = defaultObject(userID, taskID)
+	default:
+		return models.CalDAVObject{}, nil, err
+	}
+
+	task, err := s.tasks.GetByID(ctx, object.TaskID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && task.UserID != userID) {
+		return models.CalDAVObject{}, nil, ErrCalDAVObjectNotFound
+	}
+
+	if err != nil {
+		return models.CalDAVObject{}, nil, err
+	}
+
+	return object, task, nil
+}
+
+func defaultObject(userID, taskID int) models.CalDAVObject {
+	return models.CalDAVObject{
+		TaskID: taskID,
+		UserID: userID,
+		Name:   fmt.Sprintf("task-%d.ics", taskID),
+		UID:    defaultTaskUID(taskID),
+	}
+}
+
+// renderObject заполняет данные и ETag ресурса. DTSTAMP берётся из времени создания задачи,
+// чтобы ETag менялся только вместе с задачей.
+func renderObject(object models.CalDAVObject, task models.Task) models.CalDAVObject {
+	var data strings.Builder
+
+	writer := ical.NewWriter(&data)
+	writer.Begin(ical.VCalendar)
+	writer.Property("VERSION", "2.0")
+	writer.Property("PRODID", "-//WebTasks//Tasks//EN")
+	writeTaskComponent(writer, ical.VTodo, object.UID, task, task.Time)
+	writer.End(ical.VCalendar)
+
+	// Запись в strings.Builder не завершается ошибкой
+	_ = writer.Flush()
+
+	sum := sha256.Sum256([]byte(data.String()))
+
+	object.Task = task
+	object.Data = data.String()
+	object.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	return object
+}
+
+func checkPreconditions(exists bool, etag, ifMatch, ifNoneMatch string) error {
+	if ifNoneMatch == "*" && exists {
+		return ErrCalDAVPrecondition
+	}
+
+	if ifMatch == "" {
+		return nil
+	}
+
+	if !exists {
+		return ErrCalDAVPrecondition
+	}
+
+	if ifMatch == "*" {
+		return nil
+	}
+
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(candidate) == etag {
+			return nil
+		}
+	}
+
+	return ErrCalDAVPrecondition
+}
+
+func parseSingleTodo(body io.Reader) (*ical.Component, error) {
+	calendar, err := ical.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendarObject, err)
+	}
+
+	todos := calendar.Find(ical.VTodo)
+	if len(todos) != 1 {
+		return nil, fmt.Errorf("%w: expected exactly one VTODO", ErrInvalidCalendarObject)
+	}
+
+	return todos[0], nil
+}
+
+// mergeTodo переносит изменения из клиента на задачу. Неизменившиеся статус и срок
+// не передаются, чтобы не терять статусы вне RFC 5545 и не спотыкаться о прошедший срок.
+func mergeTodo(incoming, existing models.Task) models.Task {
+	task := models.Task{
+		ID:         existing.ID,
+		Name:       incoming.Name,
+		Status:     incoming.Status,
+		Due:        incoming.Due,
+		Priority:   incoming.Priority,
+		Recurrence: incoming.Recurrence,
+	}
+
+	if todoStatus(existing.Status) == todoStatus(incoming.Status) {
+		task.Status = existing.Status
+	}
+
+	if task.Due.Equal(existing.Due) {
+		task.Due = time.Time{}
+	}
+
+	return task
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCalDAVRepository реализует методы CalDAVRepository для тестов.
+type MockCalDAVRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalDAVRepository) GetByName(ctx context.Context, userID int, name string) (*models.CalDAVObject, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CalDAVObject), args.Error(1)
+}
+
+func (m *MockCalDAVRepository) GetByUser(ctx context.Context, userID int) ([]models.CalDAVObject, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.CalDAVObject), args.Error(1)
+}
+
+func (m *MockCalDAVRepository) Save(ctx context.Context, object models.CalDAVObject) error {
+	return m.Called(ctx, object).Error(0)
+}
+
+const phoneTodo = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:abc@phone\r\n" +
+	"SUMMARY:Buy milk\r\nSTATUS:NEEDS-ACTION\r\nPRIORITY:1\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+func newTestCalDAVService() (*calDAVServiceImpl, *MockCalDAVRepository, *MockTaskRepository) {
+	repo := new(MockCalDAVRepository)
+	tasks := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+
+	service := NewCalDAVService(repo, NewTaskService(tasks, fields, nil)).(*calDAVServiceImpl)
+	service.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	return service, repo, tasks
+}
+
+func TestCalDAVService_Objects(t *testing.T) {
+	service, repo, tasks := newTestCalDAVService()
+	ctx := context.Background()
+
+	repo.On("GetByUser", ctx, 7).Return([]models.CalDAVObject{{TaskID: 2, UserID: 7, Name: "phone.ics", UID: "abc@phone"}}, nil)
+	tasks.On("Stream", ctx, models.TaskFilter{UserID: 7}, mock.Anything).Return([]models.Task{
+		{ID: 1, Name: "Write report", UserID: 7},
+		{ID: 2, Name: "Buy milk", UserID: 7},
+	}, nil)
+
+	objects, err := service.Objects(ctx, 7)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	require.Equal(t, "task-1.ics", objects[0].Name)
+	require.Contains(t, objects[0].Data, "UID:task-1@webtasks\r\n")
+	require.Equal(t, "phone.ics", objects[1].Name)
+	require.Contains(t, objects[1].Data, "UID:abc@phone\r\n")
+
+	// ETag зависит только от содержимого задачи
+	again, err := service.Objects(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, objects[0].ETag, again[0].ETag)
+	require.NotEqual(t, objects[0].ETag, objects[1].ETag)
+}
+
+func TestCalDAVService_Object(t *testing.T) {
+	service, repo, tasks := newTestCalDAVService()
+	ctx := context.Background()
+
+	repo.On("GetByName", ctx, 7, mock.Anything).Return(nil, sql.ErrNoRows)
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1, Name: "Write report", UserID: 7}, nil)
+	tasks.On("GetByID", ctx, 2).Return(&models.Task{ID: 2, Name: "Foreign", UserID: 8}, nil)
+
+	object, err := service.Object(ctx, 7, "task-1.ics")
+	require.NoError(t, err)
+	require.Equal(t, 1, object.TaskID)
+	require.Contains(t, object.Data, "SUMMARY:Write report\r\n")
+	require.Regexp(t, `^"[0-9a-f]{32}"$`, object.ETag)
+
+	// Чужая задача и неизвестное имя не находятся
+	_, err = service.Object(ctx, 7, "task-2.ics")
+	require.ErrorIs(t, err, ErrCalDAVObjectNotFound)
+
+	_, err = service.Object(ctx, 7, "notes.ics")
+	require.ErrorIs(t, err, ErrCalDAVObjectNotFound)
+}
+
+func TestCalDAVService_PutCreates(t *testing.T) {
+	service, repo, tasks := newTestCalDAVService()
+	ctx := context.Background()
+
+	repo.On("GetByName", ctx, 7, "phone.ics").Return(nil, sql.ErrNoRows)
+	tasks.On("Create", ctx, mock.MatchedBy(func(task *models.Task) bool {
+		return task.UserID == 7 && task.Name == "Buy milk" && task.Priority == models.PriorityUrgent &&
+			task.Time.Equal(service.now())
+	})).Return(&models.Task{ID: 5, Name: "Buy milk", UserID: 7, Status: "Pending", Priority: models.PriorityUrgent}, nil)
+	repo.On("Save", ctx, models.CalDAVObject{TaskID: 5, UserID: 7, Name: "phone.ics", UID: "abc@phone"}).Return(nil)
+
+	// Ресурса ещё нет, поэтому If-Match не выполняется
+	_, _, err := service.Put(ctx, 7, "phone.ics", strings.NewReader(phoneTodo), `"etag"`, "")
+	require.ErrorIs(t, err, ErrCalDAVPrecondition)
+
+	object, created, err := service.Put(ctx, 7, "phone.ics", strings.NewReader(phoneTodo), "", "*")
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, 5, object.TaskID)
+	require.Contains(t, object.Data, "UID:abc@phone\r\n")
+
+	_, _, err = service.Put(ctx, 7, "phone.ics", strings.NewReader("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), "", "")
+	require.ErrorIs(t, err, ErrInvalidCalendarObject)
+
+	repo.AssertExpectations(t)
+}
+
+func TestCalDAVService_PutUpdates(t *testing.T) {
+	service, repo, tasks := newTestCalDAVService()
+	ctx := context.Background()
+
+	stored := &models.CalDAVObject{TaskID: 5, UserID: 7, Name: "phone.ics", UID: "abc@phone"}
+	existing := &models.Task{ID: 5, Name: "Milk", UserID: 7, Status: "Waiting", Priority: models.PriorityLow}
+
+	repo.On("GetByName", ctx, 7, "phone.ics").Return(stored, nil)
+	tasks.On("GetByID", ctx, 5).Return(existing, nil)
+
+	// Статус вне RFC 5545 сохраняется, если клиент его не менял
+	tasks.On("Update", ctx, mock.MatchedBy(func(task *models.Task) bool {
+		return task.ID == 5 && task.Name == "Buy milk" && task.Status == "Waiting" && task.Priority == models.PriorityUrgent
+	})).Return(&models.Task{ID: 5, Name: "Buy milk", UserID: 7, Status: "Waiting", Priority: models.PriorityUrgent}, nil)
+
+	current, err := service.Object(ctx, 7, "phone.ics")
+	require.NoError(t, err)
+
+	_, _, err = service.Put(ctx, 7, "phone.ics", strings.NewReader(phoneTodo), `"stale"`, "")
+	require.ErrorIs(t, err, ErrCalDAVPrecondition)
+
+	_, _, err = service.Put(ctx, 7, "phone.ics", strings.NewReader(phoneTodo), "", "*")
+	require.ErrorIs(t, err, ErrCalDAVPrecondition)
+
+	object, created, err := service.Put(ctx, 7, "phone.ics", strings.NewReader(phoneTodo), current.ETag, "")
+	require.NoError(t, err)
+	require.False(t, created)
+	require.NotEqual(t, current.ETag, object.ETag)
+
+	tasks.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestCalDAVService_Delete(t *testing.T) {
+	service, repo, tasks := newTestCalDAVService()
+	ctx := context.Background()
+
+	repo.On("GetByName", ctx, 7, "task-1.ics").Return(nil, sql.ErrNoRows)
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1, Name: "Write report", UserID: 7}, nil)
+	tasks.On("Delete", ctx, 1).Return(nil)
+
+	require.ErrorIs(t, service.Delete(ctx, 7, "task-1.ics", `"stale"`), ErrCalDAVPrecondition)
+	require.NoError(t, service.Delete(ctx, 7, "task-1.ics", "*"))
+
+	tasks.AssertNumberOfCalls(t, "Delete", 1)
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"WebTasks/internal/ical"
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultEventDuration = 30 * time.Minute
+
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+type CalendarService interface {
+	FeedToken(ctx context.Context, userID int) (string, error)
+	RotateFeedToken(ctx context.Context, userID int) (string, error)
+	WriteFeed(ctx context.Context, token, component string, w io.Writer) error
+}
+
+type calendarServiceImpl struct {
+	repo  repositories.CalendarRepository
+	tasks repositories.TaskRepository
+	now   func() time.Time
+}
+
+func NewCalendarService(repo repositories.CalendarRepository, tasks repositories.TaskRepository) CalendarService {
+	return &calendarServiceImpl{repo: repo, tasks: tasks, now: time.Now}
+}
+
+// FeedToken возвращает секретный токен ссылки на календарь, создавая его при первом обращении.
+func (s *calendarServiceImpl) FeedToken(ctx context.Context, userID int) (string, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return "", err
+	}
+
+	return s.repo.EnsureToken(ctx, userID, token)
+}
+
+// RotateFeedToken выдаёт новый токен; старая ссылка перестаёт работать.
+func (s *calendarServiceImpl) RotateFeedToken(ctx context.Context, userID int) (string, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return "", err
+	}
+
+	return s.repo.ReplaceToken(ctx, userID, token)
+}
+
+// WriteFeed пишет в w календарь задач со сроком, в которых владелец токена автор, исполнитель
+// или наблюдатель, компонентами VTODO или VEVENT. Неизвестный токен даёт ErrCalendarFeedNotFound
+// до начала записи.
+func (s *calendarServiceImpl) WriteFeed(ctx context.Context, token, component string, w io.Writer) error {
+	if component != ical.VTodo && component != ical.VEvent {
+		return fmt.Errorf("unsupported calendar component %q", component)
+	}
+
+	userID, err := s.repo.GetUserIDByToken(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCalendarFeedNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	stamp := s.now()
+	writer := ical.NewWriter(w)
+
+	writer.Begin(ical.VCalendar)
+	writer.Property("VERSION", "2.0")
+	writer.Property("PRODID", "-//WebTasks//Tasks//EN")
+	writer.Property("CALSCALE", "GREGORIAN")
+	writer.Text("X-WR-CALNAME", "WebTasks")
+
+	err = s.tasks.Stream(ctx, models.TaskFilter{MemberID: userID, SortBy: "due"}, func(task models.Task) error {
+		if task.Due.IsZero() {
+			return nil
+		}
+
+		writeTaskComponent(writer, component, defaultTaskUID(task.ID), task, stamp)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.End(ical.VCalendar)
+
+	return writer.Flush()
+}
+
+// defaultTaskUID - UID задачи, для которой клиент CalDAV не задал свой.
+func defaultTaskUID(taskID int) string {
+	return fmt.Sprintf("task-%d@webtasks", taskID)
+}
+
+func writeTaskComponent(writer *ical.Writer, component, uid string, task models.Task, stamp time.Time) {
+	writer.Begin(component)
+	writer.Text("UID", uid)
+	writer.Time("DTSTAMP", stamp)
+	writer.Text("SUMMARY", task.Name)
+
+	if !task.Time.IsZero() {
+		writer.Time("CREATED", task.Time)
+	}
+
+	if component == ical.VTodo {
+		writer.Time("DUE", task.Due)
+		writer.Property("STATUS", todoStatus(task.Status))
+		writer.Property("PRIORITY", strconv.Itoa(icalPriority(task.Priority)))
+	} else {
+		// Событие заканчивается к сроку задачи и длится столько, сколько она оценена
+		duration := time.Duration(task.EstimateMinutes) * time.Minute
+		if duration <= 0 {
+			duration = defaultEventDuration
+		}
+
+		writer.Time("DTSTART", task.Due.Add(-duration))
+		writer.Time("DTEND", task.Due)
+		writer.Property("STATUS", eventStatus(task.Status))
+	}
+
+	if task.Recurrence != "" {
+		writer.Property("RRULE", task.Recurrence)
+	}
+
+	writer.End(component)
+}
+
+func todoStatus(status string) string {
+	switch {
+	case strings.EqualFold(status, "cancelled"):
+		return "CANCELLED"
+	case models.IsClosedStatus(status):
+		return "COMPLETED"
+	case strings.Contains(strings.ToLower(status), "progress"):
+		return "IN-PROCESS"
+	}
+
+	return "NEEDS-ACTION"
+}
+
+func eventStatus(status string) string {
+	if strings.EqualFold(status, "cancelled") {
+		return "CANCELLED"
+	}
+
+	return "CONFIRMED"
+}
+
+// icalPriority переводит приоритет задачи в шкалу PRIORITY: 1 - наивысший, 9 - низший.
+func icalPriority(priority string) int {
+	switch priority {
+	case models.PriorityUrgent:
+		return 1
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityLow:
+		return 9
+	}
+
+	return 5
+}
+
+// readImportICS превращает VTODO файла .ics в строки загрузки; номер строки - номер VTODO.
+func readImportICS(body io.Reader) ([]importRow, error) {
+	calendar, err := ical.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	todos := calendar.Find(ical.VTodo)
+	if len(todos) > maxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows per import", ErrInvalidImport, maxImportRows)
+	}
+
+	rows := make([]importRow, len(todos))
+
+	for i, todo := range todos {
+		rows[i] = importRow{line: i + 1}
+		rows[i].task, rows[i].err = todoToImportTask(todo)
+
+		if rows[i].err != nil {
+			rows[i].err.Row = i + 1
+		}
+	}
+
+	return rows, nil
+}
+
+func todoToImportTask(todo *ical.Component) (models.ImportTask, *models.ImportRowError) {
+	task := models.ImportTask{
+		Task: models.Task{
+			Name:       strings.TrimSpace(todo.Text("SUMMARY")),
+			Status:     taskStatus(todo.Text("STATUS")),
+			Recurrence: todo.Text("RRULE"),
+		},
+		Tags: todo.List("CATEGORIES"),
+	}
+
+	for name, target := range map[string]*time.Time{"DUE": &task.Due, "CREATED": &task.Time} {
+		property, ok := todo.Property(name)
+		if !ok {
+			continue
+		}
+
+		value, _, err := ical.ParseTime(property)
+		if err != nil {
+			return task, &models.ImportRowError{Column: name, Error: "expected a DATE or DATE-TIME value"}
+		}
+
+		*target = value
+	}
+
+	if value := todo.Text("PRIORITY"); value != "" {
+		priority, err := strconv.Atoi(value)
+		if err != nil || priority < 0 || priority > 9 {
+			return task, &models.ImportRowError{Column: "PRIORITY", Error: "expected a number from 0 to 9"}
+		}
+
+		task.Priority = taskPriority(priority)
+	}
+
+	return task, nil
+}
+
+func taskStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "COMPLETED":
+		return "Completed"
+	case "IN-PROCESS":
+		return "In Progress"
+	case "CANCELLED":
+		return "Cancelled"
+	}
+
+	return "Pending"
+}
+
+// taskPriority переводит PRIORITY (RFC 5545: 1-4 высокий, 5 средний, 6-9 низкий, 0 - не задан).
+func taskPriority(priority int) string {
+	switch {
+	case priority == 0:
+		return ""
+	case priority == 1:
+		return models.PriorityUrgent
+	case priority <= 4:
+		return models.PriorityHigh
+	case priority == 5:
+		return models.PriorityMedium
+	}
+
+	return models.PriorityLow
+}
+
+func newFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
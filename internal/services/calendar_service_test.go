@@ -0,0 +1,158 @@
+package services
+
+import (
+	"WebTasks/internal/ical"
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCalendarRepository реализует методы CalendarRepository для тестов.
+type MockCalendarRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarRepository) EnsureToken(ctx context.Context, userID int, token string) (string, error) {
+	args := m.Called(ctx, userID, token)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarRepository) ReplaceToken(ctx context.Context, userID int, token string) (string, error) {
+	args := m.Called(ctx, userID, token)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarRepository) GetUserIDByToken(ctx context.Context, token string) (int, error) {
+	args := m.Called(ctx, token)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCalendarService_FeedToken(t *testing.T) {
+	repo := new(MockCalendarRepository)
+	service := NewCalendarService(repo, new(MockTaskRepository))
+
+	ctx := context.Background()
+	isToken := mock.MatchedBy(func(token string) bool { return len(token) == 64 })
+
+	repo.On("EnsureToken", ctx, 7, isToken).Return("existing", nil)
+	repo.On("ReplaceToken", ctx, 7, isToken).Return("fresh", nil)
+
+	token, err := service.FeedToken(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, "existing", token)
+
+	token, err = service.RotateFeedToken(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, "fresh", token)
+}
+
+func TestCalendarService_WriteFeed(t *testing.T) {
+	repo := new(MockCalendarRepository)
+	tasks := new(MockTaskRepository)
+	service := NewCalendarService(repo, tasks).(*calendarServiceImpl)
+	service.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+	due := time.Date(2030, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	repo.On("GetUserIDByToken", ctx, "secret").Return(7, nil)
+	repo.On("GetUserIDByToken", ctx, "unknown").Return(0, sql.ErrNoRows)
+	tasks.On("Stream", ctx, models.TaskFilter{MemberID: 7, SortBy: "due"}, mock.Anything).Return([]models.Task{
+		{ID: 1, Name: "Pay rent, again", Status: "Pending", Due: due, Priority: models.PriorityHigh, Recurrence: "FREQ=MONTHLY"},
+		{ID: 2, Name: "Done", Status: "Completed", Due: due, EstimateMinutes: 90},
+		{ID: 3, Name: "Someday"},
+	}, nil)
+
+	var out strings.Builder
+	require.NoError(t, service.WriteFeed(ctx, "secret", ical.VTodo, &out))
+
+	feed := out.String()
+	require.True(t, strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.Contains(t, feed, "BEGIN:VTODO\r\nUID:task-1@webtasks\r\nDTSTAMP:20300101T000000Z\r\n"+
+		"SUMMARY:Pay rent\\, again\r\nDUE:20300102T150000Z\r\nSTATUS:NEEDS-ACTION\r\nPRIORITY:3\r\n"+
+		"RRULE:FREQ=MONTHLY\r\nEND:VTODO\r\n")
+	require.Contains(t, feed, "STATUS:COMPLETED")
+	require.NotContains(t, feed, "Someday")
+	require.True(t, strings.HasSuffix(feed, "END:VCALENDAR\r\n"))
+
+	// Событие заканчивается к сроку и длится по оценке задачи
+	out.Reset()
+	require.NoError(t, service.WriteFeed(ctx, "secret", ical.VEvent, &out))
+	require.Contains(t, out.String(), "DTSTART:20300102T133000Z\r\nDTEND:20300102T150000Z\r\nSTATUS:CONFIRMED")
+
+	out.Reset()
+	require.ErrorIs(t, service.WriteFeed(ctx, "unknown", ical.VTodo, &out), ErrCalendarFeedNotFound)
+	require.Empty(t, out.String())
+}
+
+func TestTaskService_Import_ICS(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+
+	source := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\nUID:1\r\nSUMMARY:Water plants\r\nDUE:20990102T090000Z\r\n" +
+		"STATUS:IN-PROCESS\r\nPRIORITY:1\r\nRRULE:freq=weekly;byday=MO,TH\r\nCATEGORIES:home\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:2\r\nSUMMARY:Broken\r\nDUE:tomorrow\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	report, err := service.Import(ctx, models.ImportOptions{Format: models.TransferICS, UserID: 7, DryRun: true}, strings.NewReader(source))
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Total)
+	require.Equal(t, []models.ImportRowError{{Row: 2, Column: "DUE", Error: "expected a DATE or DATE-TIME value"}}, report.Errors)
+
+	source = strings.Replace(source, "DUE:tomorrow", "DUE;VALUE=DATE:20990103", 1)
+
+	mockRepo.On("ApplyBulk", ctx, mock.MatchedBy(func(changes []models.TaskChange) bool {
+		task := changes[0].Task
+		return len(changes) == 2 && task.Name == "Water plants" && task.Status == "In Progress" &&
+			task.Priority == models.PriorityUrgent && task.Recurrence == "FREQ=WEEKLY;BYDAY=MO,TH" &&
+			task.Due.Equal(time.Date(2099, 1, 2, 9, 0, 0, 0, time.UTC)) && changes[0].Tags[0] == "home" &&
+			changes[1].Task.Status == "Pending" && changes[1].Task.Priority == models.PriorityMedium
+	})).Return([]*models.Task{{ID: 1}, {ID: 2}}, nil)
+
+	report, err = service.Import(ctx, models.ImportOptions{Format: models.TransferICS, UserID: 7}, strings.NewReader(source))
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Imported)
+
+	_, err = service.Import(ctx, models.ImportOptions{Format: models.TransferICS}, strings.NewReader("BEGIN:VTODO\r\n"))
+	require.ErrorIs(t, err, ErrInvalidImport)
+}
+
+func TestNormalizeRecurrence(t *testing.T) {
+	valid := map[string]string{
+		"":                                     "",
+		"RRULE:freq=daily;interval=2":          "FREQ=DAILY;INTERVAL=2",
+		"FREQ=WEEKLY;BYDAY=MO,-1FR;WKST=MO":    "FREQ=WEEKLY;BYDAY=MO,-1FR;WKST=MO",
+		"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12":  "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12",
+		"FREQ=YEARLY;BYMONTH=3;UNTIL=20301231": "FREQ=YEARLY;BYMONTH=3;UNTIL=20301231",
+	}
+
+	for rule, expected := range valid {
+		normalized, err := NormalizeRecurrence(rule)
+		require.NoError(t, err, rule)
+		require.Equal(t, expected, normalized)
+	}
+
+	for _, rule := range []string{
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20301231",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYSETPOS=1",
+	} {
+		_, err := NormalizeRecurrence(rule)
+		require.Error(t, err, rule)
+	}
+}
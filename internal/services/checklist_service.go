@@ -0,0 +1,46 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrChecklistItemNotFound = errors.New("checklist item not found")
+
+type ChecklistService interface {
+	GetByTask(ctx context.Context, taskID int) ([]models.ChecklistItem, error)
+	SetDone(ctx context.Context, taskID, id int, done bool) (models.ChecklistItem, error)
+}
+
+type checklistServiceImpl struct {
+	repo  repositories.ChecklistRepository
+	tasks repositories.TaskRepository
+}
+
+func NewChecklistService(repo repositories.ChecklistRepository, tasks repositories.TaskRepository) ChecklistService {
+	return &checklistServiceImpl{repo: repo, tasks: tasks}
+}
+
+func (s *checklistServiceImpl) GetByTask(ctx context.Context, taskID int) ([]models.ChecklistItem, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+func (s *checklistServiceImpl) SetDone(ctx context.Context, taskID, id int, done bool) (models.ChecklistItem, error) {
+	item, err := s.repo.SetDone(ctx, taskID, id, done)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ChecklistItem{}, ErrChecklistItemNotFound
+	}
+
+	if err != nil {
+		return models.ChecklistItem{}, err
+	}
+
+	return *item, nil
+}
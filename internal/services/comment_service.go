@@ -0,0 +1,28 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+)
+
+type CommentService interface {
+	GetByTask(ctx context.Context, taskID int) ([]models.Comment, error)
+}
+
+type commentServiceImpl struct {
+	repo  repositories.CommentRepository
+	tasks repositories.TaskRepository
+}
+
+func NewCommentService(repo repositories.CommentRepository, tasks repositories.TaskRepository) CommentService {
+	return &commentServiceImpl{repo: repo, tasks: tasks}
+}
+
+func (s *commentServiceImpl) GetByTask(ctx context.Context, taskID int) ([]models.Comment, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
@@ -0,0 +1,17 @@
+package services
+
+import "context"
+
+type userIDKey struct{}
+
+// WithUserID сохраняет в контексте ID пользователя, от имени которого выполняется запрос.
+// Его задаёт IdentityMiddleware; сервисы берут из него автора создаваемых задач.
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext возвращает ID пользователя, сохранённый WithUserID.
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int)
+	return userID, ok
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultHealthTimeout = 2 * time.Second
+
+// HealthService отвечает на пробы оркестратора. Live говорит только о том, что процесс
+// жив; Ready проверяет базу, версию схемы и фоновые задачи и перестаёт проходить после
+// Drain, чтобы балансировщик снял экземпляр до остановки сервера.
+type HealthService interface {
+	Live() models.HealthReport
+	Ready(ctx context.Context) models.HealthReport
+	Status(ctx context.Context) models.StatusReport
+	WorkerStarted(name string)
+	WorkerStopped(name string)
+	Drain()
+}
+
+type healthServiceImpl struct {
+	repo          repositories.HealthRepository
+	schemaVersion int
+	timeout       time.Duration
+	startedAt     time.Time
+	now           func() time.Time
+
+	mu       sync.Mutex
+	workers  map[string]bool
+	draining atomic.Bool
+}
+
+// NewHealthService создаёт сервис проверок; schemaVersion - версия схемы, которую ожидает
+// сборка (db.SchemaVersion), timeout ограничивает обращения к базе, <= 0 заменяется на 2 с.
+func NewHealthService(repo repositories.HealthRepository, schemaVersion int, timeout time.Duration) HealthService {
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+
+	return &healthServiceImpl{
+		repo:          repo,
+		schemaVersion: schemaVersion,
+		timeout:       timeout,
+		startedAt:     time.Now(),
+		now:           time.Now,
+		workers:       make(map[string]bool),
+	}
+}
+
+func (s *healthServiceImpl) Live() models.HealthReport {
+	return models.HealthReport{Status: models.HealthOK}
+}
+
+func (s *healthServiceImpl) Ready(ctx context.Context) models.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	checks := map[string]models.HealthCheck{
+		"database":   s.check(func() error { return s.repo.Ping(ctx) }),
+		"migrations": s.check(func() error { return s.checkSchema(ctx) }),
+		"workers":    s.check(s.checkWorkers),
+		"serving":    s.check(s.checkServing),
+	}
+
+	report := models.HealthReport{Status: models.HealthOK, Checks: checks}
+
+	for _, check := range checks {
+		if check.Status != models.HealthOK {
+			report.Status = models.HealthFail
+		}
+	}
+
+	return report
+}
+
+func (s *healthServiceImpl) Status(ctx context.Context) models.StatusReport {
+	report := models.StatusReport{
+		HealthReport: s.Ready(ctx),
+		Build:        buildInfo(),
+		StartedAt:    s.startedAt.UTC(),
+		Uptime:       s.now().Sub(s.startedAt).Round(time.Second).String(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Версия схемы в отчёте - фактическая; 0, если её не удалось прочитать
+	report.SchemaVersion, _ = s.repo.SchemaVersion(ctx)
+
+	stats := s.repo.Stats()
+	report.Pool = models.PoolStats{
+		MaxOpen:      stats.MaxOpenConnections,
+		Open:         stats.OpenConnections,
+		InUse:        stats.InUse,
+		Idle:         stats.Idle,
+		WaitCount:    stats.WaitCount,
+		WaitDuration: stats.WaitDuration.String(),
+	}
+
+	s.mu.Lock()
+	report.Workers = maps.Clone(s.workers)
+	s.mu.Unlock()
+
+	return report
+}
+
+// WorkerStarted и WorkerStopped отмечают состояние фоновой задачи; остановленная задача
+// делает экземпляр неготовым.
+func (s *healthServiceImpl) WorkerStarted(name string) {
+	s.setWorker(name, true)
+}
+
+func (s *healthServiceImpl) WorkerStopped(name string) {
+	s.setWorker(name, false)
+}
+
+// Drain переводит экземпляр в неготовые; вызывается в начале остановки сервера.
+func (s *healthServiceImpl) Drain() {
+	s.draining.Store(true)
+}
+
+func (s *healthServiceImpl) setWorker(name string, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.workers[name] = running
+}
+
+// check выполняет проверку и замеряет её длительность.
+func (s *healthServiceImpl) check(fn func() error) models.HealthCheck {
+	start := s.now()
+	err := fn()
+
+	check := models.HealthCheck{Status: models.HealthOK, Duration: s.now().Sub(start).String()}
+	if err != nil {
+		check.Status = models.HealthFail
+		check.Error = err.Error()
+	}
+
+	return check
+}
+
+func (s *healthServiceImpl) checkSchema(ctx context.Context) error {
+	version, err := s.repo.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Схема может опережать сборку: при поэтапном обновлении новый экземпляр применяет
+	// миграции раньше, чем остановятся старые, и они должны оставаться готовыми
+	if version < s.schemaVersion {
+		return fmt.Errorf("schema version %d, expected at least %d", version, s.schemaVersion)
+	}
+
+	return nil
+}
+
+func (s *healthServiceImpl) checkWorkers() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stopped []string
+
+	for name, running := range s.workers {
+		if !running {
+			stopped = append(stopped, name)
+		}
+	}
+
+	if len(stopped) > 0 {
+		slices.Sort(stopped)
+		return fmt.Errorf("stopped: %s", strings.Join(stopped, ", "))
+	}
+
+	return nil
+}
+
+func (s *healthServiceImpl) checkServing() error {
+	if s.draining.Load() {
+		return fmt.Errorf("shutting down")
+	}
+
+	return nil
+}
+
+// buildInfo читает версию модуля и данные VCS, которые go build встраивает в бинарник.
+func buildInfo() models.BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return models.BuildInfo{Version: "unknown"}
+	}
+
+	build := models.BuildInfo{Version: info.Main.Version, GoVersion: info.GoVersion}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.Time = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+
+	return build
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHealthRepository реализует методы HealthRepository для тестов.
+type MockHealthRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockHealthRepository) SchemaVersion(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockHealthRepository) Stats() sql.DBStats {
+	return m.Called().Get(0).(sql.DBStats)
+}
+
+func TestHealthService_Ready(t *testing.T) {
+	repo := new(MockHealthRepository)
+	service := NewHealthService(repo, 41, 0)
+
+	repo.On("Ping", mock.Anything).Return(nil)
+	repo.On("SchemaVersion", mock.Anything).Return(41, nil).Once()
+
+	service.WorkerStarted("idempotency_purger")
+
+	report := service.Ready(context.Background())
+	require.Equal(t, models.HealthOK, report.Status)
+	require.Len(t, report.Checks, 4)
+
+	// Схема новее сборки - миграции уже применил следующий выпуск
+	repo.On("SchemaVersion", mock.Anything).Return(42, nil).Once()
+
+	report = service.Ready(context.Background())
+	require.Equal(t, models.HealthOK, report.Checks["migrations"].Status)
+
+	// Отставшая схема и остановленная фоновая задача
+	repo.On("SchemaVersion", mock.Anything).Return(40, nil).Once()
+	service.WorkerStopped("idempotency_purger")
+
+	report = service.Ready(context.Background())
+	require.Equal(t, models.HealthFail, report.Status)
+	require.Equal(t, "schema version 40, expected at least 41", report.Checks["migrations"].Error)
+	require.Equal(t, "stopped: idempotency_purger", report.Checks["workers"].Error)
+
+	// После Drain экземпляр не готов, но жив
+	repo.On("SchemaVersion", mock.Anything).Return(0, errors.New("connection refused"))
+	service.Drain()
+
+	report = service.Ready(context.Background())
+	require.Equal(t, models.HealthFail, report.Checks["serving"].Status)
+	require.Equal(t, models.HealthOK, service.Live().Status)
+
+	repo.AssertExpectations(t)
+}
+
+func TestHealthService_Status(t *testing.T) {
+	repo := new(MockHealthRepository)
+	service := NewHealthService(repo, 41, 0)
+
+	repo.On("Ping", mock.Anything).Return(nil)
+	repo.On("SchemaVersion", mock.Anything).Return(41, nil)
+	repo.On("Stats").Return(sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 1, Idle: 2})
+
+	service.WorkerStarted("idempotency_purger")
+
+	report := service.Status(context.Background())
+	require.Equal(t, models.HealthOK, report.Status)
+	require.Equal(t, 41, report.SchemaVersion)
+	require.Equal(t, models.PoolStats{MaxOpen: 10, Open: 3, InUse: 1, Idle: 2, WaitDuration: "0s"}, report.Pool)
+	require.Equal(t, map[string]bool{"idempotency_purger": true}, report.Workers)
+	require.NotEmpty(t, report.Build.GoVersion)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultIdempotencyTTL = 24 * time.Hour
+	maxIdempotencyKeyLen  = 255
+)
+
+var (
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	ErrIdempotencyMismatch   = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, key string, userID int, endpoint, fingerprint string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record models.IdempotencyRecord) error
+	Release(ctx context.Context, key string, userID int, endpoint string) error
+	Purge(ctx context.Context) (int64, error)
+	RunPurger(ctx context.Context, interval time.Duration)
+	SetTTL(ttl time.Duration)
+}
+
+type idempotencyServiceImpl struct {
+	repo repositories.IdempotencyRepository
+	ttl  atomic.Int64
+	now  func() time.Time
+}
+
+// NewIdempotencyService создаёт сервис ключей идемпотентности; ttl <= 0 заменяется на сутки.
+func NewIdempotencyService(repo repositories.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	s := &idempotencyServiceImpl{repo: repo, now: time.Now}
+	s.SetTTL(ttl)
+
+	return s
+}
+
+// SetTTL меняет срок хранения ключей на лету; ttl <= 0 заменяется на сутки.
+func (s *idempotencyServiceImpl) SetTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+
+	s.ttl.Store(int64(ttl))
+}
+
+// Begin занимает ключ для нового запроса и возвращает nil. Если запрос с этим ключом
+// уже выполнен, возвращается сохранённая запись для повтора ответа.
+func (s *idempotencyServiceImpl) Begin(
+	ctx context.Context,
+	key string,
+	userID int,
+	endpoint, fingerprint string,
+) (*models.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	now := s.now()
+	record := models.IdempotencyRecord{
+		Key:         key,
+		UserID:      userID,
+		Endpoint:    endpoint,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+	}
+
+	reserved, err := s.repo.Reserve(ctx, record, now.Add(-time.Duration(s.ttl.Load())))
+	if err != nil {
+		return nil, err
+	}
+
+	if reserved {
+		return nil, nil
+	}
+
+	stored, err := s.repo.Get(ctx, key, userID, endpoint)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Запись успели освободить между Reserve и Get
+		return nil, ErrIdempotencyInProgress
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyMismatch
+	}
+
+	if stored.Status == 0 {
+		return nil, ErrIdempotencyInProgress
+	}
+
+	return stored, nil
+}
+
+func (s *idempotencyServiceImpl) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	return s.repo.Complete(ctx, record)
+}
+
+// Release освобождает ключ, чтобы клиент мог повторить запрос, завершившийся сбоем.
+func (s *idempotencyServiceImpl) Release(ctx context.Context, key string, userID int, endpoint string) error {
+	return s.repo.Delete(ctx, key, userID, endpoint)
+}
+
+// Purge удаляет просроченные ключи.
+func (s *idempotencyServiceImpl) Purge(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, s.now().Add(-time.Duration(s.ttl.Load())))
+}
+
+// RunPurger периодически удаляет просроченные ключи до отмены ctx.
+func (s *idempotencyServiceImpl) RunPurger(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.Purge(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "purging idempotency keys failed", "error", err)
+				continue
+			}
+
+			if deleted > 0 {
+				slog.InfoContext(ctx, "purged idempotency keys", "deleted", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockIdempotencyRepository реализует методы IdempotencyRepository для тестов.
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, record models.IdempotencyRecord, expiredBefore time.Time) (bool, error) {
+	args := m.Called(ctx, record, expiredBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Get(ctx context.Context, key string, userID int, endpoint string) (*models.IdempotencyRecord, error) {
+	args := m.Called(ctx, key, userID, endpoint)
+	record, _ := args.Get(0).(*models.IdempotencyRecord)
+
+	return record, args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	return m.Called(ctx, record).Error(0)
+}
+
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, key string, userID int, endpoint string) error {
+	return m.Called(ctx, key, userID, endpoint).Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	service := NewIdempotencyService(repo, time.Hour).(*idempotencyServiceImpl)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	expiredBefore := now.Add(-time.Hour)
+
+	reserve := func(key, fingerprint string) models.IdempotencyRecord {
+		return models.IdempotencyRecord{Key: key, UserID: 1, Endpoint: "POST /tasks", Fingerprint: fingerprint, CreatedAt: now}
+	}
+
+	// Новый ключ занимается запросом
+	repo.On("Reserve", ctx, reserve("new", "f1"), expiredBefore).Return(true, nil)
+
+	stored, err := service.Begin(ctx, "new", 1, "POST /tasks", "f1")
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	// Выполненный запрос повторяется, другой запрос с тем же ключом отклоняется
+	done := &models.IdempotencyRecord{Key: "done", Fingerprint: "f1", Status: http.StatusCreated, Body: []byte(`{"id":1}`)}
+	repo.On("Reserve", ctx, reserve("done", "f1"), expiredBefore).Return(false, nil)
+	repo.On("Reserve", ctx, reserve("done", "f2"), expiredBefore).Return(false, nil)
+	repo.On("Get", ctx, "done", 1, "POST /tasks").Return(done, nil)
+
+	stored, err = service.Begin(ctx, "done", 1, "POST /tasks", "f1")
+	require.NoError(t, err)
+	require.Equal(t, done, stored)
+
+	_, err = service.Begin(ctx, "done", 1, "POST /tasks", "f2")
+	require.ErrorIs(t, err, ErrIdempotencyMismatch)
+
+	// Исходный запрос ещё выполняется
+	repo.On("Reserve", ctx, reserve("busy", "f1"), expiredBefore).Return(false, nil)
+	repo.On("Get", ctx, "busy", 1, "POST /tasks").Return(&models.IdempotencyRecord{Fingerprint: "f1"}, nil)
+
+	_, err = service.Begin(ctx, "busy", 1, "POST /tasks", "f1")
+	require.ErrorIs(t, err, ErrIdempotencyInProgress)
+
+	repo.On("Reserve", ctx, reserve("gone", "f1"), expiredBefore).Return(false, nil)
+	repo.On("Get", ctx, "gone", 1, "POST /tasks").Return(nil, sql.ErrNoRows)
+
+	_, err = service.Begin(ctx, "gone", 1, "POST /tasks", "f1")
+	require.ErrorIs(t, err, ErrIdempotencyInProgress)
+
+	_, err = service.Begin(ctx, "", 1, "POST /tasks", "f1")
+	require.ErrorIs(t, err, ErrInvalidIdempotencyKey)
+}
+
+func TestIdempotencyService_Purge(t *testing.T) {
+	repo := new(MockIdempotencyRepository)
+	service := NewIdempotencyService(repo, 0).(*idempotencyServiceImpl)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	// Без настройки ключи хранятся сутки
+	repo.On("DeleteExpired", mock.Anything, now.Add(-24*time.Hour)).Return(int64(2), nil)
+
+	deleted, err := service.Purge(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	// Воркер завершается при отмене контекста
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+
+	go func() {
+		service.RunPurger(ctx, time.Millisecond)
+		close(finished)
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop")
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const maxMembersPerRequest = 100
+
+var ErrInvalidMember = errors.New("invalid task member")
+
+// MemberService управляет исполнителями и наблюдателями задачи. Recipients - список
+// пользователей, которым адресуются события задачи: автор, исполнители и наблюдатели.
+type MemberService interface {
+	GetByTask(ctx context.Context, taskID int) (models.TaskMembers, error)
+	Add(ctx context.Context, taskID int, role string, userIDs []int) (models.TaskMembers, error)
+	Remove(ctx context.Context, taskID int, role string, userID int) (models.TaskMembers, error)
+	Recipients(ctx context.Context, taskID int) ([]int, error)
+}
+
+type memberServiceImpl struct {
+	repo  repositories.MemberRepository
+	tasks repositories.TaskRepository
+}
+
+func NewMemberService(repo repositories.MemberRepository, tasks repositories.TaskRepository) MemberService {
+	return &memberServiceImpl{repo: repo, tasks: tasks}
+}
+
+func (s *memberServiceImpl) GetByTask(ctx context.Context, taskID int) (models.TaskMembers, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+// Add добавляет пользователей в роль; повторное добавление не считается ошибкой.
+func (s *memberServiceImpl) Add(ctx context.Context, taskID int, role string, userIDs []int) (models.TaskMembers, error) {
+	if err := checkMemberRole(role); err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	if len(userIDs) == 0 || len(userIDs) > maxMembersPerRequest {
+		return models.TaskMembers{}, fmt.Errorf("%w: expected 1 to %d user IDs", ErrInvalidMember, maxMembersPerRequest)
+	}
+
+	for _, id := range userIDs {
+		if id <= 0 {
+			return models.TaskMembers{}, fmt.Errorf("%w: user ID must be positive", ErrInvalidMember)
+		}
+	}
+
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	userIDs = slices.Clone(userIDs)
+	slices.Sort(userIDs)
+	userIDs = slices.Compact(userIDs)
+
+	err := s.repo.Add(ctx, taskID, role, userIDs)
+	if errors.Is(err, repositories.ErrMissingReference) {
+		return models.TaskMembers{}, ErrUserNotFound
+	}
+
+	if err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+// Remove убирает пользователя из роли; отсутствие пользователя в роли не считается ошибкой.
+func (s *memberServiceImpl) Remove(ctx context.Context, taskID int, role string, userID int) (models.TaskMembers, error) {
+	if err := checkMemberRole(role); err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	if err := s.repo.Remove(ctx, taskID, role, userID); err != nil {
+		return models.TaskMembers{}, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+func (s *memberServiceImpl) Recipients(ctx context.Context, taskID int) ([]int, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Recipients(ctx, taskID)
+}
+
+func checkMemberRole(role string) error {
+	if role != models.RoleAssignee && role != models.RoleWatcher {
+		return fmt.Errorf("%w: role must be %q or %q", ErrInvalidMember, models.RoleAssignee, models.RoleWatcher)
+	}
+
+	return nil
+}
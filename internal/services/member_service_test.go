@@ -0,0 +1,94 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMemberRepository реализует методы MemberRepository для тестов.
+type MockMemberRepository struct {
+	mock.Mock
+}
+
+func (m *MockMemberRepository) GetByTask(ctx context.Context, taskID int) (models.TaskMembers, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).(models.TaskMembers), args.Error(1)
+}
+
+func (m *MockMemberRepository) Add(ctx context.Context, taskID int, role string, userIDs []int) error {
+	return m.Called(ctx, taskID, role, userIDs).Error(0)
+}
+
+func (m *MockMemberRepository) Remove(ctx context.Context, taskID int, role string, userID int) error {
+	return m.Called(ctx, taskID, role, userID).Error(0)
+}
+
+func (m *MockMemberRepository) Recipients(ctx context.Context, taskID int) ([]int, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func TestMemberService_Add(t *testing.T) {
+	mockRepo := new(MockMemberRepository)
+	mockTasks := new(MockTaskRepository)
+	service := NewMemberService(mockRepo, mockTasks)
+
+	ctx := context.Background()
+	members := models.TaskMembers{
+		Assignees: []models.TaskMember{{UserID: 2, Name: "Jane"}, {UserID: 3, Name: "John"}},
+		Watchers:  []models.TaskMember{},
+	}
+
+	mockTasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	mockTasks.On("GetByID", ctx, 2).Return(nil, sql.ErrNoRows)
+	mockRepo.On("Add", ctx, 1, models.RoleAssignee, []int{2, 3}).Return(nil).Once()
+	mockRepo.On("Add", ctx, 1, models.RoleWatcher, []int{99}).Return(repositories.ErrMissingReference).Once()
+	mockRepo.On("GetByTask", ctx, 1).Return(members, nil)
+
+	// Повторы в запросе схлопываются
+	got, err := service.Add(ctx, 1, models.RoleAssignee, []int{3, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, members, got)
+
+	// Ошибка: несуществующий пользователь
+	_, err = service.Add(ctx, 1, models.RoleWatcher, []int{99})
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	// Ошибка: неизвестная роль и пустой список
+	_, err = service.Add(ctx, 1, "owner", []int{2})
+	require.ErrorIs(t, err, ErrInvalidMember)
+
+	_, err = service.Add(ctx, 1, models.RoleWatcher, nil)
+	require.ErrorIs(t, err, ErrInvalidMember)
+
+	// Ошибка: задача не найдена
+	_, err = service.Add(ctx, 2, models.RoleWatcher, []int{2})
+	require.ErrorIs(t, err, ErrTaskNotFound)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMemberService_Remove(t *testing.T) {
+	mockRepo := new(MockMemberRepository)
+	mockTasks := new(MockTaskRepository)
+	service := NewMemberService(mockRepo, mockTasks)
+
+	ctx := context.Background()
+	members := models.TaskMembers{Assignees: []models.TaskMember{}, Watchers: []models.TaskMember{}}
+
+	mockTasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	mockRepo.On("Remove", ctx, 1, models.RoleWatcher, 2).Return(nil)
+	mockRepo.On("GetByTask", ctx, 1).Return(members, nil)
+
+	got, err := service.Remove(ctx, 1, models.RoleWatcher, 2)
+	require.NoError(t, err)
+	require.Equal(t, members, got)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"WebTasks/internal/importers"
+	"WebTasks/internal/metrics"
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxMigrationTasks    = 5000
+	maxTaskNameBytes     = 50 // Ограничение prepareCreate на длину названия
+	maxCommentAuthorLen  = 255
+	defaultTaskStatus    = "Pending"
+	untitledMigratedTask = "Untitled"
+)
+
+var ErrInvalidMigration = errors.New("invalid migration")
+
+// MigrationService переносит проекты из выгрузок Trello, Todoist и Jira.
+type MigrationService interface {
+	Import(ctx context.Context, options models.MigrationOptions, body io.Reader) (models.MigrationReport, error)
+}
+
+type migrationServiceImpl struct {
+	repo  repositories.MigrationRepository
+	quota TaskQuotaChecker
+	now   func() time.Time
+}
+
+// NewMigrationService создаёт сервис миграции. quota может быть nil - тогда квота
+// задач не проверяется (перенос из командной строки).
+func NewMigrationService(repo repositories.MigrationRepository, quota TaskQuotaChecker) MigrationService {
+	return &migrationServiceImpl{repo: repo, quota: quota, now: time.Now}
+}
+
+// Import разбирает выгрузку и создаёт проекты с задачами от имени options.UserID одной
+// транзакцией. В отличие от Create, прошедшие сроки сохраняются, а длинные названия
+// обрезаются: полное название остаётся первым комментарием задачи.
+func (s *migrationServiceImpl) Import(
+	ctx context.Context,
+	options models.MigrationOptions,
+	body io.Reader,
+) (models.MigrationReport, error) {
+	report := models.MigrationReport{Source: options.Source, DryRun: options.DryRun, Warnings: []string{}}
+
+	parsed, err := importers.Parse(options.Source, body)
+	if err != nil {
+		return report, fmt.Errorf("%w: %v", ErrInvalidMigration, err)
+	}
+
+	report.Warnings = append(report.Warnings, parsed.Warnings...)
+
+	if options.Project != "" && len(parsed.Projects) > 1 {
+		return report, fmt.Errorf("%w: project name can only be set for a single-project export", ErrInvalidMigration)
+	}
+
+	normalizer := migrationNormalizer{userID: options.UserID, now: s.now(), report: &report}
+	counts := make([]int, len(parsed.Projects))
+
+	for i := range parsed.Projects {
+		project := &parsed.Projects[i]
+
+		if options.Project != "" {
+			project.Name = options.Project
+		}
+
+		project.Name = truncateRunes(strings.TrimSpace(project.Name), maxProjectNameLength)
+		if project.Name == "" {
+			project.Name = "Imported from " + options.Source
+		}
+
+		before := report.Tasks
+
+		for j := range project.Tasks {
+			normalizer.normalize(&project.Tasks[j])
+		}
+
+		counts[i] = report.Tasks - before
+	}
+
+	if report.Tasks > maxMigrationTasks {
+		return report, fmt.Errorf("%w: at most %d tasks per migration", ErrInvalidMigration, maxMigrationTasks)
+	}
+
+	if s.quota != nil {
+		var tasks []models.Task
+		for _, project := range parsed.Projects {
+			tasks = append(tasks, treeTasks(project.Tasks)...)
+		}
+
+		if err := s.quota.ReserveTasks(ctx, tasks); err != nil {
+			return report, err
+		}
+	}
+
+	projects := make([]models.Project, len(parsed.Projects))
+	for i, project := range parsed.Projects {
+		projects[i] = models.Project{Name: project.Name, OwnerID: options.UserID}
+	}
+
+	if !options.DryRun && len(parsed.Projects) > 0 {
+		projects, err = s.repo.Import(ctx, parsed.Projects, options.UserID)
+		if err != nil {
+			return report, err
+		}
+
+		metrics.TasksCreated(metrics.SourceMigration, report.Tasks)
+	}
+
+	report.Projects = make([]models.MigratedProject, len(projects))
+	for i, project := range projects {
+		report.Projects[i] = models.MigratedProject{Project: project, Tasks: counts[i]}
+	}
+
+	return report, nil
+}
+
+// migrationNormalizer приводит перенесённые задачи к ограничениям WebTasks и считает итоги.
+type migrationNormalizer struct {
+	userID int
+	now    time.Time
+	report *models.MigrationReport
+}
+
+func (n *migrationNormalizer) normalize(tree *models.TaskTree) {
+	n.report.Tasks++
+
+	task := &tree.Task
+	task.ID = 0
+	task.UserID = n.userID
+	task.CreatedBy = n.userID
+	task.Recurrence = ""
+
+	if task.Time.IsZero() {
+		task.Time = n.now
+	}
+
+	name := strings.Join(strings.Fields(task.Name), " ")
+	if name == "" {
+		name = untitledMigratedTask
+	}
+
+	task.Name = truncateBytes(name, maxTaskNameBytes)
+
+	var comments []models.Comment
+
+	if task.Name != name {
+		comments = append(comments, models.Comment{Body: "Full title: " + name, CreatedAt: task.Time})
+	}
+
+	task.Status = truncateBytes(strings.TrimSpace(task.Status), 50)
+	if task.Status == "" {
+		task.Status = defaultTaskStatus
+	}
+
+	if !validPriority(task.Priority) {
+		task.Priority = models.PriorityMedium
+	}
+
+	if task.EstimateMinutes < 0 {
+		task.EstimateMinutes = 0
+	}
+
+	tree.Tags = n.tags(task.Name, tree.Tags)
+	tree.Checklist, tree.Done = n.checklist(tree.Checklist, tree.Done)
+
+	for _, comment := range tree.Comments {
+		comment.Body = strings.TrimSpace(comment.Body)
+		if comment.Body == "" {
+			continue
+		}
+
+		comment.Author = truncateRunes(strings.TrimSpace(comment.Author), maxCommentAuthorLen)
+		if comment.CreatedAt.IsZero() {
+			comment.CreatedAt = task.Time
+		}
+
+		comments = append(comments, comment)
+	}
+
+	tree.Comments = comments
+	n.report.Comments += len(comments)
+
+	for i := range tree.Subtasks {
+		n.normalize(&tree.Subtasks[i])
+	}
+}
+
+// tags пропускает метки, которые нельзя превратить в тег, с предупреждением.
+func (n *migrationNormalizer) tags(taskName string, labels []string) []string {
+	valid := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		tag := strings.Join(strings.Fields(label), "-")
+
+		if _, err := NormalizeTags([]string{tag}); err != nil {
+			n.report.Warnings = append(n.report.Warnings, fmt.Sprintf("task %q: label %q is skipped", taskName, label))
+			continue
+		}
+
+		valid = append(valid, tag)
+	}
+
+	tags, _ := NormalizeTags(valid)
+
+	return tags
+}
+
+func (n *migrationNormalizer) checklist(items []string, done []bool) ([]string, []bool) {
+	var (
+		texts []string
+		marks []bool
+	)
+
+	for i, text := range items {
+		text = truncateRunes(strings.TrimSpace(text), maxChecklistItemLen)
+		if text == "" {
+			continue
+		}
+
+		texts = append(texts, text)
+		marks = append(marks, i < len(done) && done[i])
+	}
+
+	n.report.Checklist += len(texts)
+
+	return texts, marks
+}
+
+// truncateBytes укорачивает строку до limit байт по границе символа, отмечая обрезку многоточием.
+func truncateBytes(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	cut := limit - len("...")
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+
+	return value[:cut] + "..."
+}
+
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+
+	return string([]rune(value)[:limit])
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMigrationRepository реализует методы MigrationRepository для тестов.
+type MockMigrationRepository struct {
+	mock.Mock
+}
+
+func (m *MockMigrationRepository) Import(ctx context.Context, projects []models.ImportedProject, ownerID int) ([]models.Project, error) {
+	args := m.Called(ctx, projects, ownerID)
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+const migrationTodoist = "TYPE,CONTENT,PRIORITY,INDENT,DATE\n" +
+	"task,Renew the passport and the driving licence before the summer trip,9,1,2020-01-01\n" +
+	"task,Call @mom,,2,\n" +
+	"note,   ,,,\n" +
+	"task,Buy @gifts-for-the-whole-family-and-all-of-the-neighbours-too,2,1,\n"
+
+func newTestMigrationService() (*migrationServiceImpl, *MockMigrationRepository) {
+	repo := new(MockMigrationRepository)
+
+	service := NewMigrationService(repo, nil).(*migrationServiceImpl)
+	service.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	return service, repo
+}
+
+func TestMigrationService_Import(t *testing.T) {
+	service, repo := newTestMigrationService()
+	ctx := context.Background()
+
+	var imported []models.ImportedProject
+
+	repo.On("Import", ctx, mock.Anything, 7).Run(func(args mock.Arguments) {
+		imported = args.Get(1).([]models.ImportedProject)
+	}).Return([]models.Project{{ID: 3, Name: "Personal", OwnerID: 7}}, nil)
+
+	report, err := service.Import(ctx, models.MigrationOptions{
+		Source: models.MigrationTodoist, Project: " Personal ", UserID: 7,
+	}, strings.NewReader(migrationTodoist))
+	require.NoError(t, err)
+
+	require.Equal(t, 3, report.Tasks)
+	require.Equal(t, []models.MigratedProject{{Project: models.Project{ID: 3, Name: "Personal", OwnerID: 7}, Tasks: 3}}, report.Projects)
+
+	require.Len(t, imported, 1)
+	require.Equal(t, "Personal", imported[0].Name)
+
+	passport := imported[0].Tasks[0].Task
+	require.LessOrEqual(t, len(passport.Name), 50)
+	require.True(t, strings.HasSuffix(passport.Name, "..."))
+	require.Equal(t, 7, passport.UserID)
+	require.Equal(t, "Pending", passport.Status)
+	require.Equal(t, models.PriorityMedium, passport.Priority)
+	require.Equal(t, service.now(), passport.Time)
+
+	// Прошедший срок переносится как есть, полное название - в комментарии
+	require.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), passport.Due)
+	require.Equal(t, "Full title: Renew the passport and the driving licence before the summer trip",
+		imported[0].Tasks[0].Comments[0].Body)
+
+	// Пустая заметка отброшена
+	call := imported[0].Tasks[0].Subtasks[0]
+	require.Equal(t, []string{"mom"}, call.Tags)
+	require.Empty(t, call.Comments)
+	require.Equal(t, 1, report.Comments)
+
+	// Слишком длинная метка не может быть тегом
+	require.Len(t, report.Warnings, 1)
+	require.Contains(t, report.Warnings[0], `"gifts-for-the-whole-family`)
+}
+
+func TestMigrationService_Import_DryRun(t *testing.T) {
+	service, repo := newTestMigrationService()
+	ctx := context.Background()
+
+	report, err := service.Import(ctx, models.MigrationOptions{
+		Source: models.MigrationTodoist, DryRun: true, UserID: 7,
+	}, strings.NewReader(migrationTodoist))
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, "Todoist", report.Projects[0].Project.Name)
+	require.Zero(t, report.Projects[0].Project.ID)
+
+	_, err = service.Import(ctx, models.MigrationOptions{Source: models.MigrationTrello, UserID: 7}, strings.NewReader("not json"))
+	require.ErrorIs(t, err, ErrInvalidMigration)
+
+	repo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultNextUpLimit = 10
+	maxNextUpLimit     = 100
+)
+
+var (
+	ErrInvalidDependency = errors.New("task cannot depend on itself")
+	ErrDependencyCycle   = errors.New("dependency would create a cycle")
+)
+
+// ScoringWeights - веса составляющих оценки задачи в списке "что делать дальше".
+type ScoringWeights struct {
+	Priority float64 // Приоритет задачи
+	DueDate  float64 // Близость срока
+	Age      float64 // Как давно задача создана
+	Blocking float64 // Сколько задач ждут эту
+	Blocked  float64 // Штраф за незакрытые блокирующие задачи
+}
+
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{Priority: 4, DueDate: 3, Age: 1, Blocking: 2, Blocked: 10}
+}
+
+type PlanningService interface {
+	NextUp(ctx context.Context, userID, limit int) ([]models.ScoredTask, error)
+	GetDependencies(ctx context.Context, taskID int) ([]models.Task, error)
+	AddDependency(ctx context.Context, taskID, dependsOnID int) error
+	RemoveDependency(ctx context.Context, taskID, dependsOnID int) error
+	SetWeights(weights ScoringWeights)
+}
+
+type planningServiceImpl struct {
+	repo      repositories.PlanningRepository
+	tasks     repositories.TaskRepository
+	weights   atomic.Pointer[ScoringWeights]
+	calendars WorkCalendarProvider
+	now       func() time.Time
+}
+
+// NewPlanningService создаёт сервис планирования; calendars может быть nil, см. NewTaskService.
+func NewPlanningService(
+	repo repositories.PlanningRepository,
+	tasks repositories.TaskRepository,
+	weights ScoringWeights,
+	calendars WorkCalendarProvider,
+) PlanningService {
+	s := &planningServiceImpl{repo: repo, tasks: tasks, calendars: calendars, now: time.Now}
+	s.SetWeights(weights)
+
+	return s
+}
+
+// SetWeights заменяет веса оценки на лету; нулевые веса заменяются на DefaultScoringWeights.
+func (s *planningServiceImpl) SetWeights(weights ScoringWeights) {
+	if weights == (ScoringWeights{}) {
+		weights = DefaultScoringWeights()
+	}
+
+	s.weights.Store(&weights)
+}
+
+// NextUp возвращает открытые задачи пользователя, упорядоченные по убыванию оценки.
+func (s *planningServiceImpl) NextUp(ctx context.Context, userID, limit int) ([]models.ScoredTask, error) {
+	if limit <= 0 {
+		limit = defaultNextUpLimit
+	}
+
+	if limit > maxNextUpLimit {
+		limit = maxNextUpLimit
+	}
+
+	candidates, err := s.repo.GetNextUpCandidates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := userCalendar(ctx, s.calendars, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	weights := *s.weights.Load()
+	scored := make([]models.ScoredTask, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		scored = append(scored, scoreTask(candidate, weights, now, calendar))
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+
+		return scored[i].ID < scored[j].ID
+	})
+
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	return scored, nil
+}
+
+func (s *planningServiceImpl) GetDependencies(ctx context.Context, taskID int) ([]models.Task, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDependencies(ctx, taskID)
+}
+
+func (s *planningServiceImpl) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	if taskID == dependsOnID {
+		return ErrInvalidDependency
+	}
+
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return err
+	}
+
+	if err := checkTaskExists(ctx, s.tasks, dependsOnID); err != nil {
+		return err
+	}
+
+	// Если dependsOnID уже (транзитивно) зависит от taskID, новая связь замкнёт цикл
+	cycle, err := s.repo.HasDependencyPath(ctx, dependsOnID, taskID)
+	if err != nil {
+		return err
+	}
+
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	return s.repo.AddDependency(ctx, taskID, dependsOnID)
+}
+
+func (s *planningServiceImpl) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveDependency(ctx, taskID, dependsOnID)
+}
+
+// scoreTask складывает нормированные к [0, 1] составляющие, умноженные на веса:
+// приоритет, близость срока в рабочих днях (просроченные - 1), возраст (насыщается за 30 дней)
+// и число ожидающих задач (насыщается на трёх). Заблокированная задача получает штраф.
+func scoreTask(candidate models.TaskCandidate, weights ScoringWeights, now time.Time, calendar *businessCalendar) models.ScoredTask {
+	breakdown := map[string]float64{
+		"priority": weights.Priority * priorityValue(candidate.Priority),
+		"due_date": weights.DueDate * dueProximity(candidate.Due, now, calendar),
+		"age":      weights.Age * ageValue(candidate.Time, now),
+		"blocking": weights.Blocking * math.Min(float64(candidate.Blocks), 3) / 3,
+		"blocked":  0,
+	}
+
+	if candidate.OpenBlockers > 0 {
+		breakdown["blocked"] = -weights.Blocked
+	}
+
+	var score float64
+	for _, value := range breakdown {
+		score += value
+	}
+
+	return models.ScoredTask{
+		Task:      candidate.Task,
+		Score:     math.Round(score*1000) / 1000,
+		Overdue:   isOverdue(candidate.Due, now),
+		Breakdown: breakdown,
+	}
+}
+
+func priorityValue(priority string) float64 {
+	for i, p := range models.Priorities {
+		if p == priority {
+			return float64(i+1) / float64(len(models.Priorities))
+		}
+	}
+
+	return priorityValue(models.PriorityMedium)
+}
+
+// dueProximity считает оставшееся время без выходных и праздников между сегодня и днём срока.
+func dueProximity(due, now time.Time, calendar *businessCalendar) float64 {
+	if due.IsZero() {
+		return 0
+	}
+
+	if isOverdue(due, now) {
+		return 1
+	}
+
+	days := due.Sub(now).Hours()/24 - float64(calendar.daysOff(now, due))
+
+	return 1 / (1 + math.Max(days, 0))
+}
+
+// isOverdue сравнивает моменты времени, поэтому не зависит от поясов сервера и пользователя.
+func isOverdue(due, now time.Time) bool {
+	return !due.IsZero() && !due.After(now)
+}
+
+func ageValue(created, now time.Time) float64 {
+	if created.IsZero() || created.After(now) {
+		return 0
+	}
+
+	return math.Min(now.Sub(created).Hours()/24/30, 1)
+}
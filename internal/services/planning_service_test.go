@@ -0,0 +1,114 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPlanningRepository реализует методы PlanningRepository для тестов.
+type MockPlanningRepository struct {
+	mock.Mock
+}
+
+func (m *MockPlanningRepository) AddDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return m.Called(ctx, taskID, dependsOnID).Error(0)
+}
+
+func (m *MockPlanningRepository) RemoveDependency(ctx context.Context, taskID, dependsOnID int) error {
+	return m.Called(ctx, taskID, dependsOnID).Error(0)
+}
+
+func (m *MockPlanningRepository) GetDependencies(ctx context.Context, taskID int) ([]models.Task, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockPlanningRepository) HasDependencyPath(ctx context.Context, fromID, toID int) (bool, error) {
+	args := m.Called(ctx, fromID, toID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPlanningRepository) GetNextUpCandidates(ctx context.Context, userID int) ([]models.TaskCandidate, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.TaskCandidate), args.Error(1)
+}
+
+func TestScoreTask(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	weights := DefaultScoringWeights()
+	calendar := newBusinessCalendar(models.WorkCalendar{})
+
+	overdue := scoreTask(models.TaskCandidate{
+		Task: models.Task{Priority: models.PriorityUrgent, Due: now.Add(-time.Hour), Time: now.AddDate(0, -2, 0)},
+	}, weights, now, calendar)
+
+	// Все составляющие на максимуме, кроме числа ожидающих задач
+	require.InDelta(t, 4+3+1, overdue.Score, 0.001)
+	require.InDelta(t, 3, overdue.Breakdown["due_date"], 0.001)
+	require.True(t, overdue.Overdue)
+
+	// 1 марта 2024 года - пятница: до понедельника один рабочий день, выходные не считаются
+	monday := scoreTask(models.TaskCandidate{Task: models.Task{Due: now.AddDate(0, 0, 3), Time: now}}, weights, now, calendar)
+	require.InDelta(t, 1.5, monday.Breakdown["due_date"], 0.001)
+	require.False(t, monday.Overdue)
+
+	noDue := scoreTask(models.TaskCandidate{Task: models.Task{Priority: models.PriorityLow, Time: now}}, weights, now, calendar)
+	require.InDelta(t, 1, noDue.Score, 0.001)
+
+	blocked := scoreTask(models.TaskCandidate{
+		Task:         models.Task{Priority: models.PriorityUrgent, Time: now},
+		OpenBlockers: 1,
+	}, weights, now, calendar)
+	require.Less(t, blocked.Score, noDue.Score)
+}
+
+func TestPlanningService_NextUp(t *testing.T) {
+	repo := new(MockPlanningRepository)
+	service := NewPlanningService(repo, new(MockTaskRepository), ScoringWeights{}, nil).(*planningServiceImpl)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	repo.On("GetNextUpCandidates", ctx, 7).Return([]models.TaskCandidate{
+		{Task: models.Task{ID: 1, Priority: models.PriorityLow, Time: now}},
+		{Task: models.Task{ID: 2, Priority: models.PriorityHigh, Time: now, Due: now.Add(24 * time.Hour)}},
+		{Task: models.Task{ID: 3, Priority: models.PriorityUrgent, Time: now}, OpenBlockers: 2},
+		{Task: models.Task{ID: 4, Priority: models.PriorityMedium, Time: now}, Blocks: 3},
+	}, nil)
+
+	result, err := service.NextUp(ctx, 7, 3)
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	ids := []int{result[0].ID, result[1].ID, result[2].ID}
+	require.Equal(t, []int{2, 4, 1}, ids)
+}
+
+func TestPlanningService_AddDependency(t *testing.T) {
+	repo := new(MockPlanningRepository)
+	tasks := new(MockTaskRepository)
+	service := NewPlanningService(repo, tasks, DefaultScoringWeights(), nil)
+
+	ctx := context.Background()
+
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	tasks.On("GetByID", ctx, 2).Return(&models.Task{ID: 2}, nil)
+	tasks.On("GetByID", ctx, 3).Return(nil, sql.ErrNoRows)
+	repo.On("HasDependencyPath", ctx, 2, 1).Return(false, nil)
+	repo.On("HasDependencyPath", ctx, 1, 2).Return(true, nil)
+	repo.On("AddDependency", ctx, 1, 2).Return(nil)
+
+	require.NoError(t, service.AddDependency(ctx, 1, 2))
+	require.ErrorIs(t, service.AddDependency(ctx, 2, 1), ErrDependencyCycle)
+	require.ErrorIs(t, service.AddDependency(ctx, 1, 1), ErrInvalidDependency)
+	require.ErrorIs(t, service.AddDependency(ctx, 1, 3), ErrTaskNotFound)
+
+	repo.AssertNumberOfCalls(t, "AddDependency", 1)
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxProjectNameLength = 100
+	maxCustomTextLength  = 1000
+)
+
+var (
+	ErrProjectNotFound     = errors.New("project not found")
+	ErrCustomFieldNotFound = errors.New("custom field not found")
+	ErrCustomFieldExists   = errors.New("custom field already exists")
+	ErrInvalidCustomField  = errors.New("invalid custom field")
+	ErrNotProjectMember    = errors.New("not a member of the project")
+	ErrNotProjectOwner     = errors.New("only the project owner can manage members")
+)
+
+// customFieldName - имя поля используется как ключ в JSON и в параметрах ?cf.<name>=.
+var customFieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+type ProjectService interface {
+	Create(ctx context.Context, project models.Project) (models.Project, error)
+	GetByID(ctx context.Context, id int) (models.Project, error)
+	GetAll(ctx context.Context) ([]models.Project, error)
+	GetFields(ctx context.Context, projectID int) ([]models.CustomField, error)
+	CreateField(ctx context.Context, field models.CustomField) (models.CustomField, error)
+	DeleteField(ctx context.Context, projectID, id int) error
+	ValidateCustomFields(ctx context.Context, projectID int, values models.CustomValues) (models.CustomValues, error)
+	CheckMember(ctx context.Context, projectID, userID int) error
+	AddMembers(ctx context.Context, projectID int, userIDs []int) error
+	RemoveMember(ctx context.Context, projectID, userID int) error
+}
+
+type projectServiceImpl struct {
+	repo  repositories.ProjectRepository
+	users repositories.UserRepository
+}
+
+func NewProjectService(repo repositories.ProjectRepository, users repositories.UserRepository) ProjectService {
+	return &projectServiceImpl{repo: repo, users: users}
+}
+
+func (s *projectServiceImpl) Create(ctx context.Context, project models.Project) (models.Project, error) {
+	project.Name = strings.TrimSpace(project.Name)
+
+	if project.Name == "" {
+		return models.Project{}, errors.New("project name is required")
+	}
+
+	if utf8.RuneCountInString(project.Name) > maxProjectNameLength {
+		return models.Project{}, errors.New("project name is too long")
+	}
+
+	created, err := s.repo.Create(ctx, &project)
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return *created, nil
+}
+
+func (s *projectServiceImpl) GetByID(ctx context.Context, id int) (models.Project, error) {
+	project, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Project{}, ErrProjectNotFound
+	}
+
+	if err != nil {
+		return models.Project{}, err
+	}
+
+	return *project, nil
+}
+
+func (s *projectServiceImpl) GetAll(ctx context.Context) ([]models.Project, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *projectServiceImpl) GetFields(ctx context.Context, projectID int) ([]models.CustomField, error) {
+	if _, err := s.GetByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetFields(ctx, projectID)
+}
+
+func (s *projectServiceImpl) CreateField(ctx context.Context, field models.CustomField) (models.CustomField, error) {
+	if err := validateFieldDefinition(&field); err != nil {
+		return models.CustomField{}, err
+	}
+
+	if _, err := s.GetByID(ctx, field.ProjectID); err != nil {
+		return models.CustomField{}, err
+	}
+
+	created, err := s.repo.CreateField(ctx, &field)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return models.CustomField{}, ErrCustomFieldExists
+	}
+
+	if err != nil {
+		return models.CustomField{}, err
+	}
+
+	return *created, nil
+}
+
+func (s *projectServiceImpl) DeleteField(ctx context.Context, projectID, id int) error {
+	err := s.repo.DeleteField(ctx, projectID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCustomFieldNotFound
+	}
+
+	return err
+}
+
+// CheckMember возвращает ErrNotProjectMember, если пользователь не владеет проектом и не
+// добавлен в его участники. Неизвестный пользователь (ID 0) участником не бывает.
+func (s *projectServiceImpl) CheckMember(ctx context.Context, projectID, userID int) error {
+	if userID == 0 {
+		return ErrNotProjectMember
+	}
+
+	member, err := s.repo.IsMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !member {
+		return ErrNotProjectMember
+	}
+
+	return nil
+}
+
+// AddMembers добавляет участников проекта. Управлять участниками может только владелец,
+// от имени которого выполняется запрос.
+func (s *projectServiceImpl) AddMembers(ctx context.Context, projectID int, userIDs []int) error {
+	if err := s.checkOwner(ctx, projectID); err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if _, err := s.users.GetByID(ctx, userID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("%w: %d", ErrUserNotFound, userID)
+			}
+
+			return err
+		}
+	}
+
+	for _, userID := range userIDs {
+		if err := s.repo.AddMember(ctx, projectID, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *projectServiceImpl) RemoveMember(ctx context.Context, projectID, userID int) error {
+	if err := s.checkOwner(ctx, projectID); err != nil {
+		return err
+	}
+
+	err := s.repo.RemoveMember(ctx, projectID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+
+	return err
+}
+
+// checkOwner проверяет, что запрос выполняется от имени владельца проекта.
+func (s *projectServiceImpl) checkOwner(ctx context.Context, projectID int) error {
+	project, err := s.GetByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := UserIDFromContext(ctx)
+	if userID == 0 || project.OwnerID != userID {
+		return ErrNotProjectOwner
+	}
+
+	return nil
+}
+
+// ValidateCustomFields проверяет значения по определениям полей проекта и приводит их
+// к виду хранения: число - float64, дата - "2006-01-02", пользователь - ID.
+// Значение null удаляет поле.
+func (s *projectServiceImpl) ValidateCustomFields(
+	ctx context.Context,
+	projectID int,
+	values models.CustomValues,
+) (models.CustomValues, error) {
+	if projectID == 0 {
+		if len(values) > 0 {
+			return nil, fmt.Errorf("%w: custom fields require a project", ErrInvalidCustomField)
+		}
+
+		return values, nil
+	}
+
+	fields, err := s.GetFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		definitions[field.Name] = field
+	}
+
+	normalized := make(models.CustomValues, len(values))
+
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+
+		field, ok := definitions[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidCustomField, name)
+		}
+
+		converted, err := s.convertValue(ctx, field, value)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized[name] = converted
+	}
+
+	for _, field := range fields {
+		if _, ok := normalized[field.Name]; field.Required && !ok {
+			return nil, fmt.Errorf("%w: field %q is required", ErrInvalidCustomField, field.Name)
+		}
+	}
+
+	return normalized, nil
+}
+
+func (s *projectServiceImpl) convertValue(ctx context.Context, field models.CustomField, value interface{}) (interface{}, error) {
+	invalid := func(expected string) error {
+		return fmt.Errorf("%w: field %q must be %s", ErrInvalidCustomField, field.Name, expected)
+	}
+
+	switch field.Type {
+	case models.FieldTypeText:
+		text, ok := value.(string)
+		if !ok || utf8.RuneCountInString(text) > maxCustomTextLength {
+			return nil, invalid(fmt.Sprintf("a string of at most %d characters", maxCustomTextLength))
+		}
+
+		return text, nil
+
+	case models.FieldTypeNumber:
+		number, ok := toFloat(value)
+		if !ok {
+			return nil, invalid("a number")
+		}
+
+		return number, nil
+
+	case models.FieldTypeDate:
+		text, ok := value.(string)
+		if !ok {
+			return nil, invalid("a date (YYYY-MM-DD)")
+		}
+
+		date, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			if date, err = time.Parse(time.RFC3339, text); err != nil {
+				return nil, invalid("a date (YYYY-MM-DD)")
+			}
+		}
+
+		return date.Format(time.DateOnly), nil
+
+	case models.FieldTypeEnum:
+		text, ok := value.(string)
+		if !ok || !containsString(field.Options, text) {
+			return nil, invalid("one of: " + strings.Join(field.Options, ", "))
+		}
+
+		return text, nil
+
+	case models.FieldTypeUser:
+		number, ok := toFloat(value)
+		if !ok || number <= 0 || number != math.Trunc(number) {
+			return nil, invalid("a user ID")
+		}
+
+		userID := int(number)
+
+		if _, err := s.users.GetByID(ctx, userID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: field %q refers to unknown user %d", ErrInvalidCustomField, field.Name, userID)
+			}
+
+			return nil, err
+		}
+
+		return userID, nil
+	}
+
+	return nil, invalid("of a known type")
+}
+
+// validateFieldDefinition проверяет имя, тип и варианты значений нового поля.
+func validateFieldDefinition(field *models.CustomField) error {
+	field.Name = strings.TrimSpace(field.Name)
+
+	if !customFieldName.MatchString(field.Name) {
+		return fmt.Errorf("%w: name must match %s", ErrInvalidCustomField, customFieldName.String())
+	}
+
+	if !containsString(models.FieldTypes, field.Type) {
+		return fmt.Errorf("%w: type must be one of: %s", ErrInvalidCustomField, strings.Join(models.FieldTypes, ", "))
+	}
+
+	if field.Type != models.FieldTypeEnum {
+		if len(field.Options) > 0 {
+			return fmt.Errorf("%w: options are allowed only for enum fields", ErrInvalidCustomField)
+		}
+
+		return nil
+	}
+
+	if len(field.Options) == 0 {
+		return fmt.Errorf("%w: enum field needs at least one option", ErrInvalidCustomField)
+	}
+
+	seen := make(map[string]struct{}, len(field.Options))
+
+	for i, option := range field.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return fmt.Errorf("%w: enum options cannot be empty", ErrInvalidCustomField)
+		}
+
+		if _, ok := seen[option]; ok {
+			return fmt.Errorf("%w: duplicate enum option %q", ErrInvalidCustomField, option)
+		}
+
+		seen[option] = struct{}{}
+		field.Options[i] = option
+	}
+
+	return nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	}
+
+	return 0, false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
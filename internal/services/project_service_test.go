@@ -0,0 +1,200 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectRepository реализует методы ProjectRepository для тестов.
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) Create(ctx context.Context, project *models.Project) (*models.Project, error) {
+	args := m.Called(ctx, project)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetByID(ctx context.Context, id int) (*models.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetAll(ctx context.Context) ([]models.Project, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) IsMember(ctx context.Context, projectID, userID int) (bool, error) {
+	args := m.Called(ctx, projectID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProjectRepository) AddMember(ctx context.Context, projectID, userID int) error {
+	return m.Called(ctx, projectID, userID).Error(0)
+}
+
+func (m *MockProjectRepository) RemoveMember(ctx context.Context, projectID, userID int) error {
+	return m.Called(ctx, projectID, userID).Error(0)
+}
+
+func (m *MockProjectRepository) CreateField(ctx context.Context, field *models.CustomField) (*models.CustomField, error) {
+	args := m.Called(ctx, field)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomField), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetFields(ctx context.Context, projectID int) ([]models.CustomField, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]models.CustomField), args.Error(1)
+}
+
+func (m *MockProjectRepository) DeleteField(ctx context.Context, projectID, id int) error {
+	return m.Called(ctx, projectID, id).Error(0)
+}
+
+// testProjectFields - набор полей проекта 1 на все поддерживаемые типы.
+var testProjectFields = []models.CustomField{
+	{ID: 1, ProjectID: 1, Name: "client", Type: models.FieldTypeText, Required: true},
+	{ID: 2, ProjectID: 1, Name: "points", Type: models.FieldTypeNumber},
+	{ID: 3, ProjectID: 1, Name: "release", Type: models.FieldTypeDate},
+	{ID: 4, ProjectID: 1, Name: "stage", Type: models.FieldTypeEnum, Options: []string{"design", "build"}},
+	{ID: 5, ProjectID: 1, Name: "reviewer", Type: models.FieldTypeUser},
+}
+
+// testProjectMember - участник проекта 1 в newTestProjectService.
+const testProjectMember = 7
+
+func newTestProjectService() (ProjectService, *MockProjectRepository, *MockUserRepository) {
+	repo := new(MockProjectRepository)
+	users := new(MockUserRepository)
+
+	repo.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, Name: "Site"}, nil).Maybe()
+	repo.On("GetByID", mock.Anything, 2).Return(nil, sql.ErrNoRows).Maybe()
+	repo.On("GetFields", mock.Anything, 1).Return(testProjectFields, nil).Maybe()
+	repo.On("IsMember", mock.Anything, 1, testProjectMember).Return(true, nil).Maybe()
+
+	return NewProjectService(repo, users), repo, users
+}
+
+func TestProjectService_CreateField(t *testing.T) {
+	service, repo, _ := newTestProjectService()
+	ctx := context.Background()
+
+	invalid := []models.CustomField{
+		{ProjectID: 1, Name: "Has Space", Type: models.FieldTypeText},
+		{ProjectID: 1, Name: "budget", Type: "money"},
+		{ProjectID: 1, Name: "stage", Type: models.FieldTypeEnum},
+		{ProjectID: 1, Name: "stage", Type: models.FieldTypeEnum, Options: []string{"a", "a"}},
+		{ProjectID: 1, Name: "points", Type: models.FieldTypeNumber, Options: []string{"1"}},
+	}
+
+	for _, field := range invalid {
+		_, err := service.CreateField(ctx, field)
+		require.ErrorIs(t, err, ErrInvalidCustomField, field.Name)
+	}
+
+	_, err := service.CreateField(ctx, models.CustomField{ProjectID: 2, Name: "client", Type: models.FieldTypeText})
+	require.ErrorIs(t, err, ErrProjectNotFound)
+
+	repo.On("CreateField", ctx, mock.MatchedBy(func(f *models.CustomField) bool { return f.Name == "stage" })).
+		Return(&models.CustomField{ID: 4, ProjectID: 1, Name: "stage", Type: models.FieldTypeEnum}, nil)
+	repo.On("CreateField", ctx, mock.MatchedBy(func(f *models.CustomField) bool { return f.Name == "client" })).
+		Return(nil, repositories.ErrDuplicate)
+
+	created, err := service.CreateField(ctx, models.CustomField{
+		ProjectID: 1, Name: "stage", Type: models.FieldTypeEnum, Options: []string{" design ", "build"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, created.ID)
+
+	_, err = service.CreateField(ctx, models.CustomField{ProjectID: 1, Name: "client", Type: models.FieldTypeText})
+	require.ErrorIs(t, err, ErrCustomFieldExists)
+}
+
+func TestProjectService_ValidateCustomFields(t *testing.T) {
+	service, _, users := newTestProjectService()
+	ctx := context.Background()
+
+	users.On("GetByID", ctx, 7).Return(&models.User{ID: 7}, nil)
+	users.On("GetByID", ctx, 8).Return(nil, sql.ErrNoRows)
+
+	values, err := service.ValidateCustomFields(ctx, 1, models.CustomValues{
+		"client":   "Acme",
+		"points":   float64(5),
+		"release":  "2024-05-01T10:00:00Z",
+		"stage":    "build",
+		"reviewer": float64(7),
+		"points_x": nil,
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.CustomValues{
+		"client":   "Acme",
+		"points":   float64(5),
+		"release":  "2024-05-01",
+		"stage":    "build",
+		"reviewer": 7,
+	}, values)
+
+	invalid := []models.CustomValues{
+		{"client": "Acme", "points": "five"},
+		{"client": "Acme", "release": "May 1st"},
+		{"client": "Acme", "stage": "done"},
+		{"client": "Acme", "reviewer": 1.5},
+		{"client": "Acme", "reviewer": float64(8)},
+		{"client": "Acme", "budget": float64(1)},
+		{"points": float64(1)},
+	}
+
+	for _, value := range invalid {
+		_, err := service.ValidateCustomFields(ctx, 1, value)
+		require.ErrorIs(t, err, ErrInvalidCustomField, value)
+	}
+
+	_, err = service.ValidateCustomFields(ctx, 0, models.CustomValues{"client": "Acme"})
+	require.ErrorIs(t, err, ErrInvalidCustomField)
+
+	_, err = service.ValidateCustomFields(ctx, 2, nil)
+	require.ErrorIs(t, err, ErrProjectNotFound)
+}
+
+func TestProjectService_Members(t *testing.T) {
+	service, repo, users := newTestProjectService()
+	ctx := context.Background()
+	owner := WithUserID(ctx, 7)
+
+	repo.On("GetByID", mock.Anything, 3).Return(&models.Project{ID: 3, Name: "Shop", OwnerID: 7}, nil)
+	users.On("GetByID", mock.Anything, 8).Return(&models.User{ID: 8}, nil)
+	users.On("GetByID", mock.Anything, 99).Return(nil, sql.ErrNoRows)
+	repo.On("AddMember", mock.Anything, 3, 8).Return(nil)
+	repo.On("RemoveMember", mock.Anything, 3, 9).Return(sql.ErrNoRows)
+
+	// Участников добавляет только владелец
+	require.ErrorIs(t, service.AddMembers(WithUserID(ctx, 8), 3, []int{8}), ErrNotProjectOwner)
+	require.ErrorIs(t, service.AddMembers(ctx, 3, []int{8}), ErrNotProjectOwner)
+	require.ErrorIs(t, service.AddMembers(owner, 2, []int{8}), ErrProjectNotFound)
+	require.ErrorIs(t, service.AddMembers(owner, 3, []int{8, 99}), ErrUserNotFound)
+	repo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything)
+
+	require.NoError(t, service.AddMembers(owner, 3, []int{8}))
+	require.ErrorIs(t, service.RemoveMember(owner, 3, 9), ErrUserNotFound)
+
+	require.NoError(t, service.CheckMember(ctx, 1, testProjectMember))
+	require.ErrorIs(t, service.CheckMember(ctx, 1, 0), ErrNotProjectMember)
+
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,570 @@
+package services
+
+import (
+	"WebTasks/internal/metrics"
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxQuickAddLength = 500
+
+// Время суток по умолчанию, минуты от начала дня.
+const (
+	quickAddEndOfDay  = 23*60 + 59 // Срок, заданный только датой
+	quickAddMorning   = 9 * 60
+	quickAddAfternoon = 14 * 60
+	quickAddEvening   = 19 * 60
+	quickAddNoon      = 12 * 60
+)
+
+var ErrInvalidQuickAdd = errors.New("invalid quick add")
+
+// QuickAdd разбирает строку вида "Позвонить клиенту завтра в 15:00 #sales !high" и создаёт
+// задачу с тегами от имени request.UserID. Даты понимаются в поясе запроса, а без него -
+// в поясе пользователя; рабочие дни берутся из его календаря. При DryRun задача только проверяется.
+func (s *taskServiceImpl) QuickAdd(ctx context.Context, request models.QuickAddRequest) (models.QuickAddResult, error) {
+	text := strings.TrimSpace(request.Text)
+	if text == "" {
+		return models.QuickAddResult{}, fmt.Errorf("%w: text is required", ErrInvalidQuickAdd)
+	}
+
+	if utf8.RuneCountInString(text) > maxQuickAddLength {
+		return models.QuickAddResult{}, fmt.Errorf("%w: text must not exceed %d characters", ErrInvalidQuickAdd, maxQuickAddLength)
+	}
+
+	// Пояс запроса важнее сохранённого в профиле
+	calendar, err := userCalendar(ctx, s.calendars, request.UserID)
+	if err != nil {
+		return models.QuickAddResult{}, err
+	}
+
+	if request.Timezone != "" {
+		if calendar.location, err = loadTimezone(request.Timezone); err != nil {
+			return models.QuickAddResult{}, fmt.Errorf("%w: %v", ErrInvalidQuickAdd, err)
+		}
+	}
+
+	now := s.now()
+	parsed := parseQuickAdd(text, now.In(calendar.location), calendar)
+
+	task := models.Task{
+		Name:     parsed.Name,
+		Status:   "Pending",
+		Time:     now,
+		UserID:   request.UserID,
+		Priority: parsed.Priority,
+	}
+
+	if parsed.Due != nil {
+		task.Due = parsed.Due.UTC()
+	}
+
+	tags, err := NormalizeTags(parsed.Tags)
+	if err == nil {
+		task, err = s.prepareCreate(ctx, task)
+	}
+
+	if err != nil {
+		return models.QuickAddResult{}, fmt.Errorf("%w: %v", ErrInvalidQuickAdd, err)
+	}
+
+	if err := s.newTaskQuota().reserve(ctx, task); err != nil {
+		return models.QuickAddResult{}, err
+	}
+
+	result := models.QuickAddResult{Task: task, Tags: tags, Parse: parsed}
+	if request.DryRun {
+		return result, nil
+	}
+
+	created, err := s.repo.ApplyBulk(ctx, []models.TaskChange{{Op: models.BulkCreate, Task: &task, Tags: tags}})
+	if err != nil {
+		var bulkErr *repositories.BulkError
+		if errors.As(err, &bulkErr) {
+			return models.QuickAddResult{}, fmt.Errorf("%w: %s", ErrInvalidQuickAdd, bulkErrorMessage(bulkErr.Err))
+		}
+
+		return models.QuickAddResult{}, err
+	}
+
+	result.Task = *created[0]
+
+	metrics.TasksCreated(metrics.SourceQuickAdd, 1)
+
+	return result, nil
+}
+
+var (
+	quickAddClock   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	quickAddDotDate = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{2}|\d{4}))?$`)
+	quickAddDay     = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	quickAddYear    = regexp.MustCompile(`^\d{4}$`)
+)
+
+// quickAddPrepositions стоят перед датой или временем и входят в распознанный фрагмент.
+var quickAddPrepositions = map[string]bool{
+	"on": true, "at": true, "by": true, "в": true, "во": true, "на": true, "к": true,
+}
+
+var quickAddNext = map[string]bool{
+	"next": true, "следующий": true, "следующую": true, "следующее": true, "следующей": true, "след": true,
+}
+
+var quickAddWeekdays = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday, "понедельник": time.Monday, "пн": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "вторник": time.Tuesday, "вт": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "среда": time.Wednesday, "среду": time.Wednesday, "ср": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday, "четверг": time.Thursday, "чт": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "пятница": time.Friday, "пятницу": time.Friday, "пт": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "суббота": time.Saturday, "субботу": time.Saturday, "сб": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday, "воскресенье": time.Sunday, "вс": time.Sunday,
+}
+
+var quickAddMonths = map[string]time.Month{
+	"january": time.January, "jan": time.January, "января": time.January, "янв": time.January,
+	"february": time.February, "feb": time.February, "февраля": time.February, "фев": time.February,
+	"march": time.March, "mar": time.March, "марта": time.March, "мар": time.March,
+	"april": time.April, "apr": time.April, "апреля": time.April, "апр": time.April,
+	"may": time.May, "мая": time.May,
+	"june": time.June, "jun": time.June, "июня": time.June, "июн": time.June,
+	"july": time.July, "jul": time.July, "июля": time.July, "июл": time.July,
+	"august": time.August, "aug": time.August, "августа": time.August, "авг": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September, "сентября": time.September, "сен": time.September,
+	"october": time.October, "oct": time.October, "октября": time.October, "окт": time.October,
+	"november": time.November, "nov": time.November, "ноября": time.November, "ноя": time.November,
+	"december": time.December, "dec": time.December, "декабря": time.December, "дек": time.December,
+}
+
+// quickAddDays - дни относительно сегодняшнего.
+var quickAddDays = map[string]int{
+	"today": 0, "сегодня": 0,
+	"tomorrow": 1, "tmr": 1, "завтра": 1,
+	"послезавтра": 2,
+}
+
+// quickAddDayParts - время суток словом; tonight заодно означает сегодня.
+var quickAddDayParts = map[string]int{
+	"morning": quickAddMorning, "утром": quickAddMorning,
+	"afternoon": quickAddAfternoon, "днём": quickAddAfternoon, "днем": quickAddAfternoon,
+	"evening": quickAddEvening, "tonight": quickAddEvening, "вечером": quickAddEvening,
+	"noon": quickAddNoon, "полдень": quickAddNoon,
+}
+
+// quickAddMeridiems - уточнения после часа: "3 pm", "в 7 вечера".
+var quickAddMeridiems = map[string]string{
+	"am": "am", "утра": "am", "ночи": "am",
+	"pm": "pm", "дня": "pm", "вечера": "pm",
+}
+
+// quickAddBusiness - слова перед единицей "день", делающие интервал рабочим: "in 3 business days".
+var quickAddBusiness = map[string]bool{
+	"business": true, "working": true, "рабочих": true, "рабочий": true, "рабочие": true, "рабочего": true,
+}
+
+// quickAddUnits - единицы интервала "in 3 days" / "через 3 дня".
+var quickAddUnits = map[string]string{
+	"minute": "minute", "minutes": "minute", "min": "minute", "mins": "minute",
+	"минуту": "minute", "минуты": "minute", "минут": "minute", "мин": "minute",
+	"hour": "hour", "hours": "hour", "h": "hour", "hr": "hour", "hrs": "hour",
+	"час": "hour", "часа": "hour", "часов": "hour",
+	"day": "day", "days": "day", "день": "day", "дня": "day", "дней": "day",
+	"workday": "workday", "workdays": "workday",
+	"week": "week", "weeks": "week", "неделю": "week", "недели": "week", "недель": "week",
+	"month": "month", "months": "month", "месяц": "month", "месяца": "month", "месяцев": "month",
+}
+
+var quickAddPriorities = map[string]string{
+	"urgent": models.PriorityUrgent, "срочно": models.PriorityUrgent, "1": models.PriorityUrgent,
+	"high": models.PriorityHigh, "высокий": models.PriorityHigh, "важно": models.PriorityHigh, "2": models.PriorityHigh,
+	"medium": models.PriorityMedium, "средний": models.PriorityMedium, "3": models.PriorityMedium,
+	"low": models.PriorityLow, "низкий": models.PriorityLow, "4": models.PriorityLow,
+}
+
+// quickAddParser разбирает строку по словам. Дата и время задаются не более одного
+// раза: повторные упоминания остаются в названии задачи.
+type quickAddParser struct {
+	now      time.Time // Текущее время в часовом поясе пользователя
+	calendar *businessCalendar
+	words    []string
+	keys     []string // Слова в нижнем регистре без знаков препинания по краям
+	date     *time.Time
+	clock    int // Минуты от начала дня, -1 - не задано
+}
+
+// parseQuickAdd выделяет из текста срок, теги (#tag) и приоритет (!high, !срочно, !1).
+// День недели без "next" означает ближайший такой день, считая сегодняшний, а с "next"
+// ("в следующую пятницу") - день следующей недели. Срок без времени ставится на конец дня,
+// время без даты - на ближайший такой момент. Рабочие дни ("in 3 business days",
+// "через 2 рабочих дня") отсчитываются по calendar.
+func parseQuickAdd(text string, now time.Time, calendar *businessCalendar) models.QuickAddParse {
+	p := quickAddParser{now: now, calendar: calendar, words: strings.Fields(text), clock: -1}
+	p.keys = make([]string, len(p.words))
+
+	for i, word := range p.words {
+		p.keys[i] = strings.Trim(strings.ToLower(word), ",.;!?()\"'«»")
+	}
+
+	parsed := models.QuickAddParse{Tokens: []models.QuickAddToken{}}
+	name := make([]string, 0, len(p.words))
+	dueEnd := -1
+
+	for i := 0; i < len(p.words); {
+		kind, n := p.match(i, &parsed)
+		if n == 0 {
+			name = append(name, p.words[i])
+			i++
+
+			continue
+		}
+
+		fragment := strings.Join(p.words[i:i+n], " ")
+
+		// Соседние фрагменты срока ("завтра" и "в 15:00") показываются одним
+		if last := len(parsed.Tokens) - 1; kind == models.QuickAddDue && dueEnd == i && parsed.Tokens[last].Kind == kind {
+			parsed.Tokens[last].Text += " " + fragment
+		} else {
+			parsed.Tokens = append(parsed.Tokens, models.QuickAddToken{Kind: kind, Text: fragment})
+		}
+
+		i += n
+
+		if kind == models.QuickAddDue {
+			dueEnd = i
+		}
+	}
+
+	parsed.Name = strings.Join(name, " ")
+	parsed.Due = p.due()
+	parsed.Timezone = now.Location().String()
+
+	return parsed
+}
+
+// match распознаёт фрагмент, начинающийся со слова i, и возвращает его вид и длину в словах.
+func (p *quickAddParser) match(i int, parsed *models.QuickAddParse) (string, int) {
+	word := p.words[i]
+
+	if tag, ok := strings.CutPrefix(word, "#"); ok {
+		if tag = strings.TrimRight(tag, ",.;:!?"); tag != "" {
+			parsed.Tags = append(parsed.Tags, tag)
+			return models.QuickAddTag, 1
+		}
+	}
+
+	if value, ok := strings.CutPrefix(word, "!"); ok {
+		if priority, ok := quickAddPriorities[strings.ToLower(strings.TrimRight(value, ",.;:?"))]; ok {
+			parsed.Priority = priority
+			return models.QuickAddPriority, 1
+		}
+	}
+
+	if n := p.matchWhen(i, false); n > 0 {
+		return models.QuickAddDue, n
+	}
+
+	if quickAddPrepositions[p.keys[i]] && i+1 < len(p.keys) {
+		if n := p.matchWhen(i+1, true); n > 0 {
+			return models.QuickAddDue, n + 1
+		}
+	}
+
+	return "", 0
+}
+
+// matchWhen распознаёт дату, время или интервал. Голое число считается часом только после
+// предлога ("в 15", "at 9") или с уточнением ("3 pm", "7 вечера").
+func (p *quickAddParser) matchWhen(i int, afterPreposition bool) int {
+	if p.date == nil {
+		if date, n := p.matchDate(i); n > 0 {
+			p.date = &date
+			return n
+		}
+	}
+
+	if p.clock < 0 {
+		if clock, ok := quickAddDayParts[p.keys[i]]; ok {
+			p.clock = clock
+
+			if p.keys[i] == "tonight" && p.date == nil {
+				today := p.today()
+				p.date = &today
+			}
+
+			return 1
+		}
+
+		if clock, n := p.matchClock(i, afterPreposition); n > 0 {
+			p.clock = clock
+			return n
+		}
+	}
+
+	return p.matchInterval(i)
+}
+
+func (p *quickAddParser) today() time.Time {
+	return time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+}
+
+func (p *quickAddParser) key(i int) string {
+	if i < len(p.keys) {
+		return p.keys[i]
+	}
+
+	return ""
+}
+
+func (p *quickAddParser) matchDate(i int) (time.Time, int) {
+	today := p.today()
+	key := p.keys[i]
+
+	if days, ok := quickAddDays[key]; ok {
+		return today.AddDate(0, 0, days), 1
+	}
+
+	if key == "day" && p.key(i+1) == "after" && p.key(i+2) == "tomorrow" {
+		return today.AddDate(0, 0, 2), 3
+	}
+
+	if weekday, ok := quickAddWeekdays[key]; ok {
+		return today.AddDate(0, 0, (int(weekday)-int(today.Weekday())+7)%7), 1
+	}
+
+	if quickAddNext[key] {
+		// Понедельник следующей недели
+		monday := today.AddDate(0, 0, 7-mondayIndex(today.Weekday()))
+
+		if weekday, ok := quickAddWeekdays[p.key(i+1)]; ok {
+			return monday.AddDate(0, 0, mondayIndex(weekday)), 2
+		}
+
+		if next := p.key(i + 1); next == "week" || next == "неделе" {
+			return monday, 2
+		}
+	}
+
+	if date, err := time.ParseInLocation(time.DateOnly, key, today.Location()); err == nil {
+		return date, 1
+	}
+
+	if match := quickAddDotDate.FindStringSubmatch(key); match != nil {
+		day, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+
+		year := -1
+		if match[3] != "" {
+			year, _ = strconv.Atoi(match[3])
+			if year < 100 {
+				year += 2000
+			}
+		}
+
+		if date, ok := p.calendarDate(year, time.Month(month), day); ok {
+			return date, 1
+		}
+	}
+
+	// "5 января", "5th of may", "jan 5" с необязательным годом
+	if match := quickAddDay.FindStringSubmatch(key); match != nil {
+		day, _ := strconv.Atoi(match[1])
+
+		n := 1
+		if p.key(i+n) == "of" {
+			n++
+		}
+
+		if month, ok := quickAddMonths[p.key(i+n)]; ok {
+			return p.datedMonth(i+n+1, month, day, n+1)
+		}
+	}
+
+	if month, ok := quickAddMonths[key]; ok {
+		if match := quickAddDay.FindStringSubmatch(p.key(i + 1)); match != nil {
+			day, _ := strconv.Atoi(match[1])
+			return p.datedMonth(i+2, month, day, 2)
+		}
+	}
+
+	return time.Time{}, 0
+}
+
+// datedMonth дополняет дату годом из слова i, если он указан.
+func (p *quickAddParser) datedMonth(i int, month time.Month, day, n int) (time.Time, int) {
+	year := -1
+	if quickAddYear.MatchString(p.key(i)) {
+		year, _ = strconv.Atoi(p.key(i))
+		n++
+	}
+
+	if date, ok := p.calendarDate(year, month, day); ok {
+		return date, n
+	}
+
+	return time.Time{}, 0
+}
+
+// calendarDate проверяет дату; без года (year < 0) берётся ближайшая такая дата не раньше сегодня.
+func (p *quickAddParser) calendarDate(year int, month time.Month, day int) (time.Time, bool) {
+	today := p.today()
+
+	explicit := year >= 0
+	if !explicit {
+		year = today.Year()
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Day() != day || date.Month() != month {
+		return time.Time{}, false
+	}
+
+	if !explicit && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+
+	return date, true
+}
+
+func (p *quickAddParser) matchClock(i int, afterPreposition bool) (int, int) {
+	match := quickAddClock.FindStringSubmatch(p.keys[i])
+	if match == nil {
+		return 0, 0
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	meridiem := match[3]
+
+	n := 1
+	if value, ok := quickAddMeridiems[p.key(i+1)]; ok && meridiem == "" {
+		meridiem = value
+		n++
+	}
+
+	if match[2] == "" && meridiem == "" && !afterPreposition {
+		return 0, 0
+	}
+
+	if minute > 59 || hour > 23 || (meridiem != "" && (hour == 0 || hour > 12)) {
+		return 0, 0
+	}
+
+	switch {
+	case meridiem == "pm" && hour < 12:
+		hour += 12
+	case meridiem == "am" && hour == 12:
+		hour = 0
+	}
+
+	return hour*60 + minute, n
+}
+
+// matchInterval распознаёт "in 3 days", "in an hour", "через 2 часа", "через неделю",
+// "in 3 business days", "через 5 рабочих дней".
+func (p *quickAddParser) matchInterval(i int) int {
+	if key := p.keys[i]; key != "in" && key != "через" {
+		return 0
+	}
+
+	amount, n := 1, 1
+
+	switch count := p.key(i + 1); count {
+	case "a", "an":
+		n++
+	default:
+		if value, err := strconv.Atoi(count); err == nil && value > 0 && value <= 1000 {
+			amount = value
+			n++
+		}
+	}
+
+	business := quickAddBusiness[p.key(i+n)]
+	if business {
+		n++
+	}
+
+	unit, ok := quickAddUnits[p.key(i+n)]
+	if !ok || (business && unit != "day") {
+		return 0
+	}
+
+	if business {
+		unit = "workday"
+	}
+
+	if unit == "minute" || unit == "hour" {
+		if p.date != nil || p.clock >= 0 {
+			return 0
+		}
+
+		step := time.Minute
+		if unit == "hour" {
+			step = time.Hour
+		}
+
+		at := p.now.Add(time.Duration(amount) * step)
+		date := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+
+		p.date = &date
+		p.clock = at.Hour()*60 + at.Minute()
+
+		return n + 1
+	}
+
+	if p.date != nil {
+		return 0
+	}
+
+	date := p.today()
+
+	switch unit {
+	case "day":
+		date = date.AddDate(0, 0, amount)
+	case "workday":
+		date = p.calendar.addBusinessDays(date, amount)
+	case "week":
+		date = date.AddDate(0, 0, 7*amount)
+	case "month":
+		date = date.AddDate(0, amount, 0)
+	}
+
+	p.date = &date
+
+	return n + 1
+}
+
+// due собирает срок из даты и времени суток.
+func (p *quickAddParser) due() *time.Time {
+	if p.date == nil && p.clock < 0 {
+		return nil
+	}
+
+	date, clock := p.today(), p.clock
+
+	switch {
+	case p.date != nil:
+		date = *p.date
+
+		if clock < 0 {
+			clock = quickAddEndOfDay
+		}
+	case date.Add(time.Duration(clock) * time.Minute).Before(p.now):
+		date = date.AddDate(0, 0, 1)
+	}
+
+	due := time.Date(date.Year(), date.Month(), date.Day(), clock/60, clock%60, 0, 0, date.Location())
+
+	return &due
+}
+
+// mondayIndex - номер дня в неделе, начинающейся с понедельника.
+func mondayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
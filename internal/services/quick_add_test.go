@@ -0,0 +1,131 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	// Среда, 2 января 2030 года
+	now := time.Date(2030, 1, 2, 10, 0, 0, 0, moscow)
+	at := func(month time.Month, day, hour, minute int) *time.Time {
+		due := time.Date(2030, month, day, hour, minute, 0, 0, moscow)
+		return &due
+	}
+
+	cases := []struct {
+		text string
+		name string
+		due  *time.Time
+	}{
+		{"deploy api next friday", "deploy api", at(time.January, 11, 23, 59)},
+		{"deploy api friday", "deploy api", at(time.January, 4, 23, 59)},
+		{"в следующую пятницу сдать отчёт", "сдать отчёт", at(time.January, 11, 23, 59)},
+		{"на следующей неделе разобрать почту", "разобрать почту", at(time.January, 7, 23, 59)},
+		{"standup at 9am", "standup", at(time.January, 3, 9, 0)},
+		{"через 2 часа проверить сборку", "проверить сборку", at(time.January, 2, 12, 0)},
+		{"review PR in 3 days", "review PR", at(time.January, 5, 23, 59)},
+		{"Встреча 15.02 в 7 вечера", "Встреча", at(time.February, 15, 19, 0)},
+		{"lunch on march 1st at noon", "lunch", at(time.March, 1, 12, 0)},
+		{"завтра вечером завтра", "завтра", at(time.January, 3, 19, 0)},
+		{"Buy 2 apples в офис", "Buy 2 apples в офис", nil},
+	}
+
+	for _, tc := range cases {
+		parsed := parseQuickAdd(tc.text, now, newBusinessCalendar(models.WorkCalendar{}))
+		assert.Equal(t, tc.name, parsed.Name, tc.text)
+		assert.Equal(t, tc.due, parsed.Due, tc.text)
+	}
+
+	// Рабочие дни считаются по календарю: 7 января - праздник
+	holidays := newBusinessCalendar(models.WorkCalendar{
+		Timezone: "Europe/Moscow",
+		Holidays: []models.Holiday{{Date: "2030-01-07"}},
+	})
+
+	parsed := parseQuickAdd("ship release in 3 business days", now, holidays)
+	assert.Equal(t, at(time.January, 8, 23, 59), parsed.Due)
+
+	parsed = parseQuickAdd("через 1 рабочий день созвон в 10:00", now, holidays)
+	assert.Equal(t, "созвон", parsed.Name)
+	assert.Equal(t, at(time.January, 3, 10, 0), parsed.Due)
+
+	// Прошедшая дата без года переносится на следующий год
+	parsed = parseQuickAdd("Отпуск 1 января", now, newBusinessCalendar(models.WorkCalendar{}))
+	require.NotNil(t, parsed.Due)
+	assert.Equal(t, 2031, parsed.Due.Year())
+
+	parsed = parseQuickAdd("Позвонить клиенту завтра в 15:00 #sales !high", now, newBusinessCalendar(models.WorkCalendar{}))
+	assert.Equal(t, models.QuickAddParse{
+		Name:     "Позвонить клиенту",
+		Due:      at(time.January, 3, 15, 0),
+		Tags:     []string{"sales"},
+		Priority: models.PriorityHigh,
+		Timezone: "Europe/Moscow",
+		Tokens: []models.QuickAddToken{
+			{Kind: models.QuickAddDue, Text: "завтра в 15:00"},
+			{Kind: models.QuickAddTag, Text: "#sales"},
+			{Kind: models.QuickAddPriority, Text: "!high"},
+		},
+	}, parsed)
+}
+
+func TestTaskService_QuickAdd(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil).(*taskServiceImpl)
+	service.now = func() time.Time { return time.Date(2030, 1, 2, 7, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+
+	mockRepo.On("ApplyBulk", ctx, mock.MatchedBy(func(changes []models.TaskChange) bool {
+		task := changes[0].Task
+		return len(changes) == 1 && task.Name == "Позвонить клиенту" && task.UserID == 7 &&
+			task.Priority == models.PriorityHigh && task.Due.Equal(time.Date(2030, 1, 3, 12, 0, 0, 0, time.UTC)) &&
+			changes[0].Tags[0] == "sales"
+	})).Return([]*models.Task{{ID: 5, Name: "Позвонить клиенту"}}, nil)
+
+	result, err := service.QuickAdd(ctx, models.QuickAddRequest{
+		Text:     "Позвонить клиенту завтра в 15:00 #Sales !high",
+		Timezone: "Europe/Moscow",
+		UserID:   7,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, result.Task.ID)
+	require.Equal(t, []string{"sales"}, result.Tags)
+
+	// Пробный разбор не пишет в базу; без названия задачу создать нельзя
+	result, err = service.QuickAdd(ctx, models.QuickAddRequest{Text: "call mom tomorrow", UserID: 7, DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, "medium", result.Task.Priority)
+
+	for _, request := range []models.QuickAddRequest{
+		{Text: "  "},
+		{Text: "tomorrow !high"},
+		{Text: "call mom", Timezone: "Mars/Olympus"},
+	} {
+		_, err = service.QuickAdd(ctx, request)
+		require.ErrorIs(t, err, ErrInvalidQuickAdd, request.Text)
+	}
+
+	// Без пояса в запросе берётся пояс из профиля пользователя
+	calendars := new(MockWorkCalendarRepository)
+	calendars.On("Get", ctx, 7).Return(models.WorkCalendar{Timezone: "Europe/Moscow"}, nil)
+	service.calendars = NewWorkCalendarService(calendars)
+
+	result, err = service.QuickAdd(ctx, models.QuickAddRequest{Text: "call mom tomorrow at 15:00", UserID: 7, DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2030, 1, 3, 12, 0, 0, 0, time.UTC), result.Task.Due)
+	require.Equal(t, "Europe/Moscow", result.Parse.Timezone)
+
+	mockRepo.AssertNumberOfCalls(t, "ApplyBulk", 1)
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Группы маршрутов с отдельными ограничениями частоты запросов.
+const (
+	RateLimitRead  = "read"  // Чтение
+	RateLimitWrite = "write" // Изменения
+	RateLimitHeavy = "heavy" // Пакетные операции, импорт и выгрузка
+)
+
+// RateLimitRule - корзина токенов: Requests запросов за Period в среднем и не больше
+// Burst подряд. Requests = 0 снимает ограничение, Burst = 0 означает Burst = Requests.
+type RateLimitRule struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (r RateLimitRule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+
+	return r.Requests
+}
+
+// rate - скорость пополнения корзины в токенах в секунду.
+func (r RateLimitRule) rate() float64 {
+	return float64(r.Requests) / r.Period.Seconds()
+}
+
+// refill - время, за которое пустая корзина наполняется целиком.
+func (r RateLimitRule) refill() time.Duration {
+	return time.Duration(float64(r.burst()) / r.rate() * float64(time.Second))
+}
+
+// RateLimitRules - ограничения групп маршрутов для пользователей с API-ключом и общее
+// ограничение для запросов без ключа, которые считаются по IP-адресу. Users задаёт особые
+// правила отдельных пользователей; ключ - subject из RateLimitUserSubject.
+type RateLimitRules struct {
+	Read      RateLimitRule
+	Write     RateLimitRule
+	Heavy     RateLimitRule
+	Anonymous RateLimitRule
+	Users     map[string]RateLimitOverride
+}
+
+// RateLimitOverride заменяет правила групп для одного пользователя; nil - общее правило группы.
+type RateLimitOverride struct {
+	Read  *RateLimitRule
+	Write *RateLimitRule
+	Heavy *RateLimitRule
+}
+
+func (o RateLimitOverride) rule(group string) *RateLimitRule {
+	switch group {
+	case RateLimitHeavy:
+		return o.Heavy
+	case RateLimitWrite:
+		return o.Write
+	default:
+		return o.Read
+	}
+}
+
+// RateLimitUserSubject - subject корзин пользователя с API-ключом.
+func RateLimitUserSubject(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// RateLimitDecision - итог проверки запроса и данные для заголовков RateLimit-*.
+type RateLimitDecision struct {
+	Allowed    bool
+	Limited    bool          // К запросу применялось ограничение
+	Limit      int           // Ёмкость корзины
+	Remaining  int           // Оставшиеся запросы
+	Reset      time.Duration // Через сколько корзина наполнится
+	RetryAfter time.Duration // Через сколько появится токен, если запрос отклонён
+	Policy     string        // Значение RateLimit-Policy: "<запросов>;w=<секунд>"
+}
+
+// RateLimitStore хранит корзины токенов: в памяти для одного экземпляра сервиса или
+// в базе данных (repositories.RateLimitRepository) для нескольких.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error)
+	Purge(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+type RateLimitService interface {
+	Allow(ctx context.Context, group, subject string, anonymous bool) (RateLimitDecision, error)
+	SetRules(rules RateLimitRules)
+	RunPurger(ctx context.Context, interval time.Duration)
+}
+
+type rateLimitServiceImpl struct {
+	store RateLimitStore
+	rules atomic.Pointer[RateLimitRules]
+}
+
+func NewRateLimitService(store RateLimitStore, rules RateLimitRules) RateLimitService {
+	s := &rateLimitServiceImpl{store: store}
+	s.SetRules(rules)
+
+	return s
+}
+
+// SetRules заменяет ограничения на лету; корзины сохраняются.
+func (s *rateLimitServiceImpl) SetRules(rules RateLimitRules) {
+	s.rules.Store(&rules)
+}
+
+// Allow забирает токен из корзины subject (пользователя или IP-адреса) в группе group.
+// Запросы без API-ключа считаются по правилу Anonymous независимо от группы, для
+// пользователя из Users действует его правило группы, если оно задано.
+func (s *rateLimitServiceImpl) Allow(ctx context.Context, group, subject string, anonymous bool) (RateLimitDecision, error) {
+	rules := s.rules.Load()
+
+	var rule RateLimitRule
+
+	switch {
+	case anonymous:
+		rule, group = rules.Anonymous, "anonymous"
+	case group == RateLimitHeavy:
+		rule = rules.Heavy
+	case group == RateLimitWrite:
+		rule = rules.Write
+	default:
+		rule = rules.Read
+	}
+
+	if override, ok := rules.Users[subject]; ok && !anonymous {
+		if r := override.rule(group); r != nil {
+			rule = *r
+		}
+	}
+
+	if rule.Requests <= 0 || rule.Period <= 0 {
+		return RateLimitDecision{Allowed: true}, nil
+	}
+
+	tokens, allowed, err := s.store.Take(ctx, group+":"+subject, rule.rate(), rule.burst())
+	if err != nil {
+		return RateLimitDecision{}, err
+	}
+
+	decision := RateLimitDecision{
+		Allowed:   allowed,
+		Limited:   true,
+		Limit:     rule.burst(),
+		Remaining: int(math.Max(math.Floor(tokens), 0)),
+		Reset:     secondsFor(float64(rule.burst())-tokens, rule.rate()),
+		Policy:    fmt.Sprintf("%d;w=%d", rule.Requests, int(rule.Period.Seconds())),
+	}
+
+	if !allowed {
+		decision.RetryAfter = secondsFor(1-tokens, rule.rate())
+	}
+
+	return decision, nil
+}
+
+// secondsFor - время, за которое накопится tokens токенов, с округлением вверх до секунды.
+func secondsFor(tokens, rate float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(tokens/rate)) * time.Second
+}
+
+// RunPurger удаляет наполнившиеся корзины каждые interval до отмены ctx.
+func (s *rateLimitServiceImpl) RunPurger(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.store.Purge(ctx, s.maxRefill())
+			if err != nil {
+				slog.ErrorContext(ctx, "purging rate limit buckets failed", "error", err)
+				continue
+			}
+
+			if deleted > 0 {
+				slog.DebugContext(ctx, "purged rate limit buckets", "deleted", deleted)
+			}
+		}
+	}
+}
+
+// maxRefill - время наполнения самой медленной корзины: корзины, простоявшие дольше,
+// полны при любом правиле.
+func (s *rateLimitServiceImpl) maxRefill() time.Duration {
+	rules := s.rules.Load()
+
+	var longest time.Duration
+
+	all := []RateLimitRule{rules.Read, rules.Write, rules.Heavy, rules.Anonymous}
+	for _, override := range rules.Users {
+		for _, rule := range []*RateLimitRule{override.Read, override.Write, override.Heavy} {
+			if rule != nil {
+				all = append(all, *rule)
+			}
+		}
+	}
+
+	for _, rule := range all {
+		if rule.Requests > 0 && rule.Period > 0 && rule.refill() > longest {
+			longest = rule.refill()
+		}
+	}
+
+	return longest
+}
+
+// memoryRateLimitStore хранит корзины в памяти процесса.
+type memoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	now     func() time.Time
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewMemoryRateLimitStore создаёт хранилище корзин в памяти. Подходит, когда запущен
+// один экземпляр сервиса: у каждого экземпляра были бы свои корзины.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{buckets: make(map[string]*memoryBucket), now: time.Now}
+}
+
+func (m *memoryRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(burst), updatedAt: now}
+		m.buckets[key] = bucket
+	}
+
+	elapsed := math.Max(now.Sub(bucket.updatedAt).Seconds(), 0)
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+elapsed*rate)
+	bucket.updatedAt = now
+
+	if bucket.tokens < 1 {
+		return bucket.tokens, false, nil
+	}
+
+	bucket.tokens--
+
+	return bucket.tokens, true, nil
+}
+
+func (m *memoryRateLimitStore) Purge(ctx context.Context, idle time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+
+	before := m.now().Add(-idle)
+
+	for key, bucket := range m.buckets {
+		if bucket.updatedAt.Before(before) {
+			delete(m.buckets, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingRateLimitStore имитирует недоступную базу корзин.
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (float64, bool, error) {
+	return 0, false, errors.New("connection refused")
+}
+
+func (failingRateLimitStore) Purge(ctx context.Context, idle time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func newTestRateLimitService(rules RateLimitRules) (RateLimitService, *memoryRateLimitStore, *time.Time) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	store.now = func() time.Time { return now }
+
+	return NewRateLimitService(store, rules), store, &now
+}
+
+func TestRateLimitService_Allow(t *testing.T) {
+	service, _, now := newTestRateLimitService(RateLimitRules{
+		Write: RateLimitRule{Requests: 60, Period: time.Minute, Burst: 2},
+	})
+
+	ctx := context.Background()
+
+	decision, err := service.Allow(ctx, RateLimitWrite, "user:1", false)
+	require.NoError(t, err)
+	require.Equal(t, RateLimitDecision{
+		Allowed: true, Limited: true, Limit: 2, Remaining: 1, Reset: time.Second, Policy: "60;w=60",
+	}, decision)
+
+	decision, err = service.Allow(ctx, RateLimitWrite, "user:1", false)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 0, decision.Remaining)
+	require.Equal(t, 2*time.Second, decision.Reset)
+
+	// Корзина пуста: токен появится через секунду
+	decision, err = service.Allow(ctx, RateLimitWrite, "user:1", false)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, time.Second, decision.RetryAfter)
+
+	// У другого пользователя своя корзина
+	decision, err = service.Allow(ctx, RateLimitWrite, "user:2", false)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+
+	*now = now.Add(1500 * time.Millisecond)
+
+	decision, err = service.Allow(ctx, RateLimitWrite, "user:1", false)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 0, decision.Remaining)
+
+	// У чтения нет ограничения
+	decision, err = service.Allow(ctx, RateLimitRead, "user:1", false)
+	require.NoError(t, err)
+	require.Equal(t, RateLimitDecision{Allowed: true}, decision)
+}
+
+func TestRateLimitService_UserOverride(t *testing.T) {
+	service, _, _ := newTestRateLimitService(RateLimitRules{
+		Read:  RateLimitRule{Requests: 60, Period: time.Minute, Burst: 1},
+		Write: RateLimitRule{Requests: 60, Period: time.Minute, Burst: 1},
+		Heavy: RateLimitRule{Requests: 1, Period: time.Minute},
+		Users: map[string]RateLimitOverride{
+			RateLimitUserSubject(7): {
+				Heavy: &RateLimitRule{Requests: 60, Period: time.Minute, Burst: 3},
+				Write: &RateLimitRule{},
+			},
+		},
+	})
+
+	ctx := context.Background()
+
+	// Особое правило группы заменяет общее только для своего пользователя
+	decision, err := service.Allow(ctx, RateLimitHeavy, "user:7", false)
+	require.NoError(t, err)
+	require.Equal(t, 3, decision.Limit)
+	require.Equal(t, "60;w=60", decision.Policy)
+
+	decision, err = service.Allow(ctx, RateLimitHeavy, "user:8", false)
+	require.NoError(t, err)
+	require.Equal(t, 1, decision.Limit)
+
+	// Нулевое правило снимает ограничение группы, незаданная группа берёт общее правило
+	for i := 0; i < 3; i++ {
+		decision, err = service.Allow(ctx, RateLimitWrite, "user:7", false)
+		require.NoError(t, err)
+		require.Equal(t, RateLimitDecision{Allowed: true}, decision)
+	}
+
+	decision, err = service.Allow(ctx, RateLimitRead, "user:7", false)
+	require.NoError(t, err)
+	require.Equal(t, 1, decision.Limit)
+
+	// Запрос без ключа особые правила не получает, даже если subject совпал
+	decision, err = service.Allow(ctx, RateLimitHeavy, "user:7", true)
+	require.NoError(t, err)
+	require.Equal(t, RateLimitDecision{Allowed: true}, decision)
+}
+
+func TestRateLimitService_Anonymous(t *testing.T) {
+	service, store, _ := newTestRateLimitService(RateLimitRules{
+		Write:     RateLimitRule{Requests: 100, Period: time.Minute},
+		Anonymous: RateLimitRule{Requests: 1, Period: time.Minute},
+	})
+
+	ctx := context.Background()
+
+	// Без API-ключа действует общее правило для всех групп
+	decision, err := service.Allow(ctx, RateLimitWrite, "ip:10.0.0.1", true)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, "1;w=60", decision.Policy)
+
+	decision, err = service.Allow(ctx, RateLimitRead, "ip:10.0.0.1", true)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, time.Minute, decision.RetryAfter)
+
+	require.Contains(t, store.buckets, "anonymous:ip:10.0.0.1")
+}
+
+func TestRateLimitService_SetRules(t *testing.T) {
+	service, _, _ := newTestRateLimitService(RateLimitRules{
+		Heavy: RateLimitRule{Requests: 1, Period: time.Minute},
+	})
+
+	ctx := context.Background()
+
+	_, err := service.Allow(ctx, RateLimitHeavy, "user:1", false)
+	require.NoError(t, err)
+
+	decision, err := service.Allow(ctx, RateLimitHeavy, "user:1", false)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+
+	service.SetRules(RateLimitRules{})
+
+	decision, err = service.Allow(ctx, RateLimitHeavy, "user:1", false)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.False(t, decision.Limited)
+}
+
+func TestRateLimitService_StoreError(t *testing.T) {
+	service := NewRateLimitService(failingRateLimitStore{}, RateLimitRules{
+		Read: RateLimitRule{Requests: 10, Period: time.Second},
+	})
+
+	_, err := service.Allow(context.Background(), RateLimitRead, "user:1", false)
+	require.Error(t, err)
+}
+
+func TestMemoryRateLimitStore_Purge(t *testing.T) {
+	service, store, now := newTestRateLimitService(RateLimitRules{
+		Read:  RateLimitRule{Requests: 60, Period: time.Minute, Burst: 120},
+		Write: RateLimitRule{Requests: 10, Period: time.Minute},
+	})
+
+	ctx := context.Background()
+
+	_, err := service.Allow(ctx, RateLimitRead, "user:1", false)
+	require.NoError(t, err)
+
+	*now = now.Add(time.Minute)
+
+	_, err = service.Allow(ctx, RateLimitRead, "user:2", false)
+	require.NoError(t, err)
+
+	// Самая медленная корзина наполняется за две минуты
+	require.Equal(t, 2*time.Minute, service.(*rateLimitServiceImpl).maxRefill())
+
+	deleted, err := store.Purge(ctx, 30*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	require.Contains(t, store.buckets, "read:user:2")
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const maxRecurrenceLength = 255
+
+var (
+	recurrenceFrequencies = map[string]bool{"DAILY": true, "WEEKLY": true, "MONTHLY": true, "YEARLY": true}
+	recurrenceWeekdayRe   = regexp.MustCompile(`^[+-]?([1-9]|[1-4][0-9]|5[0-3])?(MO|TU|WE|TH|FR|SA|SU)$`)
+	recurrenceUntilRe     = regexp.MustCompile(`^\d{8}(T\d{6}Z?)?$`)
+)
+
+// NormalizeRecurrence проверяет правило повторения задачи - подмножество RRULE из RFC 5545
+// (FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, WKST) - и приводит его
+// к верхнему регистру. Пустая строка означает задачу без повторения.
+func NormalizeRecurrence(rule string) (string, error) {
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	rule = strings.TrimPrefix(rule, "RRULE:")
+
+	if rule == "" {
+		return "", nil
+	}
+
+	if len(rule) > maxRecurrenceLength {
+		return "", errors.New("recurrence rule is too long")
+	}
+
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return "", fmt.Errorf("invalid recurrence part %q", part)
+		}
+
+		if seen[name] {
+			return "", fmt.Errorf("recurrence part %s is repeated", name)
+		}
+
+		seen[name] = true
+
+		if err := checkRecurrencePart(name, value); err != nil {
+			return "", err
+		}
+	}
+
+	if !seen["FREQ"] {
+		return "", errors.New("recurrence rule requires FREQ")
+	}
+
+	if seen["COUNT"] && seen["UNTIL"] {
+		return "", errors.New("recurrence rule cannot have both COUNT and UNTIL")
+	}
+
+	return rule, nil
+}
+
+func checkRecurrencePart(name, value string) error {
+	switch name {
+	case "FREQ":
+		if !recurrenceFrequencies[value] {
+			return errors.New("recurrence FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+		}
+	case "INTERVAL", "COUNT":
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return fmt.Errorf("recurrence %s must be a positive number", name)
+		}
+	case "UNTIL":
+		if !recurrenceUntilRe.MatchString(value) {
+			return errors.New("recurrence UNTIL must be a date (YYYYMMDD) or date-time")
+		}
+	case "BYDAY", "WKST":
+		for _, day := range strings.Split(value, ",") {
+			if !recurrenceWeekdayRe.MatchString(day) || (name == "WKST" && len(day) != 2) {
+				return fmt.Errorf("invalid recurrence %s %q", name, day)
+			}
+		}
+	case "BYMONTHDAY", "BYMONTH":
+		limit := 31
+		if name == "BYMONTH" {
+			limit = 12
+		}
+
+		for _, item := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(item)
+			if err != nil || n == 0 || n > limit || n < -limit || (name == "BYMONTH" && n < 0) {
+				return fmt.Errorf("invalid recurrence %s %q", name, item)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported recurrence part %s", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxTagLength = 50
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+type TagService interface {
+	GetByTask(ctx context.Context, taskID int) ([]string, error)
+	Set(ctx context.Context, taskID int, tags []string) ([]string, error)
+}
+
+type tagServiceImpl struct {
+	repo  repositories.TagRepository
+	tasks repositories.TaskRepository
+}
+
+func NewTagService(repo repositories.TagRepository, tasks repositories.TaskRepository) TagService {
+	return &tagServiceImpl{repo: repo, tasks: tasks}
+}
+
+func (s *tagServiceImpl) GetByTask(ctx context.Context, taskID int) ([]string, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+func (s *tagServiceImpl) Set(ctx context.Context, taskID int, tags []string) ([]string, error) {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Set(ctx, taskID, normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// NormalizeTags приводит теги к нижнему регистру, убирает ведущий '#',
+// дубликаты и сортирует результат.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength || strings.IndexFunc(tag, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+		}
+
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	sort.Strings(normalized)
+
+	return normalized, nil
+}
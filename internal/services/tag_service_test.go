@@ -0,0 +1,67 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTagRepository реализует методы TagRepository для тестов.
+type MockTagRepository struct {
+	mock.Mock
+}
+
+func (m *MockTagRepository) GetByTask(ctx context.Context, taskID int) ([]string, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTagRepository) Set(ctx context.Context, taskID int, tags []string) error {
+	return m.Called(ctx, taskID, tags).Error(0)
+}
+
+func (m *MockTagRepository) Add(ctx context.Context, taskID int, tag string) error {
+	return m.Called(ctx, taskID, tag).Error(0)
+}
+
+func (m *MockTagRepository) Remove(ctx context.Context, taskID int, tag string) error {
+	return m.Called(ctx, taskID, tag).Error(0)
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" #Backend", "urgent", "backend", "Срочно"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend", "urgent", "срочно"}, tags)
+
+	_, err = NormalizeTags([]string{"two words"})
+	require.ErrorIs(t, err, ErrInvalidTag)
+
+	_, err = NormalizeTags([]string{"#"})
+	require.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func TestTagService_Set(t *testing.T) {
+	mockRepo := new(MockTagRepository)
+	mockTasks := new(MockTaskRepository)
+	service := NewTagService(mockRepo, mockTasks)
+
+	ctx := context.Background()
+
+	mockTasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	mockTasks.On("GetByID", ctx, 2).Return(nil, sql.ErrNoRows)
+	mockRepo.On("Set", ctx, 1, []string{"backend", "urgent"}).Return(nil)
+
+	tags, err := service.Set(ctx, 1, []string{"Urgent", "#backend"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend", "urgent"}, tags)
+
+	// Ошибка: задача не найдена
+	_, err = service.Set(ctx, 2, []string{"backend"})
+	require.ErrorIs(t, err, ErrTaskNotFound)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"WebTasks/internal/metrics"
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const maxBulkOperations = 500
+
+var ErrInvalidBulk = errors.New("invalid bulk request")
+
+// Bulk выполняет пакет операций. Все операции сначала проверяются по состоянию до пакета;
+// в режиме atomic (по умолчанию) пакет применяется в одной транзакции только без ошибок,
+// в режиме best_effort каждая прошедшая проверку операция применяется отдельно.
+// Одну задачу в пакете может переписать только одна операция create/update/status/delete.
+func (s *taskServiceImpl) Bulk(ctx context.Context, request models.BulkRequest) (models.BulkResponse, error) {
+	mode := request.Mode
+	if mode == "" {
+		mode = models.BulkModeAtomic
+	}
+
+	if mode != models.BulkModeAtomic && mode != models.BulkModeBestEffort {
+		return models.BulkResponse{}, fmt.Errorf("%w: mode must be %s or %s", ErrInvalidBulk, models.BulkModeAtomic, models.BulkModeBestEffort)
+	}
+
+	if len(request.Operations) == 0 || len(request.Operations) > maxBulkOperations {
+		return models.BulkResponse{}, fmt.Errorf("%w: expected 1-%d operations", ErrInvalidBulk, maxBulkOperations)
+	}
+
+	response := models.BulkResponse{Mode: mode, Results: make([]models.BulkResult, len(request.Operations))}
+	changes := make([]models.TaskChange, len(request.Operations))
+	touched := make(map[int]bulkTouch)
+	quota := s.newTaskQuota()
+	failed := false
+
+	for i, operation := range request.Operations {
+		response.Results[i] = models.BulkResult{Index: i, Op: operation.Op, ID: operation.ID}
+
+		change, err := s.prepareBulkOperation(ctx, operation, i, touched, quota)
+		if err != nil {
+			response.Results[i].Status = models.BulkResultFailed
+			response.Results[i].Error = bulkErrorMessage(err)
+			failed = true
+
+			continue
+		}
+
+		changes[i] = change
+	}
+
+	if mode == models.BulkModeAtomic {
+		if err := s.applyAtomic(ctx, changes, failed, &response); err != nil {
+			return models.BulkResponse{}, err
+		}
+	} else {
+		s.applyBestEffort(ctx, changes, &response)
+	}
+
+	for _, result := range response.Results {
+		if result.Status == models.BulkResultOK {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	observeBulk(response, touched)
+
+	return response, nil
+}
+
+func (s *taskServiceImpl) applyAtomic(ctx context.Context, changes []models.TaskChange, failed bool, response *models.BulkResponse) error {
+	if !failed {
+		tasks, err := s.repo.ApplyBulk(ctx, changes)
+		if err == nil {
+			for i := range response.Results {
+				markBulkApplied(&response.Results[i], tasks[i])
+			}
+
+			return nil
+		}
+
+		var bulkErr *repositories.BulkError
+		if !errors.As(err, &bulkErr) {
+			return err
+		}
+
+		response.Results[bulkErr.Index].Status = models.BulkResultFailed
+		response.Results[bulkErr.Index].Error = bulkErrorMessage(bulkErr.Err)
+	}
+
+	for i := range response.Results {
+		if response.Results[i].Status == "" {
+			response.Results[i].Status = models.BulkResultSkipped
+		}
+	}
+
+	return nil
+}
+
+func (s *taskServiceImpl) applyBestEffort(ctx context.Context, changes []models.TaskChange, response *models.BulkResponse) {
+	for i := range response.Results {
+		if response.Results[i].Status == models.BulkResultFailed {
+			continue
+		}
+
+		tasks, err := s.repo.ApplyBulk(ctx, changes[i:i+1])
+		if err != nil {
+			var bulkErr *repositories.BulkError
+			if errors.As(err, &bulkErr) {
+				err = bulkErr.Err
+			}
+
+			response.Results[i].Status = models.BulkResultFailed
+			response.Results[i].Error = bulkErrorMessage(err)
+
+			continue
+		}
+
+		markBulkApplied(&response.Results[i], tasks[0])
+	}
+}
+
+// bulkTouch запоминает операцию пакета, которая переписывает задачу.
+type bulkTouch struct {
+	index  int
+	op     string
+	closes bool // Операция закрывает открытую задачу
+}
+
+// observeBulk учитывает в метриках созданные и закрытые пакетом задачи.
+func observeBulk(response models.BulkResponse, touched map[int]bulkTouch) {
+	created, completed := 0, 0
+
+	for _, result := range response.Results {
+		if result.Op == models.BulkCreate && result.Status == models.BulkResultOK {
+			created++
+		}
+	}
+
+	for _, touch := range touched {
+		if touch.closes && response.Results[touch.index].Status == models.BulkResultOK {
+			completed++
+		}
+	}
+
+	metrics.TasksCreated(metrics.SourceBulk, created)
+	metrics.TasksCompleted(completed)
+}
+
+func (s *taskServiceImpl) prepareBulkOperation(
+	ctx context.Context,
+	operation models.BulkOperation,
+	index int,
+	touched map[int]bulkTouch,
+	quota *taskQuota,
+) (models.TaskChange, error) {
+	if operation.Op == models.BulkCreate {
+		if operation.Task == nil {
+			return models.TaskChange{}, errors.New("task is required")
+		}
+
+		task := *operation.Task
+		task.ID = 0
+
+		tags, err := NormalizeTags(operation.Tags)
+		if err != nil {
+			return models.TaskChange{}, err
+		}
+
+		prepared, err := s.prepareCreate(ctx, task)
+		if err != nil {
+			return models.TaskChange{}, err
+		}
+
+		if err := quota.reserve(ctx, prepared); err != nil {
+			return models.TaskChange{}, err
+		}
+
+		return models.TaskChange{Op: models.BulkCreate, Task: &prepared, Tags: tags}, nil
+	}
+
+	if operation.ID <= 0 {
+		return models.TaskChange{}, errors.New("id is required")
+	}
+
+	if previous, ok := touched[operation.ID]; ok {
+		if previous.op == models.BulkDelete {
+			return models.TaskChange{}, fmt.Errorf("task is deleted by operation %d", previous.index)
+		}
+
+		if rewritesTask(operation.Op) {
+			return models.TaskChange{}, fmt.Errorf("task is already changed by operation %d", previous.index)
+		}
+	}
+
+	existing, err := s.repo.GetByID(ctx, operation.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskChange{}, ErrTaskNotFound
+	}
+
+	if err != nil {
+		return models.TaskChange{}, err
+	}
+
+	change := models.TaskChange{Op: operation.Op, TaskID: operation.ID}
+
+	switch operation.Op {
+	case models.BulkUpdate, models.BulkStatus:
+		var task models.Task
+
+		if operation.Op == models.BulkUpdate {
+			if operation.Task == nil {
+				return models.TaskChange{}, errors.New("task is required")
+			}
+
+			task = *operation.Task
+		} else {
+			if operation.Status == "" {
+				return models.TaskChange{}, errors.New("status is required")
+			}
+
+			// Остальные поля подставит prepareUpdate, поэтому просроченный срок не мешает закрытию
+			task = models.Task{Name: existing.Name, Status: operation.Status}
+		}
+
+		task.ID = operation.ID
+
+		prepared, err := s.prepareUpdate(ctx, task, existing)
+		if err != nil {
+			return models.TaskChange{}, err
+		}
+
+		if reopensTask(existing, prepared) {
+			if err := quota.reserve(ctx, prepared); err != nil {
+				return models.TaskChange{}, err
+			}
+		}
+
+		change.Op = models.BulkUpdate
+		change.Task = &prepared
+
+	case models.BulkDelete:
+
+	case models.BulkAddTags, models.BulkRemoveTags, models.BulkSetTags:
+		tags, err := NormalizeTags(operation.Tags)
+		if err != nil {
+			return models.TaskChange{}, err
+		}
+
+		if len(tags) == 0 && operation.Op != models.BulkSetTags {
+			return models.TaskChange{}, errors.New("tags are required")
+		}
+
+		change.Tags = tags
+
+	default:
+		return models.TaskChange{}, fmt.Errorf("unknown operation %q", operation.Op)
+	}
+
+	if rewritesTask(operation.Op) {
+		closes := change.Task != nil && closesTask(existing, *change.Task)
+		touched[operation.ID] = bulkTouch{index: index, op: operation.Op, closes: closes}
+	}
+
+	return change, nil
+}
+
+func rewritesTask(op string) bool {
+	return op == models.BulkUpdate || op == models.BulkStatus || op == models.BulkDelete
+}
+
+func markBulkApplied(result *models.BulkResult, task *models.Task) {
+	result.Status = models.BulkResultOK
+	result.Task = task
+
+	if task != nil {
+		result.ID = task.ID
+	}
+}
+
+// bulkErrorMessage скрывает подробности ошибок базы данных, оставляя понятные клиенту причины.
+func bulkErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, ErrTaskNotFound):
+		return ErrTaskNotFound.Error()
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "request cancelled"
+	}
+
+	var dbErr interface{ SQLState() string }
+	if errors.As(err, &dbErr) {
+		return "failed to apply operation"
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Bulk_Atomic(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+
+	// Просроченный срок не мешает смене статуса
+	overdue := &models.Task{ID: 2, Name: "Overdue", Status: "Pending", Due: time.Now().Add(-time.Hour), Priority: models.PriorityLow}
+	mockRepo.On("GetByID", ctx, 2).Return(overdue, nil)
+	mockRepo.On("GetByID", ctx, 3).Return(&models.Task{ID: 3, Name: "Tagged"}, nil)
+
+	mockRepo.On("ApplyBulk", ctx, mock.MatchedBy(func(changes []models.TaskChange) bool {
+		return len(changes) == 3 &&
+			changes[0].Op == models.BulkCreate && changes[0].Task.Priority == models.PriorityMedium &&
+			changes[0].Tags[0] == "release" &&
+			changes[1].Op == models.BulkUpdate && changes[1].Task.Status == "Completed" &&
+			changes[1].Task.Priority == models.PriorityLow &&
+			changes[2].Op == models.BulkAddTags && changes[2].TaskID == 3
+	})).Return([]*models.Task{{ID: 10, Name: "Deploy"}, {ID: 2, Status: "Completed"}, nil}, nil).Once()
+
+	response, err := service.Bulk(ctx, models.BulkRequest{Operations: []models.BulkOperation{
+		{Op: models.BulkCreate, Task: &models.Task{Name: "Deploy"}, Tags: []string{"Release"}},
+		{Op: models.BulkStatus, ID: 2, Status: "Completed"},
+		{Op: models.BulkAddTags, ID: 3, Tags: []string{"ops"}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, models.BulkModeAtomic, response.Mode)
+	require.Equal(t, 3, response.Succeeded)
+	require.Equal(t, 10, response.Results[0].ID)
+	require.Equal(t, models.BulkResultOK, response.Results[2].Status)
+
+	// Ошибка проверки отменяет весь пакет, в базу ничего не уходит
+	mockRepo.On("GetByID", ctx, 99).Return((*models.Task)(nil), sql.ErrNoRows)
+
+	response, err = service.Bulk(ctx, models.BulkRequest{Operations: []models.BulkOperation{
+		{Op: models.BulkStatus, ID: 2, Status: "Completed"},
+		{Op: models.BulkDelete, ID: 99},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 2, response.Failed)
+	require.Equal(t, models.BulkResultSkipped, response.Results[0].Status)
+	require.Equal(t, models.BulkResultFailed, response.Results[1].Status)
+	require.Equal(t, "task not found", response.Results[1].Error)
+
+	// Ошибка в транзакции относится к конкретной операции
+	mockRepo.On("ApplyBulk", ctx, mock.Anything).
+		Return(nil, &repositories.BulkError{Index: 1, Err: sql.ErrNoRows}).Once()
+
+	response, err = service.Bulk(ctx, models.BulkRequest{Operations: []models.BulkOperation{
+		{Op: models.BulkAddTags, ID: 3, Tags: []string{"ops"}},
+		{Op: models.BulkDelete, ID: 2},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, models.BulkResultSkipped, response.Results[0].Status)
+	require.Equal(t, "task not found", response.Results[1].Error)
+
+	mockRepo.AssertNumberOfCalls(t, "ApplyBulk", 2)
+}
+
+func TestTaskService_Bulk_BestEffort(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, 1).Return(&models.Task{ID: 1, Name: "Task"}, nil)
+	mockRepo.On("ApplyBulk", ctx, []models.TaskChange{{Op: models.BulkDelete, TaskID: 1}}).Return([]*models.Task{nil}, nil).Once()
+
+	response, err := service.Bulk(ctx, models.BulkRequest{Mode: models.BulkModeBestEffort, Operations: []models.BulkOperation{
+		{Op: models.BulkCreate, Task: &models.Task{Name: ""}},
+		{Op: models.BulkDelete, ID: 1},
+		{Op: models.BulkStatus, ID: 1, Status: "Completed"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 1, response.Succeeded)
+	require.Equal(t, 2, response.Failed)
+	require.Equal(t, "task name is required", response.Results[0].Error)
+	require.Equal(t, models.BulkResultOK, response.Results[1].Status)
+	require.Equal(t, "task is deleted by operation 1", response.Results[2].Error)
+
+	mockRepo.AssertNumberOfCalls(t, "ApplyBulk", 1)
+}
+
+func TestTaskService_Bulk_Invalid(t *testing.T) {
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(new(MockTaskRepository), fields, nil)
+
+	ctx := context.Background()
+
+	_, err := service.Bulk(ctx, models.BulkRequest{})
+	require.ErrorIs(t, err, ErrInvalidBulk)
+
+	_, err = service.Bulk(ctx, models.BulkRequest{Mode: "eventually", Operations: []models.BulkOperation{{Op: models.BulkDelete, ID: 1}}})
+	require.ErrorIs(t, err, ErrInvalidBulk)
+
+	_, err = service.Bulk(ctx, models.BulkRequest{Operations: make([]models.BulkOperation, maxBulkOperations+1)})
+	require.ErrorIs(t, err, ErrInvalidBulk)
+}
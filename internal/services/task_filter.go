@@ -0,0 +1,87 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseTaskFilter разбирает параметры списка задач: user_id, assignee, watcher, project_id,
+// parent_id, status, cf.<поле>=<значение>, sort (поле или cf.<поле>), order=asc|desc, limit, offset.
+// assignee и watcher принимают ID пользователя или "me" (models.CurrentUser, см. TaskFilter.ForUser).
+// Отрицательные ID отклоняются: иначе assignee=-1 совпал бы с внутренним значением "me".
+func ParseTaskFilter(query url.Values) (models.TaskFilter, error) {
+	filter := models.TaskFilter{
+		Status: query.Get("status"),
+		SortBy: query.Get("sort"),
+	}
+
+	integers := map[string]*int{
+		"user_id":    &filter.UserID,
+		"project_id": &filter.ProjectID,
+		"parent_id":  &filter.ParentID,
+		"limit":      &filter.Limit,
+		"offset":     &filter.Offset,
+	}
+
+	members := map[string]*int{
+		"assignee": &filter.AssigneeID,
+		"watcher":  &filter.WatcherID,
+	}
+
+	for name, target := range members {
+		switch value := query.Get(name); value {
+		case "":
+		case "me":
+			*target = models.CurrentUser
+		default:
+			integers[name] = target
+		}
+	}
+
+	for name, target := range integers {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil || (parsed < 0 && !paginationParams[name]) {
+			return filter, fmt.Errorf("%w: invalid %s", ErrInvalidTaskFilter, name)
+		}
+
+		*target = parsed
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, fmt.Errorf("%w: order must be asc or desc", ErrInvalidTaskFilter)
+	}
+
+	for key, values := range query {
+		if name, ok := strings.CutPrefix(key, repositories.CustomFieldPrefix); ok {
+			if filter.Custom == nil {
+				filter.Custom = make(map[string]string)
+			}
+
+			filter.Custom[name] = values[0]
+		}
+	}
+
+	return filter, nil
+}
+
+// paginationParams - числовые параметры, которые не являются ID; их проверяет List.
+var paginationParams = map[string]bool{"limit": true, "offset": true}
+
+// taskFilterParams - параметры, которые понимает ParseTaskFilter, кроме cf.<поле>.
+var taskFilterParams = map[string]bool{
+	"user_id": true, "assignee": true, "watcher": true, "project_id": true, "parent_id": true, "status": true,
+	"sort": true, "order": true, "limit": true, "offset": true,
+}
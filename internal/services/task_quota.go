@@ -0,0 +1,87 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrTaskQuotaExceeded = errors.New("task quota exceeded")
+
+// TaskQuotaChecker проверяет квоту для задач, которые создаются в обход TaskService:
+// миграцией из других сервисов и по шаблонам.
+type TaskQuotaChecker interface {
+	ReserveTasks(ctx context.Context, tasks []models.Task) error
+}
+
+// SetMaxTasks меняет на лету квоту незакрытых задач на пользователя; 0 снимает ограничение.
+func (s *taskServiceImpl) SetMaxTasks(maxTasks int) {
+	s.maxTasks.Store(int64(maxTasks))
+}
+
+// ReserveTasks проверяет квоту для задач одной операции; автор задач - CreatedBy.
+func (s *taskServiceImpl) ReserveTasks(ctx context.Context, tasks []models.Task) error {
+	quota := s.newTaskQuota()
+
+	for _, task := range tasks {
+		if err := quota.reserve(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// taskQuota проверяет квоту для задач, создаваемых одной операцией: пакет или импорт
+// учитывает и свои ещё не сохранённые задачи. Параллельные запросы могут превысить
+// квоту на несколько задач - она защищает от неограниченного роста, а не считает точно.
+type taskQuota struct {
+	repo repositories.TaskRepository
+	max  int
+	open map[int]int // Незакрытые задачи авторов с учётом уже проверенных
+}
+
+func (s *taskServiceImpl) newTaskQuota() *taskQuota {
+	return &taskQuota{repo: s.repo, max: int(s.maxTasks.Load()), open: make(map[int]int)}
+}
+
+// reserve учитывает открытую задачу за её автором или возвращает ErrTaskQuotaExceeded.
+// Автор (CreatedBy) берётся из контекста запроса, а не из тела, поэтому указать в задаче
+// чужой user_id или не указать его вовсе квоту не обходит. Маршруты, создающие задачи,
+// требуют известный API-ключ, поэтому без автора задачи создают только фоновые операции;
+// такие задачи и закрытые задачи квоту не расходуют.
+func (q *taskQuota) reserve(ctx context.Context, task models.Task) error {
+	if q.max <= 0 || task.CreatedBy == 0 || models.IsClosedStatus(task.Status) {
+		return nil
+	}
+
+	open, ok := q.open[task.CreatedBy]
+	if !ok {
+		var err error
+		if open, err = q.repo.CountOpenByUser(ctx, task.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	if open >= q.max {
+		return fmt.Errorf("%w: at most %d open tasks per user", ErrTaskQuotaExceeded, q.max)
+	}
+
+	q.open[task.CreatedBy] = open + 1
+
+	return nil
+}
+
+// treeTasks собирает задачи деревьев вместе с подзадачами - для проверки квоты.
+func treeTasks(trees []models.TaskTree) []models.Task {
+	var tasks []models.Task
+
+	for _, tree := range trees {
+		tasks = append(tasks, tree.Task)
+		tasks = append(tasks, treeTasks(tree.Subtasks)...)
+	}
+
+	return tasks
+}
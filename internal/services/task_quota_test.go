@@ -0,0 +1,192 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Create_Quota(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+	service.SetMaxTasks(2)
+
+	ctx := WithUserID(context.Background(), 5)
+
+	mockRepo.On("CountOpenByUser", ctx, 5).Return(1, nil).Once()
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(task *models.Task) bool { return task.CreatedBy == 5 })).
+		Return(&models.Task{ID: 1, Name: "Task"}, nil)
+
+	_, err := service.Create(ctx, models.Task{Name: "Task", UserID: 5})
+	require.NoError(t, err)
+
+	// Квота считается за автором запроса, а не за user_id из тела
+	mockRepo.On("CountOpenByUser", ctx, 5).Return(2, nil)
+
+	_, err = service.Create(ctx, models.Task{Name: "Task", UserID: 9})
+	require.ErrorIs(t, err, ErrTaskQuotaExceeded)
+
+	_, err = service.Create(ctx, models.Task{Name: "Task"})
+	require.ErrorIs(t, err, ErrTaskQuotaExceeded)
+
+	// Закрытые задачи квоту не расходуют
+	_, err = service.Create(ctx, models.Task{Name: "Done", Status: "Done", UserID: 5})
+	require.NoError(t, err)
+
+	// Квота снимается на лету
+	service.SetMaxTasks(0)
+
+	_, err = service.Create(ctx, models.Task{Name: "Task", UserID: 5})
+	require.NoError(t, err)
+
+	mockRepo.AssertNumberOfCalls(t, "CountOpenByUser", 3)
+	mockRepo.AssertNumberOfCalls(t, "Create", 3)
+}
+
+func TestTaskService_Create_QuotaWithoutUser(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+	service.SetMaxTasks(1)
+
+	ctx := context.Background()
+
+	// Без пользователя в контексте (фоновые операции) квота не проверяется
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(task *models.Task) bool { return task.CreatedBy == 0 })).
+		Return(&models.Task{ID: 1, Name: "Task"}, nil)
+
+	_, err := service.Create(ctx, models.Task{Name: "Task", UserID: 5})
+	require.NoError(t, err)
+
+	mockRepo.AssertNotCalled(t, "CountOpenByUser", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Update_ReopenQuota(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+	service.SetMaxTasks(2)
+
+	// Задачу открывает другой пользователь, но квоту расходует её автор
+	ctx := WithUserID(context.Background(), 8)
+
+	mockRepo.On("GetByID", ctx, 1).Return(&models.Task{ID: 1, Name: "Task", Status: "Done", Priority: models.PriorityMedium, CreatedBy: 5}, nil)
+	mockRepo.On("CountOpenByUser", ctx, 5).Return(2, nil)
+
+	_, err := service.Update(ctx, models.Task{ID: 1, Name: "Task", Status: "Pending"})
+	require.ErrorIs(t, err, ErrTaskQuotaExceeded)
+
+	// Изменение без открытия квоту не проверяет
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(task *models.Task) bool { return task.CreatedBy == 5 })).
+		Return(&models.Task{ID: 1, Name: "Renamed", Status: "Done"}, nil)
+
+	_, err = service.Update(ctx, models.Task{ID: 1, Name: "Renamed"})
+	require.NoError(t, err)
+
+	// Пакетное открытие тоже расходует квоту
+	response, err := service.Bulk(ctx, models.BulkRequest{Operations: []models.BulkOperation{
+		{Op: models.BulkStatus, ID: 1, Status: "Pending"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, models.BulkResultFailed, response.Results[0].Status)
+	require.Equal(t, "task quota exceeded: at most 2 open tasks per user", response.Results[0].Error)
+
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+	mockRepo.AssertNotCalled(t, "ApplyBulk", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Bulk_Quota(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+	service.SetMaxTasks(2)
+
+	ctx := WithUserID(context.Background(), 5)
+
+	// Пакет учитывает и свои ещё не созданные задачи, кому бы они ни принадлежали
+	mockRepo.On("CountOpenByUser", ctx, 5).Return(1, nil).Once()
+
+	response, err := service.Bulk(ctx, models.BulkRequest{Operations: []models.BulkOperation{
+		{Op: models.BulkCreate, Task: &models.Task{Name: "First", UserID: 5}},
+		{Op: models.BulkCreate, Task: &models.Task{Name: "Second", UserID: 9}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, models.BulkResultSkipped, response.Results[0].Status)
+	require.Equal(t, models.BulkResultFailed, response.Results[1].Status)
+	require.Equal(t, "task quota exceeded: at most 2 open tasks per user", response.Results[1].Error)
+
+	mockRepo.AssertNotCalled(t, "ApplyBulk", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Import_Quota(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+	service.SetMaxTasks(2)
+
+	ctx := WithUserID(context.Background(), 5)
+
+	mockRepo.On("CountOpenByUser", ctx, 5).Return(1, nil).Once()
+
+	report, err := service.Import(ctx, models.ImportOptions{Format: models.TransferJSON, UserID: 5},
+		strings.NewReader(`[{"name": "First"}, {"name": "Second"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []models.ImportRowError{
+		{Row: 2, Error: "task quota exceeded: at most 2 open tasks per user"},
+	}, report.Errors)
+
+	mockRepo.AssertNotCalled(t, "ApplyBulk", mock.Anything, mock.Anything)
+}
+
+func TestMigrationService_Import_Quota(t *testing.T) {
+	repo := new(MockMigrationRepository)
+	tasksRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	tasks := NewTaskService(tasksRepo, fields, nil)
+	tasks.SetMaxTasks(4)
+
+	service := NewMigrationService(repo, tasks).(*migrationServiceImpl)
+	service.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	ctx := WithUserID(context.Background(), 7)
+
+	// В выгрузке три задачи вместе с подзадачей, а до квоты осталась одна
+	tasksRepo.On("CountOpenByUser", ctx, 7).Return(3, nil)
+
+	_, err := service.Import(ctx, models.MigrationOptions{Source: models.MigrationTodoist, UserID: 7},
+		strings.NewReader(migrationTodoist))
+	require.ErrorIs(t, err, ErrTaskQuotaExceeded)
+
+	repo.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTemplateService_Instantiate_Quota(t *testing.T) {
+	repo := new(MockTemplateRepository)
+	tasksRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	tasks := NewTaskService(tasksRepo, fields, nil)
+	tasks.SetMaxTasks(5)
+
+	service := NewTemplateService(repo, tasks)
+	ctx := WithUserID(context.Background(), 7)
+	template := onboardingTemplate
+
+	repo.On("GetByID", ctx, 1).Return(&template, nil)
+
+	// Шаблон создаёт четыре задачи, а до квоты осталось три
+	tasksRepo.On("CountOpenByUser", ctx, 7).Return(2, nil)
+
+	_, err := service.Instantiate(ctx, 1, models.TemplateInstantiation{
+		Variables: map[string]string{"employee": "Anna"},
+		UserID:    7,
+	})
+	require.ErrorIs(t, err, ErrTaskQuotaExceeded)
+
+	repo.AssertNotCalled(t, "Instantiate", mock.Anything, mock.Anything)
+}
@@ -1,40 +1,75 @@
 package services
 
 import (
+	"WebTasks/internal/metrics"
 	"WebTasks/internal/models"
 	"WebTasks/internal/repositories"
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+const maxTaskListLimit = 1000
+
+var (
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrInvalidTaskFilter = errors.New("invalid task filter")
+)
+
+// CustomFieldValidator проверяет пользовательские поля задачи по определениям проекта.
+type CustomFieldValidator interface {
+	GetFields(ctx context.Context, projectID int) ([]models.CustomField, error)
+	ValidateCustomFields(ctx context.Context, projectID int, values models.CustomValues) (models.CustomValues, error)
+}
+
+// TaskProjects проверяет пользовательские поля задачи и то, что её автор участвует в проекте.
+type TaskProjects interface {
+	CustomFieldValidator
+	CheckMember(ctx context.Context, projectID, userID int) error
+}
+
 type TaskService interface {
 	Create(ctx context.Context, task models.Task) (models.Task, error)
 	GetByID(ctx context.Context, id int) (*models.Task, error)
 	GetAll(ctx context.Context) ([]models.Task, error)
+	List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error)
 	Update(ctx context.Context, task models.Task) (models.Task, error)
 	Delete(ctx context.Context, id int) error
+	Bulk(ctx context.Context, request models.BulkRequest) (models.BulkResponse, error)
+	Export(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) error
+	Import(ctx context.Context, options models.ImportOptions, body io.Reader) (models.ImportReport, error)
+	QuickAdd(ctx context.Context, request models.QuickAddRequest) (models.QuickAddResult, error)
+	ReserveTasks(ctx context.Context, tasks []models.Task) error
+	SetMaxTasks(maxTasks int)
 }
 
 type taskServiceImpl struct {
-	repo repositories.TaskRepository
+	repo      repositories.TaskRepository
+	projects  TaskProjects
+	calendars WorkCalendarProvider
+	now       func() time.Time
+	maxTasks  atomic.Int64 // Квота незакрытых задач на пользователя, 0 - без ограничения
 }
 
-func NewTaskService(repo repositories.TaskRepository) TaskService {
-	return &taskServiceImpl{repo: repo}
+// NewTaskService создаёт сервис задач. calendars может быть nil - тогда сроки считаются
+// по календарю по умолчанию (UTC, пн-пт).
+func NewTaskService(repo repositories.TaskRepository, projects TaskProjects, calendars WorkCalendarProvider) TaskService {
+	return &taskServiceImpl{repo: repo, projects: projects, calendars: calendars, now: time.Now}
 }
 
 func (s *taskServiceImpl) Create(ctx context.Context, task models.Task) (models.Task, error) {
-	if task.Name == "" {
-		return models.Task{}, errors.New("task name is required")
-	}
-
-	if len(task.Name) > 50 {
-		return models.Task{}, errors.New("task name is too long")
+	task, err := s.prepareCreate(ctx, task)
+	if err != nil {
+		return models.Task{}, err
 	}
 
-	if !task.Due.IsZero() && task.Due.Before(time.Now()) {
-		return models.Task{}, errors.New("due date cannot be in the past")
+	if err := s.newTaskQuota().reserve(ctx, task); err != nil {
+		return models.Task{}, err
 	}
 
 	createdTask, err := s.repo.Create(ctx, &task)
@@ -42,6 +77,8 @@ func (s *taskServiceImpl) Create(ctx context.Context, task models.Task) (models.
 		return models.Task{}, err
 	}
 
+	metrics.TasksCreated(metrics.SourceAPI, 1)
+
 	return *createdTask, nil
 }
 
@@ -49,6 +86,70 @@ func (s *taskServiceImpl) GetAll(ctx context.Context) ([]models.Task, error) {
 	return s.repo.GetAll(ctx)
 }
 
+// List отбирает задачи по фильтру. Сортировка по числовому пользовательскому полю
+// возможна только в пределах проекта, где известен тип поля.
+func (s *taskServiceImpl) List(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	filter, err := s.checkFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Limit > maxTaskListLimit {
+		filter.Limit = maxTaskListLimit
+	}
+
+	return s.repo.List(ctx, filter)
+}
+
+// checkFilter проверяет фильтр списка и определяет, сортировать ли пользовательское поле как число.
+func (s *taskServiceImpl) checkFilter(ctx context.Context, filter models.TaskFilter) (models.TaskFilter, error) {
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return filter, fmt.Errorf("%w: limit and offset cannot be negative", ErrInvalidTaskFilter)
+	}
+
+	if filter.AssigneeID < 0 || filter.WatcherID < 0 || filter.MemberID < 0 {
+		return filter, fmt.Errorf("%w: \"me\" requires an API key", ErrInvalidTaskFilter)
+	}
+
+	names := make([]string, 0, len(filter.Custom)+1)
+	for name := range filter.Custom {
+		names = append(names, name)
+	}
+
+	sortField, sortByCustom := strings.CutPrefix(filter.SortBy, repositories.CustomFieldPrefix)
+	if sortByCustom {
+		names = append(names, sortField)
+	}
+
+	for _, name := range names {
+		if !customFieldName.MatchString(name) {
+			return filter, fmt.Errorf("%w: bad custom field name %q", ErrInvalidTaskFilter, name)
+		}
+	}
+
+	if filter.ProjectID != 0 && len(names) > 0 {
+		fields, err := s.projects.GetFields(ctx, filter.ProjectID)
+		if err != nil {
+			return filter, err
+		}
+
+		types := make(map[string]string, len(fields))
+		for _, field := range fields {
+			types[field.Name] = field.Type
+		}
+
+		for _, name := range names {
+			if _, ok := types[name]; !ok {
+				return filter, fmt.Errorf("%w: unknown custom field %q", ErrInvalidTaskFilter, name)
+			}
+		}
+
+		filter.SortNumeric = sortByCustom && types[sortField] == models.FieldTypeNumber
+	}
+
+	return filter, nil
+}
+
 func (s *taskServiceImpl) GetByID(ctx context.Context, id int) (*models.Task, error) {
 	return s.repo.GetByID(ctx, id)
 }
@@ -63,6 +164,90 @@ func (s *taskServiceImpl) Update(ctx context.Context, task models.Task) (models.
 		return models.Task{}, err
 	}
 
+	task, err = s.prepareUpdate(ctx, task, existingTask)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	// Повторно открытая задача снова расходует квоту своего автора
+	if reopensTask(existingTask, task) {
+		if err := s.newTaskQuota().reserve(ctx, task); err != nil {
+			return models.Task{}, err
+		}
+	}
+
+	updatedTask, err := s.repo.Update(ctx, &task)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	if closesTask(existingTask, task) {
+		metrics.TasksCompleted(1)
+	}
+
+	return *updatedTask, nil
+}
+
+// closesTask сообщает, переводит ли изменение открытую задачу в закрытый статус.
+func closesTask(existing *models.Task, task models.Task) bool {
+	return !models.IsClosedStatus(existing.Status) && models.IsClosedStatus(task.Status)
+}
+
+// reopensTask сообщает, переводит ли изменение закрытую задачу в открытый статус.
+func reopensTask(existing *models.Task, task models.Task) bool {
+	return models.IsClosedStatus(existing.Status) && !models.IsClosedStatus(task.Status)
+}
+
+// prepareCreate проверяет новую задачу и заполняет значения по умолчанию.
+func (s *taskServiceImpl) prepareCreate(ctx context.Context, task models.Task) (models.Task, error) {
+	if task.Name == "" {
+		return models.Task{}, errors.New("task name is required")
+	}
+
+	if len(task.Name) > 50 {
+		return models.Task{}, errors.New("task name is too long")
+	}
+
+	if !task.Due.IsZero() && task.Due.Before(s.now()) {
+		return models.Task{}, errors.New("due date cannot be in the past")
+	}
+
+	if task.Priority == "" {
+		task.Priority = models.PriorityMedium
+	}
+
+	if err := validatePlanning(task); err != nil {
+		return models.Task{}, err
+	}
+
+	recurrence, err := NormalizeRecurrence(task.Recurrence)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	task.Recurrence = recurrence
+
+	// Автор - владелец API-ключа запроса; по нему считается квота задач
+	task.CreatedBy, _ = UserIDFromContext(ctx)
+
+	if task.ProjectID != 0 {
+		if err := s.projects.CheckMember(ctx, task.ProjectID, task.CreatedBy); err != nil {
+			return models.Task{}, err
+		}
+	}
+
+	customFields, err := s.projects.ValidateCustomFields(ctx, task.ProjectID, task.CustomFields)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	task.CustomFields = customFields
+
+	return task, nil
+}
+
+// prepareUpdate проверяет изменения и дополняет незаполненные поля значениями existingTask.
+func (s *taskServiceImpl) prepareUpdate(ctx context.Context, task models.Task, existingTask *models.Task) (models.Task, error) {
 	if task.Name == "" {
 		return models.Task{}, errors.New("task name is required")
 	}
@@ -71,7 +256,7 @@ func (s *taskServiceImpl) Update(ctx context.Context, task models.Task) (models.
 		return models.Task{}, errors.New("task name must not exceed 50 characters")
 	}
 
-	if !task.Due.IsZero() && task.Due.Before(time.Now()) {
+	if !task.Due.IsZero() && task.Due.Before(s.now()) {
 		return models.Task{}, errors.New("due date cannot be in the past")
 	}
 
@@ -79,6 +264,8 @@ func (s *taskServiceImpl) Update(ctx context.Context, task models.Task) (models.
 		task.Status = existingTask.Status
 	}
 
+	task.CreatedBy = existingTask.CreatedBy
+
 	if task.Time.IsZero() {
 		task.Time = existingTask.Time
 	}
@@ -87,10 +274,107 @@ func (s *taskServiceImpl) Update(ctx context.Context, task models.Task) (models.
 		task.Due = existingTask.Due
 	}
 
-	updatedTask, err := s.repo.Update(ctx, &task)
+	if task.Priority == "" {
+		task.Priority = existingTask.Priority
+	}
+
+	if task.EstimateMinutes == 0 {
+		task.EstimateMinutes = existingTask.EstimateMinutes
+	}
+
+	if task.ProjectID == 0 {
+		task.ProjectID = existingTask.ProjectID
+	}
+
+	if task.Recurrence == "" {
+		task.Recurrence = existingTask.Recurrence
+	}
+
+	// В пределах проекта пришедшие поля накладываются на сохранённые, null удаляет поле
+	if task.ProjectID == existingTask.ProjectID {
+		task.CustomFields = mergeCustomFields(existingTask.CustomFields, task.CustomFields)
+	}
+
+	if err := validatePlanning(task); err != nil {
+		return models.Task{}, err
+	}
+
+	recurrence, err := NormalizeRecurrence(task.Recurrence)
 	if err != nil {
 		return models.Task{}, err
 	}
 
-	return *updatedTask, nil
+	task.Recurrence = recurrence
+
+	// Перенести задачу в другой проект может только его участник
+	if task.ProjectID != existingTask.ProjectID {
+		userID, _ := UserIDFromContext(ctx)
+		if err := s.projects.CheckMember(ctx, task.ProjectID, userID); err != nil {
+			return models.Task{}, err
+		}
+	}
+
+	customFields, err := s.projects.ValidateCustomFields(ctx, task.ProjectID, task.CustomFields)
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	task.CustomFields = customFields
+
+	return task, nil
+}
+
+// validatePlanning проверяет приоритет и оценку задачи.
+func validatePlanning(task models.Task) error {
+	if !validPriority(task.Priority) {
+		return fmt.Errorf("priority must be one of: %s", strings.Join(models.Priorities, ", "))
+	}
+
+	if task.EstimateMinutes < 0 {
+		return errors.New("estimate cannot be negative")
+	}
+
+	return nil
+}
+
+func validPriority(priority string) bool {
+	for _, p := range models.Priorities {
+		if p == priority {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mergeCustomFields(existing, incoming models.CustomValues) models.CustomValues {
+	if incoming == nil {
+		return existing
+	}
+
+	merged := make(models.CustomValues, len(existing)+len(incoming))
+	for name, value := range existing {
+		merged[name] = value
+	}
+
+	for name, value := range incoming {
+		if value == nil {
+			delete(merged, name)
+			continue
+		}
+
+		merged[name] = value
+	}
+
+	return merged
+}
+
+// checkTaskExists возвращает ErrTaskNotFound, если задачи с таким ID нет.
+func checkTaskExists(ctx context.Context, repo repositories.TaskRepository, taskID int) error {
+	_, err := repo.GetByID(ctx, taskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound
+	}
+
+	return err
 }
@@ -4,7 +4,12 @@ import (
 	"WebTasks/internal/models"
 	"context"
 	"errors"
+	"net/url"
+	"testing"
 	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type TaskRepository interface {
@@ -107,3 +112,161 @@ func (s *taskService) Update(ctx context.Context, newData models.Task) (models.T
 func (s *taskService) Delete(ctx context.Context, id int) error {
 	return s.repo.Delete(ctx, id)
 }
+
+func TestTaskService_Create_Planning(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+
+	// Приоритет по умолчанию - medium
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(task *models.Task) bool {
+		return task.Priority == models.PriorityMedium && task.EstimateMinutes == 30
+	})).Return(&models.Task{ID: 1, Name: "Task", Priority: models.PriorityMedium, EstimateMinutes: 30}, nil)
+
+	created, err := service.Create(ctx, models.Task{Name: "Task", EstimateMinutes: 30})
+	require.NoError(t, err)
+	require.Equal(t, models.PriorityMedium, created.Priority)
+
+	_, err = service.Create(ctx, models.Task{Name: "Task", Priority: "critical"})
+	require.EqualError(t, err, "priority must be one of: low, medium, high, urgent")
+
+	_, err = service.Create(ctx, models.Task{Name: "Task", EstimateMinutes: -5})
+	require.EqualError(t, err, "estimate cannot be negative")
+
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestTaskService_ProjectMembership(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, projects, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+	outsider := WithUserID(ctx, 9)
+	values := models.CustomValues{"client": "Acme"}
+
+	projects.On("IsMember", mock.Anything, 1, 9).Return(false, nil)
+
+	// Поле user_id задаёт клиент, поэтому участие определяется только по автору запроса
+	_, err := service.Create(outsider, models.Task{Name: "Task", UserID: 7, ProjectID: 1, CustomFields: values})
+	require.ErrorIs(t, err, ErrNotProjectMember)
+
+	_, err = service.Create(ctx, models.Task{Name: "Task", ProjectID: 1, CustomFields: values})
+	require.ErrorIs(t, err, ErrNotProjectMember)
+
+	// Перенести свою задачу в чужой проект тоже нельзя
+	mockRepo.On("GetByID", mock.Anything, 5).Return(&models.Task{ID: 5, Name: "Task", Priority: models.PriorityLow}, nil)
+
+	_, err = service.Update(outsider, models.Task{ID: 5, Name: "Task", ProjectID: 1, CustomFields: values})
+	require.ErrorIs(t, err, ErrNotProjectMember)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.ProjectID == 1 && task.CreatedBy == testProjectMember
+	})).Return(&models.Task{ID: 6, ProjectID: 1}, nil)
+
+	_, err = service.Create(WithUserID(ctx, testProjectMember), models.Task{Name: "Task", ProjectID: 1, CustomFields: values})
+	require.NoError(t, err)
+}
+
+func TestTaskService_Update_KeepsPlanning(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+	existing := &models.Task{ID: 1, Name: "Task", Status: "Pending", Priority: models.PriorityHigh, EstimateMinutes: 90}
+
+	mockRepo.On("GetByID", ctx, 1).Return(existing, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(task *models.Task) bool {
+		return task.Priority == models.PriorityHigh && task.EstimateMinutes == 90 && task.Name == "Renamed"
+	})).Return(&models.Task{ID: 1, Name: "Renamed", Priority: models.PriorityHigh, EstimateMinutes: 90}, nil)
+
+	updated, err := service.Update(ctx, models.Task{ID: 1, Name: "Renamed"})
+	require.NoError(t, err)
+	require.Equal(t, 90, updated.EstimateMinutes)
+
+	_, err = service.Update(ctx, models.Task{ID: 1, Name: "Renamed", Priority: "someday"})
+	require.Error(t, err)
+}
+
+func TestTaskService_CustomFields(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := WithUserID(context.Background(), testProjectMember)
+
+	// Обязательное поле client не заполнено
+	_, err := service.Create(ctx, models.Task{Name: "Task", ProjectID: 1, CustomFields: models.CustomValues{"points": float64(3)}})
+	require.ErrorIs(t, err, ErrInvalidCustomField)
+
+	existing := &models.Task{
+		ID: 1, Name: "Task", Priority: models.PriorityLow, ProjectID: 1,
+		CustomFields: models.CustomValues{"client": "Acme", "points": float64(3), "stage": "design"},
+	}
+
+	// Пришедшие значения накладываются на сохранённые, null удаляет поле
+	mockRepo.On("GetByID", ctx, 1).Return(existing, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(task *models.Task) bool {
+		_, hasStage := task.CustomFields["stage"]
+		return task.ProjectID == 1 && task.CustomFields["client"] == "Acme" &&
+			task.CustomFields["points"] == float64(8) && !hasStage
+	})).Return(&models.Task{ID: 1}, nil)
+
+	_, err = service.Update(ctx, models.Task{ID: 1, Name: "Task", CustomFields: models.CustomValues{"points": float64(8), "stage": nil}})
+	require.NoError(t, err)
+
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestTaskService_List(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+
+	expected := models.TaskFilter{
+		ProjectID:   1,
+		Custom:      map[string]string{"stage": "build"},
+		SortBy:      "cf.points",
+		SortNumeric: true,
+	}
+	mockRepo.On("List", ctx, expected).Return([]models.Task{{ID: 1}}, nil)
+
+	tasks, err := service.List(ctx, models.TaskFilter{ProjectID: 1, Custom: map[string]string{"stage": "build"}, SortBy: "cf.points"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	_, err = service.List(ctx, models.TaskFilter{ProjectID: 1, SortBy: "cf.budget"})
+	require.ErrorIs(t, err, ErrInvalidTaskFilter)
+
+	_, err = service.List(ctx, models.TaskFilter{Custom: map[string]string{"Bad Name": "x"}})
+	require.ErrorIs(t, err, ErrInvalidTaskFilter)
+
+	_, err = service.List(ctx, models.TaskFilter{Limit: -1})
+	require.ErrorIs(t, err, ErrInvalidTaskFilter)
+}
+
+func TestParseTaskFilter_Members(t *testing.T) {
+	filter, err := ParseTaskFilter(url.Values{"assignee": {"me"}, "watcher": {"3"}})
+	require.NoError(t, err)
+	require.Equal(t, models.TaskFilter{AssigneeID: models.CurrentUser, WatcherID: 3}, filter)
+	require.Equal(t, 7, filter.ForUser(7).AssigneeID)
+
+	// Отрицательный ID не должен совпасть с внутренним значением "me"
+	for _, query := range []url.Values{
+		{"assignee": {"-1"}},
+		{"watcher": {"-1"}},
+		{"user_id": {"-5"}},
+		{"project_id": {"-2"}},
+	} {
+		_, err := ParseTaskFilter(query)
+		require.ErrorIs(t, err, ErrInvalidTaskFilter, query.Encode())
+	}
+}
@@ -0,0 +1,391 @@
+package services
+
+import (
+	"WebTasks/internal/metrics"
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxImportRows = 5000
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// importColumns - поля задачи, которые можно загрузить из CSV, кроме cf.<поле>.
+var importColumns = map[string]bool{
+	"name": true, "status": true, "time": true, "due": true, "priority": true,
+	"estimate_minutes": true, "project_id": true, "tags": true, "custom_fields": true,
+}
+
+// importDateLayouts - форматы дат, которые принимаются в колонках time и due.
+var importDateLayouts = []string{time.RFC3339, time.DateTime, "2006-01-02 15:04", time.DateOnly}
+
+// Export передаёт задачи по фильтру в fn по мере чтения из базы. Ошибки фильтра
+// возвращаются до первого вызова fn.
+func (s *taskServiceImpl) Export(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) error {
+	filter, err := s.checkFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Stream(ctx, filter, fn)
+}
+
+// Import проверяет строки файла правилами Create и, если ошибок нет и это не пробный
+// запуск, создаёт все задачи одной транзакцией от имени options.UserID.
+func (s *taskServiceImpl) Import(ctx context.Context, options models.ImportOptions, body io.Reader) (models.ImportReport, error) {
+	report := models.ImportReport{DryRun: options.DryRun, Errors: []models.ImportRowError{}}
+
+	var (
+		rows []importRow
+		err  error
+	)
+
+	switch options.Format {
+	case models.TransferCSV:
+		rows, err = s.readImportCSV(ctx, body, options.Mapping)
+	case models.TransferJSON:
+		rows, err = readImportJSON(body)
+	case models.TransferICS:
+		rows, err = readImportICS(body)
+	default:
+		return report, fmt.Errorf("%w: format must be %s, %s or %s", ErrInvalidImport,
+			models.TransferCSV, models.TransferJSON, models.TransferICS)
+	}
+
+	if err != nil {
+		return report, err
+	}
+
+	report.Total = len(rows)
+	changes := make([]models.TaskChange, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	quota := s.newTaskQuota()
+	now := s.now()
+
+	for _, row := range rows {
+		if row.err != nil {
+			report.Errors = append(report.Errors, *row.err)
+			continue
+		}
+
+		task := row.task.Task
+		task.ID = 0
+		task.UserID = options.UserID
+
+		if task.Time.IsZero() {
+			task.Time = now
+		}
+
+		tags, err := NormalizeTags(row.task.Tags)
+		if err == nil {
+			task, err = s.prepareCreate(ctx, task)
+		}
+
+		if err == nil {
+			err = quota.reserve(ctx, task)
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, models.ImportRowError{Row: row.line, Error: err.Error()})
+			continue
+		}
+
+		changes = append(changes, models.TaskChange{Op: models.BulkCreate, Task: &task, Tags: tags})
+		lines = append(lines, row.line)
+	}
+
+	if len(report.Errors) > 0 || options.DryRun || len(changes) == 0 {
+		return report, nil
+	}
+
+	created, err := s.repo.ApplyBulk(ctx, changes)
+	if err != nil {
+		var bulkErr *repositories.BulkError
+		if !errors.As(err, &bulkErr) {
+			return report, err
+		}
+
+		report.Errors = append(report.Errors, models.ImportRowError{Row: lines[bulkErr.Index], Error: bulkErrorMessage(bulkErr.Err)})
+
+		return report, nil
+	}
+
+	for _, task := range created {
+		report.Tasks = append(report.Tasks, *task)
+	}
+
+	report.Imported = len(created)
+
+	metrics.TasksCreated(metrics.SourceImport, len(created))
+
+	return report, nil
+}
+
+// importRow - разобранная строка файла или ошибка её разбора.
+type importRow struct {
+	line int
+	task models.ImportTask
+	err  *models.ImportRowError
+}
+
+// readImportJSON читает массив задач. Неизвестные поля и данные после массива - ошибка,
+// как и в телах запросов API: опечатка в имени поля не должна молча терять значение.
+func readImportJSON(body io.Reader) ([]importRow, error) {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	var tasks []models.ImportTask
+	if err := decoder.Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("%w: expected a JSON array of tasks: %w", ErrInvalidImport, err)
+	}
+
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		if err == nil {
+			err = errors.New("unexpected data after the array")
+		}
+
+		return nil, fmt.Errorf("%w: expected a JSON array of tasks: %w", ErrInvalidImport, err)
+	}
+
+	if len(tasks) > maxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows per import", ErrInvalidImport, maxImportRows)
+	}
+
+	rows := make([]importRow, len(tasks))
+	for i, task := range tasks {
+		rows[i] = importRow{line: i + 1, task: task}
+	}
+
+	return rows, nil
+}
+
+func (s *taskServiceImpl) readImportCSV(ctx context.Context, body io.Reader, mapping map[string]string) ([]importRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read CSV header", ErrInvalidImport)
+	}
+
+	columns, err := mapImportColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := customFieldTypes{fields: s.projects, types: make(map[int]map[string]string)}
+
+	var rows []importRow
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("%w: at most %d rows per import", ErrInvalidImport, maxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		task, rowErr := parseImportRecord(ctx, record, columns, header, &fields)
+		if rowErr != nil {
+			rowErr.Row = line
+		}
+
+		rows = append(rows, importRow{line: line, task: task, err: rowErr})
+	}
+
+	return rows, nil
+}
+
+// mapImportColumns возвращает номер колонки CSV для каждого загружаемого поля.
+// Колонки без сопоставления игнорируются.
+func mapImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	if len(header) > 0 {
+		// Excel сохраняет CSV в UTF-8 с BOM
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int)
+
+	for field, column := range mapping {
+		if !isImportField(field) {
+			return nil, fmt.Errorf("%w: unknown field %q in mapping", ErrInvalidImport, field)
+		}
+
+		position, ok := positions[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("%w: column %q not found", ErrInvalidImport, column)
+		}
+
+		columns[field] = position
+	}
+
+	for name, position := range positions {
+		if _, mapped := columns[name]; !mapped && isImportField(name) {
+			columns[name] = position
+		}
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("%w: name column is required", ErrInvalidImport)
+	}
+
+	return columns, nil
+}
+
+func isImportField(field string) bool {
+	if name, ok := strings.CutPrefix(field, repositories.CustomFieldPrefix); ok {
+		return customFieldName.MatchString(name)
+	}
+
+	return importColumns[field]
+}
+
+func parseImportRecord(
+	ctx context.Context,
+	record []string,
+	columns map[string]int,
+	header []string,
+	fields *customFieldTypes,
+) (models.ImportTask, *models.ImportRowError) {
+	var task models.ImportTask
+
+	cell := func(field string) string {
+		if position, ok := columns[field]; ok && position < len(record) {
+			return strings.TrimSpace(record[position])
+		}
+
+		return ""
+	}
+
+	invalid := func(field, expected string) *models.ImportRowError {
+		return &models.ImportRowError{Column: header[columns[field]], Error: "expected " + expected}
+	}
+
+	task.Name = cell("name")
+	task.Status = cell("status")
+	task.Priority = cell("priority")
+
+	for field, target := range map[string]*time.Time{"time": &task.Time, "due": &task.Due} {
+		if value := cell(field); value != "" {
+			parsed, ok := parseImportDate(value)
+			if !ok {
+				return task, invalid(field, "a date (YYYY-MM-DD or RFC 3339)")
+			}
+
+			*target = parsed
+		}
+	}
+
+	for field, target := range map[string]*int{"estimate_minutes": &task.EstimateMinutes, "project_id": &task.ProjectID} {
+		if value := cell(field); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return task, invalid(field, "an integer")
+			}
+
+			*target = parsed
+		}
+	}
+
+	if value := cell("tags"); value != "" {
+		task.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+	}
+
+	if value := cell("custom_fields"); value != "" {
+		if err := json.Unmarshal([]byte(value), &task.CustomFields); err != nil {
+			return task, invalid("custom_fields", "a JSON object")
+		}
+	}
+
+	for field := range columns {
+		name, ok := strings.CutPrefix(field, repositories.CustomFieldPrefix)
+		if !ok || cell(field) == "" {
+			continue
+		}
+
+		value, err := fields.convert(ctx, task.ProjectID, name, cell(field))
+		if err != nil {
+			return task, &models.ImportRowError{Column: header[columns[field]], Error: err.Error()}
+		}
+
+		if task.CustomFields == nil {
+			task.CustomFields = make(models.CustomValues)
+		}
+
+		task.CustomFields[name] = value
+	}
+
+	return task, nil
+}
+
+func parseImportDate(value string) (time.Time, bool) {
+	for _, layout := range importDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// customFieldTypes кэширует типы пользовательских полей проектов на время загрузки.
+type customFieldTypes struct {
+	fields CustomFieldValidator
+	types  map[int]map[string]string
+}
+
+// convert приводит текст ячейки к типу поля: числа и пользователи становятся числами,
+// остальное проверит ValidateCustomFields.
+func (c *customFieldTypes) convert(ctx context.Context, projectID int, name, text string) (interface{}, error) {
+	if projectID == 0 {
+		return text, nil
+	}
+
+	types, ok := c.types[projectID]
+	if !ok {
+		fields, err := c.fields.GetFields(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		types = make(map[string]string, len(fields))
+		for _, field := range fields {
+			types[field.Name] = field.Type
+		}
+
+		c.types[projectID] = types
+	}
+
+	if types[name] != models.FieldTypeNumber && types[name] != models.FieldTypeUser {
+		return text, nil
+	}
+
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, errors.New("expected a number")
+	}
+
+	return number, nil
+}
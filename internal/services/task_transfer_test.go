@@ -0,0 +1,133 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importCSV = "\ufeffTitle,State,Deadline,Project,Tags,Points,Client,Extra\n" +
+	"Deploy,Pending,2099-01-02,1,ops;release,5,Acme,x\n"
+
+func TestTaskService_Import_CSV(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := WithUserID(context.Background(), testProjectMember)
+	options := models.ImportOptions{
+		Format: models.TransferCSV,
+		UserID: 7,
+		Mapping: map[string]string{
+			"name": "Title", "status": "State", "due": "Deadline", "project_id": "Project",
+			"cf.points": "Points", "cf.client": "Client",
+		},
+	}
+
+	// Ошибки в строках отменяют загрузку целиком
+	withErrors := importCSV +
+		",Pending,,,,,,\n" +
+		"Review,Pending,tomorrow,,,,,\n" +
+		"Estimate,Pending,,1,,many,Acme,\n"
+
+	report, err := service.Import(ctx, options, strings.NewReader(withErrors))
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Total)
+	require.Equal(t, []models.ImportRowError{
+		{Row: 3, Error: "task name is required"},
+		{Row: 4, Column: "Deadline", Error: "expected a date (YYYY-MM-DD or RFC 3339)"},
+		{Row: 5, Column: "Points", Error: "expected a number"},
+	}, report.Errors)
+
+	// Пробный запуск только проверяет строки
+	options.DryRun = true
+
+	report, err = service.Import(ctx, options, strings.NewReader(importCSV))
+	require.NoError(t, err)
+	require.Empty(t, report.Errors)
+	require.Zero(t, report.Imported)
+
+	options.DryRun = false
+
+	mockRepo.On("ApplyBulk", ctx, mock.MatchedBy(func(changes []models.TaskChange) bool {
+		task := changes[0].Task
+		return len(changes) == 1 && task.UserID == 7 && !task.Time.IsZero() && task.ProjectID == 1 &&
+			task.CustomFields["points"] == float64(5) && task.CustomFields["client"] == "Acme" &&
+			strings.Join(changes[0].Tags, ",") == "ops,release"
+	})).Return([]*models.Task{{ID: 10, Name: "Deploy"}}, nil).Once()
+
+	report, err = service.Import(ctx, options, strings.NewReader(importCSV))
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Imported)
+	require.Equal(t, 10, report.Tasks[0].ID)
+
+	mockRepo.AssertNumberOfCalls(t, "ApplyBulk", 1)
+}
+
+func TestTaskService_Import_JSON(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+	options := models.ImportOptions{Format: models.TransferJSON, UserID: 7}
+
+	report, err := service.Import(ctx, options, strings.NewReader(`[{"name":"A","tags":["x"]},{"name":"B","priority":"someday"}]`))
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Total)
+	require.Len(t, report.Errors, 1)
+	require.Equal(t, 2, report.Errors[0].Row)
+
+	mockRepo.AssertNotCalled(t, "ApplyBulk", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Import_Invalid(t *testing.T) {
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(new(MockTaskRepository), fields, nil)
+
+	ctx := context.Background()
+
+	requests := []struct {
+		options models.ImportOptions
+		body    string
+	}{
+		{models.ImportOptions{Format: "xml"}, ""},
+		{models.ImportOptions{Format: models.TransferJSON}, `{"name":"A"}`},
+		{models.ImportOptions{Format: models.TransferJSON}, `[{"name":"A","stauts":"Done"}]`},
+		{models.ImportOptions{Format: models.TransferJSON}, `[{"name":"A"}] [{"name":"B"}]`},
+		{models.ImportOptions{Format: models.TransferCSV}, "title,status\nA,Pending\n"},
+		{models.ImportOptions{Format: models.TransferCSV, Mapping: map[string]string{"name": "Summary"}}, "title\nA\n"},
+		{models.ImportOptions{Format: models.TransferCSV, Mapping: map[string]string{"owner": "title"}}, "title\nA\n"},
+	}
+
+	for _, request := range requests {
+		_, err := service.Import(ctx, request.options, strings.NewReader(request.body))
+		require.ErrorIs(t, err, ErrInvalidImport, request.body)
+	}
+}
+
+func TestTaskService_Export(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	fields, _, _ := newTestProjectService()
+	service := NewTaskService(mockRepo, fields, nil)
+
+	ctx := context.Background()
+
+	mockRepo.On("Stream", ctx, models.TaskFilter{UserID: 7}, mock.Anything).
+		Return([]models.Task{{ID: 1}, {ID: 2}}, nil)
+
+	var ids []int
+	err := service.Export(ctx, models.TaskFilter{UserID: 7}, func(task models.Task) error {
+		ids = append(ids, task.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, ids)
+
+	err = service.Export(ctx, models.TaskFilter{UserID: 7, Limit: -1}, func(models.Task) error { return nil })
+	require.ErrorIs(t, err, ErrInvalidTaskFilter)
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultTemplateStatus = "Pending"
+	maxTemplateNameLength = 100
+	maxTemplateDepth      = 5
+	maxTemplateTasks      = 100
+	maxChecklistItemLen   = 500
+)
+
+var (
+	ErrTemplateNotFound  = errors.New("template not found")
+	ErrTemplateExists    = errors.New("template already exists")
+	ErrInvalidTemplate   = errors.New("invalid template")
+	ErrTemplateVariables = errors.New("cannot instantiate template")
+)
+
+// templatePlaceholderRe находит подстановки {{name}} в названиях и пунктах чек-листа.
+var templatePlaceholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+type TemplateService interface {
+	Create(ctx context.Context, template models.TaskTemplate) (models.TaskTemplate, error)
+	GetByID(ctx context.Context, id int) (models.TaskTemplate, error)
+	GetAll(ctx context.Context) ([]models.TaskTemplate, error)
+	Delete(ctx context.Context, id int) error
+	Instantiate(ctx context.Context, id int, params models.TemplateInstantiation) ([]models.Task, error)
+}
+
+type templateServiceImpl struct {
+	repo  repositories.TemplateRepository
+	quota TaskQuotaChecker
+	now   func() time.Time
+}
+
+// NewTemplateService создаёт сервис шаблонов. quota может быть nil - тогда квота
+// задач при создании задач по шаблону не проверяется.
+func NewTemplateService(repo repositories.TemplateRepository, quota TaskQuotaChecker) TemplateService {
+	return &templateServiceImpl{repo: repo, quota: quota, now: time.Now}
+}
+
+func (s *templateServiceImpl) Create(ctx context.Context, template models.TaskTemplate) (models.TaskTemplate, error) {
+	template.Name = strings.TrimSpace(template.Name)
+
+	if template.Name == "" {
+		return models.TaskTemplate{}, fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+
+	if utf8.RuneCountInString(template.Name) > maxTemplateNameLength {
+		return models.TaskTemplate{}, fmt.Errorf("%w: name is too long", ErrInvalidTemplate)
+	}
+
+	count := 0
+	if err := normalizeTemplateTask(&template.Task, 1, &count); err != nil {
+		return models.TaskTemplate{}, err
+	}
+
+	created, err := s.repo.Create(ctx, &template)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return models.TaskTemplate{}, ErrTemplateExists
+	}
+
+	if err != nil {
+		return models.TaskTemplate{}, err
+	}
+
+	return *created, nil
+}
+
+func (s *templateServiceImpl) GetByID(ctx context.Context, id int) (models.TaskTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TaskTemplate{}, ErrTemplateNotFound
+	}
+
+	if err != nil {
+		return models.TaskTemplate{}, err
+	}
+
+	return *template, nil
+}
+
+func (s *templateServiceImpl) GetAll(ctx context.Context) ([]models.TaskTemplate, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *templateServiceImpl) Delete(ctx context.Context, id int) error {
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTemplateNotFound
+	}
+
+	return err
+}
+
+// Instantiate создаёт задачи по шаблону. Кроме переданных переменных доступна {{date}} -
+// дата отсчёта сроков в формате 2006-01-02.
+func (s *templateServiceImpl) Instantiate(
+	ctx context.Context,
+	id int,
+	params models.TemplateInstantiation,
+) ([]models.Task, error) {
+	template, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+
+	start := params.Start
+	if start.IsZero() {
+		start = now
+	}
+
+	variables := map[string]string{"date": start.Format(time.DateOnly)}
+	for name, value := range params.Variables {
+		variables[name] = value
+	}
+
+	builder := taskTreeBuilder{variables: variables, start: start, now: now, userID: params.UserID}
+
+	tree := builder.build(template.Task, defaultTemplateStatus)
+	if len(builder.missing) > 0 {
+		return nil, fmt.Errorf("%w: missing variables: %s", ErrTemplateVariables, strings.Join(builder.missingNames(), ", "))
+	}
+
+	if builder.err != nil {
+		return nil, builder.err
+	}
+
+	if s.quota != nil {
+		if err := s.quota.ReserveTasks(ctx, treeTasks([]models.TaskTree{tree})); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Instantiate(ctx, &tree)
+}
+
+// taskTreeBuilder превращает задачу шаблона в дерево задач с подставленными переменными.
+// Недостающие переменные собираются целиком, чтобы сообщить обо всех сразу.
+type taskTreeBuilder struct {
+	variables map[string]string
+	start     time.Time
+	now       time.Time
+	userID    int
+	missing   map[string]struct{}
+	err       error
+}
+
+func (b *taskTreeBuilder) build(item models.TemplateTask, parentStatus string) models.TaskTree {
+	status := item.Status
+	if status == "" {
+		status = parentStatus
+	}
+
+	priority := item.Priority
+	if priority == "" {
+		priority = models.PriorityMedium
+	}
+
+	task := models.Task{
+		Name:      b.substitute(item.Name),
+		Status:    status,
+		Time:      b.now,
+		UserID:    b.userID,
+		Priority:  priority,
+		CreatedBy: b.userID,
+	}
+
+	if len(task.Name) > 50 && b.err == nil {
+		b.err = fmt.Errorf("%w: task name %q is too long", ErrTemplateVariables, task.Name)
+	}
+
+	// Смещение проверено при сохранении шаблона
+	if offset, _ := parseDueOffset(item.DueOffset); offset > 0 {
+		task.Due = b.start.Add(offset)
+	}
+
+	tree := models.TaskTree{Task: task, Tags: item.Tags}
+
+	for _, text := range item.Checklist {
+		tree.Checklist = append(tree.Checklist, b.substitute(text))
+	}
+
+	for _, subtask := range item.Subtasks {
+		tree.Subtasks = append(tree.Subtasks, b.build(subtask, status))
+	}
+
+	return tree
+}
+
+func (b *taskTreeBuilder) substitute(text string) string {
+	return templatePlaceholderRe.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templatePlaceholderRe.FindStringSubmatch(placeholder)[1]
+
+		value, ok := b.variables[name]
+		if !ok {
+			if b.missing == nil {
+				b.missing = make(map[string]struct{})
+			}
+
+			b.missing[name] = struct{}{}
+		}
+
+		return value
+	})
+}
+
+func (b *taskTreeBuilder) missingNames() []string {
+	names := make([]string, 0, len(b.missing))
+	for name := range b.missing {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// normalizeTemplateTask проверяет задачу шаблона и её подзадачи, нормализуя теги.
+func normalizeTemplateTask(item *models.TemplateTask, depth int, count *int) error {
+	*count++
+
+	if *count > maxTemplateTasks {
+		return fmt.Errorf("%w: at most %d tasks per template", ErrInvalidTemplate, maxTemplateTasks)
+	}
+
+	if depth > maxTemplateDepth {
+		return fmt.Errorf("%w: subtasks can be nested at most %d levels deep", ErrInvalidTemplate, maxTemplateDepth)
+	}
+
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return fmt.Errorf("%w: task name is required", ErrInvalidTemplate)
+	}
+
+	if len(item.Status) > 50 {
+		return fmt.Errorf("%w: status is too long", ErrInvalidTemplate)
+	}
+
+	if item.Priority != "" && !validPriority(item.Priority) {
+		return fmt.Errorf("%w: priority must be one of: %s", ErrInvalidTemplate, strings.Join(models.Priorities, ", "))
+	}
+
+	if _, err := parseDueOffset(item.DueOffset); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	tags, err := NormalizeTags(item.Tags)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	item.Tags = tags
+
+	for i, text := range item.Checklist {
+		text = strings.TrimSpace(text)
+		if text == "" || utf8.RuneCountInString(text) > maxChecklistItemLen {
+			return fmt.Errorf("%w: checklist items must be 1-%d characters", ErrInvalidTemplate, maxChecklistItemLen)
+		}
+
+		item.Checklist[i] = text
+	}
+
+	for i := range item.Subtasks {
+		if err := normalizeTemplateTask(&item.Subtasks[i], depth+1, count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseDueOffset разбирает смещение срока: необязательные дни ("3d") и остаток
+// в формате time.ParseDuration ("4h30m").
+func parseDueOffset(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	rest := value
+
+	var days int
+
+	if i := strings.IndexByte(rest, 'd'); i >= 0 {
+		parsed, err := strconv.Atoi(rest[:i])
+		if err != nil || parsed < 0 {
+			return 0, fmt.Errorf("invalid due offset %q", value)
+		}
+
+		days = parsed
+		rest = rest[i+1:]
+	}
+
+	var duration time.Duration
+
+	if rest != "" {
+		parsed, err := time.ParseDuration(rest)
+		if err != nil || parsed < 0 {
+			return 0, fmt.Errorf("invalid due offset %q", value)
+		}
+
+		duration = parsed
+	}
+
+	return time.Duration(days)*24*time.Hour + duration, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTemplateRepository реализует методы TemplateRepository для тестов.
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) Create(ctx context.Context, template *models.TaskTemplate) (*models.TaskTemplate, error) {
+	args := m.Called(ctx, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetByID(ctx context.Context, id int) (*models.TaskTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetAll(ctx context.Context) ([]models.TaskTemplate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) Delete(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockTemplateRepository) Instantiate(ctx context.Context, tree *models.TaskTree) ([]models.Task, error) {
+	args := m.Called(ctx, tree)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+var onboardingTemplate = models.TaskTemplate{
+	ID:   1,
+	Name: "Onboarding",
+	Task: models.TemplateTask{
+		Name:      "Onboard {{employee}}",
+		DueOffset: "5d",
+		Tags:      []string{"hr"},
+		Subtasks: []models.TemplateTask{
+			{Name: "Laptop for {{ employee }}", DueOffset: "1d", Checklist: []string{"Order on {{date}}", "Install VPN"}},
+			{Name: "Accounts", Status: "Blocked", Priority: models.PriorityHigh, Subtasks: []models.TemplateTask{{Name: "Mail"}}},
+		},
+	},
+}
+
+func TestParseDueOffset(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":      0,
+		"3d":    72 * time.Hour,
+		"1d12h": 36 * time.Hour,
+		"90m":   90 * time.Minute,
+	}
+
+	for value, expected := range cases {
+		offset, err := parseDueOffset(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, offset, value)
+	}
+
+	for _, value := range []string{"-1d", "xd", "1w", "-2h"} {
+		_, err := parseDueOffset(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestTemplateService_Create(t *testing.T) {
+	repo := new(MockTemplateRepository)
+	service := NewTemplateService(repo, nil)
+	ctx := context.Background()
+
+	invalid := []models.TaskTemplate{
+		{Name: "", Task: models.TemplateTask{Name: "Task"}},
+		{Name: "No task name"},
+		{Name: "Bad offset", Task: models.TemplateTask{Name: "Task", DueOffset: "soon"}},
+		{Name: "Bad priority", Task: models.TemplateTask{Name: "Task", Priority: "asap"}},
+		{Name: "Bad subtask", Task: models.TemplateTask{Name: "Task", Subtasks: []models.TemplateTask{{Name: " "}}}},
+		{Name: "Bad checklist", Task: models.TemplateTask{Name: "Task", Checklist: []string{""}}},
+	}
+
+	for _, template := range invalid {
+		_, err := service.Create(ctx, template)
+		require.ErrorIs(t, err, ErrInvalidTemplate, template.Name)
+	}
+
+	repo.On("Create", ctx, mock.MatchedBy(func(tpl *models.TaskTemplate) bool {
+		return tpl.Task.Tags[0] == "deploy" && tpl.Task.Checklist[0] == "Run migrations"
+	})).Return(&models.TaskTemplate{ID: 2, Name: "Deploy"}, nil)
+
+	created, err := service.Create(ctx, models.TaskTemplate{
+		Name: " Deploy ",
+		Task: models.TemplateTask{Name: "Deploy {{version}}", Tags: []string{"#Deploy"}, Checklist: []string{" Run migrations "}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, created.ID)
+}
+
+func TestTemplateService_Instantiate(t *testing.T) {
+	repo := new(MockTemplateRepository)
+	service := NewTemplateService(repo, nil).(*templateServiceImpl)
+
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	template := onboardingTemplate
+
+	repo.On("GetByID", ctx, 1).Return(&template, nil)
+	repo.On("GetByID", ctx, 2).Return(nil, sql.ErrNoRows)
+
+	var tree *models.TaskTree
+
+	repo.On("Instantiate", ctx, mock.AnythingOfType("*models.TaskTree")).
+		Run(func(args mock.Arguments) { tree = args.Get(1).(*models.TaskTree) }).
+		Return([]models.Task{{ID: 10}, {ID: 11}, {ID: 12}, {ID: 13}}, nil)
+
+	tasks, err := service.Instantiate(ctx, 1, models.TemplateInstantiation{
+		Variables: map[string]string{"employee": "Anna"},
+		UserID:    7,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 4)
+
+	require.Equal(t, "Onboard Anna", tree.Task.Name)
+	require.Equal(t, "Pending", tree.Task.Status)
+	require.Equal(t, 7, tree.Task.UserID)
+	require.Equal(t, now.AddDate(0, 0, 5), tree.Task.Due)
+	require.Equal(t, []string{"hr"}, tree.Tags)
+
+	laptop := tree.Subtasks[0]
+	require.Equal(t, "Laptop for Anna", laptop.Task.Name)
+	require.Equal(t, []string{"Order on 2024-03-01", "Install VPN"}, laptop.Checklist)
+
+	// Статус наследуется от ближайшего родителя, у которого он задан
+	accounts := tree.Subtasks[1]
+	require.Equal(t, "Blocked", accounts.Subtasks[0].Task.Status)
+	require.Equal(t, models.PriorityMedium, accounts.Subtasks[0].Task.Priority)
+	require.True(t, accounts.Task.Due.IsZero())
+
+	_, err = service.Instantiate(ctx, 1, models.TemplateInstantiation{})
+	require.ErrorIs(t, err, ErrTemplateVariables)
+	require.Contains(t, err.Error(), "employee")
+
+	_, err = service.Instantiate(ctx, 2, models.TemplateInstantiation{})
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+
+	repo.AssertNumberOfCalls(t, "Instantiate", 1)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrTimerRunning      = errors.New("a timer is already running")
+	ErrNoRunningTimer    = errors.New("no running timer")
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrInvalidTimeEntry  = errors.New("invalid time entry")
+	ErrInvalidReport     = errors.New("invalid report parameters")
+)
+
+// ReportGroupings - допустимые группировки отчёта по времени.
+var ReportGroupings = []string{"user", "task", "tag", "date"}
+
+type TimeTrackingService interface {
+	StartTimer(ctx context.Context, userID, taskID int, note string) (models.TimeEntry, error)
+	StopTimer(ctx context.Context, userID int) (models.TimeEntry, error)
+	GetRunning(ctx context.Context, userID int) (models.TimeEntry, error)
+	LogWork(ctx context.Context, entry models.TimeEntry) (models.TimeEntry, error)
+	GetByTask(ctx context.Context, taskID int) ([]models.TimeEntry, error)
+	Delete(ctx context.Context, userID, id int) error
+	Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error)
+}
+
+type timeTrackingServiceImpl struct {
+	repo  repositories.TimeEntryRepository
+	tasks repositories.TaskRepository
+	now   func() time.Time
+}
+
+func NewTimeTrackingService(repo repositories.TimeEntryRepository, tasks repositories.TaskRepository) TimeTrackingService {
+	return &timeTrackingServiceImpl{repo: repo, tasks: tasks, now: time.Now}
+}
+
+func (s *timeTrackingServiceImpl) StartTimer(ctx context.Context, userID, taskID int, note string) (models.TimeEntry, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	_, err := s.repo.GetRunning(ctx, userID)
+	if err == nil {
+		return models.TimeEntry{}, ErrTimerRunning
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.TimeEntry{}, err
+	}
+
+	created, err := s.repo.Create(ctx, &models.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: s.now(),
+		Note:      note,
+	})
+
+	// Параллельный запуск второго таймера отсекается уникальным индексом
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return models.TimeEntry{}, ErrTimerRunning
+	}
+
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	return *created, nil
+}
+
+func (s *timeTrackingServiceImpl) StopTimer(ctx context.Context, userID int) (models.TimeEntry, error) {
+	stopped, err := s.repo.Stop(ctx, userID, s.now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TimeEntry{}, ErrNoRunningTimer
+	}
+
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	return *stopped, nil
+}
+
+func (s *timeTrackingServiceImpl) GetRunning(ctx context.Context, userID int) (models.TimeEntry, error) {
+	running, err := s.repo.GetRunning(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TimeEntry{}, ErrNoRunningTimer
+	}
+
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	return *running, nil
+}
+
+// LogWork добавляет завершённую запись вручную.
+func (s *timeTrackingServiceImpl) LogWork(ctx context.Context, entry models.TimeEntry) (models.TimeEntry, error) {
+	if entry.StartedAt.IsZero() || entry.EndedAt == nil {
+		return models.TimeEntry{}, fmt.Errorf("%w: start and end are required", ErrInvalidTimeEntry)
+	}
+
+	if !entry.EndedAt.After(entry.StartedAt) {
+		return models.TimeEntry{}, fmt.Errorf("%w: end must be after start", ErrInvalidTimeEntry)
+	}
+
+	if entry.EndedAt.After(s.now()) {
+		return models.TimeEntry{}, fmt.Errorf("%w: end cannot be in the future", ErrInvalidTimeEntry)
+	}
+
+	if err := checkTaskExists(ctx, s.tasks, entry.TaskID); err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	created, err := s.repo.Create(ctx, &entry)
+	if err != nil {
+		return models.TimeEntry{}, err
+	}
+
+	return *created, nil
+}
+
+func (s *timeTrackingServiceImpl) GetByTask(ctx context.Context, taskID int) ([]models.TimeEntry, error) {
+	if err := checkTaskExists(ctx, s.tasks, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByTask(ctx, taskID)
+}
+
+func (s *timeTrackingServiceImpl) Delete(ctx context.Context, userID, id int) error {
+	err := s.repo.Delete(ctx, userID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTimeEntryNotFound
+	}
+
+	return err
+}
+
+// Report строит отчёт; по умолчанию - по задачам за последние 30 дней, даты - в UTC.
+func (s *timeTrackingServiceImpl) Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = "task"
+	}
+
+	if !validGrouping(filter.GroupBy) {
+		return nil, fmt.Errorf("%w: unknown group_by %q", ErrInvalidReport, filter.GroupBy)
+	}
+
+	if filter.To.IsZero() {
+		filter.To = s.now()
+	}
+
+	if filter.From.IsZero() {
+		filter.From = filter.To.AddDate(0, 0, -30)
+	}
+
+	if !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidReport)
+	}
+
+	if filter.Timezone == "" {
+		filter.Timezone = "UTC"
+	} else if _, err := loadTimezone(filter.Timezone); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+
+	return s.repo.Report(ctx, filter)
+}
+
+func validGrouping(groupBy string) bool {
+	for _, grouping := range ReportGroupings {
+		if grouping == groupBy {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTimeEntryRepository реализует методы TimeEntryRepository для тестов.
+type MockTimeEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimeEntryRepository) Create(ctx context.Context, entry *models.TimeEntry) (*models.TimeEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetRunning(ctx context.Context, userID int) (*models.TimeEntry, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Stop(ctx context.Context, userID int, endedAt time.Time) (*models.TimeEntry, error) {
+	args := m.Called(ctx, userID, endedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) GetByTask(ctx context.Context, taskID int) ([]models.TimeEntry, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Delete(ctx context.Context, userID, id int) error {
+	return m.Called(ctx, userID, id).Error(0)
+}
+
+func (m *MockTimeEntryRepository) Report(ctx context.Context, filter models.TimeReportFilter) ([]models.TimeReportRow, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]models.TimeReportRow), args.Error(1)
+}
+
+func newTestTimeTrackingService(now time.Time) (*timeTrackingServiceImpl, *MockTimeEntryRepository, *MockTaskRepository) {
+	repo := new(MockTimeEntryRepository)
+	tasks := new(MockTaskRepository)
+	service := NewTimeTrackingService(repo, tasks).(*timeTrackingServiceImpl)
+	service.now = func() time.Time { return now }
+
+	return service, repo, tasks
+}
+
+func TestTimeTrackingService_StartTimer(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	service, repo, tasks := newTestTimeTrackingService(now)
+	ctx := context.Background()
+
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	repo.On("GetRunning", ctx, 2).Return(nil, sql.ErrNoRows).Once()
+	repo.On("Create", ctx, &models.TimeEntry{TaskID: 1, UserID: 2, StartedAt: now, Note: "review"}).
+		Return(&models.TimeEntry{ID: 5, TaskID: 1, UserID: 2, StartedAt: now}, nil)
+
+	entry, err := service.StartTimer(ctx, 2, 1, "review")
+	require.NoError(t, err)
+	require.Equal(t, 5, entry.ID)
+
+	// Второй таймер при уже запущенном не стартует
+	repo.On("GetRunning", ctx, 2).Return(&models.TimeEntry{ID: 5}, nil).Once()
+
+	_, err = service.StartTimer(ctx, 2, 1, "")
+	require.ErrorIs(t, err, ErrTimerRunning)
+
+	// Гонка, пойманная уникальным индексом, даёт ту же ошибку
+	repo.On("GetRunning", ctx, 3).Return(nil, sql.ErrNoRows)
+	repo.On("Create", ctx, mock.MatchedBy(func(e *models.TimeEntry) bool { return e.UserID == 3 })).
+		Return(nil, repositories.ErrDuplicate)
+
+	_, err = service.StartTimer(ctx, 3, 1, "")
+	require.ErrorIs(t, err, ErrTimerRunning)
+}
+
+func TestTimeTrackingService_StopTimer(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	service, repo, _ := newTestTimeTrackingService(now)
+	ctx := context.Background()
+
+	repo.On("Stop", ctx, 2, now).Return(&models.TimeEntry{ID: 5, EndedAt: &now}, nil)
+	repo.On("Stop", ctx, 3, now).Return(nil, sql.ErrNoRows)
+
+	entry, err := service.StopTimer(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, now, *entry.EndedAt)
+
+	_, err = service.StopTimer(ctx, 3)
+	require.ErrorIs(t, err, ErrNoRunningTimer)
+}
+
+func TestTimeTrackingService_LogWork(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	service, repo, tasks := newTestTimeTrackingService(now)
+	ctx := context.Background()
+
+	start := now.Add(-2 * time.Hour)
+	end := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	_, err := service.LogWork(ctx, models.TimeEntry{TaskID: 1, StartedAt: start})
+	require.ErrorIs(t, err, ErrInvalidTimeEntry)
+
+	_, err = service.LogWork(ctx, models.TimeEntry{TaskID: 1, StartedAt: end, EndedAt: &start})
+	require.ErrorIs(t, err, ErrInvalidTimeEntry)
+
+	_, err = service.LogWork(ctx, models.TimeEntry{TaskID: 1, StartedAt: start, EndedAt: &future})
+	require.ErrorIs(t, err, ErrInvalidTimeEntry)
+
+	entry := models.TimeEntry{TaskID: 1, UserID: 2, StartedAt: start, EndedAt: &end}
+
+	tasks.On("GetByID", ctx, 1).Return(&models.Task{ID: 1}, nil)
+	repo.On("Create", ctx, &entry).Return(&models.TimeEntry{ID: 9, TaskID: 1, UserID: 2, StartedAt: start, EndedAt: &end}, nil)
+
+	created, err := service.LogWork(ctx, entry)
+	require.NoError(t, err)
+	require.Equal(t, 9, created.ID)
+}
+
+func TestTimeTrackingService_Report(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	service, repo, _ := newTestTimeTrackingService(now)
+	ctx := context.Background()
+
+	expectedFilter := models.TimeReportFilter{GroupBy: "task", From: now.AddDate(0, 0, -30), To: now, Timezone: "UTC"}
+	rows := []models.TimeReportRow{{Key: "1", Label: "Task 1", Entries: 2, Seconds: 3600}}
+
+	repo.On("Report", ctx, expectedFilter).Return(rows, nil)
+
+	result, err := service.Report(ctx, models.TimeReportFilter{})
+	require.NoError(t, err)
+	require.Equal(t, rows, result)
+
+	_, err = service.Report(ctx, models.TimeReportFilter{GroupBy: "project"})
+	require.ErrorIs(t, err, ErrInvalidReport)
+
+	_, err = service.Report(ctx, models.TimeReportFilter{GroupBy: "user", From: now, To: now.Add(-time.Hour)})
+	require.ErrorIs(t, err, ErrInvalidReport)
+
+	_, err = service.Report(ctx, models.TimeReportFilter{GroupBy: "date", Timezone: "Mars/Olympus"})
+	require.ErrorIs(t, err, ErrInvalidReport)
+}
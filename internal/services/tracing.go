@@ -0,0 +1,182 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/tracing"
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceTaskService оборачивает сервис задач: каждый вызов выполняется в своём спане
+// "TaskService.<метод>", ошибка отмечается в спане.
+func TraceTaskService(next TaskService) TaskService {
+	return &tracedTaskService{next: next}
+}
+
+// TraceUserService оборачивает сервис пользователей так же, как TraceTaskService.
+func TraceUserService(next UserService) UserService {
+	return &tracedUserService{next: next}
+}
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+type tracedTaskService struct {
+	next TaskService
+}
+
+func (s *tracedTaskService) Create(ctx context.Context, task models.Task) (created models.Task, err error) {
+	ctx, span := startSpan(ctx, "TaskService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	created, err = s.next.Create(ctx, task)
+	span.SetAttributes(attribute.Int("task.id", created.ID))
+
+	return created, err
+}
+
+func (s *tracedTaskService) GetByID(ctx context.Context, id int) (task *models.Task, err error) {
+	ctx, span := startSpan(ctx, "TaskService.GetByID", attribute.Int("task.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetByID(ctx, id)
+}
+
+func (s *tracedTaskService) GetAll(ctx context.Context) (tasks []models.Task, err error) {
+	ctx, span := startSpan(ctx, "TaskService.GetAll")
+	defer func() { tracing.End(span, err) }()
+
+	tasks, err = s.next.GetAll(ctx)
+	span.SetAttributes(attribute.Int("task.count", len(tasks)))
+
+	return tasks, err
+}
+
+func (s *tracedTaskService) List(ctx context.Context, filter models.TaskFilter) (tasks []models.Task, err error) {
+	ctx, span := startSpan(ctx, "TaskService.List")
+	defer func() { tracing.End(span, err) }()
+
+	tasks, err = s.next.List(ctx, filter)
+	span.SetAttributes(attribute.Int("task.count", len(tasks)))
+
+	return tasks, err
+}
+
+func (s *tracedTaskService) Update(ctx context.Context, task models.Task) (updated models.Task, err error) {
+	ctx, span := startSpan(ctx, "TaskService.Update", attribute.Int("task.id", task.ID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Update(ctx, task)
+}
+
+func (s *tracedTaskService) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := startSpan(ctx, "TaskService.Delete", attribute.Int("task.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, id)
+}
+
+func (s *tracedTaskService) Bulk(ctx context.Context, request models.BulkRequest) (response models.BulkResponse, err error) {
+	ctx, span := startSpan(ctx, "TaskService.Bulk",
+		attribute.String("bulk.mode", request.Mode),
+		attribute.Int("bulk.operations", len(request.Operations)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	response, err = s.next.Bulk(ctx, request)
+	span.SetAttributes(attribute.Int("bulk.failed", response.Failed))
+
+	return response, err
+}
+
+func (s *tracedTaskService) Export(ctx context.Context, filter models.TaskFilter, fn func(models.Task) error) (err error) {
+	ctx, span := startSpan(ctx, "TaskService.Export")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Export(ctx, filter, fn)
+}
+
+func (s *tracedTaskService) Import(ctx context.Context, options models.ImportOptions, body io.Reader) (report models.ImportReport, err error) {
+	ctx, span := startSpan(ctx, "TaskService.Import", attribute.Bool("import.dry_run", options.DryRun))
+	defer func() { tracing.End(span, err) }()
+
+	report, err = s.next.Import(ctx, options, body)
+	span.SetAttributes(attribute.Int("import.imported", report.Imported))
+
+	return report, err
+}
+
+func (s *tracedTaskService) QuickAdd(ctx context.Context, request models.QuickAddRequest) (result models.QuickAddResult, err error) {
+	ctx, span := startSpan(ctx, "TaskService.QuickAdd", attribute.Bool("quick_add.dry_run", request.DryRun))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.QuickAdd(ctx, request)
+}
+
+func (s *tracedTaskService) ReserveTasks(ctx context.Context, tasks []models.Task) (err error) {
+	ctx, span := startSpan(ctx, "TaskService.ReserveTasks", attribute.Int("task.count", len(tasks)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.ReserveTasks(ctx, tasks)
+}
+
+func (s *tracedTaskService) SetMaxTasks(maxTasks int) {
+	s.next.SetMaxTasks(maxTasks)
+}
+
+type tracedUserService struct {
+	next UserService
+}
+
+func (s *tracedUserService) Create(ctx context.Context, user models.User) (created models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	created, err = s.next.Create(ctx, user)
+	span.SetAttributes(attribute.Int("user.id", created.ID))
+
+	return created, err
+}
+
+func (s *tracedUserService) GetAll(ctx context.Context) (users []models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetAll")
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetAll(ctx)
+}
+
+func (s *tracedUserService) GetByID(ctx context.Context, id int) (user models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetByID", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.GetByID(ctx, id)
+}
+
+// GetByKey не пишет ключ в спан: это секрет пользователя.
+func (s *tracedUserService) GetByKey(ctx context.Context, key string) (user models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetByKey")
+	defer func() { tracing.End(span, err) }()
+
+	user, err = s.next.GetByKey(ctx, key)
+	span.SetAttributes(attribute.Int("user.id", user.ID))
+
+	return user, err
+}
+
+func (s *tracedUserService) Update(ctx context.Context, user models.User) (updated models.User, err error) {
+	ctx, span := startSpan(ctx, "UserService.Update", attribute.Int("user.id", user.ID))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Update(ctx, user)
+}
+
+func (s *tracedUserService) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := startSpan(ctx, "UserService.Delete", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return s.next.Delete(ctx, id)
+}
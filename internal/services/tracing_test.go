@@ -0,0 +1,53 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceUserService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mockRepo := new(MockUserRepository)
+	service := TraceUserService(NewUserService(mockRepo))
+
+	parent, span := otel.Tracer("test").Start(context.Background(), "GET /users/{id}")
+
+	// Репозиторий получает контекст со спаном сервиса
+	mockRepo.On("GetByID", mock.MatchedBy(func(ctx context.Context) bool {
+		return trace.SpanFromContext(ctx).SpanContext().SpanID() != span.SpanContext().SpanID()
+	}), 1).Return(&models.User{ID: 1, Name: "Alice"}, nil)
+	mockRepo.On("GetByID", mock.Anything, 2).Return(nil, sql.ErrNoRows)
+
+	user, err := service.GetByID(parent, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Name)
+
+	_, err = service.GetByID(parent, 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "UserService.GetByID", spans[0].Name())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Len(t, spans[1].Events(), 1) // Событие exception с текстом ошибки
+	mockRepo.AssertExpectations(t)
+}
@@ -11,6 +11,7 @@ type UserService interface {
 	Create(ctx context.Context, user models.User) (models.User, error)
 	GetAll(ctx context.Context) ([]models.User, error)
 	GetByID(ctx context.Context, id int) (models.User, error)
+	GetByKey(ctx context.Context, key string) (models.User, error)
 	Update(ctx context.Context, user models.User) (models.User, error)
 	Delete(ctx context.Context, id int) error
 }
@@ -28,6 +29,10 @@ func (s *userServiceImpl) Create(ctx context.Context, user models.User) (models.
 		return models.User{}, errors.New("name and key are required")
 	}
 
+	if err := checkTimezone(user.Timezone); err != nil {
+		return models.User{}, err
+	}
+
 	createdUser, err := s.repo.Create(ctx, &user)
 	if err != nil {
 		return models.User{}, err
@@ -49,11 +54,28 @@ func (s *userServiceImpl) GetByID(ctx context.Context, id int) (models.User, err
 	return *user, nil
 }
 
+func (s *userServiceImpl) GetByKey(ctx context.Context, key string) (models.User, error) {
+	if key == "" {
+		return models.User{}, errors.New("key is required")
+	}
+
+	user, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return *user, nil
+}
+
 func (s *userServiceImpl) Update(ctx context.Context, user models.User) (models.User, error) {
 	if user.Name == "" {
 		return models.User{}, errors.New("name is required")
 	}
 
+	if err := checkTimezone(user.Timezone); err != nil {
+		return models.User{}, err
+	}
+
 	updatedUser, err := s.repo.Update(ctx, &user)
 	if err != nil {
 		return models.User{}, err
@@ -65,3 +87,14 @@ func (s *userServiceImpl) Update(ctx context.Context, user models.User) (models.
 func (s *userServiceImpl) Delete(ctx context.Context, id int) error {
 	return s.repo.Delete(ctx, id)
 }
+
+// checkTimezone проверяет зону IANA; пустая строка оставляет зону без изменений (UTC для новых).
+func checkTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+
+	_, err := loadTimezone(timezone)
+
+	return err
+}
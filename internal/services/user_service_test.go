@@ -33,6 +33,14 @@ func (m *MockUserRepository) GetByID(ctx context.Context, id int) (*models.User,
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByKey(ctx context.Context, key string) (*models.User, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *models.User) (*models.User, error) {
 	args := m.Called(ctx, user)
 	return args.Get(0).(*models.User), args.Error(1)
@@ -99,6 +107,32 @@ func TestUserService_GetByID(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_GetByKey(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+
+	ctx := context.Background()
+	validUser := models.User{
+		ID:   1,
+		Name: "Valid User",
+		Key:  "valid-key",
+	}
+
+	// Успешный поиск по ключу
+	mockRepo.On("GetByKey", ctx, "valid-key").Return(&validUser, nil)
+
+	result, err := service.GetByKey(ctx, "valid-key")
+	require.NoError(t, err)
+	require.Equal(t, validUser, result)
+	mockRepo.AssertExpectations(t)
+
+	// Ошибка: пустой ключ
+	_, err = service.GetByKey(ctx, "")
+	require.Error(t, err)
+	require.Equal(t, "key is required", err.Error())
+	mockRepo.AssertNotCalled(t, "GetByKey", ctx, "")
+}
+
 func TestUserService_Update(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo)
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxViewNameLength = 100
+
+var (
+	ErrViewNotFound  = errors.New("view not found")
+	ErrViewExists    = errors.New("view with this name already exists")
+	ErrViewForbidden = errors.New("only the owner can change a view")
+	ErrInvalidView   = errors.New("invalid view")
+)
+
+// viewColumns - колонки задачи, которые можно сохранить в виде, кроме cf.<поле>.
+var viewColumns = map[string]bool{
+	"id": true, "name": true, "status": true, "time": true, "due": true, "user_id": true,
+	"project_id": true, "parent_id": true, "priority": true, "estimate_minutes": true, "custom_fields": true,
+}
+
+type ViewService interface {
+	Create(ctx context.Context, view models.SavedView) (models.SavedView, error)
+	GetByID(ctx context.Context, userID, id int) (models.SavedView, error)
+	GetAll(ctx context.Context, userID int) ([]models.SavedView, error)
+	Update(ctx context.Context, userID int, view models.SavedView) (models.SavedView, error)
+	Delete(ctx context.Context, userID, id int) error
+	Tasks(ctx context.Context, userID, id int, overrides url.Values) ([]models.Task, error)
+}
+
+type viewServiceImpl struct {
+	repo     repositories.ViewRepository
+	tasks    TaskService
+	projects repositories.ProjectRepository
+}
+
+func NewViewService(
+	repo repositories.ViewRepository,
+	tasks TaskService,
+	projects repositories.ProjectRepository,
+) ViewService {
+	return &viewServiceImpl{repo: repo, tasks: tasks, projects: projects}
+}
+
+func (s *viewServiceImpl) Create(ctx context.Context, view models.SavedView) (models.SavedView, error) {
+	if err := s.validate(ctx, &view); err != nil {
+		return models.SavedView{}, err
+	}
+
+	created, err := s.repo.Create(ctx, &view)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return models.SavedView{}, ErrViewExists
+	}
+
+	if err != nil {
+		return models.SavedView{}, err
+	}
+
+	return *created, nil
+}
+
+// GetByID возвращает вид, если он принадлежит пользователю или открыт в проекте,
+// в котором пользователь участвует. Чужие недоступные виды не отличаются от несуществующих.
+func (s *viewServiceImpl) GetByID(ctx context.Context, userID, id int) (models.SavedView, error) {
+	view, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SavedView{}, ErrViewNotFound
+	}
+
+	if err != nil {
+		return models.SavedView{}, err
+	}
+
+	if view.UserID == userID {
+		return *view, nil
+	}
+
+	if view.ProjectID == 0 {
+		return models.SavedView{}, ErrViewNotFound
+	}
+
+	member, err := s.projects.IsMember(ctx, view.ProjectID, userID)
+	if err != nil {
+		return models.SavedView{}, err
+	}
+
+	if !member {
+		return models.SavedView{}, ErrViewNotFound
+	}
+
+	return *view, nil
+}
+
+func (s *viewServiceImpl) GetAll(ctx context.Context, userID int) ([]models.SavedView, error) {
+	return s.repo.GetVisible(ctx, userID)
+}
+
+func (s *viewServiceImpl) Update(ctx context.Context, userID int, view models.SavedView) (models.SavedView, error) {
+	existing, err := s.GetByID(ctx, userID, view.ID)
+	if err != nil {
+		return models.SavedView{}, err
+	}
+
+	if existing.UserID != userID {
+		return models.SavedView{}, ErrViewForbidden
+	}
+
+	view.UserID = existing.UserID
+
+	if err := s.validate(ctx, &view); err != nil {
+		return models.SavedView{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, &view)
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return models.SavedView{}, ErrViewExists
+	}
+
+	if err != nil {
+		return models.SavedView{}, err
+	}
+
+	return *updated, nil
+}
+
+func (s *viewServiceImpl) Delete(ctx context.Context, userID, id int) error {
+	existing, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if existing.UserID != userID {
+		return ErrViewForbidden
+	}
+
+	err = s.repo.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrViewNotFound
+	}
+
+	return err
+}
+
+// Tasks выполняет сохранённый запрос так же, как GET /tasks. Параметры overrides
+// заменяют сохранённые (например, limit и offset для постраничного вывода),
+// а проект вида, если он задан, изменить нельзя.
+func (s *viewServiceImpl) Tasks(ctx context.Context, userID, id int, overrides url.Values) ([]models.Task, error) {
+	view, err := s.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(view.Query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: stored query is corrupted", ErrInvalidTaskFilter)
+	}
+
+	for key, value := range overrides {
+		values[key] = value
+	}
+
+	filter, err := ParseTaskFilter(values)
+	if err != nil {
+		return nil, err
+	}
+
+	filter = filter.ForUser(userID)
+
+	if view.ProjectID != 0 {
+		filter.ProjectID = view.ProjectID
+	}
+
+	return s.tasks.List(ctx, filter)
+}
+
+// validate проверяет имя, запрос и колонки вида; запрос приводится к каноническому виду.
+func (s *viewServiceImpl) validate(ctx context.Context, view *models.SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+
+	if view.Name == "" || utf8.RuneCountInString(view.Name) > maxViewNameLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidView, maxViewNameLength)
+	}
+
+	values, err := url.ParseQuery(strings.TrimPrefix(view.Query, "?"))
+	if err != nil {
+		return fmt.Errorf("%w: malformed query", ErrInvalidView)
+	}
+
+	for key := range values {
+		if !taskFilterParams[key] && !strings.HasPrefix(key, repositories.CustomFieldPrefix) {
+			return fmt.Errorf("%w: unknown query parameter %q", ErrInvalidView, key)
+		}
+	}
+
+	if _, err := ParseTaskFilter(values); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+
+	// Проект вида задаётся полем project_id, дублировать его в запросе незачем
+	if view.ProjectID != 0 {
+		values.Del("project_id")
+	}
+
+	view.Query = values.Encode()
+
+	seen := make(map[string]bool, len(view.Columns))
+
+	for _, column := range view.Columns {
+		name, custom := strings.CutPrefix(column, repositories.CustomFieldPrefix)
+		if (custom && !customFieldName.MatchString(name)) || (!custom && !viewColumns[column]) {
+			return fmt.Errorf("%w: unknown column %q", ErrInvalidView, column)
+		}
+
+		if seen[column] {
+			return fmt.Errorf("%w: duplicate column %q", ErrInvalidView, column)
+		}
+
+		seen[column] = true
+	}
+
+	if view.ProjectID != 0 {
+		if _, err := s.projects.GetByID(ctx, view.ProjectID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrProjectNotFound
+			}
+
+			return err
+		}
+
+		// Открыть вид можно только в своём проекте; чужой проект не отличается от несуществующего
+		member, err := s.projects.IsMember(ctx, view.ProjectID, view.UserID)
+		if err != nil {
+			return err
+		}
+
+		if !member {
+			return ErrProjectNotFound
+		}
+	}
+
+	return nil
+}
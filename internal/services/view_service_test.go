@@ -0,0 +1,178 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockViewRepository реализует методы ViewRepository для тестов.
+type MockViewRepository struct {
+	mock.Mock
+}
+
+func (m *MockViewRepository) Create(ctx context.Context, view *models.SavedView) (*models.SavedView, error) {
+	args := m.Called(ctx, view)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedView), args.Error(1)
+}
+
+func (m *MockViewRepository) GetByID(ctx context.Context, id int) (*models.SavedView, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedView), args.Error(1)
+}
+
+func (m *MockViewRepository) GetVisible(ctx context.Context, userID int) ([]models.SavedView, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.SavedView), args.Error(1)
+}
+
+func (m *MockViewRepository) Update(ctx context.Context, view *models.SavedView) (*models.SavedView, error) {
+	args := m.Called(ctx, view)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedView), args.Error(1)
+}
+
+func (m *MockViewRepository) Delete(ctx context.Context, id int) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func newTestViewService() (ViewService, *MockViewRepository, *MockTaskRepository, *MockProjectRepository) {
+	repo := new(MockViewRepository)
+	tasks := new(MockTaskRepository)
+	fields, projects, _ := newTestProjectService()
+
+	return NewViewService(repo, NewTaskService(tasks, fields, nil), projects), repo, tasks, projects
+}
+
+func TestViewService_Create(t *testing.T) {
+	service, repo, _, projects := newTestViewService()
+	ctx := context.Background()
+
+	projects.On("IsMember", ctx, 1, 7).Return(true, nil)
+	projects.On("IsMember", ctx, 1, 9).Return(false, nil)
+
+	invalid := []models.SavedView{
+		{UserID: 7, Name: " "},
+		{UserID: 7, Name: "Typo", Query: "stauts=Pending"},
+		{UserID: 7, Name: "Bad order", Query: "order=sideways"},
+		{UserID: 7, Name: "Bad column", Columns: []string{"password"}},
+		{UserID: 7, Name: "Dup column", Columns: []string{"name", "name"}},
+	}
+
+	for _, view := range invalid {
+		_, err := service.Create(ctx, view)
+		require.ErrorIs(t, err, ErrInvalidView, view.Name)
+	}
+
+	_, err := service.Create(ctx, models.SavedView{UserID: 7, Name: "Shared", ProjectID: 2})
+	require.ErrorIs(t, err, ErrProjectNotFound)
+
+	// Открыть вид в проекте, где пользователь не участвует, нельзя
+	_, err = service.Create(ctx, models.SavedView{UserID: 9, Name: "Foreign", ProjectID: 1})
+	require.ErrorIs(t, err, ErrProjectNotFound)
+
+	// Запрос хранится в каноническом виде, project_id из запроса уходит в поле вида
+	repo.On("Create", ctx, &models.SavedView{
+		UserID:    7,
+		ProjectID: 1,
+		Name:      "Build stage",
+		Query:     "cf.stage=build&order=desc&sort=due",
+		Columns:   []string{"name", "due", "cf.stage"},
+	}).Return(&models.SavedView{ID: 3}, nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(v *models.SavedView) bool { return v.Name == "Mine" })).
+		Return(nil, repositories.ErrDuplicate)
+
+	created, err := service.Create(ctx, models.SavedView{
+		UserID:    7,
+		ProjectID: 1,
+		Name:      " Build stage ",
+		Query:     "?sort=due&order=desc&cf.stage=build&project_id=5",
+		Columns:   []string{"name", "due", "cf.stage"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, created.ID)
+
+	_, err = service.Create(ctx, models.SavedView{UserID: 7, Name: "Mine"})
+	require.ErrorIs(t, err, ErrViewExists)
+}
+
+func TestViewService_Access(t *testing.T) {
+	service, repo, _, projects := newTestViewService()
+	ctx := context.Background()
+
+	projects.On("IsMember", ctx, 1, 8).Return(true, nil)
+	projects.On("IsMember", ctx, 1, 9).Return(false, nil)
+
+	repo.On("GetByID", ctx, 1).Return(&models.SavedView{ID: 1, UserID: 7, Name: "Personal"}, nil)
+	repo.On("GetByID", ctx, 2).Return(&models.SavedView{ID: 2, UserID: 7, ProjectID: 1, Name: "Shared"}, nil)
+	repo.On("GetByID", ctx, 3).Return(nil, sql.ErrNoRows)
+
+	_, err := service.GetByID(ctx, 7, 1)
+	require.NoError(t, err)
+
+	// Личный вид чужому пользователю не виден, общий виден участнику проекта, но не изменяется
+	_, err = service.GetByID(ctx, 8, 1)
+	require.ErrorIs(t, err, ErrViewNotFound)
+
+	_, err = service.GetByID(ctx, 8, 2)
+	require.NoError(t, err)
+
+	// Вне проекта общий вид не отличается от несуществующего
+	_, err = service.GetByID(ctx, 9, 2)
+	require.ErrorIs(t, err, ErrViewNotFound)
+
+	_, err = service.Tasks(ctx, 9, 2, url.Values{})
+	require.ErrorIs(t, err, ErrViewNotFound)
+
+	require.ErrorIs(t, service.Delete(ctx, 8, 2), ErrViewForbidden)
+
+	_, err = service.Update(ctx, 8, models.SavedView{ID: 2, Name: "Hijacked"})
+	require.ErrorIs(t, err, ErrViewForbidden)
+
+	require.ErrorIs(t, service.Delete(ctx, 7, 3), ErrViewNotFound)
+
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestViewService_Tasks(t *testing.T) {
+	service, repo, tasks, projects := newTestViewService()
+	ctx := context.Background()
+
+	projects.On("IsMember", ctx, 1, 8).Return(true, nil)
+
+	repo.On("GetByID", ctx, 2).Return(&models.SavedView{
+		ID: 2, UserID: 7, ProjectID: 1, Query: "cf.points=5&limit=10&sort=cf.points&status=Pending",
+	}, nil)
+
+	tasks.On("List", ctx, models.TaskFilter{
+		ProjectID:   1,
+		Status:      "Pending",
+		Custom:      map[string]string{"points": "5"},
+		SortBy:      "cf.points",
+		SortNumeric: true,
+		Limit:       20,
+		Offset:      40,
+	}).Return([]models.Task{{ID: 1}}, nil)
+
+	// limit и offset из запроса переопределяют сохранённые, а проект вида - нет
+	result, err := service.Tasks(ctx, 8, 2, url.Values{"limit": {"20"}, "offset": {"40"}, "project_id": {"9"}})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	_, err = service.Tasks(ctx, 8, 2, url.Values{"limit": {"many"}})
+	require.ErrorIs(t, err, ErrInvalidTaskFilter)
+}
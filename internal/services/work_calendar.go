@@ -0,0 +1,245 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"WebTasks/internal/repositories"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxHolidays       = 1000
+	maxHolidayNameLen = 100
+)
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrInvalidWorkCalendar = errors.New("invalid work calendar")
+)
+
+// WorkCalendarProvider отдаёт часовой пояс и рабочий календарь пользователя.
+type WorkCalendarProvider interface {
+	Get(ctx context.Context, userID int) (models.WorkCalendar, error)
+}
+
+type WorkCalendarService interface {
+	Get(ctx context.Context, userID int) (models.WorkCalendar, error)
+	Update(ctx context.Context, userID int, calendar models.WorkCalendar) (models.WorkCalendar, error)
+}
+
+type workCalendarServiceImpl struct {
+	repo repositories.WorkCalendarRepository
+}
+
+func NewWorkCalendarService(repo repositories.WorkCalendarRepository) WorkCalendarService {
+	return &workCalendarServiceImpl{repo: repo}
+}
+
+func (s *workCalendarServiceImpl) Get(ctx context.Context, userID int) (models.WorkCalendar, error) {
+	calendar, err := s.repo.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WorkCalendar{}, ErrUserNotFound
+	}
+
+	return calendar, err
+}
+
+// Update проверяет и сохраняет календарь целиком. Рабочие дни приводятся к порядку недели,
+// праздники - к порядку дат; пустой пояс означает UTC, пустой список дней - пн-пт.
+func (s *workCalendarServiceImpl) Update(ctx context.Context, userID int, calendar models.WorkCalendar) (models.WorkCalendar, error) {
+	calendar, err := normalizeWorkCalendar(calendar)
+	if err != nil {
+		return models.WorkCalendar{}, err
+	}
+
+	err = s.repo.Replace(ctx, userID, calendar)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WorkCalendar{}, ErrUserNotFound
+	}
+
+	if err != nil {
+		return models.WorkCalendar{}, err
+	}
+
+	return calendar, nil
+}
+
+func normalizeWorkCalendar(calendar models.WorkCalendar) (models.WorkCalendar, error) {
+	if calendar.Timezone == "" {
+		calendar.Timezone = "UTC"
+	}
+
+	if _, err := loadTimezone(calendar.Timezone); err != nil {
+		return calendar, fmt.Errorf("%w: %v", ErrInvalidWorkCalendar, err)
+	}
+
+	if len(calendar.Workdays) == 0 {
+		calendar.Workdays = models.DefaultWorkdays
+	}
+
+	var workdays [7]bool
+
+	for _, day := range calendar.Workdays {
+		index := slices.Index(models.Weekdays, strings.ToLower(strings.TrimSpace(day)))
+		if index < 0 {
+			return calendar, fmt.Errorf("%w: unknown weekday %q, expected one of %s",
+				ErrInvalidWorkCalendar, day, strings.Join(models.Weekdays, ", "))
+		}
+
+		workdays[index] = true
+	}
+
+	calendar.Workdays = make([]string, 0, len(models.Weekdays))
+
+	// Неделя в ответе начинается с понедельника
+	for i := range models.Weekdays {
+		if day := (i + 1) % 7; workdays[day] {
+			calendar.Workdays = append(calendar.Workdays, models.Weekdays[day])
+		}
+	}
+
+	if len(calendar.Holidays) > maxHolidays {
+		return calendar, fmt.Errorf("%w: at most %d holidays", ErrInvalidWorkCalendar, maxHolidays)
+	}
+
+	holidays := make([]models.Holiday, 0, len(calendar.Holidays))
+	seen := make(map[string]bool, len(calendar.Holidays))
+
+	for _, holiday := range calendar.Holidays {
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(holiday.Date))
+		if err != nil {
+			return calendar, fmt.Errorf("%w: holiday date %q must be YYYY-MM-DD", ErrInvalidWorkCalendar, holiday.Date)
+		}
+
+		holiday.Date = date.Format(time.DateOnly)
+		holiday.Name = strings.TrimSpace(holiday.Name)
+
+		if utf8.RuneCountInString(holiday.Name) > maxHolidayNameLen {
+			return calendar, fmt.Errorf("%w: holiday name must not exceed %d characters", ErrInvalidWorkCalendar, maxHolidayNameLen)
+		}
+
+		if seen[holiday.Date] {
+			return calendar, fmt.Errorf("%w: duplicate holiday %s", ErrInvalidWorkCalendar, holiday.Date)
+		}
+
+		seen[holiday.Date] = true
+		holidays = append(holidays, holiday)
+	}
+
+	slices.SortFunc(holidays, func(a, b models.Holiday) int { return strings.Compare(a.Date, b.Date) })
+	calendar.Holidays = holidays
+
+	return calendar, nil
+}
+
+// loadTimezone загружает зону IANA. "Local" не принимается: зона сервера пользователю неизвестна.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+
+	return location, nil
+}
+
+// userCalendar загружает календарь пользователя; без provider действует календарь по умолчанию.
+func userCalendar(ctx context.Context, provider WorkCalendarProvider, userID int) (*businessCalendar, error) {
+	if provider == nil {
+		return newBusinessCalendar(models.WorkCalendar{}), nil
+	}
+
+	calendar, err := provider.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBusinessCalendar(calendar), nil
+}
+
+// businessCalendar отвечает на вопросы о рабочих днях в часовом поясе пользователя.
+type businessCalendar struct {
+	location *time.Location
+	workdays [7]bool
+	holidays map[string]bool
+}
+
+// newBusinessCalendar строит календарь из сохранённых настроек. Неизвестный пояс
+// заменяется на UTC, пустой список рабочих дней - на пн-пт.
+func newBusinessCalendar(calendar models.WorkCalendar) *businessCalendar {
+	location, err := loadTimezone(calendar.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	c := &businessCalendar{location: location, holidays: make(map[string]bool, len(calendar.Holidays))}
+
+	workdays := calendar.Workdays
+	if len(workdays) == 0 {
+		workdays = models.DefaultWorkdays
+	}
+
+	for _, day := range workdays {
+		if index := slices.Index(models.Weekdays, day); index >= 0 {
+			c.workdays[index] = true
+		}
+	}
+
+	for _, holiday := range calendar.Holidays {
+		c.holidays[holiday.Date] = true
+	}
+
+	return c
+}
+
+func (c *businessCalendar) isWorkday(date time.Time) bool {
+	date = date.In(c.location)
+	return c.workdays[date.Weekday()] && !c.holidays[date.Format(time.DateOnly)]
+}
+
+// addBusinessDays отсчитывает days рабочих дней после date, сохраняя время суток в поясе
+// календаря: шаг по дням делается в нём, чтобы переход на летнее время не сдвигал час.
+func (c *businessCalendar) addBusinessDays(date time.Time, days int) time.Time {
+	date = date.In(c.location)
+
+	if c.workdays == [7]bool{} {
+		return date.AddDate(0, 0, days)
+	}
+
+	for days > 0 {
+		date = date.AddDate(0, 0, 1)
+
+		if c.isWorkday(date) {
+			days--
+		}
+	}
+
+	return date
+}
+
+// daysOff - число нерабочих дней после from до to включительно, по датам в поясе календаря.
+func (c *businessCalendar) daysOff(from, to time.Time) int {
+	from, to = from.In(c.location), to.In(c.location)
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, c.location)
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, c.location)
+
+	var count int
+
+	for day = day.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !c.isWorkday(day) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"WebTasks/internal/models"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockWorkCalendarRepository реализует методы WorkCalendarRepository для тестов.
+type MockWorkCalendarRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkCalendarRepository) Get(ctx context.Context, userID int) (models.WorkCalendar, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(models.WorkCalendar), args.Error(1)
+}
+
+func (m *MockWorkCalendarRepository) Replace(ctx context.Context, userID int, calendar models.WorkCalendar) error {
+	return m.Called(ctx, userID, calendar).Error(0)
+}
+
+func TestWorkCalendarService_Update(t *testing.T) {
+	repo := new(MockWorkCalendarRepository)
+	service := NewWorkCalendarService(repo)
+
+	ctx := context.Background()
+	expected := models.WorkCalendar{
+		Timezone: "Asia/Dubai",
+		Workdays: []string{"mon", "tue", "wed", "thu", "sun"},
+		Holidays: []models.Holiday{{Date: "2030-01-01", Name: "New Year"}, {Date: "2030-03-08"}},
+	}
+
+	repo.On("Replace", ctx, 7, expected).Return(nil)
+	repo.On("Replace", ctx, 8, mock.Anything).Return(sql.ErrNoRows)
+
+	calendar, err := service.Update(ctx, 7, models.WorkCalendar{
+		Timezone: "Asia/Dubai",
+		Workdays: []string{"SUN", "thu", "wed", "tue", "mon", "mon"},
+		Holidays: []models.Holiday{{Date: "2030-03-08"}, {Date: " 2030-01-01", Name: " New Year "}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, calendar)
+
+	_, err = service.Update(ctx, 8, models.WorkCalendar{})
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	for _, invalid := range []models.WorkCalendar{
+		{Timezone: "Mars/Olympus"},
+		{Timezone: "Local"},
+		{Workdays: []string{"funday"}},
+		{Holidays: []models.Holiday{{Date: "01.01.2030"}}},
+		{Holidays: []models.Holiday{{Date: "2030-01-01"}, {Date: "2030-01-01"}}},
+	} {
+		_, err = service.Update(ctx, 7, invalid)
+		require.ErrorIs(t, err, ErrInvalidWorkCalendar)
+	}
+
+	repo.AssertNumberOfCalls(t, "Replace", 2)
+}
+
+func TestBusinessCalendar(t *testing.T) {
+	calendar := newBusinessCalendar(models.WorkCalendar{
+		Timezone: "Europe/Moscow",
+		Holidays: []models.Holiday{{Date: "2030-01-07"}},
+	})
+
+	moscow := calendar.location
+
+	// Пятница, 4 января 2030 года: три рабочих дня - вт, ср, чт (пн - праздник)
+	friday := time.Date(2030, 1, 4, 18, 0, 0, 0, moscow)
+	require.Equal(t, time.Date(2030, 1, 10, 18, 0, 0, 0, moscow), calendar.addBusinessDays(friday, 3))
+
+	// Суббота и воскресенье по Москве, хотя в UTC срок ещё в пятницу вечером не наступил
+	require.Equal(t, 3, calendar.daysOff(friday, time.Date(2030, 1, 7, 23, 0, 0, 0, moscow)))
+	require.False(t, calendar.isWorkday(time.Date(2030, 1, 4, 22, 0, 0, 0, time.UTC)))
+	require.True(t, calendar.isWorkday(time.Date(2030, 1, 4, 20, 0, 0, 0, time.UTC)))
+}
+
+func TestBusinessCalendar_DaylightSaving(t *testing.T) {
+	calendar := newBusinessCalendar(models.WorkCalendar{Timezone: "America/New_York"})
+
+	// Пятница, 8 марта 2030 года, 9:00 по Нью-Йорку, передана в UTC. В воскресенье 10 марта
+	// часы переводятся на летнее время, а срок остаётся в 9:00 по местному времени.
+	friday := time.Date(2030, 3, 8, 14, 0, 0, 0, time.UTC)
+	monday := calendar.addBusinessDays(friday, 1)
+
+	require.Equal(t, time.Date(2030, 3, 11, 9, 0, 0, 0, calendar.location), monday)
+	require.Equal(t, time.Date(2030, 3, 11, 13, 0, 0, 0, time.UTC), monday.UTC())
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"WebTasks/config"
+	"WebTasks/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// BlobStore хранит содержимое вложений по ключу (хэшу содержимого).
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore создаёт хранилище по настройкам из конфигурации.
+func NewBlobStore(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStore(cfg.Dir)
+	case "s3":
+		// Запросы к S3 несут X-Request-ID и traceparent запроса, который их вызвал
+		return NewS3Store(cfg.S3.Endpoint, cfg.S3.Region, cfg.S3.Bucket, cfg.S3.AccessKey, cfg.S3.SecretKey, utils.NewCorrelationClient())
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+func validateKey(key string) error {
+	if !keyPattern.MatchString(key) || key == "." || key == ".." {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return nil
+}
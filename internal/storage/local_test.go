@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_PutOpenDelete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	err = store.Put(ctx, "abcdef", strings.NewReader("content"), 7)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "ab", "abcdef"))
+
+	exists, err := store.Exists(ctx, "abcdef")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	blob, err := store.Open(ctx, "abcdef")
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+	require.NoError(t, blob.Close())
+
+	require.NoError(t, store.Delete(ctx, "abcdef"))
+
+	_, err = store.Open(ctx, "abcdef")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+
+	// Повторное удаление не считается ошибкой
+	assert.NoError(t, store.Delete(ctx, "abcdef"))
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "../escape", strings.NewReader("x"), 1)
+	assert.Error(t, err)
+
+	_, err = store.Open(context.Background(), "a/b")
+	assert.Error(t, err)
+}
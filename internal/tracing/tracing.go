@@ -0,0 +1,144 @@
+// Package tracing настраивает трассировку OpenTelemetry: провайдер спанов, экспорт
+// по OTLP/HTTP или в stdout и распространение заголовков W3C traceparent.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation - имя библиотеки инструментирования в спанах.
+const instrumentation = "WebTasks"
+
+// Экспортёры спанов.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// maxStatementLength ограничивает размер текста SQL в атрибутах спана.
+const maxStatementLength = 2000
+
+// Options задаёт экспорт спанов.
+type Options struct {
+	Exporter    string  // none, stdout или otlp
+	Endpoint    string  // Адрес коллектора OTLP/HTTP: host:port или URL
+	Insecure    bool    // Отправлять спаны по HTTP без TLS
+	SampleRatio float64 // Доля записываемых трасс от 0 до 1
+	ServiceName string  // service.name в ресурсах спанов
+	Version     string  // service.version
+}
+
+// Setup устанавливает глобальные провайдер спанов и пропагатор W3C trace context.
+// Пропагатор устанавливается и без экспорта, чтобы входящий traceparent передавался
+// дальше. Возвращённая функция выгружает накопленные спаны и останавливает экспорт.
+func Setup(ctx context.Context, options Options, stdout io.Writer) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+
+	switch options.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(stdout)); err != nil {
+			return nil, fmt.Errorf("stdout exporter: %w", err)
+		}
+	case ExporterOTLP:
+		var err error
+		if exporter, err = otlptracehttp.New(ctx, otlpOptions(options)...); err != nil {
+			return nil, fmt.Errorf("otlp exporter: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", options.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(options.ServiceName),
+		semconv.ServiceVersion(options.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Решение о записи принимает вызывающий сервис, если он прислал traceparent
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func otlpOptions(options Options) []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+
+	// OTEL_EXPORTER_OTLP_ENDPOINT задаётся URL, а параметр конфигурации обычно host:port
+	if strings.Contains(options.Endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimSuffix(options.Endpoint, "/")+"/v1/traces"))
+	} else if options.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(options.Endpoint))
+	}
+
+	if options.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	return opts
+}
+
+// Tracer возвращает трассировщик сервиса. Он обращается к глобальному провайдеру при
+// каждом спане, поэтому пакеты могут получать его до вызова Setup.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// End завершает спан, отмечая в нём ошибку err, если она есть.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+var (
+	sqlString  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumber  = regexp.MustCompile(`([^\w$.])-?\d+(?:\.\d+)?\b`)
+	sqlSpacing = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeSQL готовит текст запроса для атрибута db.query.text: заменяет строковые
+// и числовые литералы на "?" и схлопывает пробелы. Параметры $1, $2 остаются как есть:
+// значения аргументов в спаны не попадают.
+func SanitizeSQL(query string) string {
+	query = sqlString.ReplaceAllString(query, "?")
+	query = sqlNumber.ReplaceAllString(query, "${1}?")
+	query = strings.TrimSpace(sqlSpacing.ReplaceAllString(query, " "))
+
+	if len(query) > maxStatementLength {
+		query = query[:maxStatementLength] + "..."
+	}
+
+	return query
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeSQL(t *testing.T) {
+	tests := map[string]string{
+		"SELECT id FROM public.tasks\n\tWHERE id = $1":                                   "SELECT id FROM public.tasks WHERE id = $1",
+		"SELECT * FROM tasks WHERE name = 'O''Brien' AND due > '0001-01-01 00:00:00+00'": "SELECT * FROM tasks WHERE name = ? AND due > ?",
+		"SELECT * FROM tasks LIMIT 10 OFFSET 20":                                         "SELECT * FROM tasks LIMIT ? OFFSET ?",
+		"SELECT t1.id, v2 FROM t1 WHERE x = -1.5 AND y = $12":                            "SELECT t1.id, v2 FROM t1 WHERE x = ? AND y = $12",
+	}
+
+	for query, want := range tests {
+		assert.Equal(t, want, SanitizeSQL(query), query)
+	}
+}
+
+// collector - заглушка коллектора OTLP/HTTP, запоминающая принятые запросы.
+type collector struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	c.mu.Lock()
+	c.requests = append(c.requests, r)
+	c.bodies = append(c.bodies, body)
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetup_OTLP(t *testing.T) {
+	stub := &collector{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	shutdown, err := Setup(ctx, Options{
+		Exporter:    ExporterOTLP,
+		Endpoint:    server.URL,
+		Insecure:    true,
+		SampleRatio: 1,
+		ServiceName: "webtasks-test",
+	}, io.Discard)
+	require.NoError(t, err)
+
+	_, span := Tracer().Start(ctx, "select tasks")
+	span.End()
+
+	// Shutdown выгружает накопленные спаны
+	require.NoError(t, shutdown(ctx))
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	require.Len(t, stub.requests, 1)
+	assert.Equal(t, "/v1/traces", stub.requests[0].URL.Path)
+	assert.Equal(t, "application/x-protobuf", stub.requests[0].Header.Get("Content-Type"))
+	assert.True(t, bytes.Contains(stub.bodies[0], []byte("select tasks")))
+	assert.True(t, bytes.Contains(stub.bodies[0], []byte("webtasks-test")))
+}
+
+func TestSetup_Stdout(t *testing.T) {
+	var out bytes.Buffer
+
+	ctx := context.Background()
+	shutdown, err := Setup(ctx, Options{Exporter: ExporterStdout, SampleRatio: 1, ServiceName: "webtasks"}, &out)
+	require.NoError(t, err)
+
+	_, span := Tracer().Start(ctx, "TaskService.Create")
+	span.End()
+
+	require.NoError(t, shutdown(ctx))
+	assert.Contains(t, out.String(), `"Name":"TaskService.Create"`)
+
+	_, err = Setup(ctx, Options{Exporter: "zipkin"}, &out)
+	assert.ErrorContains(t, err, `unknown trace exporter "zipkin"`)
+}
@@ -1 +1,182 @@
 package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Форматы журнала.
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// redacted заменяет значения секретов в журнале.
+const redacted = "[REDACTED]"
+
+// logLevel - текущий уровень журнала; SetLogLevel меняет его без пересоздания логгера.
+var logLevel = new(slog.LevelVar)
+
+// SetupLogger делает логгер NewLogger логгером по умолчанию с уровнем level.
+// Через него же идут записи стандартного пакета log.
+func SetupLogger(w io.Writer, format, level string) error {
+	if err := SetLogLevel(level); err != nil {
+		return err
+	}
+
+	logger, err := NewLogger(w, format)
+	if err != nil {
+		return err
+	}
+
+	slog.SetDefault(logger)
+
+	return nil
+}
+
+// NewLogger создаёт логгер в формате format с общим уровнем SetLogLevel. Логгер
+// дополняет записи полями из контекста и скрывает секреты.
+func NewLogger(w io.Writer, format string) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: logLevel, ReplaceAttr: redactAttr}
+
+	switch format {
+	case LogFormatJSON:
+		return slog.New(contextHandler{slog.NewJSONHandler(w, options)}), nil
+	case LogFormatText, "":
+		return slog.New(contextHandler{slog.NewTextHandler(w, options)}), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// SetLogLevel меняет уровень журнала: debug, info, warn или error.
+func SetLogLevel(level string) error {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("log level: %w", err)
+	}
+
+	logLevel.Set(parsed)
+
+	return nil
+}
+
+// logFields - поля записей журнала в пределах запроса. Хранится указатель, чтобы поля,
+// добавленные внутренними middleware (пользователь), видели и внешние (итог запроса).
+type logFields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+type logFieldsKey struct{}
+
+// WithLogAttrs возвращает контекст, записи журнала с которым дополняются attrs.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	fields := &logFields{attrs: append(LogAttrs(ctx), attrs...)}
+
+	return context.WithValue(ctx, logFieldsKey{}, fields)
+}
+
+// AddLogAttrs дополняет поля, заданные ранее WithLogAttrs, так что они попадают и в записи
+// по родительским контекстам. Без WithLogAttrs в ctx вызов ничего не делает.
+func AddLogAttrs(ctx context.Context, attrs ...slog.Attr) {
+	if fields, ok := ctx.Value(logFieldsKey{}).(*logFields); ok {
+		fields.mu.Lock()
+		fields.attrs = append(fields.attrs, attrs...)
+		fields.mu.Unlock()
+	}
+}
+
+// LogAttrs возвращает поля журнала из ctx.
+func LogAttrs(ctx context.Context) []slog.Attr {
+	fields, ok := ctx.Value(logFieldsKey{}).(*logFields)
+	if !ok {
+		return nil
+	}
+
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+
+	return slices.Clone(fields.attrs)
+}
+
+// contextHandler добавляет к записи идентификатор запроса, поля из контекста
+// и идентификаторы трассы.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+
+	record.AddAttrs(LogAttrs(ctx)...)
+
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// secretKeys - поля, значения которых не пишутся в журнал.
+var secretKeys = map[string]bool{
+	"password":      true,
+	"secret":        true,
+	"secret_key":    true,
+	"access_key":    true,
+	"api_key":       true,
+	"key":           true,
+	"token":         true,
+	"authorization": true,
+	"dsn":           true,
+}
+
+var (
+	// password=... в строке подключения и user:password@ в URL
+	dsnPassword = regexp.MustCompile(`(?i)(password=)('(?:[^'\\]|\\.)*'|\S+)`)
+	urlPassword = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+	bearerToken = regexp.MustCompile(`(?i)(bearer\s+)\S+`)
+)
+
+// Redact скрывает пароли и токены в произвольном тексте: сообщениях и текстах ошибок.
+func Redact(s string) string {
+	s = dsnPassword.ReplaceAllString(s, "${1}"+redacted)
+	s = urlPassword.ReplaceAllString(s, "${1}"+redacted+"@")
+
+	return bearerToken.ReplaceAllString(s, "${1}"+redacted)
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if secretKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch value := attr.Value.Resolve(); value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(Redact(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			attr.Value = slog.StringValue(Redact(err.Error()))
+		}
+	}
+
+	return attr
+}
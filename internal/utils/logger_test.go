@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var records []map[string]interface{}
+
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var record map[string]interface{}
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogger_ContextAttrsAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, SetLogLevel("info"))
+	logger, err := NewLogger(&buf, LogFormatJSON)
+	require.NoError(t, err)
+
+	ctx := WithLogAttrs(WithRequestID(context.Background(), "abc"), slog.String("method", "GET"))
+	inner := WithLogAttrs(ctx, slog.String("route", "/tasks/{id}"))
+
+	// Поле, добавленное по внутреннему контексту, видно и во внешнем
+	AddLogAttrs(inner, slog.Int("user_id", 7))
+
+	logger.DebugContext(ctx, "skipped")
+	logger.InfoContext(ctx, "outer")
+	logger.InfoContext(inner, "inner")
+
+	require.NoError(t, SetLogLevel("debug"))
+	logger.DebugContext(context.Background(), "debug")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "outer", records[0]["msg"])
+	assert.Equal(t, "abc", records[0]["request_id"])
+	assert.NotContains(t, records[0], "user_id")
+
+	assert.Equal(t, "abc", records[1]["request_id"])
+	assert.Equal(t, "GET", records[1]["method"])
+	assert.Equal(t, "/tasks/{id}", records[1]["route"])
+	assert.Equal(t, float64(7), records[1]["user_id"])
+
+	assert.Equal(t, "DEBUG", records[2]["level"])
+
+	assert.Error(t, SetLogLevel("verbose"))
+	_, err = NewLogger(&buf, "xml")
+	assert.Error(t, err)
+}
+
+func TestLogger_Redaction(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, SetLogLevel("info"))
+	logger, err := NewLogger(&buf, LogFormatJSON)
+	require.NoError(t, err)
+
+	logger.Info("connecting host=db password=root dbname=tasks",
+		"password", "root",
+		"api_key", "key123",
+		"url", "postgres://app:s3cret@db:5432/tasks",
+		"header", "Bearer token-value",
+		"error", errors.New(`pq: password authentication failed: password='p w'`),
+		"user", "postgres",
+	)
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	assert.Equal(t, "connecting host=db password=[REDACTED] dbname=tasks", record["msg"])
+	assert.Equal(t, "[REDACTED]", record["password"])
+	assert.Equal(t, "[REDACTED]", record["api_key"])
+	assert.Equal(t, "postgres://app:[REDACTED]@db:5432/tasks", record["url"])
+	assert.Equal(t, "Bearer [REDACTED]", record["header"])
+	assert.Equal(t, "pq: password authentication failed: password=[REDACTED]", record["error"])
+	assert.Equal(t, "postgres", record["user"])
+}
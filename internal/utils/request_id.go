@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// RequestIDHeader - заголовок идентификатора запроса во входящих запросах, ответах
+// и исходящих вызовах.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern ограничивает идентификаторы от клиентов: они попадают в журнал и заголовки.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type requestIDKey struct{}
+
+// NewRequestID возвращает случайный идентификатор запроса из 16 шестнадцатеричных символов.
+func NewRequestID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
+
+// ValidRequestID сообщает, можно ли принять идентификатор запроса от клиента.
+func ValidRequestID(id string) bool {
+	return requestIDPattern.MatchString(id)
+}
+
+// WithRequestID сохраняет идентификатор запроса в контексте. Записи журнала с этим
+// контекстом получают поле request_id, исходящие вызовы - заголовок X-Request-ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса или пустую строку.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// CorrelationTransport передаёт в исходящие запросы идентификатор запроса и контекст
+// трассы (traceparent) из контекста запроса, чтобы вызов можно было найти в журналах
+// и трассах получателя.
+type CorrelationTransport struct {
+	Base http.RoundTripper // По умолчанию http.DefaultTransport
+}
+
+// NewCorrelationClient возвращает HTTP-клиент с CorrelationTransport.
+func NewCorrelationClient() *http.Client {
+	return &http.Client{Transport: CorrelationTransport{}}
+}
+
+func (t CorrelationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := req.Context()
+
+	// RoundTripper не должен менять исходный запрос
+	req = req.Clone(ctx)
+
+	if id := RequestIDFromContext(ctx); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return base.RoundTrip(req)
+}
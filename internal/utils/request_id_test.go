@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("4bf92f3577b34da6"))
+	assert.True(t, ValidRequestID("client:req-1.2_3"))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("with space"))
+	assert.False(t, ValidRequestID(string(make([]byte, 129))))
+	assert.Len(t, NewRequestID(), 16)
+}
+
+func TestCorrelationTransport(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var received http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	span := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+
+	ctx := trace.ContextWithSpanContext(WithRequestID(context.Background(), "req-1"), span)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := NewCorrelationClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "req-1", received.Get(RequestIDHeader))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", received.Get("traceparent"))
+
+	// Исходный запрос не меняется
+	assert.Empty(t, req.Header.Get(RequestIDHeader))
+}